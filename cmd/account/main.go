@@ -0,0 +1,379 @@
+// =============================================================================
+// FILE: cmd/account/main.go
+// ROLE: Account Inspector — Balance, Nonce, Code and Storage, Proven Per Provider
+// =============================================================================
+//
+// SYSTEM CONTEXT
+// ==============
+// This is the entry point for the `account` command. It asks every provider
+// for one account's state at one block with eth_getProof (EIP-1186), and
+// verifies each answer locally instead of trusting it:
+//
+//   provider's header   ══ its hash             (rpc.VerifyBlockHash)
+//   provider's proof    ══ header.stateRoot     (rpc.VerifyProof)
+//
+// A provider serving bogus state — a stale cache, a node that is still
+// syncing and guesses, a proxy that rewrites answers — cannot produce a
+// proof that hashes up to a real state root. And since every provider is
+// checked on its own, no single one has to be trusted.
+//
+// Usage examples:
+//   account 0xd8dA6BF26964aF9D7eEd9e03E53415D37aA96045
+//   account 0xdAC17F958D2ee523a2206206994597C13D831ec7 --slots 0,1,0x2
+//   account 0x... --block 19000000
+//   account 0x... --json          ← reports/account-YYYYMMDD-HHMMSS.json
+//
+// EXECUTION FLOW
+// ==============
+//
+//   1. main()
+//      ├─ config.LoadEnv(), flag.Parse()
+//      ├─ flags.Setup()           ← Load config; --transport, --record/--replay, chain check (internal/cli)
+//      └─ runAccount()
+//           │
+//           ├─ Resolve the block:
+//           │   "latest" → the LOWEST head among providers (as in cmd/logs),
+//           │   so every provider is asked about a block it has
+//           │
+//           ├─ Fan out (errgroup, same pattern as cmd/snapshot), per provider:
+//           │   GetBlock(n)  → stateRoot, VerifyBlockHash
+//           │   GetProof(address, slots, n) → VerifyProof against stateRoot
+//           │
+//           └─ Output:
+//               ├─ --json? → buildReport() → reportjson.Write()
+//               └─ Terminal? → format.FormatAccount()
+//
+// STATE AVAILABILITY
+// ==================
+// Full (non-archive) nodes keep state for only the most recent blocks —
+// typically 128. Asking one for an older block fails with "missing trie
+// node" or similar; that is a property of the node, not a verification
+// failure, and shows up as an error row.
+// =============================================================================
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/dando385/eth-rpc-monitor/internal/cli"
+	"github.com/dando385/eth-rpc-monitor/internal/config"
+	"github.com/dando385/eth-rpc-monitor/internal/format"
+	"github.com/dando385/eth-rpc-monitor/internal/reportjson"
+	"github.com/dando385/eth-rpc-monitor/internal/rpc"
+)
+
+// =============================================================================
+// SECTION 1: JSON Report Types
+// =============================================================================
+
+// AccountReport is the top-level JSON structure for `account --json`.
+type AccountReport struct {
+	Timestamp time.Time      `json:"timestamp"`
+	Address   string         `json:"address"`
+	Block     uint64         `json:"block"`
+	Slots     []string       `json:"slots"`
+	Providers []AccountEntry `json:"providers"`
+}
+
+// AccountEntry is one provider's answer. Balance is a decimal wei string
+// (it routinely exceeds float64 precision). Fields are omitted when the
+// fetch failed.
+type AccountEntry struct {
+	Name           string      `json:"name"`
+	LatencyMS      int64       `json:"latency_ms"`
+	Error          string      `json:"error,omitempty"`
+	BlockHash      string      `json:"block_hash,omitempty"`
+	StateRoot      string      `json:"state_root,omitempty"`
+	HeaderVerified bool        `json:"header_verified"`
+	Balance        string      `json:"balance_wei,omitempty"`
+	Nonce          uint64      `json:"nonce"`
+	CodeHash       string      `json:"code_hash,omitempty"`
+	StorageHash    string      `json:"storage_hash,omitempty"`
+	Storage        []SlotEntry `json:"storage,omitempty"`
+	Verified       bool        `json:"verified"` // Header and every proof verified
+	Problems       []string    `json:"problems,omitempty"`
+}
+
+// SlotEntry is one storage slot as a provider reported it.
+type SlotEntry struct {
+	Slot     string `json:"slot"`
+	Value    string `json:"value"`
+	Verified bool   `json:"verified"`
+}
+
+// buildReport converts the per-provider results to the JSON report.
+func buildReport(address string, number uint64, slots []string, results []format.AccountResult) AccountReport {
+	report := AccountReport{
+		Timestamp: time.Now(),
+		Address:   address,
+		Block:     number,
+		Slots:     slots,
+		Providers: make([]AccountEntry, len(results)),
+	}
+	for i, r := range results {
+		e := AccountEntry{Name: r.Provider, LatencyMS: r.Latency.Milliseconds()}
+		if r.Error != nil {
+			e.Error = r.Error.Error()
+			report.Providers[i] = e
+			continue
+		}
+		p := r.Proof
+		e.BlockHash, e.StateRoot = r.BlockHash, r.StateRoot
+		e.HeaderVerified = r.Header.OK()
+		e.Balance = rpc.ParseHexBigInt(p.Balance).String()
+		e.Nonce, _ = rpc.ParseHexUint64(p.Nonce)
+		e.CodeHash, e.StorageHash = p.CodeHash, p.StorageHash
+		for j, s := range p.StorageProof {
+			slot := s.Key
+			if j < len(slots) {
+				slot = slots[j]
+			}
+			ok := e.HeaderVerified && r.Check.Account == nil && j < len(r.Check.Slots) && r.Check.Slots[j] == nil
+			e.Storage = append(e.Storage, SlotEntry{Slot: slot, Value: s.Value, Verified: ok})
+		}
+		e.Verified = r.Verified()
+		e.Problems = r.Problems()
+		report.Providers[i] = e
+	}
+	return report
+}
+
+// =============================================================================
+// SECTION 2: Argument Parsing
+// =============================================================================
+
+// parseAddress checks that s is a 20-byte hex address and lowercases it.
+func parseAddress(s string) (string, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	digits := strings.TrimPrefix(s, "0x")
+	if len(digits) != 40 || !strings.HasPrefix(s, "0x") || strings.Trim(digits, "0123456789abcdef") != "" {
+		return "", fmt.Errorf("%q is not a 20-byte 0x-hex address", s)
+	}
+	return s, nil
+}
+
+// parseSlots splits a comma-separated slot list. Each slot is decimal or
+// 0x-hex and is returned as a 32-byte 0x-hex key, the form every node
+// accepts.
+func parseSlots(s string) ([]string, error) {
+	var slots []string
+	for _, part := range strings.Split(s, ",") {
+		part = strings.ToLower(strings.TrimSpace(part))
+		if part == "" {
+			continue
+		}
+		digits := strings.TrimPrefix(part, "0x")
+		if digits == part {
+			n, err := strconv.ParseUint(part, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("slot %q is not a decimal or 0x-hex number", part)
+			}
+			digits = strconv.FormatUint(n, 16)
+		}
+		if len(digits) == 0 || len(digits) > 64 || strings.Trim(digits, "0123456789abcdef") != "" {
+			return nil, fmt.Errorf("slot %q is not a 32-byte hex value", part)
+		}
+		slots = append(slots, "0x"+strings.Repeat("0", 64-len(digits))+digits)
+	}
+	return slots, nil
+}
+
+// resolveBlock turns the --block argument into a number. "latest" (or "")
+// maps to head; "earliest" to 0; decimal and 0x-hex numbers are parsed.
+// "pending" has no state root to prove against and is rejected.
+func resolveBlock(arg string, head uint64) (uint64, error) {
+	arg = strings.TrimSpace(strings.ToLower(arg))
+	switch {
+	case arg == "" || arg == "latest":
+		return head, nil
+	case arg == "earliest":
+		return 0, nil
+	case arg == "pending":
+		return 0, fmt.Errorf("pending block has no state root to verify against")
+	case strings.HasPrefix(arg, "0x"):
+		return rpc.ParseHexUint64(arg)
+	default:
+		return strconv.ParseUint(arg, 10, 64)
+	}
+}
+
+// =============================================================================
+// SECTION 3: Head Resolution
+// =============================================================================
+
+// lowestHead asks every provider for its head and returns the lowest one
+// (see cmd/logs). Providers that fail here are skipped; they will fail, and
+// be reported, when asked for the account.
+func lowestHead(ctx context.Context, clients []*rpc.Client) (uint64, error) {
+	heads := make([]uint64, len(clients))
+	ok := make([]bool, len(clients))
+	var mu sync.Mutex
+
+	g, gctx := errgroup.WithContext(ctx)
+	for i, c := range clients {
+		i, c := i, c
+		g.Go(func() error {
+			h, _, err := c.BlockNumber(gctx)
+			mu.Lock()
+			heads[i], ok[i] = h, err == nil
+			mu.Unlock()
+			return nil
+		})
+	}
+	g.Wait()
+
+	var lowest uint64
+	found := false
+	for i := range clients {
+		if ok[i] && (!found || heads[i] < lowest) {
+			lowest, found = heads[i], true
+		}
+	}
+	if !found {
+		return 0, fmt.Errorf("no provider reported a head block")
+	}
+	return lowest, nil
+}
+
+// =============================================================================
+// SECTION 4: Main Logic
+// =============================================================================
+
+// fetchAccount gets one provider's header and proof for the account at
+// block number and verifies them.
+func fetchAccount(ctx context.Context, c *rpc.Client, address string, slots []string, number uint64) format.AccountResult {
+	r := format.AccountResult{Provider: c.Name()}
+	blockNum := fmt.Sprintf("0x%x", number)
+
+	block, _, err := c.GetBlock(ctx, blockNum)
+	if err == nil && block == nil {
+		err = fmt.Errorf("block %d not found", number)
+	}
+	if err != nil {
+		r.Error = fmt.Errorf("fetching block: %w", err)
+		return r
+	}
+	r.BlockHash, r.StateRoot = block.Hash, block.StateRoot
+	r.Header = rpc.VerifyBlockHash(block)
+
+	proof, latency, err := c.GetProof(ctx, address, slots, blockNum)
+	r.Latency = latency
+	if err != nil {
+		r.Error = err
+		return r
+	}
+	r.Proof = proof
+	r.Check = rpc.VerifyProof(block.StateRoot, address, slots, proof)
+	return r
+}
+
+// runAccount resolves the block, queries every provider concurrently and
+// renders the result.
+func runAccount(cfg *config.Config, address string, slots []string, blockArg string, jsonOut bool) error {
+	// Two calls per provider after the head lookup.
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Defaults.Timeout*3)
+	defer cancel()
+
+	clients := make([]*rpc.Client, len(cfg.Providers))
+	for i, p := range cfg.Providers {
+		clients[i] = rpc.NewClient(p.Name, p.URL, p.Timeout, p.ClientOptions()...)
+	}
+
+	var head uint64
+	if a := strings.TrimSpace(strings.ToLower(blockArg)); a == "" || a == "latest" {
+		var err error
+		if head, err = lowestHead(ctx, clients); err != nil {
+			return err
+		}
+	}
+	number, err := resolveBlock(blockArg, head)
+	if err != nil {
+		return fmt.Errorf("invalid --block %q: %w", blockArg, err)
+	}
+
+	results := make([]format.AccountResult, len(clients))
+	var mu sync.Mutex
+	g, gctx := errgroup.WithContext(ctx)
+	for i, c := range clients {
+		i, c := i, c
+		g.Go(func() error {
+			r := fetchAccount(gctx, c, address, slots, number)
+			mu.Lock()
+			results[i] = r
+			mu.Unlock()
+			return nil
+		})
+	}
+	g.Wait()
+
+	if jsonOut {
+		report := buildReport(address, number, slots, results)
+		filepath, err := reportjson.Write(report, "account")
+		if err != nil {
+			return fmt.Errorf("failed to write JSON report: %w", err)
+		}
+		fmt.Fprintf(os.Stderr, "JSON report written to: %s\n", filepath)
+		for _, e := range report.Providers {
+			for _, p := range e.Problems {
+				fmt.Fprintf(os.Stderr, "Warning: %s: %s\n", e.Name, p)
+			}
+		}
+		return nil
+	}
+
+	format.FormatAccount(os.Stdout, address, number, slots, results)
+	return nil
+}
+
+// =============================================================================
+// SECTION 5: Entry Point
+// =============================================================================
+
+func main() {
+	config.LoadEnv()
+
+	var (
+		flags    = cli.Register(flag.CommandLine, "")
+		block    = flag.String("block", "latest", "Block (decimal, 0x-hex, latest = lowest head among providers, or earliest)")
+		slotsArg = flag.String("slots", "", "Comma-separated storage slots to prove (decimal or 0x-hex)")
+		jsonOut  = flag.Bool("json", false, "Output JSON report to reports directory")
+	)
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "Usage: account [flags] <address>")
+		os.Exit(2)
+	}
+	address, err := parseAddress(flag.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	slots, err := parseSlots(*slotsArg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	cfg, cassette, err := flags.Setup("account")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	defer cassette.Close()
+
+	if err := runAccount(cfg, address, slots, *block, *jsonOut); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
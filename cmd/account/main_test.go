@@ -0,0 +1,96 @@
+package main
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dando385/eth-rpc-monitor/internal/format"
+	"github.com/dando385/eth-rpc-monitor/internal/rpc"
+)
+
+func TestParseAddress(t *testing.T) {
+	got, err := parseAddress(" 0xD8dA6BF26964aF9D7eEd9e03E53415D37aA96045 ")
+	if err != nil || got != "0xd8da6bf26964af9d7eed9e03e53415d37aa96045" {
+		t.Fatalf("got %q, %v", got, err)
+	}
+	for _, bad := range []string{"", "0x1234", "d8da6bf26964af9d7eed9e03e53415d37aa96045", "0x" + strings.Repeat("zz", 20)} {
+		if _, err := parseAddress(bad); err == nil {
+			t.Fatalf("parseAddress(%q): expected error", bad)
+		}
+	}
+}
+
+func TestParseSlots(t *testing.T) {
+	pad := func(d string) string { return "0x" + strings.Repeat("0", 64-len(d)) + d }
+	got, err := parseSlots("0, 10 ,0xA,,")
+	if err != nil || !reflect.DeepEqual(got, []string{pad("0"), pad("a"), pad("a")}) {
+		t.Fatalf("got %v, %v", got, err)
+	}
+	if got, err := parseSlots(""); err != nil || got != nil {
+		t.Fatalf("empty list = %v, %v", got, err)
+	}
+	for _, bad := range []string{"x", "0x", "0xgg", "0x" + strings.Repeat("1", 65)} {
+		if _, err := parseSlots(bad); err == nil {
+			t.Fatalf("parseSlots(%q): expected error", bad)
+		}
+	}
+}
+
+func TestResolveBlock(t *testing.T) {
+	tests := []struct {
+		arg     string
+		want    uint64
+		wantErr bool
+	}{
+		{"latest", 500, false},
+		{"", 500, false},
+		{"earliest", 0, false},
+		{"19000000", 19000000, false},
+		{"0x10", 16, false},
+		{"pending", 0, true},
+		{"abc", 0, true},
+	}
+	for _, tc := range tests {
+		got, err := resolveBlock(tc.arg, 500)
+		if (err != nil) != tc.wantErr || (!tc.wantErr && got != tc.want) {
+			t.Fatalf("resolveBlock(%q) = %d, %v", tc.arg, got, err)
+		}
+	}
+}
+
+func TestBuildReport(t *testing.T) {
+	hash := "0x" + strings.Repeat("ab", 32)
+	slots := []string{"0x" + strings.Repeat("0", 64)}
+	verified := format.AccountResult{
+		Provider: "good", BlockHash: hash, StateRoot: "0xroot",
+		Header: rpc.HashCheck{Claimed: hash, Computed: hash},
+		Proof: &rpc.AccountProof{
+			Balance: "0x3635c9adc5dea00000", Nonce: "0x7", CodeHash: "0xcode", StorageHash: "0xstorage",
+			StorageProof: []rpc.StorageProof{{Key: "0x0", Value: "0x2a"}},
+		},
+		Check: rpc.ProofCheck{Slots: []error{nil}},
+	}
+	badSlot := verified
+	badSlot.Provider = "bad"
+	badSlot.Check = rpc.ProofCheck{Slots: []error{errors.New("returned value 0x2b, proven 0x2a")}}
+	down := format.AccountResult{Provider: "down", Error: errors.New("timeout")}
+
+	report := buildReport("0xabc", 100, slots, []format.AccountResult{verified, badSlot, down})
+	if len(report.Providers) != 3 || report.Block != 100 || report.Address != "0xabc" {
+		t.Fatalf("report = %+v", report)
+	}
+	g := report.Providers[0]
+	if !g.Verified || !g.HeaderVerified || g.Balance != "1000000000000000000000" || g.Nonce != 7 ||
+		len(g.Storage) != 1 || g.Storage[0].Slot != slots[0] || g.Storage[0].Value != "0x2a" || !g.Storage[0].Verified {
+		t.Fatalf("verified entry = %+v", g)
+	}
+	b := report.Providers[1]
+	if b.Verified || b.Storage[0].Verified || !reflect.DeepEqual(b.Problems, []string{"slot 0: returned value 0x2b, proven 0x2a"}) {
+		t.Fatalf("bad entry = %+v", b)
+	}
+	if d := report.Providers[2]; d.Error != "timeout" || d.Verified || d.Storage != nil {
+		t.Fatalf("down entry = %+v", d)
+	}
+}
@@ -0,0 +1,210 @@
+// =============================================================================
+// FILE: cmd/archive/main.go
+// ROLE: Archive Depth Probe — How Far Back Can Each Provider Serve State?
+// =============================================================================
+//
+// SYSTEM CONTEXT
+// ==============
+// This is the entry point for the `archive` command. "Archive access" is a
+// pricing tier and a node flag, rarely a documented fact about an endpoint.
+// This command measures it: for every provider, the oldest block at which
+// eth_getBalance and eth_getStorageAt still answer, found by stepping back
+// exponentially from the head and then binary-searching the edge
+// (rpc.ProbeArchive). The error at the edge — "missing trie node", "header
+// not found" and similar — is kept, since it says which kind of pruning hit.
+//
+// Usage examples:
+//   archive                   ← Probe every provider
+//   archive --address 0x...   ← Probe with another account's balance/storage
+//   archive --json            ← Capability report: reports/archive-*.json
+//
+// EXECUTION FLOW
+// ==============
+//
+//   1. main()
+//      ├─ config.LoadEnv(), flag.Parse()
+//      ├─ flags.Setup()           ← Load config; --transport, --record/--replay, chain check (internal/cli)
+//      └─ runArchive()
+//           │
+//           ├─ Fan out (errgroup, same pattern as cmd/nodeinfo), per provider:
+//           │   BlockNumber() → its own head
+//           │   ProbeArchive(head) → oldest servable block
+//           │
+//           └─ Output:
+//               ├─ --json? → buildReport() → reportjson.Write()
+//               └─ Terminal? → format.FormatArchive()
+//
+// COST
+// ====
+// A 128-block full node takes about 30 calls, an archive node about 50 on
+// mainnet. Calls count against each provider's rate_limit like any other.
+// =============================================================================
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/dando385/eth-rpc-monitor/internal/cli"
+	"github.com/dando385/eth-rpc-monitor/internal/config"
+	"github.com/dando385/eth-rpc-monitor/internal/format"
+	"github.com/dando385/eth-rpc-monitor/internal/reportjson"
+	"github.com/dando385/eth-rpc-monitor/internal/rpc"
+)
+
+// defaultAddress is probed unless --address says otherwise. The zero
+// address has a balance on every Ethereum chain (tokens are burned to it),
+// so its state exists at every block.
+const defaultAddress = "0x0000000000000000000000000000000000000000"
+
+// probeBudget bounds the whole run, in multiples of the request timeout.
+// Each call already has its own timeout; this only stops a provider that
+// keeps answering slowly from holding the command open for long.
+const probeBudget = 100
+
+// =============================================================================
+// SECTION 1: JSON Report Types
+// =============================================================================
+
+// ArchiveReport is the capability report written by `archive --json`.
+type ArchiveReport struct {
+	Timestamp time.Time      `json:"timestamp"`
+	Address   string         `json:"address"`
+	Providers []ArchiveEntry `json:"providers"`
+}
+
+// ArchiveEntry is one provider's historical-state capability. The boundary
+// fields describe the newest block that failed, and are omitted for
+// archive nodes.
+type ArchiveEntry struct {
+	Name            string `json:"name"`
+	Type            string `json:"type"` // Configured type (informational)
+	Error           string `json:"error,omitempty"`
+	Head            uint64 `json:"head,omitempty"`
+	OldestBlock     uint64 `json:"oldest_block"`
+	DepthBlocks     uint64 `json:"depth_blocks"`
+	Archive         bool   `json:"archive"`
+	BoundaryBlock   uint64 `json:"boundary_block,omitempty"`
+	BoundaryPattern string `json:"boundary_pattern,omitempty"` // rpc.StateUnavailable match
+	BoundaryError   string `json:"boundary_error,omitempty"`
+	Calls           int    `json:"calls"`
+	ElapsedMS       int64  `json:"elapsed_ms"`
+}
+
+// buildReport converts probe results to the JSON report. types holds each
+// provider's configured type, index-aligned with results.
+func buildReport(address string, types []string, results []format.ArchiveResult) ArchiveReport {
+	report := ArchiveReport{Timestamp: time.Now(), Address: address, Providers: make([]ArchiveEntry, len(results))}
+	for i, r := range results {
+		e := ArchiveEntry{Name: r.Provider, Type: types[i]}
+		if p := r.Probe; p != nil {
+			e.Head, e.Calls, e.ElapsedMS = p.Head, p.Calls, p.Elapsed.Milliseconds()
+		}
+		if r.Error != nil {
+			e.Error = r.Error.Error()
+			report.Providers[i] = e
+			continue
+		}
+		p := r.Probe
+		e.OldestBlock, e.DepthBlocks, e.Archive = p.Oldest, p.Depth(), p.Archive
+		if !p.Archive {
+			e.BoundaryBlock, e.BoundaryPattern, e.BoundaryError = p.Boundary, p.Pattern, p.Message
+		}
+		report.Providers[i] = e
+	}
+	return report
+}
+
+// =============================================================================
+// SECTION 2: Main Logic
+// =============================================================================
+
+// probe finds one provider's head and then its oldest servable state.
+func probe(ctx context.Context, c *rpc.Client, address string) (*rpc.ArchiveProbe, error) {
+	head, _, err := c.BlockNumber(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("head: %w", err)
+	}
+	return c.ProbeArchive(ctx, head, address)
+}
+
+// runArchive probes every provider concurrently and renders the results.
+func runArchive(cfg *config.Config, address string, jsonOut bool) error {
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Defaults.Timeout*probeBudget)
+	defer cancel()
+
+	if !jsonOut {
+		fmt.Printf("\nProbing historical state on %d providers...\n", len(cfg.Providers))
+	}
+
+	results := make([]format.ArchiveResult, len(cfg.Providers))
+	types := make([]string, len(cfg.Providers))
+	var mu sync.Mutex
+	g, gctx := errgroup.WithContext(ctx)
+	for i, p := range cfg.Providers {
+		i, p := i, p
+		types[i] = p.Type
+		g.Go(func() error {
+			client := rpc.NewClient(p.Name, p.URL, p.Timeout, p.ClientOptions()...)
+			pr, err := probe(gctx, client, address)
+			mu.Lock()
+			results[i] = format.ArchiveResult{Provider: p.Name, Probe: pr, Error: err}
+			mu.Unlock()
+			return nil
+		})
+	}
+	g.Wait()
+
+	if jsonOut {
+		filepath, err := reportjson.Write(buildReport(address, types, results), "archive")
+		if err != nil {
+			return fmt.Errorf("failed to write JSON report: %w", err)
+		}
+		fmt.Fprintf(os.Stderr, "JSON report written to: %s\n", filepath)
+		return nil
+	}
+
+	format.FormatArchive(os.Stdout, results)
+	return nil
+}
+
+// =============================================================================
+// SECTION 3: Entry Point
+// =============================================================================
+
+func main() {
+	config.LoadEnv()
+
+	var (
+		flags   = cli.Register(flag.CommandLine, "")
+		address = flag.String("address", defaultAddress, "Account whose balance and storage slot 0 are requested")
+		jsonOut = flag.Bool("json", false, "Output JSON capability report to reports directory")
+	)
+	flag.Parse()
+
+	addr := strings.ToLower(strings.TrimSpace(*address))
+	if digits := strings.TrimPrefix(addr, "0x"); len(digits) != 40 || digits == addr || strings.Trim(digits, "0123456789abcdef") != "" {
+		fmt.Fprintf(os.Stderr, "Error: --address %q is not a 20-byte 0x-hex address\n", *address)
+		os.Exit(1)
+	}
+
+	cfg, cassette, err := flags.Setup("archive")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	defer cassette.Close()
+
+	if err := runArchive(cfg, addr, *jsonOut); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dando385/eth-rpc-monitor/internal/format"
+	"github.com/dando385/eth-rpc-monitor/internal/rpc"
+)
+
+func TestBuildReport(t *testing.T) {
+	results := []format.ArchiveResult{
+		{Provider: "archive", Probe: &rpc.ArchiveProbe{Head: 1000, Oldest: 0, Archive: true, Calls: 20}},
+		{Provider: "full", Probe: &rpc.ArchiveProbe{
+			Head: 1000, Oldest: 873, Boundary: 872, Calls: 28,
+			Pattern: "missing trie node", Message: "RPC error -32000: missing trie node 8d1e (path )",
+		}},
+		{Provider: "flaky", Probe: &rpc.ArchiveProbe{Head: 1000, Calls: 3}, Error: errors.New("block 984: timeout")},
+	}
+	report := buildReport("0xabc", []string{"enterprise", "public", "public"}, results)
+
+	a := report.Providers[0]
+	if !a.Archive || a.OldestBlock != 0 || a.DepthBlocks != 1000 || a.BoundaryPattern != "" || a.Type != "enterprise" {
+		t.Fatalf("archive = %+v", a)
+	}
+	f := report.Providers[1]
+	if f.Archive || f.OldestBlock != 873 || f.DepthBlocks != 127 || f.BoundaryBlock != 872 || f.BoundaryPattern != "missing trie node" {
+		t.Fatalf("full = %+v", f)
+	}
+	if e := report.Providers[2]; e.Error != "block 984: timeout" || e.Head != 1000 || e.Calls != 3 || e.Archive {
+		t.Fatalf("flaky = %+v", e)
+	}
+}
@@ -15,6 +15,9 @@
 //   block 0x121eac0                 ← Specific block by hex
 //   block latest --provider alchemy ← Latest block from specific provider
 //   block latest --json             ← Export block data as JSON report
+//   block latest --full             ← Also list every transaction (hydrated)
+//   block latest --receipts         ← Summarize receipts, check they are complete
+//   block latest --verify           ← Check every provider's copy against its header
 //
 // EXECUTION FLOW
 // ==============
@@ -23,7 +26,7 @@
 //      │
 //      ├─ config.LoadEnv()          ← Load .env file (optional)
 //      ├─ flag.Parse()              ← Parse command-line flags
-//      ├─ config.Load(*cfgPath)     ← Read providers.yaml
+//      ├─ flags.Setup()             ← Load config; --transport, --record/--replay, chain check (internal/cli)
 //      └─ runBlock(cfg, ...)        ← Execute the block inspection
 //           │
 //           ├─ Provider selection:
@@ -34,12 +37,21 @@
 //           │                           ├─ Find who has the latest block
 //           │                           └─ Pick the fastest among those
 //           │
-//           ├─ Warm-up call (BlockNumber) ← Prime the HTTP connection
+//           ├─ client.WarmUp()            ← Prime the HTTP connection (not cold)
 //           ├─ Fetch block (GetBlock)     ← The actual data fetch
+//           │   (GetFullBlock with --full: transaction objects, not hashes)
+//           ├─ Fetch receipts (--receipts) ← GetBlockReceipts + VerifyReceipts
+//           ├─ Verify (--verify)           ← verifyBlock(): the same block, hydrated,
+//           │                                 and its receipts from every provider;
+//           │                                 header hash, transactionsRoot,
+//           │                                 withdrawalsRoot, receiptsRoot, logsBloom
 //           │
 //           └─ Output:
 //               ├─ --json flag? → convertBlockToJSON() → reportjson.Write()
 //               └─ Terminal?    → format.FormatBlock()
+//                                   (+ format.FormatTransactions with --full)
+//                                   (+ format.FormatReceipts with --receipts)
+//                                   (+ format.FormatVerification with --verify)
 //
 // ARCHITECTURE: THE CMD PATTERN
 // ==============================
@@ -79,6 +91,7 @@ import (
 
 	"golang.org/x/sync/errgroup"
 
+	"github.com/dando385/eth-rpc-monitor/internal/cli"
 	"github.com/dando385/eth-rpc-monitor/internal/config"
 	"github.com/dando385/eth-rpc-monitor/internal/format"
 	"github.com/dando385/eth-rpc-monitor/internal/reportjson"
@@ -130,6 +143,149 @@ type BlockJSON struct {
 	GasLimit      uint64   `json:"gasLimit"`                // Gas limit as decimal
 	BaseFeePerGas *float64 `json:"baseFeePerGas,omitempty"` // Base fee in gwei; nil = omitted
 	Transactions  []string `json:"transactions"`            // Transaction hashes
+
+	// Header verification: the hash recomputed from the returned header
+	// fields (rpc.VerifyBlockHash). HashProblem is omitted when it matched.
+	HashVerified bool   `json:"hashVerified"`
+	HeaderLayout string `json:"headerLayout,omitempty"` // Fork whose header layout matched, e.g. "Cancun"
+	HashProblem  string `json:"hashProblem,omitempty"`
+
+	// Fields later forks added, each omitted on blocks from before its fork:
+	// withdrawals from Shanghai, blob gas and the beacon root from Cancun,
+	// the requests hash from Prague.
+	Withdrawals           *WithdrawalsJSON `json:"withdrawals,omitempty"`
+	BlobGas               *BlobGasJSON     `json:"blobGas,omitempty"`
+	ParentBeaconBlockRoot string           `json:"parentBeaconBlockRoot,omitempty"`
+	RequestsHash          string           `json:"requestsHash,omitempty"`
+
+	// TransactionObjects is only present with --full: one entry per
+	// transaction, in block order.
+	TransactionObjects []TransactionJSON `json:"transactionObjects,omitempty"`
+
+	// Receipts is only present with --receipts.
+	Receipts *ReceiptsJSON `json:"receipts,omitempty"`
+
+	// Verification is only present with --verify: one entry per provider.
+	Verification []VerificationJSON `json:"verification,omitempty"`
+}
+
+// WithdrawalsJSON summarizes a block's validator withdrawals.
+type WithdrawalsJSON struct {
+	Root      string `json:"root"`      // withdrawalsRoot
+	Count     int    `json:"count"`     // Withdrawals in the body
+	TotalGwei uint64 `json:"totalGwei"` // Their amounts summed
+}
+
+// BlobGasJSON is a block's blob gas accounting. The base fee is computed
+// from excessBlobGas under Fork's update fraction (rpc/blob.go), in gwei
+// like the block's own base fee. ScheduleAssumed is true when chain.id is
+// not set (or has no built-in fork timetable) and Fork was inferred from
+// the header layout; Osaka and later forks then read as Prague.
+type BlobGasJSON struct {
+	Used              uint64   `json:"used"`
+	Excess            uint64   `json:"excess"`
+	Blobs             int      `json:"blobs"`
+	BlobBaseFeePerGas *float64 `json:"blobBaseFeePerGas"`
+	Fork              string   `json:"fork"` // Whose target, max and update fraction were used
+	ScheduleAssumed   bool     `json:"scheduleAssumed"`
+	Target            int      `json:"target"`
+	Max               int      `json:"max"`
+}
+
+// TransactionJSON is the report form of one hydrated transaction.
+//
+// The same conventions as BlockJSON apply: counters are decimal, and every
+// fee field is in gwei and omitted when the transaction's type does not
+// carry it (a legacy transaction has no maxFeePerGas, a 1559 one may have no
+// gasPrice). Value is the exception — a decimal STRING of wei, because
+// transfer amounts routinely exceed what a float64 (or a JavaScript number
+// in whatever reads the report) can hold exactly.
+type TransactionJSON struct {
+	Hash                 string              `json:"hash"`
+	Index                uint64              `json:"transactionIndex"`
+	Type                 uint8               `json:"type"`     // EIP-2718 type byte (0-4)
+	TypeName             string              `json:"typeName"` // "legacy", "eip1559", ...
+	From                 string              `json:"from"`
+	To                   string              `json:"to,omitempty"` // Omitted for contract creation
+	Nonce                uint64              `json:"nonce"`
+	Gas                  uint64              `json:"gas"`   // Gas limit
+	Value                string              `json:"value"` // Wei, decimal string
+	EffectiveGasPrice    *float64            `json:"effectiveGasPrice,omitempty"`
+	GasPrice             *float64            `json:"gasPrice,omitempty"`
+	MaxFeePerGas         *float64            `json:"maxFeePerGas,omitempty"`
+	MaxPriorityFeePerGas *float64            `json:"maxPriorityFeePerGas,omitempty"`
+	MaxFeePerBlobGas     *float64            `json:"maxFeePerBlobGas,omitempty"`
+	BlobVersionedHashes  []string            `json:"blobVersionedHashes,omitempty"`
+	AccessList           []rpc.AccessTuple   `json:"accessList,omitempty"`
+	AuthorizationList    []rpc.Authorization `json:"authorizationList,omitempty"`
+	Input                string              `json:"input"`
+}
+
+// ReceiptsJSON is the report form of a block's receipts: a summary, the
+// completeness verdict from rpc.VerifyReceipts, and one compact entry per
+// receipt. Fee totals are decimal wei strings, like TransactionJSON.Value.
+type ReceiptsJSON struct {
+	Method         string        `json:"method"`                   // eth_getBlockReceipts or eth_getTransactionReceipt
+	FallbackReason string        `json:"fallbackReason,omitempty"` // Why eth_getBlockReceipts was not used
+	LatencyMs      int64         `json:"latencyMs"`
+	Count          int           `json:"count"`
+	Succeeded      int           `json:"succeeded"`
+	Failed         int           `json:"failed"`
+	GasUsed        uint64        `json:"gasUsed"`
+	Logs           int           `json:"logs"`
+	FeesWei        string        `json:"feesWei"`
+	Complete       bool          `json:"complete"`
+	Problems       []string      `json:"problems,omitempty"`
+	Items          []ReceiptJSON `json:"items"`
+}
+
+// ReceiptJSON is one receipt inside ReceiptsJSON.
+type ReceiptJSON struct {
+	TransactionHash   string   `json:"transactionHash"`
+	TransactionIndex  uint64   `json:"transactionIndex"`
+	Status            string   `json:"status"` // "success", "failed", or "unknown" (pre-Byzantium)
+	GasUsed           uint64   `json:"gasUsed"`
+	EffectiveGasPrice *float64 `json:"effectiveGasPrice,omitempty"` // Gwei
+	FeeWei            string   `json:"feeWei,omitempty"`
+	Logs              int      `json:"logs"`
+	ContractAddress   string   `json:"contractAddress,omitempty"`
+}
+
+// VerificationJSON is one provider's result under --verify. Roots that
+// could not be rebuilt carry a problem and no computed value; withdrawalsRoot
+// is omitted for blocks before Shanghai.
+type VerificationJSON struct {
+	Provider         string         `json:"provider"`
+	LatencyMs        int64          `json:"latencyMs"`
+	Error            string         `json:"error,omitempty"` // Fetch failed; nothing below is set
+	Hash             string         `json:"hash,omitempty"`
+	HashVerified     bool           `json:"hashVerified"`
+	HeaderLayout     string         `json:"headerLayout,omitempty"`
+	HashProblem      string         `json:"hashProblem,omitempty"`
+	TransactionsRoot *RootCheckJSON `json:"transactionsRoot,omitempty"`
+	WithdrawalsRoot  *RootCheckJSON `json:"withdrawalsRoot,omitempty"`
+	ReceiptsRoot     *RootCheckJSON `json:"receiptsRoot,omitempty"`
+	LogsBloom        *BloomJSON     `json:"logsBloom,omitempty"`
+	Verified         bool           `json:"verified"` // Every check above passed
+}
+
+// RootCheckJSON is the report form of rpc.RootCheck.
+type RootCheckJSON struct {
+	Claimed  string `json:"claimed"`
+	Computed string `json:"computed,omitempty"`
+	Count    int    `json:"count"`
+	Verified bool   `json:"verified"`
+	Problem  string `json:"problem,omitempty"`
+}
+
+// BloomJSON is the report form of rpc.BloomCheck: whether the header bloom
+// matched, and the indexes of receipts whose own bloom did not.
+type BloomJSON struct {
+	Receipts    int    `json:"receipts"`
+	BadReceipts []int  `json:"badReceipts,omitempty"`
+	BlockOK     bool   `json:"blockVerified"`
+	Verified    bool   `json:"verified"`
+	Problem     string `json:"problem,omitempty"`
 }
 
 // =============================================================================
@@ -176,7 +332,9 @@ type BlockJSON struct {
 // operator takes the address of the EXISTING variable. No copy is made.
 // However, because escape analysis moves it to the heap, the variable
 // effectively "escapes" the stack frame.
-func convertBlockToJSON(block *rpc.Block) BlockJSON {
+//
+// chainID picks the blob schedule (rpc.BlobScheduleAt); 0 = unknown.
+func convertBlockToJSON(block *rpc.Block, chainID uint64) BlockJSON {
 	// Parse hex fields to native types.
 	number, _ := rpc.ParseHexUint64(block.Number)
 	timestampUnix, _ := rpc.ParseHexUint64(block.Timestamp)
@@ -211,6 +369,27 @@ func convertBlockToJSON(block *rpc.Block) BlockJSON {
 		}
 	}
 
+	check := rpc.VerifyBlockHash(block)
+
+	p := block.ParsedOn(chainID)
+	var withdrawals *WithdrawalsJSON
+	if p.WithdrawalsRoot != "" {
+		withdrawals = &WithdrawalsJSON{Root: p.WithdrawalsRoot, Count: p.Withdrawals, TotalGwei: p.WithdrawalsGwei}
+	}
+	var blobGas *BlobGasJSON
+	if g := p.BlobGas; g != nil {
+		blobGas = &BlobGasJSON{
+			Used:              g.Used,
+			Excess:            g.Excess,
+			Blobs:             g.Blobs,
+			BlobBaseFeePerGas: weiToGwei(g.BaseFee),
+			Fork:              g.Schedule.Fork,
+			ScheduleAssumed:   g.Assumed,
+			Target:            g.Schedule.Target,
+			Max:               g.Schedule.Max,
+		}
+	}
+
 	return BlockJSON{
 		Number:        number,
 		Hash:          block.Hash,
@@ -220,9 +399,158 @@ func convertBlockToJSON(block *rpc.Block) BlockJSON {
 		GasLimit:      gasLimit,
 		BaseFeePerGas: baseFeePerGas,
 		Transactions:  block.Transactions,
+		HashVerified:  check.OK(),
+		HeaderLayout:  check.Layout,
+		HashProblem:   check.Problem(),
+
+		Withdrawals:           withdrawals,
+		BlobGas:               blobGas,
+		ParentBeaconBlockRoot: p.ParentBeaconBlockRoot,
+		RequestsHash:          p.RequestsHash,
+
+		TransactionObjects: convertTransactionsToJSON(block),
+	}
+}
+
+// convertTransactionsToJSON converts a hydrated block's transactions, or
+// returns nil for a hashes-only block so the field is omitted.
+func convertTransactionsToJSON(block *rpc.Block) []TransactionJSON {
+	if block.FullTransactions == nil {
+		return nil
+	}
+	baseFee := block.Parsed().BaseFeePerGas
+
+	out := make([]TransactionJSON, len(block.FullTransactions))
+	for i := range block.FullTransactions {
+		tx := &block.FullTransactions[i]
+		p := tx.Parsed()
+		out[i] = TransactionJSON{
+			Hash:                 p.Hash,
+			Index:                p.Index,
+			Type:                 uint8(p.Type),
+			TypeName:             p.Type.String(),
+			From:                 p.From,
+			To:                   p.To,
+			Nonce:                p.Nonce,
+			Gas:                  p.Gas,
+			Value:                p.Value.String(),
+			EffectiveGasPrice:    weiToGwei(tx.EffectiveGasPrice(baseFee)),
+			GasPrice:             weiToGwei(p.GasPrice),
+			MaxFeePerGas:         weiToGwei(p.MaxFeePerGas),
+			MaxPriorityFeePerGas: weiToGwei(p.MaxPriorityFeePerGas),
+			MaxFeePerBlobGas:     weiToGwei(p.MaxFeePerBlobGas),
+			BlobVersionedHashes:  tx.BlobVersionedHashes,
+			AccessList:           tx.AccessList,
+			AuthorizationList:    tx.AuthorizationList,
+			Input:                tx.Input,
+		}
+	}
+	return out
+}
+
+// convertReceiptsToJSON builds the receipts section of the report.
+func convertReceiptsToJSON(br *rpc.BlockReceipts, latency time.Duration, problems []string) *ReceiptsJSON {
+	out := &ReceiptsJSON{
+		Method:    br.Method,
+		LatencyMs: latency.Milliseconds(),
+		Count:     len(br.Receipts),
+		Complete:  len(problems) == 0,
+		Problems:  problems,
+		Items:     make([]ReceiptJSON, len(br.Receipts)),
+	}
+	if br.FallbackErr != nil {
+		out.FallbackReason = format.ErrorLabel(br.FallbackErr)
+	}
+
+	fees := new(big.Int)
+	for i := range br.Receipts {
+		r := &br.Receipts[i]
+		p := r.Parsed()
+		item := ReceiptJSON{
+			TransactionHash:   p.TransactionHash,
+			TransactionIndex:  p.Index,
+			Status:            "unknown",
+			GasUsed:           p.GasUsed,
+			EffectiveGasPrice: weiToGwei(p.EffectiveGasPrice),
+			Logs:              p.LogCount,
+			ContractAddress:   r.ContractAddress,
+		}
+		switch {
+		case r.Succeeded():
+			item.Status = "success"
+			out.Succeeded++
+		case r.Failed():
+			item.Status = "failed"
+			out.Failed++
+		}
+		if fee := r.Fee(); fee != nil {
+			item.FeeWei = fee.String()
+			fees.Add(fees, fee)
+		}
+		out.GasUsed += p.GasUsed
+		out.Logs += p.LogCount
+		out.Items[i] = item
+	}
+	out.FeesWei = fees.String()
+	return out
+}
+
+// convertVerificationToJSON builds the --verify section of the report.
+func convertVerificationToJSON(results []format.VerifyResult) []VerificationJSON {
+	out := make([]VerificationJSON, len(results))
+	for i, r := range results {
+		v := VerificationJSON{Provider: r.Provider, LatencyMs: r.Latency.Milliseconds()}
+		if r.Error != nil {
+			v.Error = format.ErrorLabel(r.Error)
+			out[i] = v
+			continue
+		}
+		v.Hash = r.Hash
+		v.HashVerified = r.Header.OK()
+		v.HeaderLayout = r.Header.Layout
+		v.HashProblem = r.Header.Problem()
+		v.TransactionsRoot = rootCheckToJSON(&r.Transactions)
+		v.WithdrawalsRoot = rootCheckToJSON(r.Withdrawals)
+		v.ReceiptsRoot = rootCheckToJSON(r.Receipts)
+		if b := r.Bloom; b != nil {
+			v.LogsBloom = &BloomJSON{
+				Receipts:    b.Receipts,
+				BadReceipts: b.BadReceipts,
+				BlockOK:     b.BlockOK,
+				Verified:    b.OK(),
+				Problem:     b.Problem(),
+			}
+		}
+		v.Verified = r.OK()
+		out[i] = v
+	}
+	return out
+}
+
+// rootCheckToJSON converts one root check; nil stays nil.
+func rootCheckToJSON(c *rpc.RootCheck) *RootCheckJSON {
+	if c == nil {
+		return nil
+	}
+	return &RootCheckJSON{
+		Claimed:  c.Claimed,
+		Computed: c.Computed,
+		Count:    c.Count,
+		Verified: c.OK(),
+		Problem:  c.Problem(),
 	}
 }
 
+// weiToGwei is the base-fee conversion above as a helper: nil stays nil
+// (so `omitempty` drops the field), anything else becomes *float64 gwei.
+func weiToGwei(wei *big.Int) *float64 {
+	if wei == nil {
+		return nil
+	}
+	gwei, _ := new(big.Float).Quo(new(big.Float).SetInt(wei), big.NewFloat(1e9)).Float64()
+	return &gwei
+}
+
 // =============================================================================
 // SECTION 3: Provider Selection — Finding the Fastest Provider
 // =============================================================================
@@ -336,7 +664,7 @@ func selectFastestProvider(ctx context.Context, cfg *config.Config) (*rpc.Client
 	for i, p := range cfg.Providers {
 		i, p := i, p // Shadow loop variables for goroutine safety (see comment above)
 		g.Go(func() error {
-			client := rpc.NewClient(p.Name, p.URL, p.Timeout)
+			client := rpc.NewClient(p.Name, p.URL, p.Timeout, p.ClientOptions()...)
 			blockNum, latency, err := client.BlockNumber(gctx)
 
 			r := providerResult{hasError: err != nil}
@@ -399,6 +727,80 @@ func selectFastestProvider(ctx context.Context, cfg *config.Config) (*rpc.Client
 	return fastest, nil
 }
 
+// verifyBlock fetches block number (hex) with full transaction objects, and
+// its receipts, from every provider — or only the named one — and checks
+// each copy against its own header.
+//
+// Unlike selectFastestProvider this fetches real data, so every provider
+// gets its own hydrated copy: the point is to catch the one whose body does
+// not match, not to agree on a number. Results keep config order.
+func verifyBlock(ctx context.Context, cfg *config.Config, providerName, number string) []format.VerifyResult {
+	var providers []config.Provider
+	for _, p := range cfg.Providers {
+		if providerName == "" || p.Name == providerName {
+			providers = append(providers, p)
+		}
+	}
+
+	results := make([]format.VerifyResult, len(providers))
+	var mu sync.Mutex
+	g, gctx := errgroup.WithContext(ctx)
+	for i, p := range providers {
+		i, p := i, p
+		g.Go(func() error {
+			client := rpc.NewClient(p.Name, p.URL, p.Timeout, p.ClientOptions()...)
+			block, latency, err := client.GetFullBlock(gctx, number)
+			r := checkBlock(block, err)
+			r.Provider = p.Name
+			r.Latency = latency
+			if r.Error == nil {
+				receipts, _, err := client.GetBlockReceipts(gctx, number)
+				checkReceipts(&r, block, receipts, err)
+			}
+
+			mu.Lock()
+			results[i] = r
+			mu.Unlock()
+			return nil
+		})
+	}
+	g.Wait()
+	return results
+}
+
+// checkBlock runs every self-consistency check on one fetched block.
+func checkBlock(block *rpc.Block, err error) format.VerifyResult {
+	if err == nil && block == nil {
+		err = fmt.Errorf("block not found")
+	}
+	if err != nil {
+		return format.VerifyResult{Error: err}
+	}
+	r := format.VerifyResult{
+		Hash:         block.Hash,
+		Header:       rpc.VerifyBlockHash(block),
+		Transactions: rpc.VerifyTransactionsRoot(block),
+	}
+	if w, ok := rpc.VerifyWithdrawalsRoot(block); ok {
+		r.Withdrawals = &w
+	}
+	return r
+}
+
+// checkReceipts adds the receiptsRoot and logsBloom checks to r. A failed
+// receipt fetch is reported as an unverifiable receiptsRoot.
+func checkReceipts(r *format.VerifyResult, block *rpc.Block, receipts *rpc.BlockReceipts, err error) {
+	if err != nil {
+		r.Receipts = &rpc.RootCheck{Field: "receiptsRoot", Claimed: block.ReceiptsRoot,
+			Err: fmt.Errorf("fetching receipts: %w", err)}
+		return
+	}
+	root := rpc.VerifyReceiptsRoot(block, receipts.Receipts)
+	bloom := rpc.VerifyLogsBloom(block, receipts.Receipts)
+	r.Receipts = &root
+	r.Bloom = &bloom
+}
+
 // =============================================================================
 // SECTION 4: Block Argument Normalization
 // =============================================================================
@@ -407,7 +809,8 @@ func selectFastestProvider(ctx context.Context, cfg *config.Config) (*rpc.Client
 // expected by the Ethereum JSON-RPC API.
 //
 // The Ethereum RPC accepts block identifiers in two forms:
-//  1. Special tags: "latest", "pending", "earliest"
+//  1. Special tags: "latest", "pending", "earliest", and since the Merge
+//     "safe" and "finalized" (see rpc.Client.BlockNumberByTag)
 //  2. Hex-encoded numbers: "0x10d4f"
 //
 // But users naturally type decimal numbers ("19000000"), so we need to convert.
@@ -418,6 +821,8 @@ func selectFastestProvider(ctx context.Context, cfg *config.Config) (*rpc.Client
 //	"latest"     → "latest"     (pass-through)
 //	"pending"    → "pending"    (pass-through)
 //	"earliest"   → "earliest"   (pass-through)
+//	"safe"       → "safe"       (pass-through)
+//	"finalized"  → "finalized"  (pass-through)
 //	"0x121eac0"  → "0x121eac0"  (already hex, pass-through)
 //	"19000000"   → "0x121eac0"  (decimal → hex conversion)
 //	"garbage"    → "garbage"    (invalid — let the RPC server return an error)
@@ -427,7 +832,8 @@ func normalizeBlockArg(arg string) string {
 	if arg == "" {
 		return "latest"
 	}
-	if arg == "latest" || arg == "pending" || arg == "earliest" {
+	switch arg {
+	case "latest", "pending", "earliest", "safe", "finalized":
 		return arg
 	}
 
@@ -461,7 +867,9 @@ func normalizeBlockArg(arg string) string {
 // This is the core orchestrator for the block command. It handles:
 //  1. Provider selection (manual or automatic)
 //  2. Connection warm-up
-//  3. Block fetching
+//  3. Block fetching (hashes only, or full transaction objects with --full)
+//     and, with --receipts, receipt fetching and verification; with
+//     --verify, the same block from every provider checked against its header
 //  4. Output formatting (terminal or JSON)
 //
 // PARAMETER: cfg *config.Config
@@ -489,11 +897,18 @@ func normalizeBlockArg(arg string) string {
 // error chain that preserves the original error, so callers can use
 // errors.Is() or errors.Unwrap() to inspect it. This is different from %v,
 // which would convert the error to a string, losing the original.
-func runBlock(cfg *config.Config, blockArg, providerName string, jsonOut bool) error {
+func runBlock(cfg *config.Config, blockArg, providerName string, jsonOut, full, withReceipts, verify bool) error {
 	// Create a timeout context. All RPC calls within this function will
 	// respect this deadline — if the timeout expires, in-flight HTTP requests
 	// are cancelled automatically.
-	ctx, cancel := context.WithTimeout(context.Background(), cfg.Defaults.Timeout*2)
+	//
+	// --receipts gets twice the budget: on providers without
+	// eth_getBlockReceipts it costs one call per transaction.
+	timeout := cfg.Defaults.Timeout * 2
+	if withReceipts || verify {
+		timeout *= 2
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
 	// --- Provider Selection ---
@@ -506,7 +921,7 @@ func runBlock(cfg *config.Config, blockArg, providerName string, jsonOut bool) e
 		// Manual selection: find the provider by name in the config.
 		for _, p := range cfg.Providers {
 			if p.Name == providerName {
-				client = rpc.NewClient(p.Name, p.URL, p.Timeout)
+				client = rpc.NewClient(p.Name, p.URL, p.Timeout, p.ClientOptions()...)
 				break
 			}
 		}
@@ -537,34 +952,88 @@ func runBlock(cfg *config.Config, blockArg, providerName string, jsonOut bool) e
 	//
 	// The subsequent GetBlock call reuses this pooled connection, so its
 	// measured latency reflects only the RPC processing time, not the
-	// one-time connection setup overhead.
-	client.BlockNumber(ctx)
+	// one-time connection setup overhead. With --transport cold WarmUp does
+	// nothing and GetBlock pays for its own connection, by design.
+	client.WarmUp(ctx)
 
 	// --- Fetch the Block ---
 	//
 	// client.GetBlock returns (*rpc.Block, time.Duration, error).
 	// block is a *rpc.Block — a pointer to the deserialized block data.
-	block, latency, err := client.GetBlock(ctx, blockArg)
+	//
+	// With --full we ask for hydrated transactions instead of hashes.
+	fetch := client.GetBlock
+	if full {
+		fetch = client.GetFullBlock
+	}
+	block, latency, err := fetch(ctx, blockArg)
 	if err != nil {
 		return fmt.Errorf("failed to fetch block: %w", err)
 	}
 
+	// --- Fetch Receipts (--receipts) ---
+	//
+	// Ask by the block's NUMBER, not the original argument: "latest" may
+	// have advanced since GetBlock, and the receipts must belong to the block
+	// we are about to verify them against.
+	var receipts *rpc.BlockReceipts
+	var receiptsLatency time.Duration
+	var problems []string
+	if withReceipts {
+		receipts, receiptsLatency, err = client.GetBlockReceipts(ctx, block.Number)
+		if err != nil {
+			return fmt.Errorf("failed to fetch receipts: %w", err)
+		}
+		problems = rpc.VerifyReceipts(block, receipts.Receipts)
+	}
+
+	// --- Verify (--verify) ---
+	//
+	// Again by number, so every provider is asked for the block shown.
+	var verification []format.VerifyResult
+	if verify {
+		verification = verifyBlock(ctx, cfg, providerName, block.Number)
+	}
+
 	// --- Output ---
 	if jsonOut {
 		// JSON export: convert to JSON-friendly format and write to file.
-		blockJSON := convertBlockToJSON(block)
+		blockJSON := convertBlockToJSON(block, cfg.Chain.ID)
+		if receipts != nil {
+			blockJSON.Receipts = convertReceiptsToJSON(receipts, receiptsLatency, problems)
+		}
+		if verification != nil {
+			blockJSON.Verification = convertVerificationToJSON(verification)
+		}
 		filepath, err := reportjson.Write(blockJSON, "block")
 		if err != nil {
 			return fmt.Errorf("failed to write JSON report: %w", err)
 		}
 		fmt.Fprintf(os.Stderr, "JSON report written to: %s\n", filepath)
+		if !blockJSON.HashVerified {
+			fmt.Fprintf(os.Stderr, "Warning: %s: %s\n", client.Name(), blockJSON.HashProblem)
+		}
+		for _, r := range verification {
+			for _, p := range r.Problems() {
+				fmt.Fprintf(os.Stderr, "Warning: %s: %s\n", r.Provider, p)
+			}
+		}
 		return nil
 	}
 
 	// Terminal display: render formatted, color-coded block information.
 	// block is passed as *rpc.Block — FormatBlock receives the pointer
 	// and reads through it without copying the Block struct.
-	format.FormatBlock(os.Stdout, block, client.Name(), latency)
+	format.FormatBlock(os.Stdout, block, cfg.Chain.ID, client.Name(), latency)
+	if full {
+		format.FormatTransactions(os.Stdout, block.FullTransactions, block.Parsed().BaseFeePerGas)
+	}
+	if receipts != nil {
+		format.FormatReceipts(os.Stdout, receipts, receiptsLatency, problems)
+	}
+	if verification != nil {
+		format.FormatVerification(os.Stdout, block.Parsed().Number, verification)
+	}
 	return nil
 }
 
@@ -577,14 +1046,14 @@ func runBlock(cfg *config.Config, blockArg, providerName string, jsonOut bool) e
 //   1. Loads environment variables (for API key expansion)
 //   2. Parses command-line flags
 //   3. Normalizes the block argument
-//   4. Loads configuration
+//   4. Loads configuration and applies the shared flags (internal/cli)
 //   5. Delegates to runBlock()
 //
 // FLAG PARSING AND POINTERS
 // =========================
 // Go's flag package returns POINTERS to the flag values:
 //
-//   cfgPath = flag.String("config", "config/providers.yaml", "...")
+//   provider = flag.String("provider", "", "...")
 //
 // flag.String returns *string (a pointer to string), NOT a string value.
 // Before flag.Parse() is called, the pointer points to the default value.
@@ -592,22 +1061,26 @@ func runBlock(cfg *config.Config, blockArg, providerName string, jsonOut bool) e
 // the default.
 //
 // To get the actual string value, we DEREFERENCE with *:
-//   *cfgPath  → "config/providers.yaml" (or whatever the user provided)
+//   *provider  → "" (or whatever the user provided, e.g. "alchemy")
 //
 //   In memory:
-//   ┌──────────────┐
-//   │ cfgPath: ────┼──▶ "config/providers.yaml"
-//   └──────────────┘     (this string may change after flag.Parse())
+//   ┌───────────────┐
+//   │ provider: ────┼──▶ ""
+//   └───────────────┘     (this string may change after flag.Parse())
 //
-//   After: config.Load(*cfgPath)
+//   After: runBlock(cfg, block, *provider, ...)
 //   The * dereferences the pointer, retrieving the string value.
-//   This is passed BY VALUE to config.Load() — Load receives a copy of the
+//   This is passed BY VALUE to runBlock() — it receives a copy of the
 //   string (which in Go is just a pointer+length header, very cheap to copy).
 //
+// --config, --transport, --record, --replay and --replay-latency are the
+// same in every command, so cli.Register defines them and flags.Setup
+// applies them; see internal/cli.
+//
 // ERROR HANDLING PATTERN
 // =====================
 // The two-step pattern:
-//   1. cfg, err := config.Load(...)
+//   1. cfg, cassette, err := flags.Setup(...)
 //   2. if err != nil { print error; os.Exit(1) }
 //
 // is the standard Go approach for handling errors in main(). Since main()
@@ -624,9 +1097,12 @@ func main() {
 
 	// Define command-line flags. Each flag.Type() returns a POINTER.
 	var (
-		cfgPath  = flag.String("config", "config/providers.yaml", "Config file path")
+		flags    = cli.Register(flag.CommandLine, "")
 		provider = flag.String("provider", "", "Use specific provider (empty = auto-select fastest)")
 		jsonOut  = flag.Bool("json", false, "Output JSON report to reports directory")
+		full     = flag.Bool("full", false, "Fetch full transaction objects and list them")
+		receipts = flag.Bool("receipts", false, "Fetch the block's receipts, summarize them and check completeness")
+		verify   = flag.Bool("verify", false, "Fetch the block and its receipts from every provider and check them against the header")
 	)
 
 	// Parse command-line arguments. This populates the values behind each
@@ -641,17 +1117,19 @@ func main() {
 		block = normalizeBlockArg(args[0])
 	}
 
-	// Load provider configuration from YAML.
-	// *cfgPath dereferences the pointer to get the actual string path.
-	cfg, err := config.Load(*cfgPath)
+	// Load provider configuration from YAML and apply the shared flags.
+	// The cassette is nil unless --record or --replay was given; Close is
+	// safe either way.
+	cfg, cassette, err := flags.Setup("block")
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
+	defer cassette.Close()
 
 	// Execute the block inspection.
-	// *provider and *jsonOut dereference the flag pointers to get the actual values.
-	if err := runBlock(cfg, block, *provider, *jsonOut); err != nil {
+	// The flag pointers are dereferenced to get the actual values.
+	if err := runBlock(cfg, block, *provider, *jsonOut, *full, *receipts, *verify); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
@@ -1,6 +1,13 @@
 package main
 
-import "testing"
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/dando385/eth-rpc-monitor/internal/format"
+	"github.com/dando385/eth-rpc-monitor/internal/rpc"
+)
 
 func TestNormalizeBlockArg(t *testing.T) {
 	tests := []struct {
@@ -12,6 +19,8 @@ func TestNormalizeBlockArg(t *testing.T) {
 		{"LATEST", "latest"},
 		{"pending", "pending"},
 		{"earliest", "earliest"},
+		{"Finalized", "finalized"},
+		{"safe", "safe"},
 		{"19000000", "0x121eac0"},
 		{"0xabc", "0xabc"},
 		{"not-a-number", "not-a-number"},
@@ -22,3 +31,189 @@ func TestNormalizeBlockArg(t *testing.T) {
 		}
 	}
 }
+
+func TestConvertBlockToJSON_fullTransactions(t *testing.T) {
+	block := &rpc.Block{
+		Number:        "0x10",
+		BaseFeePerGas: "0x77359400", // 2 gwei
+		Transactions:  []string{"0xa0", "0xa1"},
+		FullTransactions: []rpc.Transaction{
+			{Hash: "0xa0", From: "0xf0", To: "0xt0", Gas: "0x5208",
+				Value: "0x3635c9adc5dea00000", GasPrice: "0x12a05f200"},
+			{Hash: "0xa1", Type: "0x2", TransactionIndex: "0x1", From: "0xf1", Value: "0x0",
+				MaxFeePerGas: "0x6fc23ac00", MaxPriorityFeePerGas: "0x3b9aca00"},
+		},
+	}
+	got := convertBlockToJSON(block, 0).TransactionObjects
+	if len(got) != 2 {
+		t.Fatalf("transactionObjects = %d", len(got))
+	}
+
+	legacy := got[0]
+	if legacy.TypeName != "legacy" || legacy.Gas != 21000 || *legacy.GasPrice != 5 {
+		t.Fatalf("legacy: %+v", legacy)
+	}
+	if legacy.Value != "1000000000000000000000" { // 1000 ETH, exact
+		t.Fatalf("value = %s", legacy.Value)
+	}
+	if legacy.MaxFeePerGas != nil {
+		t.Fatal("legacy tx should omit maxFeePerGas")
+	}
+
+	dynamic := got[1]
+	if dynamic.Type != 2 || dynamic.Index != 1 || dynamic.To != "" {
+		t.Fatalf("1559: %+v", dynamic)
+	}
+	if dynamic.EffectiveGasPrice == nil || *dynamic.EffectiveGasPrice != 3 { // 2 base + 1 tip
+		t.Fatalf("effective price = %v", dynamic.EffectiveGasPrice)
+	}
+
+	// A hashes-only block has no transactionObjects at all.
+	block.FullTransactions = nil
+	if objs := convertBlockToJSON(block, 0).TransactionObjects; objs != nil {
+		t.Fatalf("hashes-only block: %+v", objs)
+	}
+}
+
+func TestConvertBlockToJSON_forkFields(t *testing.T) {
+	block := &rpc.Block{
+		Number:                "0x10",
+		WithdrawalsRoot:       "0xw",
+		Withdrawals:           []rpc.Withdrawal{{Amount: "0x10"}, {Amount: "0x20"}},
+		BlobGasUsed:           "0x40000",
+		ExcessBlobGas:         "0xa00000",
+		ParentBeaconBlockRoot: "0xbeac",
+	}
+	got := convertBlockToJSON(block, 0)
+	if w := got.Withdrawals; w == nil || w.Root != "0xw" || w.Count != 2 || w.TotalGwei != 0x30 {
+		t.Fatalf("withdrawals = %+v", w)
+	}
+	g := got.BlobGas
+	if g == nil || g.Blobs != 2 || g.Fork != "Cancun" || !g.ScheduleAssumed || g.Target != 3 || g.BlobBaseFeePerGas == nil || *g.BlobBaseFeePerGas != 23e-9 {
+		t.Fatalf("blob gas = %+v", g)
+	}
+	block.Timestamp = "0x6b49d200" // Mainnet, after BPO2
+	if g := convertBlockToJSON(block, 1).BlobGas; g.Fork != "BPO2" || g.ScheduleAssumed || g.Target != 14 || g.Max != 21 {
+		t.Fatalf("mainnet blob gas = %+v", g)
+	}
+	if got.ParentBeaconBlockRoot != "0xbeac" || got.RequestsHash != "" {
+		t.Fatalf("roots = %q, %q", got.ParentBeaconBlockRoot, got.RequestsHash)
+	}
+
+	// A pre-Shanghai block omits all of them.
+	if got := convertBlockToJSON(&rpc.Block{Number: "0x1"}, 0); got.Withdrawals != nil || got.BlobGas != nil {
+		t.Fatalf("pre-Shanghai: %+v", got)
+	}
+}
+
+func TestConvertReceiptsToJSON(t *testing.T) {
+	br := &rpc.BlockReceipts{
+		Method:      "eth_getTransactionReceipt",
+		FallbackErr: &rpc.RPCError{Code: -32601, Message: "method not found"},
+		Receipts: []rpc.Receipt{
+			{TransactionHash: "0xaa", Status: "0x1", GasUsed: "0x5208", EffectiveGasPrice: "0x3b9aca00",
+				Logs: []rpc.Log{{}}},
+			{TransactionHash: "0xbb", TransactionIndex: "0x1", Status: "0x0", GasUsed: "0x5208"},
+		},
+	}
+	got := convertReceiptsToJSON(br, 0, []string{"receipts account for 42000 gas, block used 50000"})
+
+	if got.Succeeded != 1 || got.Failed != 1 || got.GasUsed != 42000 || got.Logs != 1 {
+		t.Fatalf("summary: %+v", got)
+	}
+	if got.FeesWei != "21000000000000" || got.Complete || len(got.Problems) != 1 {
+		t.Fatalf("fees/completeness: %+v", got)
+	}
+	if got.FallbackReason == "" {
+		t.Fatal("fallback reason missing")
+	}
+	if got.Items[1].Status != "failed" || got.Items[1].FeeWei != "" || got.Items[1].TransactionIndex != 1 {
+		t.Fatalf("item: %+v", got.Items[1])
+	}
+}
+
+// mainnetBlock1 is Ethereum mainnet block 1 as eth_getBlockByNumber returns
+// it with full transactions (it has none).
+func mainnetBlock1() *rpc.Block {
+	const emptyTrie = "0x56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421"
+	return &rpc.Block{
+		Number: "0x1", Hash: "0x88e96d4537bea4d9c05d12549907b32561d3bf31f45aae734cdc119f13406cb6",
+		ParentHash:       "0xd4e56740f876aef8c010b86a40d5f56745a118d0906a34e69aec8c0db1cb8fa3",
+		Sha3Uncles:       "0x1dcc4de8dec75d7aab85b567b6ccd41ad312451b948a7413f0a142fd40d49347",
+		Miner:            "0x05a56e2d52c817161883f50c441c3228cfe54d9f",
+		StateRoot:        "0xd67e4d450343046425ae4271474353857ab860dbc0a1dde64b41b5cd3a532bf3",
+		TransactionsRoot: emptyTrie, ReceiptsRoot: emptyTrie, LogsBloom: "0x" + strings.Repeat("00", 256),
+		Difficulty: "0x3ff800000", GasLimit: "0x1388", GasUsed: "0x0", Timestamp: "0x55ba4224",
+		ExtraData:    "0x476574682f76312e302e302f6c696e75782f676f312e342e32",
+		MixHash:      "0x969b900de27b6ac6a67742365dd65f55a0526c41fd18e1b16f1a1215c2e66f59",
+		Nonce:        "0x539bd4979fef1ec4",
+		Transactions: []string{}, FullTransactions: []rpc.Transaction{},
+	}
+}
+
+func TestCheckBlock(t *testing.T) {
+	r := checkBlock(mainnetBlock1(), nil)
+	if !r.OK() || r.Withdrawals != nil || r.Header.Layout != "pre-London" {
+		t.Fatalf("block 1: %+v", r)
+	}
+
+	// A transaction the header does not commit to.
+	b := mainnetBlock1()
+	b.Transactions = []string{"0xa0"}
+	b.FullTransactions = []rpc.Transaction{{Hash: "0xa0", Nonce: "0x0", GasPrice: "0x1", Gas: "0x5208",
+		Value: "0x0", Input: "0x", V: "0x1b", R: "0x1", S: "0x1"}}
+	r = checkBlock(b, nil)
+	if r.OK() || len(r.Problems()) != 1 || !strings.HasPrefix(r.Problems()[0], "transactionsRoot: 1 items hash to") {
+		t.Fatalf("extra tx: %q", r.Problems())
+	}
+
+	if r := checkBlock(nil, nil); r.Error == nil || r.OK() {
+		t.Fatalf("missing block: %+v", r)
+	}
+}
+
+func TestConvertVerificationToJSON(t *testing.T) {
+	good := checkBlock(mainnetBlock1(), nil)
+	good.Provider = "a"
+	failed := checkBlock(nil, errors.New("connection refused"))
+	failed.Provider = "b"
+
+	got := convertVerificationToJSON([]format.VerifyResult{good, failed})
+	if v := got[0]; !v.Verified || !v.HashVerified || v.TransactionsRoot == nil ||
+		!v.TransactionsRoot.Verified || v.TransactionsRoot.Count != 0 || v.WithdrawalsRoot != nil {
+		t.Fatalf("verified provider: %+v", v)
+	}
+	if v := got[1]; v.Verified || v.Error == "" || v.TransactionsRoot != nil {
+		t.Fatalf("failed provider: %+v", v)
+	}
+}
+
+func TestCheckReceipts(t *testing.T) {
+	block := mainnetBlock1()
+	r := checkBlock(block, nil)
+	checkReceipts(&r, block, &rpc.BlockReceipts{Receipts: []rpc.Receipt{}}, nil)
+	if !r.OK() || r.Receipts == nil || r.Bloom == nil {
+		t.Fatalf("empty block: %+v", r)
+	}
+
+	// A receipt the header does not commit to fails both checks.
+	extra := rpc.Receipt{Status: "0x1", CumulativeGasUsed: "0x5208", LogsBloom: block.LogsBloom,
+		Logs: []rpc.Log{{Address: "0x" + strings.Repeat("aa", 20), Data: "0x"}}}
+	r = checkBlock(block, nil)
+	checkReceipts(&r, block, &rpc.BlockReceipts{Receipts: []rpc.Receipt{extra}}, nil)
+	if r.OK() || len(r.Problems()) != 2 {
+		t.Fatalf("extra receipt: %q", r.Problems())
+	}
+	got := convertVerificationToJSON([]format.VerifyResult{r})[0]
+	if got.ReceiptsRoot == nil || got.ReceiptsRoot.Verified || got.LogsBloom == nil ||
+		got.LogsBloom.BlockOK || len(got.LogsBloom.BadReceipts) != 1 {
+		t.Fatalf("json: %+v %+v", got.ReceiptsRoot, got.LogsBloom)
+	}
+
+	// A failed fetch leaves the root unverifiable and the bloom unchecked.
+	r = checkBlock(block, nil)
+	checkReceipts(&r, block, nil, errors.New("connection reset"))
+	if r.OK() || r.Bloom != nil || !strings.Contains(r.Problems()[0], "cannot verify receiptsRoot: fetching receipts: connection reset") {
+		t.Fatalf("failed fetch: %q", r.Problems())
+	}
+}
@@ -0,0 +1,190 @@
+// =============================================================================
+// FILE: cmd/capabilities/main.go
+// ROLE: Method Capability Probe — Which RPC Methods Does Each Provider Serve?
+// =============================================================================
+//
+// SYSTEM CONTEXT
+// ==============
+// This is the entry point for the `capabilities` command. Providers disable
+// methods silently: debug_* and trace_* on most hosted plans, txpool_*
+// behind load balancers, eth_getBlockReceipts on older nodes, and large
+// eth_getLogs ranges everywhere at a different cap. An application finds out
+// when the call fails in production. This command finds out first: it calls
+// every method of a catalog once per provider, with real arguments, and
+// classifies each answer as supported, unsupported, restricted or broken
+// (rpc/capability.go).
+//
+// The catalog is the config's `methods` section, or rpc.DefaultMethodCatalog
+// when there is none.
+//
+// Usage examples:
+//   capabilities            ← Method × provider matrix in the terminal
+//   capabilities --json     ← Capability report: reports/capabilities-*.json
+//
+// EXECUTION FLOW
+// ==============
+//
+//   1. main()
+//      ├─ config.LoadEnv(), flag.Parse()
+//      ├─ flags.Setup()           ← Load config; --transport, --record/--replay, chain check (internal/cli)
+//      └─ runCapabilities()
+//           │
+//           ├─ Fan out (errgroup, same pattern as cmd/nodeinfo), per provider:
+//           │   ProbeTarget()  → a block near its head (and a tx in it)
+//           │   ProbeMethods() → one call per catalog entry, in order
+//           │
+//           └─ Output:
+//               ├─ --json? → buildReport() → reportjson.Write()
+//               └─ Terminal? → format.FormatCapabilities()
+//
+// Each provider is probed against its own head, so a lagging provider is
+// not reported as lacking a method just because it lacks the block.
+// =============================================================================
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/dando385/eth-rpc-monitor/internal/cli"
+	"github.com/dando385/eth-rpc-monitor/internal/config"
+	"github.com/dando385/eth-rpc-monitor/internal/format"
+	"github.com/dando385/eth-rpc-monitor/internal/reportjson"
+	"github.com/dando385/eth-rpc-monitor/internal/rpc"
+)
+
+// =============================================================================
+// SECTION 1: JSON Report Types
+// =============================================================================
+
+// CapabilitiesReport is the capability report written by
+// `capabilities --json`.
+type CapabilitiesReport struct {
+	Timestamp time.Time         `json:"timestamp"`
+	Methods   []rpc.MethodProbe `json:"methods"` // The catalog, placeholders unfilled
+	Providers []ProviderEntry   `json:"providers"`
+}
+
+// ProviderEntry is one provider's column of the matrix.
+type ProviderEntry struct {
+	Name        string        `json:"name"`
+	Type        string        `json:"type"` // Configured type (informational)
+	Error       string        `json:"error,omitempty"`
+	ProbedBlock uint64        `json:"probed_block,omitempty"`
+	Supported   int           `json:"supported"`
+	Results     []MethodEntry `json:"results,omitempty"`
+}
+
+// MethodEntry is one cell: a catalog entry's outcome on one provider.
+type MethodEntry struct {
+	Name      string `json:"name"`
+	Method    string `json:"method"`
+	Status    string `json:"status"` // supported | unsupported | restricted | broken | skipped
+	LatencyMS int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
+}
+
+// buildReport converts probe results to the JSON report. types holds each
+// provider's configured type, index-aligned with results.
+func buildReport(catalog []rpc.MethodProbe, types []string, results []format.CapabilityResult) CapabilitiesReport {
+	report := CapabilitiesReport{Timestamp: time.Now(), Methods: catalog, Providers: make([]ProviderEntry, len(results))}
+	for i, r := range results {
+		e := ProviderEntry{Name: r.Provider, Type: types[i]}
+		if r.Error != nil {
+			e.Error = r.Error.Error()
+			report.Providers[i] = e
+			continue
+		}
+		e.ProbedBlock, e.Supported = r.Target.Head, r.Supported()
+		for _, m := range r.Methods {
+			me := MethodEntry{Name: m.Probe.Label(), Method: m.Probe.Method, Status: string(m.Status), LatencyMS: m.Latency.Milliseconds()}
+			if m.Error != nil {
+				me.Error = m.Error.Error()
+			}
+			e.Results = append(e.Results, me)
+		}
+		report.Providers[i] = e
+	}
+	return report
+}
+
+// =============================================================================
+// SECTION 2: Main Logic
+// =============================================================================
+
+// runCapabilities probes every provider concurrently and renders the
+// results. Within a provider the catalog runs in order, one call at a time,
+// so the probe never bursts past a provider's rate limit.
+func runCapabilities(cfg *config.Config, jsonOut bool) error {
+	catalog := cfg.Methods.Catalog()
+	if !jsonOut {
+		fmt.Printf("\nProbing %d methods on %d providers...\n", len(catalog), len(cfg.Providers))
+	}
+
+	results := make([]format.CapabilityResult, len(cfg.Providers))
+	types := make([]string, len(cfg.Providers))
+	var mu sync.Mutex
+	g, gctx := errgroup.WithContext(context.Background())
+	for i, p := range cfg.Providers {
+		i, p := i, p
+		types[i] = p.Type
+		g.Go(func() error {
+			client := rpc.NewClient(p.Name, p.URL, p.Timeout, p.ClientOptions()...)
+			r := format.CapabilityResult{Provider: p.Name}
+			r.Target, r.Error = client.ProbeTarget(gctx)
+			if r.Error == nil {
+				r.Methods = client.ProbeMethods(gctx, catalog, r.Target)
+			}
+			mu.Lock()
+			results[i] = r
+			mu.Unlock()
+			return nil
+		})
+	}
+	g.Wait()
+
+	if jsonOut {
+		filepath, err := reportjson.Write(buildReport(catalog, types, results), "capabilities")
+		if err != nil {
+			return fmt.Errorf("failed to write JSON report: %w", err)
+		}
+		fmt.Fprintf(os.Stderr, "JSON report written to: %s\n", filepath)
+		return nil
+	}
+
+	format.FormatCapabilities(os.Stdout, catalog, results)
+	return nil
+}
+
+// =============================================================================
+// SECTION 3: Entry Point
+// =============================================================================
+
+func main() {
+	config.LoadEnv()
+
+	var (
+		flags   = cli.Register(flag.CommandLine, "")
+		jsonOut = flag.Bool("json", false, "Output JSON capability report to reports directory")
+	)
+	flag.Parse()
+
+	cfg, cassette, err := flags.Setup("capabilities")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	defer cassette.Close()
+
+	if err := runCapabilities(cfg, *jsonOut); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dando385/eth-rpc-monitor/internal/format"
+	"github.com/dando385/eth-rpc-monitor/internal/rpc"
+)
+
+func TestBuildReport(t *testing.T) {
+	catalog := []rpc.MethodProbe{
+		{Method: "eth_getBlockReceipts", Params: []interface{}{"{head}"}},
+		{Name: "logs", Method: "eth_getLogs"},
+	}
+	results := []format.CapabilityResult{
+		{Provider: "a", Target: rpc.ProbeTarget{Head: 97}, Methods: []rpc.MethodResult{
+			{Probe: catalog[0], Status: rpc.CapSupported, Latency: 40 * time.Millisecond},
+			{Probe: catalog[1], Status: rpc.CapRestricted, Latency: 12 * time.Millisecond, Error: errors.New("range too large")},
+		}},
+		{Provider: "b", Error: errors.New("head: timeout")},
+	}
+	report := buildReport(catalog, []string{"public", "self_hosted"}, results)
+
+	a := report.Providers[0]
+	if a.ProbedBlock != 97 || a.Supported != 1 || len(a.Results) != 2 || a.Type != "public" {
+		t.Fatalf("a = %+v", a)
+	}
+	if r := a.Results[0]; r.Name != "eth_getBlockReceipts" || r.Status != "supported" || r.LatencyMS != 40 || r.Error != "" {
+		t.Fatalf("a[0] = %+v", r)
+	}
+	if r := a.Results[1]; r.Name != "logs" || r.Method != "eth_getLogs" || r.Status != "restricted" || r.Error != "range too large" {
+		t.Fatalf("a[1] = %+v", r)
+	}
+	if b := report.Providers[1]; b.Error != "head: timeout" || b.Results != nil {
+		t.Fatalf("b = %+v", b)
+	}
+	if len(report.Methods) != 2 {
+		t.Fatalf("methods = %+v", report.Methods)
+	}
+}
@@ -0,0 +1,324 @@
+// =============================================================================
+// FILE: cmd/fees/main.go
+// ROLE: Fee Market Comparison — What Each Provider Tells a Wallet to Pay
+// =============================================================================
+//
+// SYSTEM CONTEXT
+// ==============
+// This is the entry point for the `fees` command. A wallet backend that
+// prices transactions from its provider's fee suggestions inherits that
+// provider's oracle, and oracles disagree: one suggests a 1 gwei tip,
+// another 0.0004 gwei, for the same block. This command asks every provider
+// the same three questions (rpc/fees.go):
+//
+//   eth_gasPrice              → legacy gas price suggestion
+//   eth_maxPriorityFeePerGas  → EIP-1559 tip suggestion
+//   eth_feeHistory            → base fee, blob base fee and the tips paid
+//                               at --percentiles over the last --blocks
+//
+// and shows, per metric, the lowest, median and highest answer, and which
+// providers are more than --outlier percent away from the median.
+//
+// Usage examples:
+//   fees                          ← Table, spread and outliers
+//   fees --percentiles 5,50,95    ← Other reward percentiles
+//   fees --blocks 100             ← Longer fee history window
+//   fees --json                   ← Full report: reports/fees-*.json
+//
+// EXECUTION FLOW
+// ==============
+//
+//   1. main()
+//      ├─ config.LoadEnv(), flag.Parse()
+//      ├─ parsePercentiles()      ← "10,50,90" → [10 50 90]
+//      ├─ flags.Setup()           ← Load config; --transport, --record/--replay, chain check (internal/cli)
+//      └─ runFees()
+//           │
+//           ├─ Fan out (errgroup, same pattern as cmd/nodeinfo):
+//           │   client.FeeQuote() per provider
+//           │     └─ each method may fail on its own; see rpc/fees.go
+//           │
+//           ├─ format.FeeSpreads()   ← Min / median / max and outliers per metric
+//           │
+//           └─ Output:
+//               ├─ --json? → buildReport() → reportjson.Write()
+//               └─ Terminal? → format.FormatFees()
+// =============================================================================
+
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"math/big"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/dando385/eth-rpc-monitor/internal/cli"
+	"github.com/dando385/eth-rpc-monitor/internal/config"
+	"github.com/dando385/eth-rpc-monitor/internal/format"
+	"github.com/dando385/eth-rpc-monitor/internal/reportjson"
+	"github.com/dando385/eth-rpc-monitor/internal/rpc"
+)
+
+// maxBlocks is the most blocks geth serves in one eth_feeHistory call.
+const maxBlocks = 1024
+
+// =============================================================================
+// SECTION 1: JSON Report Types
+// =============================================================================
+
+// FeesReport is the report written by `fees --json`. Fees are decimal
+// strings in wei: they are big integers, and exact values are the point.
+type FeesReport struct {
+	Timestamp   time.Time     `json:"timestamp"`
+	Blocks      uint64        `json:"blocks"`
+	Percentiles []float64     `json:"percentiles"`
+	OutlierPct  float64       `json:"outlier_pct"`
+	Providers   []FeeEntry    `json:"providers"`
+	Spread      []SpreadEntry `json:"spread"`
+}
+
+// FeeEntry is one provider's answers.
+type FeeEntry struct {
+	Name           string            `json:"name"`
+	Type           string            `json:"type"` // Configured type (informational)
+	LatencyMS      int64             `json:"latency_ms"`
+	Error          string            `json:"error,omitempty"`
+	GasPriceWei    string            `json:"gas_price_wei,omitempty"`
+	PriorityFeeWei string            `json:"max_priority_fee_per_gas_wei,omitempty"`
+	History        *HistoryEntry     `json:"fee_history,omitempty"`
+	MethodErrors   map[string]string `json:"method_errors,omitempty"` // method → error
+}
+
+// HistoryEntry is a decoded eth_feeHistory response.
+type HistoryEntry struct {
+	OldestBlock         uint64     `json:"oldest_block"`
+	NewestBlock         uint64     `json:"newest_block"`
+	BaseFeesWei         []string   `json:"base_fees_wei"` // One per block, then the next block's
+	GasUsedRatio        []float64  `json:"gas_used_ratio"`
+	RewardsWei          [][]string `json:"rewards_wei,omitempty"` // Per block, one per percentile
+	MedianRewardsWei    []string   `json:"median_rewards_wei,omitempty"`
+	BlobBaseFeesWei     []string   `json:"blob_base_fees_wei,omitempty"`
+	BlobGasUsedRatio    []float64  `json:"blob_gas_used_ratio,omitempty"`
+	NextBaseFeeWei      string     `json:"next_base_fee_wei,omitempty"`
+	NextBlobBaseFeeWei  string     `json:"next_blob_base_fee_wei,omitempty"`
+	MeanGasUsedRatioPct float64    `json:"mean_gas_used_pct"`
+}
+
+// SpreadEntry is one metric across providers.
+type SpreadEntry struct {
+	Metric    string         `json:"metric"`
+	Providers int            `json:"providers"`
+	MinWei    string         `json:"min_wei,omitempty"`
+	MedianWei string         `json:"median_wei,omitempty"`
+	MaxWei    string         `json:"max_wei,omitempty"`
+	SpreadPct float64        `json:"spread_pct"`
+	Outliers  []OutlierEntry `json:"outliers,omitempty"`
+}
+
+// OutlierEntry is a value more than the outlier threshold from the median.
+type OutlierEntry struct {
+	Provider     string  `json:"provider"`
+	ValueWei     string  `json:"value_wei"`
+	DeviationPct float64 `json:"deviation_pct"`
+}
+
+// wei renders an optional amount: "" for nil, so omitempty drops it.
+func wei(v *big.Int) string {
+	if v == nil {
+		return ""
+	}
+	return v.String()
+}
+
+func weis(values []*big.Int) []string {
+	if len(values) == 0 {
+		return nil
+	}
+	out := make([]string, len(values))
+	for i, v := range values {
+		out[i] = v.String()
+	}
+	return out
+}
+
+// buildReport converts the quotes and spreads to the JSON report. types
+// holds each provider's configured type, index-aligned with results.
+func buildReport(blocks uint64, percentiles []float64, outlierPct float64, types []string, results []format.FeeResult, spreads []format.FeeSpread) FeesReport {
+	report := FeesReport{
+		Timestamp:   time.Now(),
+		Blocks:      blocks,
+		Percentiles: percentiles,
+		OutlierPct:  outlierPct,
+		Providers:   make([]FeeEntry, len(results)),
+		Spread:      make([]SpreadEntry, len(spreads)),
+	}
+	for i, r := range results {
+		e := FeeEntry{Name: r.Provider, Type: types[i], LatencyMS: r.Latency.Milliseconds()}
+		if r.Error != nil {
+			e.Error = r.Error.Error()
+			report.Providers[i] = e
+			continue
+		}
+		q := r.Quote
+		e.GasPriceWei, e.PriorityFeeWei = wei(q.GasPrice), wei(q.PriorityFee)
+		for method, err := range q.Errors {
+			if e.MethodErrors == nil {
+				e.MethodErrors = make(map[string]string)
+			}
+			e.MethodErrors[method] = err.Error()
+		}
+		if h := q.History; h != nil {
+			he := &HistoryEntry{
+				OldestBlock:         h.OldestBlock,
+				NewestBlock:         h.NewestBlock(),
+				BaseFeesWei:         weis(h.BaseFees),
+				GasUsedRatio:        h.GasUsedRatio,
+				BlobBaseFeesWei:     weis(h.BlobBaseFees),
+				BlobGasUsedRatio:    h.BlobGasUsedRatio,
+				NextBaseFeeWei:      wei(h.NextBaseFee()),
+				NextBlobBaseFeeWei:  wei(h.NextBlobBaseFee()),
+				MeanGasUsedRatioPct: h.MeanGasUsedRatio() * 100,
+			}
+			for _, row := range h.Rewards {
+				he.RewardsWei = append(he.RewardsWei, weis(row))
+			}
+			if h.Rewards != nil {
+				for p := range h.Percentiles {
+					he.MedianRewardsWei = append(he.MedianRewardsWei, wei(h.MedianReward(p)))
+				}
+			}
+			e.History = he
+		}
+		report.Providers[i] = e
+	}
+	for i, s := range spreads {
+		se := SpreadEntry{Metric: s.Metric, Providers: s.Providers, MinWei: wei(s.Min), MedianWei: wei(s.Median), MaxWei: wei(s.Max), SpreadPct: s.SpreadPct}
+		for _, o := range s.Outliers {
+			se.Outliers = append(se.Outliers, OutlierEntry{Provider: o.Provider, ValueWei: o.Value.String(), DeviationPct: o.DeviationPct})
+		}
+		report.Spread[i] = se
+	}
+	return report
+}
+
+// =============================================================================
+// SECTION 2: Main Logic
+// =============================================================================
+
+// parsePercentiles parses "10,50,90": numbers from 0 to 100, strictly
+// ascending, as eth_feeHistory requires. An empty string asks for none.
+func parsePercentiles(s string) ([]float64, error) {
+	var out []float64
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		p, err := strconv.ParseFloat(part, 64)
+		if err != nil || p < 0 || p > 100 {
+			return nil, fmt.Errorf("percentile %q is not a number from 0 to 100", part)
+		}
+		if len(out) > 0 && p <= out[len(out)-1] {
+			return nil, errors.New("percentiles must be strictly ascending")
+		}
+		out = append(out, p)
+	}
+	return out, nil
+}
+
+// runFees asks every provider for its fee quote concurrently, compares the
+// quotes and renders the result.
+func runFees(cfg *config.Config, blocks uint64, percentiles []float64, outlierPct float64, jsonOut bool) error {
+	// Three calls per provider, made one after another.
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Defaults.Timeout*time.Duration(len(rpc.FeeMethods)))
+	defer cancel()
+
+	if !jsonOut {
+		fmt.Printf("\nComparing fee suggestions from %d providers...\n", len(cfg.Providers))
+	}
+
+	results := make([]format.FeeResult, len(cfg.Providers))
+	types := make([]string, len(cfg.Providers))
+	var mu sync.Mutex
+	g, gctx := errgroup.WithContext(ctx)
+	for i, p := range cfg.Providers {
+		i, p := i, p
+		types[i] = p.Type
+		g.Go(func() error {
+			client := rpc.NewClient(p.Name, p.URL, p.Timeout, p.ClientOptions()...)
+			quote, latency, err := client.FeeQuote(gctx, blocks, percentiles)
+			mu.Lock()
+			results[i] = format.FeeResult{Provider: p.Name, Quote: quote, Latency: latency, Error: err}
+			mu.Unlock()
+			return nil
+		})
+	}
+	g.Wait()
+
+	metrics := format.FeeMetrics(percentiles)
+	spreads := format.FeeSpreads(results, metrics, outlierPct)
+
+	if jsonOut {
+		filepath, err := reportjson.Write(buildReport(blocks, percentiles, outlierPct, types, results, spreads), "fees")
+		if err != nil {
+			return fmt.Errorf("failed to write JSON report: %w", err)
+		}
+		fmt.Fprintf(os.Stderr, "JSON report written to: %s\n", filepath)
+		return nil
+	}
+
+	format.FormatFees(os.Stdout, results, metrics, spreads, blocks, outlierPct)
+	return nil
+}
+
+// =============================================================================
+// SECTION 3: Entry Point
+// =============================================================================
+
+func main() {
+	config.LoadEnv()
+
+	var (
+		flags       = cli.Register(flag.CommandLine, "")
+		blocks      = flag.Uint64("blocks", 20, "Blocks of eth_feeHistory to request, ending at latest (1 to 1024)")
+		percentiles = flag.String("percentiles", "10,50,90", "Reward percentiles for eth_feeHistory, ascending, comma-separated")
+		outlier     = flag.Float64("outlier", 25, "Flag values further than this many percent from the providers' median")
+		jsonOut     = flag.Bool("json", false, "Output JSON fee report to reports directory")
+	)
+	flag.Parse()
+
+	if *blocks < 1 || *blocks > maxBlocks {
+		fmt.Fprintf(os.Stderr, "Error: --blocks must be from 1 to %d\n", maxBlocks)
+		os.Exit(1)
+	}
+	pcts, err := parsePercentiles(*percentiles)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: --percentiles: %v\n", err)
+		os.Exit(1)
+	}
+	if *outlier <= 0 {
+		fmt.Fprintln(os.Stderr, "Error: --outlier must be positive")
+		os.Exit(1)
+	}
+
+	cfg, cassette, err := flags.Setup("fees")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	defer cassette.Close()
+
+	if err := runFees(cfg, *blocks, pcts, *outlier, *jsonOut); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/dando385/eth-rpc-monitor/internal/format"
+	"github.com/dando385/eth-rpc-monitor/internal/rpc"
+)
+
+func TestParsePercentiles(t *testing.T) {
+	got, err := parsePercentiles(" 10, 50,99.5 ")
+	if err != nil || len(got) != 3 || got[2] != 99.5 {
+		t.Fatalf("got %v, %v", got, err)
+	}
+	if got, err := parsePercentiles(""); err != nil || got != nil {
+		t.Fatalf("empty = %v, %v", got, err)
+	}
+	for _, bad := range []string{"50,10", "10,10", "101", "-1", "x"} {
+		if _, err := parsePercentiles(bad); err == nil {
+			t.Errorf("parsePercentiles(%q) should fail", bad)
+		}
+	}
+}
+
+func TestBuildReport(t *testing.T) {
+	history := &rpc.FeeHistory{
+		OldestBlock:  100,
+		Percentiles:  []float64{50},
+		BaseFees:     []*big.Int{big.NewInt(7), big.NewInt(8)},
+		GasUsedRatio: []float64{0.5},
+		Rewards:      [][]*big.Int{{big.NewInt(2)}},
+	}
+	results := []format.FeeResult{
+		{Provider: "a", Latency: 40 * time.Millisecond, Quote: &rpc.FeeQuote{
+			GasPrice: big.NewInt(10), History: history,
+			Errors: map[string]error{rpc.MethodMaxPriorityFee: errors.New("not supported")},
+		}},
+		{Provider: "b", Error: errors.New("timeout")},
+	}
+	metrics := format.FeeMetrics([]float64{50})
+	report := buildReport(1, []float64{50}, 25, []string{"public", "enterprise"}, results, format.FeeSpreads(results, metrics, 25))
+
+	a := report.Providers[0]
+	if a.GasPriceWei != "10" || a.PriorityFeeWei != "" || a.MethodErrors[rpc.MethodMaxPriorityFee] != "not supported" || a.LatencyMS != 40 {
+		t.Fatalf("a = %+v", a)
+	}
+	h := a.History
+	if h == nil || h.NewestBlock != 100 || h.NextBaseFeeWei != "8" || h.MedianRewardsWei[0] != "2" || h.RewardsWei[0][0] != "2" || h.BlobBaseFeesWei != nil || h.MeanGasUsedRatioPct != 50 {
+		t.Fatalf("history = %+v", h)
+	}
+	if b := report.Providers[1]; b.Error != "timeout" || b.Type != "enterprise" || b.History != nil {
+		t.Fatalf("b = %+v", b)
+	}
+	if len(report.Spread) != len(metrics) || report.Spread[0].MedianWei != "10" || report.Spread[0].Providers != 1 {
+		t.Fatalf("spread = %+v", report.Spread)
+	}
+}
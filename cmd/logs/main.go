@@ -0,0 +1,296 @@
+// =============================================================================
+// FILE: cmd/logs/main.go
+// ROLE: Log Consistency Checker — Same eth_getLogs Query, Every Provider
+// =============================================================================
+//
+// SYSTEM CONTEXT
+// ==============
+// This is the entry point for the `logs` command. It answers: "If I ask every
+// provider for the same events, do I get the same answer?"
+//
+// eth_getLogs is where providers differ most. Wide ranges get rejected with
+// provider-specific "range too large" errors, and — worse — some providers
+// return a PARTIAL result without any error at all. An indexer that trusts
+// such a provider silently loses deposits, transfers or liquidations.
+//
+// Usage examples:
+//   logs                                         ← All logs in the last 1,000 blocks
+//   logs --range 10000                           ← ... in the last 10,000 blocks
+//   logs --from 19000000 --to 19009999           ← Explicit range (decimal or hex)
+//   logs --address 0xA0b8...eB48                 ← Only the USDC contract
+//   logs --address 0xA0b8...eB48 \
+//        --topics 0xddf252ad...523b3ef           ← Only its Transfer events
+//   logs --topics '0xddf2...;;0x000...beef'      ← Transfers TO 0x...beef
+//
+// EXECUTION FLOW
+// ==============
+//
+//   1. main()
+//      ├─ Parse flags → rpc.LogFilter (addresses, topics)
+//      ├─ flags.Setup()        ← Load config; --transport, --record/--replay, chain check (internal/cli)
+//      └─ runLogs()
+//           │
+//           ├─ Resolve the range:
+//           │   "latest" → the LOWEST head among providers, so every
+//           │   provider is asked about blocks it already has
+//           │
+//           ├─ Fan out (errgroup, same pattern as cmd/snapshot):
+//           │   client.GetLogsRange() per provider
+//           │     └─ range-limit error? → split in half, query each half (rpc/logs.go)
+//           │
+//           └─ format.FormatLogs()
+//                └─ CompareLogs: reference set, missing/extra per provider
+//
+// RANGE RESOLUTION
+// ================
+// Comparing logs for "latest" across providers would be meaningless if each
+// resolved "latest" to a different block — the provider one block ahead
+// would look like it is "adding" logs. So tags are resolved ONCE, up front,
+// to a concrete number every provider has reached.
+// =============================================================================
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/dando385/eth-rpc-monitor/internal/cli"
+	"github.com/dando385/eth-rpc-monitor/internal/config"
+	"github.com/dando385/eth-rpc-monitor/internal/format"
+	"github.com/dando385/eth-rpc-monitor/internal/rpc"
+)
+
+// =============================================================================
+// SECTION 1: Filter and Range Parsing
+// =============================================================================
+
+// parseAddresses splits a comma-separated address list. Empty input means
+// "any contract".
+func parseAddresses(s string) []string {
+	var out []string
+	for _, a := range strings.Split(s, ",") {
+		if a = strings.TrimSpace(a); a != "" {
+			out = append(out, strings.ToLower(a))
+		}
+	}
+	return out
+}
+
+// parseTopics parses the --topics syntax: positions separated by ";",
+// alternatives within a position separated by ",". An empty position or "*"
+// is a wildcard. Trailing wildcards are dropped, since eth_getLogs treats a
+// shorter topics array the same way.
+//
+//	"0xsig"            → [[0xsig]]
+//	"0xsig;;0xto"      → [[0xsig] [] [0xto]]
+//	"0xa,0xb;*"        → [[0xa 0xb]]
+func parseTopics(s string) [][]string {
+	if strings.TrimSpace(s) == "" {
+		return nil
+	}
+	var topics [][]string
+	for _, pos := range strings.Split(s, ";") {
+		var alts []string
+		for _, t := range strings.Split(pos, ",") {
+			if t = strings.TrimSpace(t); t != "" && t != "*" {
+				alts = append(alts, strings.ToLower(t))
+			}
+		}
+		topics = append(topics, alts)
+	}
+	for len(topics) > 0 && len(topics[len(topics)-1]) == 0 {
+		topics = topics[:len(topics)-1]
+	}
+	return topics
+}
+
+// resolveBlock turns a block argument into a number. "latest" (or "") maps to
+// head; decimal and 0x-hex numbers are parsed.
+func resolveBlock(arg string, head uint64) (uint64, error) {
+	arg = strings.TrimSpace(strings.ToLower(arg))
+	switch {
+	case arg == "" || arg == "latest":
+		return head, nil
+	case arg == "earliest":
+		return 0, nil
+	case strings.HasPrefix(arg, "0x"):
+		return rpc.ParseHexUint64(arg)
+	default:
+		return strconv.ParseUint(arg, 10, 64)
+	}
+}
+
+// resolveRange computes [from, to]. With fromArg empty, the range is the
+// span blocks ending at to.
+func resolveRange(fromArg, toArg string, span, head uint64) (from, to uint64, err error) {
+	if to, err = resolveBlock(toArg, head); err != nil {
+		return 0, 0, fmt.Errorf("invalid --to %q: %w", toArg, err)
+	}
+	if strings.TrimSpace(fromArg) == "" {
+		if span == 0 {
+			return 0, 0, fmt.Errorf("--range must be at least 1")
+		}
+		if span > to+1 {
+			span = to + 1
+		}
+		return to - span + 1, to, nil
+	}
+	if from, err = resolveBlock(fromArg, head); err != nil {
+		return 0, 0, fmt.Errorf("invalid --from %q: %w", fromArg, err)
+	}
+	if from > to {
+		return 0, 0, fmt.Errorf("--from %d is after --to %d", from, to)
+	}
+	return from, to, nil
+}
+
+// needsHead reports whether resolving the range requires a head height.
+func needsHead(fromArg, toArg string) bool {
+	isTag := func(s string) bool {
+		s = strings.TrimSpace(strings.ToLower(s))
+		return s == "" || s == "latest"
+	}
+	return isTag(toArg) || strings.TrimSpace(strings.ToLower(fromArg)) == "latest"
+}
+
+// =============================================================================
+// SECTION 2: Head Resolution
+// =============================================================================
+
+// lowestHead asks every provider for its head and returns the lowest one,
+// so the resolved range is within every provider's chain. Providers that
+// fail here are skipped; they will fail (and be reported) in the log query.
+func lowestHead(ctx context.Context, clients []*rpc.Client) (uint64, error) {
+	heads := make([]uint64, len(clients))
+	ok := make([]bool, len(clients))
+	var mu sync.Mutex
+
+	g, gctx := errgroup.WithContext(ctx)
+	for i, c := range clients {
+		i, c := i, c
+		g.Go(func() error {
+			h, _, err := c.BlockNumber(gctx)
+			mu.Lock()
+			heads[i], ok[i] = h, err == nil
+			mu.Unlock()
+			return nil
+		})
+	}
+	g.Wait()
+
+	var lowest uint64
+	found := false
+	for i := range clients {
+		if ok[i] && (!found || heads[i] < lowest) {
+			lowest, found = heads[i], true
+		}
+	}
+	if !found {
+		return 0, fmt.Errorf("no provider reported a head block")
+	}
+	return lowest, nil
+}
+
+// =============================================================================
+// SECTION 3: Main Logic
+// =============================================================================
+
+// runLogs resolves the block range, runs the filter against every provider
+// concurrently, and renders the comparison.
+func runLogs(cfg *config.Config, filter rpc.LogFilter, fromArg, toArg string, span uint64) error {
+	// Splitting can take many round trips on a narrow-limit provider; each
+	// request still has the provider's own timeout, this only bounds the run.
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Defaults.Timeout*10)
+	defer cancel()
+
+	clients := make([]*rpc.Client, len(cfg.Providers))
+	for i, p := range cfg.Providers {
+		clients[i] = rpc.NewClient(p.Name, p.URL, p.Timeout, p.ClientOptions()...)
+	}
+
+	var head uint64
+	if needsHead(fromArg, toArg) {
+		var err error
+		if head, err = lowestHead(ctx, clients); err != nil {
+			return err
+		}
+	}
+	from, to, err := resolveRange(fromArg, toArg, span, head)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("\neth_getLogs over blocks %s → %s (%s blocks) from %d providers\n",
+		rpc.FormatNumber(from), rpc.FormatNumber(to), rpc.FormatNumber(to-from+1), len(clients))
+	if len(filter.Addresses) > 0 {
+		fmt.Printf("  address: %s\n", strings.Join(filter.Addresses, ", "))
+	}
+	for i, alts := range filter.Topics {
+		if len(alts) > 0 {
+			fmt.Printf("  topic%d:  %s\n", i, strings.Join(alts, " | "))
+		}
+	}
+	fmt.Println()
+
+	// Fan out: the same query against every provider, concurrently.
+	results := make([]format.LogsResult, len(clients))
+	var mu sync.Mutex
+	g, gctx := errgroup.WithContext(ctx)
+	for i, c := range clients {
+		i, c := i, c
+		g.Go(func() error {
+			res, latency, err := c.GetLogsRange(gctx, filter, from, to)
+			r := format.LogsResult{Provider: c.Name(), Latency: latency, Error: err}
+			if err == nil {
+				r.Logs, r.Requests, r.Splits, r.MaxSpan = res.Logs, res.Requests, res.Splits, res.MaxSpan
+			}
+			mu.Lock()
+			results[i] = r
+			mu.Unlock()
+			return nil
+		})
+	}
+	g.Wait()
+
+	format.FormatLogs(os.Stdout, results)
+	return nil
+}
+
+// =============================================================================
+// SECTION 4: Entry Point
+// =============================================================================
+
+func main() {
+	config.LoadEnv()
+
+	var (
+		flags   = cli.Register(flag.CommandLine, "")
+		address = flag.String("address", "", "Contract address(es), comma-separated (empty = any)")
+		topics  = flag.String("topics", "", "Topic filter: positions separated by ';', alternatives by ','; empty or * = any")
+		from    = flag.String("from", "", "First block (decimal, 0x-hex, or latest); default: --range blocks before --to")
+		to      = flag.String("to", "latest", "Last block (decimal, 0x-hex, or latest = lowest head among providers)")
+		span    = flag.Uint64("range", 1000, "Number of blocks to query when --from is not set")
+	)
+	flag.Parse()
+
+	cfg, cassette, err := flags.Setup("logs")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	defer cassette.Close()
+
+	filter := rpc.LogFilter{Addresses: parseAddresses(*address), Topics: parseTopics(*topics)}
+	if err := runLogs(cfg, filter, *from, *to, *span); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
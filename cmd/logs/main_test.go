@@ -0,0 +1,68 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseTopics(t *testing.T) {
+	tests := []struct {
+		in   string
+		want [][]string
+	}{
+		{"", nil},
+		{"0xSIG", [][]string{{"0xsig"}}},
+		{"0xsig;;0xto", [][]string{{"0xsig"}, nil, {"0xto"}}},
+		{"0xa, 0xb;*", [][]string{{"0xa", "0xb"}}},
+		{"*;0xfrom", [][]string{nil, {"0xfrom"}}},
+	}
+	for _, tc := range tests {
+		if got := parseTopics(tc.in); !reflect.DeepEqual(got, tc.want) {
+			t.Fatalf("parseTopics(%q) = %#v want %#v", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestParseAddresses(t *testing.T) {
+	got := parseAddresses(" 0xAbC ,, 0xdef")
+	if !reflect.DeepEqual(got, []string{"0xabc", "0xdef"}) {
+		t.Fatalf("got %v", got)
+	}
+}
+
+func TestResolveRange(t *testing.T) {
+	tests := []struct {
+		from, to  string
+		span      uint64
+		head      uint64
+		wantFrom  uint64
+		wantTo    uint64
+		wantError bool
+	}{
+		{"", "latest", 1000, 5000, 4001, 5000, false},
+		{"", "latest", 1000, 10, 0, 10, false}, // range clamped at genesis
+		{"19000000", "0x121eac9", 0, 0, 19000000, 19000009, false},
+		{"latest", "latest", 0, 42, 42, 42, false},
+		{"20", "10", 0, 0, 0, 0, true},
+		{"", "latest", 0, 5, 0, 0, true},
+		{"abc", "10", 0, 0, 0, 0, true},
+	}
+	for _, tc := range tests {
+		from, to, err := resolveRange(tc.from, tc.to, tc.span, tc.head)
+		if tc.wantError {
+			if err == nil {
+				t.Fatalf("resolveRange(%q, %q): expected error", tc.from, tc.to)
+			}
+			continue
+		}
+		if err != nil || from != tc.wantFrom || to != tc.wantTo {
+			t.Fatalf("resolveRange(%q, %q, %d, %d) = %d, %d, %v", tc.from, tc.to, tc.span, tc.head, from, to, err)
+		}
+	}
+}
+
+func TestNeedsHead(t *testing.T) {
+	if !needsHead("", "latest") || !needsHead("latest", "100") || needsHead("1", "100") {
+		t.Fatal("needsHead misjudged")
+	}
+}
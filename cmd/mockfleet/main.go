@@ -0,0 +1,236 @@
+// =============================================================================
+// FILE: cmd/mockfleet/main.go
+// ROLE: Offline Fleet — Simulated Providers for Demos and End-to-End Runs
+// =============================================================================
+//
+// SYSTEM CONTEXT
+// ==============
+// Every other command needs real endpoints, API keys and a network. This one
+// replaces them: it starts one simulated chain (internal/rpctest), serves it
+// through a fleet of local JSON-RPC providers, each with a profile from
+// rpctest.ProfileNames (healthy, slow, flaky, throttled, stale, forked,
+// pruned), and writes a providers.yaml pointing at them. Any command can
+// then run against the fleet exactly as it runs against production.
+//
+// Usage examples:
+//   mockfleet                                  ← Serve until Ctrl+C; config at /tmp/mockfleet.yaml
+//   mockfleet --providers a=healthy,b=forked   ← Pick the fleet
+//   mockfleet -- ./bin/snapshot                ← Start the fleet, run one command against it, exit
+//   mockfleet -- go run ./cmd/test --config {config} --samples 5
+//
+// With a command after "--", the fleet runs only as long as the command,
+// and mockfleet exits with its status. "{config}" in the command is
+// replaced by the config path; without it, "--config <path>" is inserted
+// right after the program name.
+//
+// EXECUTION FLOW
+// ==============
+//
+//   1. main()
+//      ├─ flag.Parse(), parseFleet()    ← "name=profile,..." → members
+//      └─ runFleet()
+//           ├─ rpctest.NewChain(), chain.Run()   ← Mine every --block-time
+//           ├─ Per member: net.Listen() + http.Serve(rpctest.NewProvider())
+//           ├─ writeConfig()                     ← providers.yaml for the fleet
+//           └─ Command? → run it : wait for Ctrl+C
+// =============================================================================
+
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/dando385/eth-rpc-monitor/internal/rpctest"
+)
+
+// member is one provider of the fleet.
+type member struct {
+	Name    string
+	Profile string
+}
+
+// =============================================================================
+// SECTION 1: Fleet Description
+// =============================================================================
+
+// parseFleet parses "name=profile,..." into members. A bare profile name is
+// its own provider name; any other name is suffixed with its profile
+// ("a=forked" → "a-forked"), so every command's output says which flaw a
+// provider was given.
+func parseFleet(spec string) ([]member, error) {
+	var fleet []member
+	seen := map[string]bool{}
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, profile, ok := strings.Cut(part, "=")
+		if !ok {
+			profile = name
+		}
+		name, profile = strings.TrimSpace(name), strings.TrimSpace(profile)
+		if _, known := rpctest.Profile(profile); !known {
+			return nil, fmt.Errorf("unknown profile %q (want one of %s)", profile, strings.Join(rpctest.ProfileNames(), ", "))
+		}
+		if name == "" {
+			return nil, fmt.Errorf("provider name for profile %q is empty", profile)
+		}
+		if name != profile {
+			name += "-" + profile
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("provider name %q is repeated", name)
+		}
+		seen[name] = true
+		fleet = append(fleet, member{Name: name, Profile: profile})
+	}
+	if len(fleet) == 0 {
+		return nil, errors.New("no providers")
+	}
+	return fleet, nil
+}
+
+// writeConfig writes a providers.yaml for the fleet: the chain section pins
+// the simulated chain, so chaincheck runs as it would in production. Every
+// member is a node on this machine, so its type is "self_hosted"; the
+// profile is already in its name (parseFleet).
+func writeConfig(w io.Writer, chain *rpctest.Chain, fleet []member, urls []string, interval time.Duration) {
+	fmt.Fprintf(w, "# Generated by mockfleet; valid while it runs.\n")
+	fmt.Fprintf(w, "defaults:\n  timeout: 5s\n  health_samples: 20\n  watch_interval: %s\n\n", interval)
+	fmt.Fprintf(w, "chain:\n  id: %d\n  genesis_hash: %q\n\n", chain.ID(), chain.Block(0).Hash)
+	fmt.Fprintf(w, "providers:\n")
+	for i, m := range fleet {
+		fmt.Fprintf(w, "  - name: %s\n    url: %s\n    type: self_hosted\n", m.Name, urls[i])
+	}
+}
+
+// commandArgs substitutes the config path into a command line: every
+// "{config}" is replaced, or "--config path" follows the program name.
+func commandArgs(args []string, path string) []string {
+	out := make([]string, 0, len(args)+2)
+	replaced := false
+	for _, a := range args {
+		if strings.Contains(a, "{config}") {
+			a, replaced = strings.ReplaceAll(a, "{config}", path), true
+		}
+		out = append(out, a)
+	}
+	if replaced {
+		return out
+	}
+	return append([]string{out[0], "--config", path}, out[1:]...)
+}
+
+// =============================================================================
+// SECTION 2: Main Logic
+// =============================================================================
+
+// runFleet serves the fleet and either runs command against it or waits
+// for ctx. It returns the command's exit code (0 without a command).
+func runFleet(ctx context.Context, opts rpctest.ChainOptions, fleet []member, listen, configPath string, command []string) (int, error) {
+	chain := rpctest.NewChain(opts)
+	go chain.Run(ctx)
+
+	urls := make([]string, len(fleet))
+	for i, m := range fleet {
+		ln, err := net.Listen("tcp", listen)
+		if err != nil {
+			return 1, fmt.Errorf("listen for %s: %w", m.Name, err)
+		}
+		defer ln.Close()
+		b, _ := rpctest.Profile(m.Profile)
+		go http.Serve(ln, rpctest.NewProvider(m.Name, chain, b))
+		urls[i] = "http://" + ln.Addr().String()
+	}
+
+	if err := os.MkdirAll(filepath.Dir(configPath), 0755); err != nil {
+		return 1, err
+	}
+	f, err := os.Create(configPath)
+	if err != nil {
+		return 1, err
+	}
+	interval := opts.BlockTime
+	if interval <= 0 {
+		interval = 12 * time.Second
+	}
+	writeConfig(f, chain, fleet, urls, interval)
+	if err := f.Close(); err != nil {
+		return 1, err
+	}
+
+	fmt.Fprintf(os.Stderr, "Chain %d at block %d, one block every %s\n", chain.ID(), chain.Head(), interval)
+	for i, m := range fleet {
+		fmt.Fprintf(os.Stderr, "  %-12s %-10s %s\n", m.Name, m.Profile, urls[i])
+	}
+	fmt.Fprintf(os.Stderr, "Config written to: %s\n", configPath)
+
+	if len(command) == 0 {
+		fmt.Fprintln(os.Stderr, "Serving until Ctrl+C")
+		<-ctx.Done()
+		return 0, nil
+	}
+
+	args := commandArgs(command, configPath)
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	err = cmd.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode(), nil
+	}
+	if err != nil {
+		return 1, err
+	}
+	return 0, nil
+}
+
+// =============================================================================
+// SECTION 3: Entry Point
+// =============================================================================
+
+func main() {
+	var (
+		providers  = flag.String("providers", strings.Join(rpctest.ProfileNames(), ","), "Fleet as name=profile (or profile) list; profiles: "+strings.Join(rpctest.ProfileNames(), ", "))
+		chainID    = flag.Uint64("chain-id", 1, "Chain ID the fleet reports")
+		length     = flag.Uint64("length", 1000, "Head block number at start")
+		blockTime  = flag.Duration("block-time", 12*time.Second, "Time between blocks")
+		reorgEvery = flag.Int("reorg-every", 0, "Reorganize the chain after every N blocks (0 = never)")
+		reorgDepth = flag.Int("reorg-depth", 1, "Blocks each reorg replaces")
+		listen     = flag.String("listen", "127.0.0.1:0", "Listen address per provider (port 0 = any free port)")
+		configPath = flag.String("write-config", filepath.Join(os.TempDir(), "mockfleet.yaml"), "Where to write the fleet's providers.yaml")
+	)
+	flag.Parse()
+
+	fleet, err := parseFleet(*providers)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: --providers: %v\n", err)
+		os.Exit(1)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	opts := rpctest.ChainOptions{ChainID: *chainID, Length: *length, BlockTime: *blockTime, ReorgEvery: *reorgEvery, ReorgDepth: *reorgDepth}
+	code, err := runFleet(ctx, opts, fleet, *listen, *configPath, flag.Args())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if code != 0 {
+		os.Exit(code)
+	}
+}
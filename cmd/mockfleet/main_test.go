@@ -0,0 +1,56 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/dando385/eth-rpc-monitor/internal/config"
+	"github.com/dando385/eth-rpc-monitor/internal/rpctest"
+)
+
+func TestParseFleet(t *testing.T) {
+	fleet, err := parseFleet("healthy, b=forked ,c=stale")
+	want := []member{{"healthy", "healthy"}, {"b-forked", "forked"}, {"c-stale", "stale"}}
+	if err != nil || !reflect.DeepEqual(fleet, want) {
+		t.Fatalf("fleet = %+v, %v", fleet, err)
+	}
+	for _, bad := range []string{"", "a=warp", "a=slow,a=slow", "slow,slow", "=slow"} {
+		if _, err := parseFleet(bad); err == nil {
+			t.Errorf("parseFleet(%q) should fail", bad)
+		}
+	}
+}
+
+func TestCommandArgs(t *testing.T) {
+	if got := commandArgs([]string{"./bin/test", "--samples", "5"}, "/tmp/f.yaml"); !reflect.DeepEqual(got, []string{"./bin/test", "--config", "/tmp/f.yaml", "--samples", "5"}) {
+		t.Fatalf("inserted = %q", got)
+	}
+	if got := commandArgs([]string{"go", "run", "./cmd/test", "--config={config}"}, "/tmp/f.yaml"); !reflect.DeepEqual(got, []string{"go", "run", "./cmd/test", "--config=/tmp/f.yaml"}) {
+		t.Fatalf("replaced = %q", got)
+	}
+}
+
+func TestWriteConfig_loads(t *testing.T) {
+	chain := rpctest.NewChain(rpctest.ChainOptions{ChainID: 17000, Length: 5})
+	path := filepath.Join(t.TempDir(), "fleet.yaml")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeConfig(f, chain, []member{{"a-healthy", "healthy"}, {"b-forked", "forked"}}, []string{"http://127.0.0.1:1", "http://127.0.0.1:2"}, 2*time.Second)
+	f.Close()
+
+	cfg, err := config.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Chain.ID != 17000 || cfg.Chain.GenesisHash != chain.Block(0).Hash || cfg.Defaults.WatchInterval != 2*time.Second {
+		t.Fatalf("config = %+v", cfg)
+	}
+	if len(cfg.Providers) != 2 || cfg.Providers[1].Name != "b-forked" || cfg.Providers[1].URL != "http://127.0.0.1:2" || cfg.Providers[1].Type != "self_hosted" {
+		t.Fatalf("providers = %+v", cfg.Providers)
+	}
+}
@@ -14,6 +14,7 @@
 //   monitor                  ← Refresh every 30s (default from config)
 //   monitor --interval 10s   ← Refresh every 10 seconds
 //   monitor --interval 5s    ← Refresh every 5 seconds (aggressive)
+//   monitor --ws             ← Also subscribe to newHeads over each ws_url
 //
 // EXECUTION FLOW
 // ==============
@@ -22,14 +23,17 @@
 //      │
 //      ├─ config.LoadEnv()          ← Load .env file
 //      ├─ flag.Parse()              ← Parse --config, --interval flags
-//      ├─ config.Load(*cfgPath)     ← Read providers.yaml
+//      ├─ flags.Setup()             ← Load config; --transport, --record/--replay, chain check (internal/cli)
 //      └─ runMonitor(cfg, interval) ← Start the monitoring loop
 //           │
 //           ├─ Set up cancellable context
 //           ├─ Set up signal handler (Ctrl+C → cancel)
+//           ├─ --ws? startPush() ← one newHeads subscription per ws_url
 //           ├─ Create ticker (fires every N seconds)
 //           │
 //           ├─ Initial fetch + display (immediate first render)
+//           │    per provider: eth_blockNumber (timed), then the
+//           │    "safe" and "finalized" heads
 //           │
 //           └─ Event loop (for { select { ... } }):
 //               │
@@ -119,6 +123,7 @@ import (
 
 	"golang.org/x/sync/errgroup"
 
+	"github.com/dando385/eth-rpc-monitor/internal/cli"
 	"github.com/dando385/eth-rpc-monitor/internal/config"
 	"github.com/dando385/eth-rpc-monitor/internal/format"
 	"github.com/dando385/eth-rpc-monitor/internal/rpc"
@@ -129,7 +134,7 @@ import (
 // =============================================================================
 
 // fetchAllProviders queries every configured provider concurrently and returns
-// their block heights and latencies.
+// their block heights (latest, safe and finalized) and latencies.
 //
 // This function is called ONCE PER CYCLE in the monitoring loop. Each call
 // represents one "frame" of the dashboard — a snapshot of all providers at
@@ -147,6 +152,9 @@ import (
 //     to know which providers to query. The pointer avoids copying the
 //     Config struct (which contains a slice of providers) on every call.
 //
+//   - clients []*rpc.Client: one client per provider, index-aligned with
+//     cfg.Providers, created once by runMonitor and reused every cycle.
+//
 // CONCURRENCY MODEL
 // =================
 // Same pattern as selectFastestProvider in cmd/block/main.go:
@@ -155,10 +163,13 @@ import (
 //   - sync.Mutex protects writes to the shared results slice
 //   - g.Wait() blocks until all goroutines complete
 //
-// Each goroutine creates its OWN rpc.Client. This is deliberate:
-//   - No connection reuse across cycles (simpler, no stale state)
-//   - Each measurement includes connection setup (realistic latency)
-//   - No shared mutable state between providers
+// The clients live for the whole session, so the transport policy decides
+// what a tick costs (see rpc/transport.go):
+//   - cold (the default): keep-alive is off, so every tick dials, and its
+//     latency includes DNS, TCP and TLS — the realistic cost of a poller
+//   - warm/http1/http2: ticks reuse the pooled connection, as a long-lived
+//     service with keep-alive would
+//   - Either way, no client is shared between providers
 //
 // LOOP VARIABLE SHADOWING: i, p := i, p
 // =======================================
@@ -166,7 +177,7 @@ import (
 // the "loop variable captured by func literal" bug. Each goroutine gets
 // its own copy of i and p. See cmd/block/main.go for the detailed memory
 // walkthrough.
-func fetchAllProviders(ctx context.Context, cfg *config.Config) []format.WatchResult {
+func fetchAllProviders(ctx context.Context, cfg *config.Config, clients []*rpc.Client) []format.WatchResult {
 	results := make([]format.WatchResult, len(cfg.Providers))
 	var mu sync.Mutex
 
@@ -175,15 +186,15 @@ func fetchAllProviders(ctx context.Context, cfg *config.Config) []format.WatchRe
 	for i, p := range cfg.Providers {
 		i, p := i, p // Shadow loop variables for goroutine safety
 		g.Go(func() error {
-			// Create a fresh client for this provider.
-			// rpc.NewClient returns *rpc.Client — a pointer to the heap-
-			// allocated Client struct. See rpc/client.go for details.
-			client := rpc.NewClient(p.Name, p.URL, p.Timeout)
+			client := clients[i]
 
 			// Query the provider's latest block number.
 			// gctx carries cancellation — if the context is cancelled
 			// (user pressed Ctrl+C), this HTTP request aborts immediately.
-			height, latency, err := client.BlockNumber(gctx)
+			// WithTiming records the DNS/connect/TLS/TTFB/body breakdown, so
+			// the dashboard can show what each tick's latency is made of.
+			var timing rpc.Timing
+			height, latency, err := client.BlockNumber(rpc.WithTiming(gctx, &timing))
 
 			// Build the result struct with all collected data.
 			// If err is non-nil, height and latency are zero values,
@@ -192,9 +203,19 @@ func fetchAllProviders(ctx context.Context, cfg *config.Config) []format.WatchRe
 				Provider:    p.Name,
 				BlockHeight: height,
 				Latency:     latency,
+				Timing:      timing,
 				Error:       err,
 			}
 
+			// The safe and finalized heads come from two more calls,
+			// made only when the provider answered at all. They are not
+			// timed: Latency and the phase columns stay eth_blockNumber's.
+			// A tag the node rejects shows as n/a without failing the row.
+			if err == nil {
+				r.Safe.Height, _, r.Safe.Error = client.BlockNumberByTag(gctx, "safe")
+				r.Finalized.Height, _, r.Finalized.Error = client.BlockNumberByTag(gctx, "finalized")
+			}
+
 			// Write to the shared results slice under mutex protection.
 			mu.Lock()
 			results[i] = r
@@ -208,7 +229,145 @@ func fetchAllProviders(ctx context.Context, cfg *config.Config) []format.WatchRe
 }
 
 // =============================================================================
-// SECTION 2: The Monitoring Loop — Event-Driven Dashboard Refresh
+// SECTION 2: Push Tracking — WebSocket newHeads Alongside Polling
+// =============================================================================
+//
+// With --ws, every provider that has a ws_url gets a long-lived WebSocket
+// subscription for the whole monitor session. Heads arrive asynchronously,
+// in their own goroutines, while the ticker keeps polling as before. The
+// headTracker is the meeting point: subscription goroutines WRITE into it
+// whenever a head arrives, and each dashboard refresh READS a snapshot.
+//
+//   alchemy ws ──▶ head #100 @ t=0.00s ─┐
+//   infura  ws ──▶ head #100 @ t=0.31s ─┼──▶ headTracker (mutex)
+//   publicnode ws ▶ head #100 @ t=0.12s ─┘        │
+//                                                 ▼  every tick
+//                              WatchResult.Push = {Height, Delay, Age}
+//
+// "Delay" is relative to firstSeen[height] — the moment ANY provider
+// delivered that height — so the first provider shows 0ms and the rest show
+// how far behind they pushed the same block.
+// =============================================================================
+
+// headTracker records pushed heads from all providers.
+type headTracker struct {
+	mu        sync.Mutex
+	firstSeen map[uint64]time.Time     // Earliest arrival of each height across providers
+	latest    map[string]rpc.Head      // Latest head per provider
+	delay     map[string]time.Duration // Delay of that latest head behind firstSeen
+	errs      map[string]error         // Connect/subscribe failure per provider
+	clients   map[string]*rpc.WSClient // For reconnect counts
+}
+
+func newHeadTracker() *headTracker {
+	return &headTracker{
+		firstSeen: make(map[uint64]time.Time),
+		latest:    make(map[string]rpc.Head),
+		delay:     make(map[string]time.Duration),
+		errs:      make(map[string]error),
+		clients:   make(map[string]*rpc.WSClient),
+	}
+}
+
+// record stores one arrival. firstSeen is pruned to the most recent 256
+// heights so a long-running monitor doesn't grow without bound.
+func (t *headTracker) record(provider string, h rpc.Head) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	first, ok := t.firstSeen[h.Number]
+	if !ok || h.Received.Before(first) {
+		t.firstSeen[h.Number] = h.Received
+		first = h.Received
+	}
+	t.latest[provider] = h
+	t.delay[provider] = h.Received.Sub(first)
+
+	if h.Number > 256 {
+		for n := range t.firstSeen {
+			if n < h.Number-256 {
+				delete(t.firstSeen, n)
+			}
+		}
+	}
+}
+
+// status builds the dashboard view for one provider, or nil if the provider
+// has no subscription (no ws_url configured).
+func (t *headTracker) status(provider string, now time.Time) *format.PushStatus {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	client, subscribed := t.clients[provider]
+	if err := t.errs[provider]; err != nil {
+		return &format.PushStatus{Error: err}
+	}
+	if !subscribed {
+		return nil
+	}
+	st := &format.PushStatus{Reconnects: client.Reconnects()}
+	st.StrayIDs, _ = client.IDMismatches()
+	if h, ok := t.latest[provider]; ok {
+		st.Height = h.Number
+		st.Delay = t.delay[provider]
+		st.Age = now.Sub(h.Received)
+	}
+	return st
+}
+
+// startPush connects and subscribes for every provider with a ws_url. Each
+// subscription is consumed by its own goroutine until ctx is cancelled.
+// Connection failures are recorded (and shown on the dashboard) rather than
+// aborting the monitor — push is an addition to polling, not a replacement.
+func startPush(ctx context.Context, cfg *config.Config) *headTracker {
+	tracker := newHeadTracker()
+
+	for _, p := range cfg.Providers {
+		if p.WSURL == "" {
+			continue
+		}
+		p := p
+		client := rpc.NewWSClient(p.Name, p.WSURL, p.Timeout, p.ClientOptions()...)
+		tracker.mu.Lock()
+		tracker.clients[p.Name] = client
+		tracker.mu.Unlock()
+
+		go func() {
+			defer client.Close()
+
+			fail := func(err error) {
+				tracker.mu.Lock()
+				tracker.errs[p.Name] = err
+				tracker.mu.Unlock()
+			}
+			if err := client.Connect(ctx); err != nil {
+				fail(err)
+				return
+			}
+			sub, err := client.SubscribeNewHeads(ctx)
+			if err != nil {
+				fail(err)
+				return
+			}
+
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case h, ok := <-sub.Heads():
+					if !ok {
+						return
+					}
+					tracker.record(p.Name, h)
+				}
+			}
+		}()
+	}
+	return tracker
+}
+
+// =============================================================================
+// SECTION 3: The Monitoring Loop — Event-Driven Dashboard Refresh
 // =============================================================================
 
 // runMonitor starts the continuous monitoring loop and handles graceful shutdown.
@@ -230,7 +389,12 @@ func fetchAllProviders(ctx context.Context, cfg *config.Config) []format.WatchRe
 // The Config is never modified after loading — it's effectively immutable
 // during the monitor's lifetime. Using a pointer here is primarily for
 // consistency and efficiency.
-func runMonitor(cfg *config.Config, intervalOverride time.Duration) error {
+//
+// PARAMETER: ws bool
+// ==================
+// When true, startPush() opens newHeads subscriptions before the first
+// fetch, and every frame attaches each provider's push status to its row.
+func runMonitor(cfg *config.Config, intervalOverride time.Duration, ws bool) error {
 	// Determine the polling interval.
 	// Flag override takes precedence over the config default.
 	interval := cfg.Defaults.WatchInterval
@@ -336,6 +500,15 @@ func runMonitor(cfg *config.Config, intervalOverride time.Duration) error {
 	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
+	// --- Push Subscriptions (--ws) ---
+	//
+	// Started once for the whole session (not per tick): the point of a
+	// subscription is that it stays open and the node pushes to us.
+	var tracker *headTracker
+	if ws {
+		tracker = startPush(ctx, cfg)
+	}
+
 	// --- Display Logic ---
 	//
 	// CLOSURE: displayResults captures `firstDisplay` by reference.
@@ -356,7 +529,35 @@ func runMonitor(cfg *config.Config, intervalOverride time.Duration) error {
 	// the simplest solution — no struct definition, no constructor, just a
 	// function that remembers one variable.
 	firstDisplay := true
+
+	// One client per provider for the whole session. Under the cold policy
+	// each still dials on every tick; warm policies keep their pool.
+	clients := make([]*rpc.Client, len(cfg.Providers))
+	for i, p := range cfg.Providers {
+		clients[i] = rpc.NewClient(p.Name, p.URL, p.Timeout, p.ClientOptions()...)
+	}
+
+	// failures accumulates each provider's failures by category across the
+	// whole session. Only this goroutine touches it (fetches have finished
+	// by the time displayResults runs), so it needs no mutex.
+	failures := make(map[string]format.ErrorCounts)
+
 	displayResults := func(results []format.WatchResult) {
+		for i := range results {
+			c, ok := failures[results[i].Provider]
+			if !ok {
+				c = format.ErrorCounts{}
+				failures[results[i].Provider] = c
+			}
+			c.Add(results[i].Error)
+			results[i].Failures = c
+		}
+		if tracker != nil {
+			now := time.Now()
+			for i := range results {
+				results[i].Push = tracker.status(results[i].Provider, now)
+			}
+		}
 		format.FormatMonitor(os.Stdout, results, interval, !firstDisplay)
 		firstDisplay = false
 	}
@@ -365,7 +566,7 @@ func runMonitor(cfg *config.Config, intervalOverride time.Duration) error {
 	//
 	// Perform the first data fetch IMMEDIATELY (don't wait for the first tick).
 	// This gives the user instant feedback when they start the monitor.
-	results := fetchAllProviders(ctx, cfg)
+	results := fetchAllProviders(ctx, cfg, clients)
 	displayResults(results)
 
 	// --- Event Loop ---
@@ -413,14 +614,14 @@ func runMonitor(cfg *config.Config, intervalOverride time.Duration) error {
 			// Fetch fresh data from all providers and update the display.
 			// The `results` here is a NEW local variable (`:=`), shadowing
 			// the outer `results`. Each cycle gets its own fresh slice.
-			results := fetchAllProviders(ctx, cfg)
+			results := fetchAllProviders(ctx, cfg, clients)
 			displayResults(results)
 		}
 	}
 }
 
 // =============================================================================
-// SECTION 3: Entry Point
+// SECTION 4: Entry Point
 // =============================================================================
 //
 // main() follows the same pattern as the other commands:
@@ -445,21 +646,45 @@ func main() {
 	config.LoadEnv()
 
 	var (
-		cfgPath  = flag.String("config", "config/providers.yaml", "Config file path")
+		flags    = cli.Register(flag.CommandLine, "")
 		interval = flag.Duration("interval", 0, "Refresh interval (0 = use config default)")
+		ws       = flag.Bool("ws", false, "Also subscribe to newHeads over each provider's ws_url")
 	)
 
 	flag.Parse()
 
-	// *cfgPath dereferences the *string pointer to get the config file path.
-	cfg, err := config.Load(*cfgPath)
+	// Cassettes cover rpc.Client only; a replay with live subscriptions
+	// would mix recorded heads with today's.
+	if *ws && *flags.Replay != "" {
+		fmt.Fprintln(os.Stderr, "Error: --replay cannot be combined with --ws")
+		os.Exit(1)
+	}
+
+	// Load the config and apply --transport, --record/--replay and the
+	// chain check (internal/cli).
+	cfg, cassette, err := flags.Setup("monitor")
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
+	defer cassette.Close()
+
+	if *ws {
+		hasWS := false
+		for _, p := range cfg.Providers {
+			if p.WSURL != "" {
+				hasWS = true
+				break
+			}
+		}
+		if !hasWS {
+			fmt.Fprintln(os.Stderr, "Error: --ws requires at least one provider with ws_url in the config")
+			os.Exit(1)
+		}
+	}
 
 	// *interval dereferences the *time.Duration pointer to get the duration value.
-	if err := runMonitor(cfg, *interval); err != nil {
+	if err := runMonitor(cfg, *interval, *ws); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
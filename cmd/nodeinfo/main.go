@@ -0,0 +1,221 @@
+// =============================================================================
+// FILE: cmd/nodeinfo/main.go
+// ROLE: Node Metadata Probe — What Is Each Provider Actually Running?
+// =============================================================================
+//
+// SYSTEM CONTEXT
+// ==============
+// This is the entry point for the `nodeinfo` command. The `type` field in
+// providers.yaml is whatever we typed in; this command asks the nodes
+// themselves. Every provider is asked for:
+//
+//   web3_clientVersion  → execution client and version (geth, nethermind,
+//                         erigon, reth, besu)
+//   eth_chainId         → chain the node signs for
+//   net_version         → p2p network it is on
+//   eth_syncing         → whether it is still catching up
+//   net_peerCount       → how well connected it is
+//
+// Usage examples:
+//   nodeinfo                ← Table plus client diversity summary
+//   nodeinfo --json         ← Export the same data to reports/nodeinfo-*.json
+//
+// EXECUTION FLOW
+// ==============
+//
+//   1. main()
+//      ├─ config.LoadEnv(), flag.Parse()
+//      ├─ flags.Setup()        ← Load config; --transport, --record/--replay, chain check (internal/cli)
+//      └─ runNodeInfo()
+//           │
+//           ├─ Fan out (errgroup, same pattern as cmd/snapshot):
+//           │   client.NodeInfo() per provider
+//           │     └─ each method may fail on its own (net_* is often
+//           │        disabled on hosted endpoints); see rpc/node.go
+//           │
+//           └─ Output:
+//               ├─ --json? → buildReport() → reportjson.Write()
+//               └─ Terminal? → format.FormatNodeInfo()
+// =============================================================================
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/dando385/eth-rpc-monitor/internal/cli"
+	"github.com/dando385/eth-rpc-monitor/internal/config"
+	"github.com/dando385/eth-rpc-monitor/internal/format"
+	"github.com/dando385/eth-rpc-monitor/internal/reportjson"
+	"github.com/dando385/eth-rpc-monitor/internal/rpc"
+)
+
+// =============================================================================
+// SECTION 1: JSON Report Types
+// =============================================================================
+
+// NodeInfoReport is the top-level JSON structure for `nodeinfo --json`.
+type NodeInfoReport struct {
+	Timestamp time.Time             `json:"timestamp"`
+	Providers []NodeInfoEntry       `json:"providers"`
+	Diversity []ClientDiversityJSON `json:"client_diversity"`
+}
+
+// NodeInfoEntry is one provider's metadata. Fields whose method failed are
+// omitted and the failure is listed under "errors" by method name.
+type NodeInfoEntry struct {
+	Name          string            `json:"name"`
+	Type          string            `json:"type"` // Configured type (informational)
+	LatencyMS     int64             `json:"latency_ms"`
+	ClientVersion string            `json:"client_version,omitempty"` // Raw web3_clientVersion
+	Client        string            `json:"client,omitempty"`         // "geth", ..., "unknown"
+	Version       string            `json:"version,omitempty"`
+	Platform      string            `json:"platform,omitempty"`
+	Runtime       string            `json:"runtime,omitempty"`
+	ChainID       *uint64           `json:"chain_id,omitempty"`
+	NetworkID     string            `json:"network_id,omitempty"`
+	Syncing       *bool             `json:"syncing,omitempty"`
+	CurrentBlock  uint64            `json:"current_block,omitempty"` // Only while syncing
+	HighestBlock  uint64            `json:"highest_block,omitempty"` // Only while syncing
+	PeerCount     *uint64           `json:"peer_count,omitempty"`
+	Error         string            `json:"error,omitempty"` // Whole probe failed
+	Errors        map[string]string `json:"errors,omitempty"`
+}
+
+// ClientDiversityJSON is one execution client's share of the providers.
+type ClientDiversityJSON struct {
+	Client    string   `json:"client"`
+	Count     int      `json:"count"`
+	Percent   float64  `json:"percent"`
+	Providers []string `json:"providers"`
+}
+
+// buildReport converts probe results to the JSON report.
+func buildReport(results []format.NodeInfoResult) NodeInfoReport {
+	report := NodeInfoReport{
+		Timestamp: time.Now(),
+		Providers: make([]NodeInfoEntry, len(results)),
+		Diversity: []ClientDiversityJSON{},
+	}
+
+	for i, r := range results {
+		e := NodeInfoEntry{Name: r.Provider, Type: r.Type, LatencyMS: r.Latency.Milliseconds()}
+		if r.Error != nil {
+			e.Error = r.Error.Error()
+			report.Providers[i] = e
+			continue
+		}
+		info := r.Info
+		if info.Err(rpc.MethodClientVersion) == nil {
+			v := info.Version
+			e.ClientVersion, e.Client, e.Version, e.Platform, e.Runtime = v.Raw, v.Client, v.Version, v.Platform, v.Runtime
+		}
+		if info.Err(rpc.MethodChainID) == nil {
+			id := info.ChainID
+			e.ChainID = &id
+		}
+		if info.Err(rpc.MethodNetVersion) == nil {
+			e.NetworkID = info.NetworkID
+		}
+		if info.Err(rpc.MethodSyncing) == nil {
+			syncing := info.Sync.Syncing
+			e.Syncing = &syncing
+			e.CurrentBlock, e.HighestBlock = info.Sync.CurrentBlock, info.Sync.HighestBlock
+		}
+		if info.Err(rpc.MethodPeerCount) == nil {
+			peers := info.PeerCount
+			e.PeerCount = &peers
+		}
+		for method, err := range info.Errors {
+			if e.Errors == nil {
+				e.Errors = make(map[string]string)
+			}
+			e.Errors[method] = err.Error()
+		}
+		report.Providers[i] = e
+	}
+
+	for _, s := range format.ClientDiversity(results) {
+		report.Diversity = append(report.Diversity, ClientDiversityJSON{
+			Client:    s.Client,
+			Count:     len(s.Providers),
+			Percent:   s.Percent,
+			Providers: s.Providers,
+		})
+	}
+	return report
+}
+
+// =============================================================================
+// SECTION 2: Main Logic
+// =============================================================================
+
+// runNodeInfo probes every provider concurrently and renders the results.
+func runNodeInfo(cfg *config.Config, jsonOut bool) error {
+	// Five sequential calls per provider, each with its own timeout.
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Defaults.Timeout*time.Duration(len(rpc.NodeInfoMethods)))
+	defer cancel()
+
+	if !jsonOut {
+		fmt.Printf("\nProbing node metadata from %d providers...\n\n", len(cfg.Providers))
+	}
+
+	results := make([]format.NodeInfoResult, len(cfg.Providers))
+	var mu sync.Mutex
+	g, gctx := errgroup.WithContext(ctx)
+	for i, p := range cfg.Providers {
+		i, p := i, p
+		g.Go(func() error {
+			client := rpc.NewClient(p.Name, p.URL, p.Timeout, p.ClientOptions()...)
+			info, latency, err := client.NodeInfo(gctx)
+			mu.Lock()
+			results[i] = format.NodeInfoResult{Provider: p.Name, Type: p.Type, Info: info, Latency: latency, Error: err}
+			mu.Unlock()
+			return nil
+		})
+	}
+	g.Wait()
+
+	if jsonOut {
+		filepath, err := reportjson.Write(buildReport(results), "nodeinfo")
+		if err != nil {
+			return fmt.Errorf("failed to write JSON report: %w", err)
+		}
+		fmt.Fprintf(os.Stderr, "JSON report written to: %s\n", filepath)
+		return nil
+	}
+
+	format.FormatNodeInfo(os.Stdout, results)
+	return nil
+}
+
+// =============================================================================
+// SECTION 3: Entry Point
+// =============================================================================
+
+func main() {
+	config.LoadEnv()
+
+	flags := cli.Register(flag.CommandLine, "")
+	jsonOut := flag.Bool("json", false, "Output JSON report to reports directory")
+	flag.Parse()
+
+	cfg, cassette, err := flags.Setup("nodeinfo")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	defer cassette.Close()
+
+	if err := runNodeInfo(cfg, *jsonOut); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dando385/eth-rpc-monitor/internal/format"
+	"github.com/dando385/eth-rpc-monitor/internal/rpc"
+)
+
+func TestBuildReport(t *testing.T) {
+	results := []format.NodeInfoResult{
+		{
+			Provider: "a",
+			Type:     "public",
+			Info: &rpc.NodeInfo{
+				Version:   rpc.ParseClientVersion("erigon/2.60.6/linux-amd64/go1.22.5"),
+				ChainID:   1,
+				NetworkID: "1",
+				Errors:    map[string]error{rpc.MethodPeerCount: errors.New("method not found")},
+			},
+		},
+		{Provider: "b", Type: "enterprise", Error: errors.New("timeout")},
+	}
+	report := buildReport(results)
+
+	a := report.Providers[0]
+	if a.Client != rpc.ClientErigon || a.Version != "2.60.6" || a.ChainID == nil || *a.ChainID != 1 {
+		t.Fatalf("a = %+v", a)
+	}
+	if a.Syncing == nil || *a.Syncing || a.PeerCount != nil || a.Errors[rpc.MethodPeerCount] == "" {
+		t.Fatalf("a sync/peers = %+v", a)
+	}
+	if b := report.Providers[1]; b.Error != "timeout" || b.Client != "" || b.ChainID != nil {
+		t.Fatalf("b = %+v", b)
+	}
+	if len(report.Diversity) != 1 || report.Diversity[0].Count != 1 || report.Diversity[0].Percent != 100 {
+		t.Fatalf("diversity = %+v", report.Diversity)
+	}
+}
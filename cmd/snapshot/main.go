@@ -15,6 +15,7 @@
 //   snapshot                ← Compare latest block across all providers
 //   snapshot 19000000       ← Compare a specific historical block
 //   snapshot latest         ← Explicit "latest" (same as no argument)
+//   snapshot finalized      ← Compare the finalized block (also: safe)
 //
 // EXECUTION FLOW
 // ==============
@@ -23,13 +24,13 @@
 //      │
 //      ├─ config.LoadEnv()              ← Load .env file
 //      ├─ flag.Parse()                  ← Parse --config flag
-//      ├─ config.Load(*cfgPath)         ← Read providers.yaml
+//      ├─ flags.Setup()                 ← Load config; --transport, --record/--replay, chain check (internal/cli)
 //      ├─ context.WithTimeout()         ← Create deadline for all operations
 //      │
 //      └─ For each provider (concurrently via errgroup):
 //          │
 //          ├─ rpc.NewClient()           ← Create provider client
-//          ├─ client.BlockNumber()      ← Warm-up (connection priming)
+//          ├─ client.WarmUp()           ← Warm-up (skipped when cold)
 //          ├─ client.GetBlock()         ← Fetch the target block
 //          │
 //          ├─ Extract hash and height from the block
@@ -39,10 +40,19 @@
 //      g.Wait()  ← Wait for all providers to finish
 //      format.FormatSnapshot()  ← Render comparison and detect mismatches
 //
+// WHY SNAPSHOT "finalized"?
+// =========================
+// Providers legitimately disagree on "latest" for a second or two while a
+// block propagates. They must NEVER disagree on "finalized": a hash
+// mismatch there means a provider is on a different chain or serving a
+// corrupt cache, and a height mismatch means one has stopped following
+// finality — which `monitor` also shows as lag in its Finalized column.
+//
 // ARCHITECTURAL SIMPLICITY
 // ========================
-// This is the simplest command in the suite — it's entirely contained in
-// main() with no helper functions. This is intentional: the logic is linear
+// This is the simplest command in the suite — apart from blockArgument(),
+// which turns the argument into an RPC block identifier, it's entirely
+// contained in main(). This is intentional: the logic is linear
 // enough that extracting functions would add indirection without improving
 // clarity. The key operations are:
 //   1. Configure and create context
@@ -70,10 +80,13 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
 	"sync"
 
 	"golang.org/x/sync/errgroup"
 
+	"github.com/dando385/eth-rpc-monitor/internal/cli"
 	"github.com/dando385/eth-rpc-monitor/internal/config"
 	"github.com/dando385/eth-rpc-monitor/internal/format"
 	"github.com/dando385/eth-rpc-monitor/internal/rpc"
@@ -114,29 +127,33 @@ func main() {
 	// Load .env file to make API keys available for URL expansion.
 	config.LoadEnv()
 
-	// Parse the --config flag. flag.String returns *string (a pointer).
-	// After flag.Parse(), *cfgPath contains the flag value or default.
-	cfgPath := flag.String("config", "config/providers.yaml", "Config file path")
+	// Register --config and the other flags every provider command shares
+	// (internal/cli). Each flag is a pointer that flag.Parse() fills in.
+	flags := cli.Register(flag.CommandLine, "")
 	flag.Parse()
 
-	// The first positional argument is the block identifier (default: "latest").
-	// Unlike cmd/block, snapshot doesn't normalize the argument (no decimal→hex
-	// conversion). If the user passes a decimal number, the Ethereum RPC will
-	// likely return an error. This is acceptable for a focused tool — use the
-	// `block` command for flexible block identification.
+	// The first positional argument is the block identifier (default:
+	// "latest"): a tag, or a decimal or 0x-hex number.
 	blockArg := "latest"
 	if args := flag.Args(); len(args) > 0 {
-		blockArg = args[0]
+		var err error
+		if blockArg, err = blockArgument(args[0]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
 	}
 
-	// Load the provider configuration.
-	// *cfgPath dereferences the pointer to get the actual string value.
-	// config.Load returns *config.Config — see config.go for details.
-	cfg, err := config.Load(*cfgPath)
+	// Load the provider configuration and apply the shared flags: the
+	// connection policy, any cassette, and the chain check. Dropping
+	// providers on the wrong chain before comparing anything matters here:
+	// a Sepolia URL in a mainnet list would otherwise show up as a hash
+	// mismatch. See internal/cli for the steps.
+	cfg, cassette, err := flags.Setup("snapshot")
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
+	defer cassette.Close()
 
 	// --- Step 2: Create Timeout Context ---
 	//
@@ -184,13 +201,14 @@ func main() {
 		g.Go(func() error {
 			// Create a client for this provider.
 			// rpc.NewClient returns *rpc.Client (a pointer).
-			client := rpc.NewClient(p.Name, p.URL, p.Timeout)
+			client := rpc.NewClient(p.Name, p.URL, p.Timeout, p.ClientOptions()...)
 
 			// WARM-UP CALL: Prime the HTTP connection.
 			// The result is discarded — this is purely to establish the
 			// TCP/TLS connection so the GetBlock call below measures only
-			// RPC latency, not connection setup overhead.
-			client.BlockNumber(gctx)
+			// RPC latency, not connection setup overhead. Skipped under
+			// the cold transport policy (see rpc/transport.go).
+			client.WarmUp(gctx)
 
 			// Fetch the target block from this provider.
 			// client.GetBlock returns (*rpc.Block, time.Duration, error).
@@ -231,6 +249,19 @@ func main() {
 				// rpc.ParseHexUint64 converts the hex block number to uint64.
 				// The _ discards the error (see types.go for rationale).
 				r.Height, _ = rpc.ParseHexUint64(block.Number)
+
+				// Recompute the hash from the header fields this provider
+				// returned; a provider whose header does not hash to its
+				// own "hash" is flagged under the table.
+				check := rpc.VerifyBlockHash(block)
+				r.HashCheck = &check
+
+				// Keep the parsed block so withdrawals, blob gas and the
+				// other later-fork fields can be compared across providers.
+				// ParsedOn prices the blob fee under the configured
+				// chain's fork timetable.
+				parsed := block.ParsedOn(cfg.Chain.ID)
+				r.Block = &parsed
 			}
 
 			// Write the result to the shared slice under mutex protection.
@@ -253,3 +284,28 @@ func main() {
 	// See internal/format/snapshot.go for the rendering and analysis logic.
 	format.FormatSnapshot(os.Stdout, results)
 }
+
+// blockArgument turns the positional argument into an RPC block
+// identifier: tags pass through, decimal heights become 0x-hex. Unlike
+// cmd/block's normalizeBlockArg it rejects anything else up front, since
+// every provider would otherwise fail with the same RPC error.
+func blockArgument(arg string) (string, error) {
+	arg = strings.TrimSpace(strings.ToLower(arg))
+	switch arg {
+	case "", "latest":
+		return "latest", nil
+	case "pending", "earliest", "safe", "finalized":
+		return arg, nil
+	}
+	if strings.HasPrefix(arg, "0x") {
+		if _, err := rpc.ParseHexUint64(arg); err != nil {
+			return "", fmt.Errorf("invalid block %q: %w", arg, err)
+		}
+		return arg, nil
+	}
+	n, err := strconv.ParseUint(arg, 10, 64)
+	if err != nil {
+		return "", fmt.Errorf("invalid block %q: want latest, safe, finalized, pending, earliest or a number", arg)
+	}
+	return fmt.Sprintf("0x%x", n), nil
+}
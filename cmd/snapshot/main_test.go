@@ -0,0 +1,27 @@
+package main
+
+import "testing"
+
+func TestBlockArgument(t *testing.T) {
+	tests := []struct {
+		in, want string
+		wantErr  bool
+	}{
+		{"", "latest", false},
+		{"LATEST", "latest", false},
+		{"finalized", "finalized", false},
+		{" Safe ", "safe", false},
+		{"pending", "pending", false},
+		{"earliest", "earliest", false},
+		{"19000000", "0x121eac0", false},
+		{"0x121EAC0", "0x121eac0", false},
+		{"0xzz", "", true},
+		{"finalised", "", true},
+	}
+	for _, tc := range tests {
+		got, err := blockArgument(tc.in)
+		if (err != nil) != tc.wantErr || got != tc.want {
+			t.Fatalf("blockArgument(%q) = %q, %v", tc.in, got, err)
+		}
+	}
+}
@@ -14,6 +14,8 @@
 //   test                  ← 30 samples per provider (default from config)
 //   test --samples 10     ← 10 samples per provider (quick check)
 //   test --json           ← Export detailed report with raw latency data
+//   test --batch 1,10,100 ← Measure JSON-RPC batch round-trips at each size
+//   test --transport all  ← Same samples under cold, warm, http1 and http2
 //
 // EXECUTION FLOW
 // ==============
@@ -22,7 +24,7 @@
 //      │
 //      ├─ config.LoadEnv()          ← Load .env file
 //      ├─ flag.Parse()              ← Parse --config, --samples, --json flags
-//      ├─ config.Load(*cfgPath)     ← Read providers.yaml
+//      ├─ flags.Setup()             ← Load config; --transport, --record/--replay, chain check (internal/cli)
 //      └─ runTest(cfg, ...)         ← Execute the health check
 //           │
 //           ├─ For each provider (concurrently via errgroup):
@@ -39,6 +41,10 @@
 //               ├─ --json? → Build TestReport → reportjson.Write()
 //               └─ Terminal? → format.FormatTest()
 //
+//      --batch mode replaces testProvider with testBatch (SECTION 4), which
+//      sends every sample as a JSON-RPC batch of each requested size.
+//      A --transport list (SECTION 5) runs testProvider once per policy.
+//
 // CS CONCEPTS IN THIS FILE
 // =========================
 // 1. SAMPLING: Why N measurements are better than 1, and how to choose N
@@ -74,14 +80,18 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	"golang.org/x/sync/errgroup"
 
+	"github.com/dando385/eth-rpc-monitor/internal/cli"
 	"github.com/dando385/eth-rpc-monitor/internal/config"
 	"github.com/dando385/eth-rpc-monitor/internal/format"
 	"github.com/dando385/eth-rpc-monitor/internal/reportjson"
@@ -130,6 +140,62 @@ type TestReportEntry struct {
 	MaxLatencyMS int64   `json:"max_latency_ms"` // Maximum observed latency in ms
 	BlockHeight  uint64  `json:"block_height"`   // Last observed block height
 	LatenciesMS  []int64 `json:"latencies_ms"`   // All raw latency samples in ms
+
+	// IDMismatches counts replies whose id answered some other request — a
+	// provider correctness problem, not a reliability one (see rpc/ids.go).
+	// These are also included in Errors under "protocol".
+	IDMismatches int `json:"id_mismatches"`
+
+	// Errors counts failed samples by category ("timeout", "rate-limited",
+	// ...; see rpc.Classify). Omitted when every sample succeeded.
+	Errors map[string]int `json:"errors,omitempty"`
+
+	// Samples is the per-sample phase breakdown, in the same order as
+	// LatenciesMS. Phases are in microseconds because DNS and body reads are
+	// routinely sub-millisecond and would round to 0 in ms.
+	Samples []SampleTiming `json:"samples"`
+}
+
+// SampleTiming is one successful sample's phase breakdown (see rpc.Timing).
+// A phase that did not happen (e.g. DNS on a reused connection) is 0.
+type SampleTiming struct {
+	LatencyMS int64 `json:"latency_ms"` // Same value as the matching latencies_ms entry
+	DNSUS     int64 `json:"dns_us"`     // Name resolution
+	ConnectUS int64 `json:"connect_us"` // TCP connect
+	TLSUS     int64 `json:"tls_us"`     // TLS handshake
+	TTFBUS    int64 `json:"ttfb_us"`    // Request written → first response byte
+	BodyUS    int64 `json:"body_us"`    // First byte → body fully read
+	QueueUS   int64 `json:"queue_us"`   // Waiting for the provider's rate limit (not in latency_ms)
+	Reused    bool  `json:"reused"`     // Connection came from the keep-alive pool
+}
+
+// BatchReport is the top-level JSON structure for `test --batch` reports.
+//
+// It mirrors TestReport, but each entry describes one provider at one batch
+// size rather than one provider overall.
+type BatchReport struct {
+	Timestamp time.Time          `json:"timestamp"`   // When the test was run
+	Samples   int                `json:"samples"`     // Batches sent per provider per size
+	Sizes     []int              `json:"batch_sizes"` // Batch sizes that were measured
+	Results   []BatchReportEntry `json:"results"`     // Per-provider, per-size results
+}
+
+// BatchReportEntry holds one provider's batch statistics at one size.
+type BatchReportEntry struct {
+	Name            string  `json:"name"`                 // Provider name
+	BatchSize       int     `json:"batch_size"`           // Requests per batch
+	Success         int     `json:"success"`              // Fully successful batches
+	Partial         int     `json:"partial"`              // Batches with element-level errors
+	Rejected        int     `json:"rejected"`             // Batches refused as a whole
+	Total           int     `json:"total"`                // Batches attempted
+	P50LatencyMS    int64   `json:"p50_latency_ms"`       // 50th percentile batch latency in ms
+	P95LatencyMS    int64   `json:"p95_latency_ms"`       // 95th percentile batch latency in ms
+	P99LatencyMS    int64   `json:"p99_latency_ms"`       // 99th percentile batch latency in ms
+	MaxLatencyMS    int64   `json:"max_latency_ms"`       // Maximum batch latency in ms
+	PerRequestP50US int64   `json:"per_request_p50_us"`   // P50 / batch size, in microseconds
+	LastError       string  `json:"last_error,omitempty"` // Most recent error, if any
+	IDMismatches    int     `json:"id_mismatches"`        // Elements answered with a foreign response id
+	LatenciesMS     []int64 `json:"latencies_ms"`         // Raw batch latencies in ms
 }
 
 // =============================================================================
@@ -186,6 +252,9 @@ func testProvider(client *rpc.Client, p config.Provider, samples int) format.Tes
 	// append() will allocate the underlying array on first use.
 	// We don't pre-allocate because we don't know how many will succeed.
 	var latencies []time.Duration
+	var timings []rpc.Timing
+	errs := format.ErrorCounts{}
+	idMismatches := 0
 	var lastHeight uint64
 	success := 0
 
@@ -193,14 +262,18 @@ func testProvider(client *rpc.Client, p config.Provider, samples int) format.Tes
 	fmt.Fprintf(os.Stderr, "\n[%s] Testing with %d samples...\n", p.Name, samples)
 
 	// WARM-UP CALL: Prime the HTTP connection.
-	// client.BlockNumber(ctx) makes one RPC call whose result is discarded.
+	// client.WarmUp(ctx) makes one RPC call whose result is discarded.
 	// This ensures the TCP connection and TLS handshake are done before
 	// we start measuring, isolating steady-state latency from setup overhead.
-	client.BlockNumber(ctx)
+	// A cold client skips it: every sample dials anew anyway.
+	client.WarmUp(ctx)
 
 	// SAMPLE LOOP: Collect N latency measurements.
 	for i := 0; i < samples; i++ {
-		height, latency, err := client.BlockNumber(ctx)
+		// Attach a fresh Timing per sample so the phase breakdown of THIS
+		// call (and only this call) is recorded — see rpc/trace.go.
+		var timing rpc.Timing
+		height, latency, err := client.BlockNumber(rpc.WithTiming(ctx, &timing))
 		if err == nil {
 			success++
 			// append() adds the latency to the slice, growing the underlying
@@ -208,12 +281,28 @@ func testProvider(client *rpc.Client, p config.Provider, samples int) format.Tes
 			// when the array is full, it allocates a new one at 2x capacity
 			// and copies existing elements. This gives O(1) amortized appends.
 			latencies = append(latencies, latency)
+			timings = append(timings, timing)
 			lastHeight = height
 			// Log each successful sample to stderr.
-			// The format "  alchemy 1/30: 23ms" shows provider, progress, and latency.
-			fmt.Fprintf(os.Stderr, "  %s %d/%d: %dms\n", p.Name, i+1, samples, latency.Milliseconds())
+			// The format "  alchemy 1/30: 23ms (ttfb 21ms, reused)" shows
+			// provider, progress, latency, and the server-side share of it.
+			conn := "reused"
+			if !timing.Reused {
+				conn = fmt.Sprintf("new conn %dms", (timing.DNS + timing.Connect + timing.TLS).Milliseconds())
+			}
+			// Time held back by the provider's rate limit is not latency;
+			// show it beside the sample rather than inside it.
+			if timing.Queue > 0 {
+				conn += fmt.Sprintf(", queued %dms", timing.Queue.Milliseconds())
+			}
+			fmt.Fprintf(os.Stderr, "  %s %d/%d: %dms (ttfb %dms, %s)\n",
+				p.Name, i+1, samples, latency.Milliseconds(), timing.TTFB.Milliseconds(), conn)
 		} else {
-			fmt.Fprintf(os.Stderr, "  %s %d/%d: ERROR - %v\n", p.Name, i+1, samples, err)
+			errs.Add(err)
+			if isIDMismatch(err) {
+				idMismatches++
+			}
+			fmt.Fprintf(os.Stderr, "  %s %d/%d: ERROR - %s\n", p.Name, i+1, samples, format.ErrorLabel(err))
 		}
 
 		// INTER-SAMPLE DELAY: Sleep 200ms between samples.
@@ -242,10 +331,21 @@ func testProvider(client *rpc.Client, p config.Provider, samples int) format.Tes
 		Success:     success,
 		Total:       samples,
 		Latencies:   latencies,
+		Timings:     timings,
+		Errors:      errs,
+		IDMismatch:  idMismatches,
 		BlockHeight: lastHeight,
 	}
 }
 
+// isIDMismatch reports whether err (possibly wrapped) is a response id
+// mismatch. errors.As walks the wrap chain and, on a match, stores the
+// concrete *rpc.IDMismatchError in mm — we only need the yes/no answer.
+func isIDMismatch(err error) bool {
+	var mm *rpc.IDMismatchError
+	return errors.As(err, &mm)
+}
+
 // =============================================================================
 // SECTION 3: Test Orchestration — Running All Providers Concurrently
 // =============================================================================
@@ -302,7 +402,7 @@ func runTest(cfg *config.Config, samplesOverride int, jsonOut bool) error {
 		i, p := i, p // Shadow loop variables for goroutine safety
 		g.Go(func() error {
 			// Each goroutine creates its own client for the provider.
-			client := rpc.NewClient(p.Name, p.URL, p.Timeout)
+			client := rpc.NewClient(p.Name, p.URL, p.Timeout, p.ClientOptions()...)
 			result := testProvider(client, p, samples)
 
 			// Write the result to the shared slice under mutex protection.
@@ -342,6 +442,19 @@ func runTest(cfg *config.Config, samplesOverride int, jsonOut bool) error {
 			for j, lat := range r.Latencies {
 				latenciesMs[j] = lat.Milliseconds()
 			}
+			sampleTimings := make([]SampleTiming, len(r.Timings))
+			for j, t := range r.Timings {
+				sampleTimings[j] = SampleTiming{
+					LatencyMS: latenciesMs[j],
+					DNSUS:     t.DNS.Microseconds(),
+					ConnectUS: t.Connect.Microseconds(),
+					TLSUS:     t.TLS.Microseconds(),
+					TTFBUS:    t.TTFB.Microseconds(),
+					BodyUS:    t.Body.Microseconds(),
+					QueueUS:   t.Queue.Microseconds(),
+					Reused:    t.Reused,
+				}
+			}
 
 			// Re-compute percentiles for the JSON report.
 			// We compute them again (rather than storing from testProvider)
@@ -358,6 +471,14 @@ func runTest(cfg *config.Config, samplesOverride int, jsonOut bool) error {
 				MaxLatencyMS: tail.Max.Milliseconds(),
 				BlockHeight:  r.BlockHeight,
 				LatenciesMS:  latenciesMs,
+				IDMismatches: r.IDMismatch,
+				Samples:      sampleTimings,
+			}
+			if r.Errors.Total() > 0 {
+				reportData.Results[i].Errors = make(map[string]int, len(r.Errors))
+				for cat, n := range r.Errors {
+					reportData.Results[i].Errors[string(cat)] = n
+				}
 			}
 		}
 
@@ -375,7 +496,325 @@ func runTest(cfg *config.Config, samplesOverride int, jsonOut bool) error {
 }
 
 // =============================================================================
-// SECTION 4: Entry Point
+// SECTION 4: Batch Mode — Measuring JSON-RPC Batch Round-Trips
+// =============================================================================
+//
+// `test --batch 1,10,100` sends every sample as a JSON-RPC batch of
+// eth_blockNumber requests, once per listed size. Comparing rows for the
+// same provider shows:
+//
+//   - Whether batching helps at all (per-request cost should fall with size)
+//   - Where the provider's batch size limit sits (rows flip to Rejected)
+//   - Whether oversized batches degrade silently (rows show Partial)
+//
+// The same warm-up and 200ms spacing as single-request mode apply, so the
+// numbers are directly comparable with a plain `test` run.
+// =============================================================================
+
+// parseBatchSizes turns "1,10,100" into []int{1, 10, 100}.
+// Every entry must be a positive integer.
+func parseBatchSizes(s string) ([]int, error) {
+	var sizes []int
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		n, err := strconv.Atoi(part)
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("invalid batch size %q", part)
+		}
+		sizes = append(sizes, n)
+	}
+	if len(sizes) == 0 {
+		return nil, fmt.Errorf("no batch sizes in %q", s)
+	}
+	return sizes, nil
+}
+
+// testBatch sends `samples` batches of `size` eth_blockNumber requests to one
+// provider and classifies each batch as OK, partial, or rejected.
+func testBatch(client *rpc.Client, p config.Provider, size, samples int) format.BatchTestResult {
+	ctx := context.Background()
+	r := format.BatchTestResult{Name: p.Name, Size: size, Total: samples}
+
+	elems := make([]rpc.BatchElem, size)
+	for i := range elems {
+		elems[i] = rpc.BatchElem{Method: "eth_blockNumber"}
+	}
+
+	fmt.Fprintf(os.Stderr, "\n[%s] Testing batch size %d with %d samples...\n", p.Name, size, samples)
+
+	// WARM-UP CALL: same rationale as testProvider — keep connection setup
+	// out of the measured batches.
+	client.WarmUp(ctx)
+
+	for i := 0; i < samples; i++ {
+		results, latency, err := client.CallBatch(ctx, elems)
+		switch {
+		case err != nil:
+			r.Rejected++
+			r.LastError = format.ErrorLabel(err)
+			fmt.Fprintf(os.Stderr, "  %s x%d %d/%d: REJECTED - %v\n", p.Name, size, i+1, samples, err)
+		default:
+			r.Latencies = append(r.Latencies, latency)
+			failed := 0
+			for _, br := range results {
+				if br.Error != nil {
+					failed++
+					r.LastError = format.ErrorLabel(br.Error)
+					if isIDMismatch(br.Error) {
+						r.IDMismatch++
+					}
+				}
+			}
+			if failed == 0 {
+				r.Success++
+				fmt.Fprintf(os.Stderr, "  %s x%d %d/%d: %dms\n", p.Name, size, i+1, samples, latency.Milliseconds())
+			} else {
+				r.Partial++
+				fmt.Fprintf(os.Stderr, "  %s x%d %d/%d: %dms, %d/%d elements failed\n",
+					p.Name, size, i+1, samples, latency.Milliseconds(), failed, size)
+			}
+		}
+
+		if i < samples-1 {
+			time.Sleep(200 * time.Millisecond)
+		}
+	}
+	return r
+}
+
+// runBatchTest is the --batch counterpart of runTest: one goroutine per
+// provider, each walking through every batch size in order.
+//
+// Sizes run sequentially within a provider (not in parallel) so that a
+// large batch can't steal bandwidth or rate-limit budget from a small one
+// and skew the comparison.
+func runBatchTest(cfg *config.Config, samples int, sizes []int, jsonOut bool) error {
+	fmt.Printf("\nTesting %d providers with %d batch samples at sizes %v...\n\n", len(cfg.Providers), samples, sizes)
+
+	perProvider := make([][]format.BatchTestResult, len(cfg.Providers))
+	var mu sync.Mutex
+	g, _ := errgroup.WithContext(context.Background())
+
+	for i, p := range cfg.Providers {
+		i, p := i, p // Shadow loop variables for goroutine safety
+		g.Go(func() error {
+			client := rpc.NewClient(p.Name, p.URL, p.Timeout, p.ClientOptions()...)
+			rows := make([]format.BatchTestResult, 0, len(sizes))
+			for _, size := range sizes {
+				rows = append(rows, testBatch(client, p, size, samples))
+			}
+			mu.Lock()
+			perProvider[i] = rows
+			mu.Unlock()
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return fmt.Errorf("running batch tests: %w", err)
+	}
+
+	// Flatten in config order so rows for one provider stay together.
+	var results []format.BatchTestResult
+	for _, rows := range perProvider {
+		results = append(results, rows...)
+	}
+
+	if jsonOut {
+		report := BatchReport{
+			Timestamp: time.Now(),
+			Samples:   samples,
+			Sizes:     sizes,
+			Results:   make([]BatchReportEntry, len(results)),
+		}
+		for i, r := range results {
+			latenciesMs := make([]int64, len(r.Latencies))
+			for j, lat := range r.Latencies {
+				latenciesMs[j] = lat.Milliseconds()
+			}
+			tail := format.CalculateTailLatency(r.Latencies)
+			report.Results[i] = BatchReportEntry{
+				Name:            r.Name,
+				BatchSize:       r.Size,
+				Success:         r.Success,
+				Partial:         r.Partial,
+				Rejected:        r.Rejected,
+				Total:           r.Total,
+				P50LatencyMS:    tail.P50.Milliseconds(),
+				P95LatencyMS:    tail.P95.Milliseconds(),
+				P99LatencyMS:    tail.P99.Milliseconds(),
+				MaxLatencyMS:    tail.Max.Milliseconds(),
+				PerRequestP50US: r.PerRequestP50().Microseconds(),
+				LastError:       r.LastError,
+				IDMismatches:    r.IDMismatch,
+				LatenciesMS:     latenciesMs,
+			}
+		}
+		filepath, err := reportjson.Write(report, "batch")
+		if err != nil {
+			return fmt.Errorf("failed to write JSON report: %w", err)
+		}
+		fmt.Fprintf(os.Stderr, "JSON report written to: %s\n", filepath)
+		return nil
+	}
+
+	format.FormatBatchTest(os.Stdout, results)
+	return nil
+}
+
+// =============================================================================
+// SECTION 5: Transport Mode — The Same Provider Under Several Policies
+// =============================================================================
+//
+// `test --transport cold,warm,http1,http2` samples every provider once per
+// listed transport policy (see rpc/transport.go) and prints the results side
+// by side. The difference between the cold and warm rows is the handshake
+// overhead each call pays when a client does not keep connections alive;
+// the http1 and http2 rows show what forcing a protocol changes.
+//
+// Policies run sequentially within a provider, like batch sizes, so one
+// policy's handshakes never compete with another's samples.
+// =============================================================================
+
+// TransportReport is the top-level JSON structure for `test --transport`
+// reports with more than one policy.
+type TransportReport struct {
+	Timestamp time.Time              `json:"timestamp"` // When the test was run
+	Samples   int                    `json:"samples"`   // Samples per provider per policy
+	Policies  []rpc.Policy           `json:"policies"`  // Policies that were measured
+	Results   []TransportReportEntry `json:"results"`   // Per-provider, per-policy results
+}
+
+// TransportReportEntry holds one provider's statistics under one policy.
+type TransportReportEntry struct {
+	Name           string         `json:"name"`             // Provider name
+	Policy         rpc.Policy     `json:"policy"`           // Transport policy
+	Proto          string         `json:"proto"`            // Protocol most samples used ("" if none succeeded)
+	Success        int            `json:"success"`          // Successful sample count
+	Total          int            `json:"total"`            // Total sample count
+	P50LatencyMS   int64          `json:"p50_latency_ms"`   // 50th percentile in ms
+	P95LatencyMS   int64          `json:"p95_latency_ms"`   // 95th percentile in ms
+	P99LatencyMS   int64          `json:"p99_latency_ms"`   // 99th percentile in ms
+	MaxLatencyMS   int64          `json:"max_latency_ms"`   // Maximum observed latency in ms
+	NewConnections int            `json:"new_connections"`  // Samples that opened a connection
+	HandshakeP50US int64          `json:"handshake_p50_us"` // P50 of DNS+connect+TLS on those samples
+	LatenciesMS    []int64        `json:"latencies_ms"`     // All raw latency samples in ms
+	Errors         map[string]int `json:"errors,omitempty"` // Failed samples by category
+
+	// Skipped is why the policy was not run (e.g. "needs https" for http2
+	// on an http:// endpoint); the measurement fields are empty then.
+	Skipped string `json:"skipped,omitempty"`
+}
+
+// buildTransportReport converts the rendered results into the JSON report.
+func buildTransportReport(results []format.TransportTestResult, samples int, policies []rpc.Policy) TransportReport {
+	report := TransportReport{
+		Timestamp: time.Now(),
+		Samples:   samples,
+		Policies:  policies,
+		Results:   make([]TransportReportEntry, len(results)),
+	}
+	for i, r := range results {
+		latenciesMs := make([]int64, len(r.Latencies))
+		for j, lat := range r.Latencies {
+			latenciesMs[j] = lat.Milliseconds()
+		}
+		tail := format.CalculateTailLatency(r.Latencies)
+		if r.Skipped != "" {
+			report.Results[i] = TransportReportEntry{Name: r.Name, Policy: r.Policy, Skipped: r.Skipped}
+			continue
+		}
+		report.Results[i] = TransportReportEntry{
+			Name:           r.Name,
+			Policy:         r.Policy,
+			Proto:          r.Proto(),
+			Success:        r.Success,
+			Total:          r.Total,
+			P50LatencyMS:   tail.P50.Milliseconds(),
+			P95LatencyMS:   tail.P95.Milliseconds(),
+			P99LatencyMS:   tail.P99.Milliseconds(),
+			MaxLatencyMS:   tail.Max.Milliseconds(),
+			NewConnections: r.NewConns(),
+			HandshakeP50US: r.Handshake().Microseconds(),
+			LatenciesMS:    latenciesMs,
+		}
+		if r.Errors.Total() > 0 {
+			report.Results[i].Errors = make(map[string]int, len(r.Errors))
+			for cat, n := range r.Errors {
+				report.Results[i].Errors[string(cat)] = n
+			}
+		}
+	}
+	return report
+}
+
+// runTransportTest is the --transport counterpart of runTest: one goroutine
+// per provider, each running the full sample loop once per policy with a
+// fresh client built for that policy.
+func runTransportTest(cfg *config.Config, samples int, policies []rpc.Policy, jsonOut bool) error {
+	fmt.Printf("\nTesting %d providers with %d samples under transport policies %v...\n\n", len(cfg.Providers), samples, policies)
+
+	perProvider := make([][]format.TransportTestResult, len(cfg.Providers))
+	var mu sync.Mutex
+	g, _ := errgroup.WithContext(context.Background())
+
+	for i, p := range cfg.Providers {
+		i, p := i, p // Shadow loop variables for goroutine safety
+		g.Go(func() error {
+			rows := make([]format.TransportTestResult, 0, len(policies))
+			for _, policy := range policies {
+				// http2 cannot be negotiated without TLS; a row of
+				// network errors would blame the provider for it.
+				if reason := policy.Unsupported(p.URL); reason != "" {
+					fmt.Fprintf(os.Stderr, "\n[%s] Transport policy: %s skipped (%s)\n", p.Name, policy, reason)
+					rows = append(rows, format.TransportTestResult{
+						TestResult: format.TestResult{Name: p.Name},
+						Policy:     policy,
+						Skipped:    reason,
+					})
+					continue
+				}
+				fmt.Fprintf(os.Stderr, "\n[%s] Transport policy: %s\n", p.Name, policy)
+				client := rpc.NewClient(p.Name, p.URL, p.Timeout, p.ClientOptions(rpc.WithPolicy(policy))...)
+				rows = append(rows, format.TransportTestResult{
+					TestResult: testProvider(client, p, samples),
+					Policy:     policy,
+				})
+			}
+			mu.Lock()
+			perProvider[i] = rows
+			mu.Unlock()
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return fmt.Errorf("running transport tests: %w", err)
+	}
+
+	var results []format.TransportTestResult
+	for _, rows := range perProvider {
+		results = append(results, rows...)
+	}
+
+	if jsonOut {
+		filepath, err := reportjson.Write(buildTransportReport(results, samples, policies), "transport")
+		if err != nil {
+			return fmt.Errorf("failed to write JSON report: %w", err)
+		}
+		fmt.Fprintf(os.Stderr, "JSON report written to: %s\n", filepath)
+		return nil
+	}
+
+	format.FormatTransportTest(os.Stdout, results)
+	return nil
+}
+
+// =============================================================================
+// SECTION 6: Entry Point
 // =============================================================================
 //
 // main() follows the same pattern as cmd/block/main.go:
@@ -391,20 +830,67 @@ func main() {
 	config.LoadEnv()
 
 	var (
-		cfgPath = flag.String("config", "config/providers.yaml", "Config file path")
+		flags   = cli.Register(flag.CommandLine, "Connection policy (cold, warm, http1, http2), or a comma-separated list or \"all\" to compare them side by side")
 		samples = flag.Int("samples", 0, "Number of test samples per provider (0 = use config default)")
 		jsonOut = flag.Bool("json", false, "Output JSON report to reports directory")
+		batch   = flag.String("batch", "", "Comma-separated JSON-RPC batch sizes to measure (e.g. 1,10,100); empty = single requests")
 	)
 
 	flag.Parse()
 
-	// *cfgPath dereferences the pointer returned by flag.String to get the
-	// actual string value for the config file path.
-	cfg, err := config.Load(*cfgPath)
+	// One policy applies to every client as usual; a list switches to
+	// transport mode, which builds its own client per policy, so
+	// UseTransport (in Setup) only ever sees a single policy or none.
+	var policies []rpc.Policy
+	if *flags.Transport != "" {
+		var err error
+		if policies, err = rpc.ParsePolicies(*flags.Transport); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	*flags.Transport = ""
+	if len(policies) == 1 {
+		*flags.Transport = string(policies[0])
+	}
+	cfg, cassette, err := flags.Setup("test")
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
+	defer cassette.Close()
+
+	// --batch and a --transport list switch modes; the sample count is
+	// resolved the same way as in runTest (flag override > config default).
+	n := cfg.Defaults.HealthSamples
+	if *samples > 0 {
+		n = *samples
+	}
+
+	if len(policies) > 1 {
+		if *batch != "" {
+			fmt.Fprintln(os.Stderr, "Error: --batch takes a single --transport policy")
+			os.Exit(1)
+		}
+		if err := runTransportTest(cfg, n, policies, *jsonOut); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *batch != "" {
+		sizes, err := parseBatchSizes(*batch)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if err := runBatchTest(cfg, n, sizes, *jsonOut); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
 
 	// *samples and *jsonOut dereference the flag pointers to get the actual
 	// int and bool values, respectively.
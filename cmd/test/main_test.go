@@ -0,0 +1,53 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dando385/eth-rpc-monitor/internal/format"
+	"github.com/dando385/eth-rpc-monitor/internal/rpc"
+)
+
+func TestParseBatchSizes(t *testing.T) {
+	got, err := parseBatchSizes(" 1, 10,100 ")
+	if err != nil || len(got) != 3 || got[0] != 1 || got[1] != 10 || got[2] != 100 {
+		t.Fatalf("got %v err=%v", got, err)
+	}
+	for _, bad := range []string{"", ",", "0", "-1", "ten"} {
+		if _, err := parseBatchSizes(bad); err == nil {
+			t.Fatalf("parseBatchSizes(%q): expected error", bad)
+		}
+	}
+}
+
+func TestBuildTransportReport(t *testing.T) {
+	policies := []rpc.Policy{rpc.PolicyCold, rpc.PolicyWarm}
+	report := buildTransportReport([]format.TransportTestResult{
+		{Policy: rpc.PolicyCold, TestResult: format.TestResult{
+			Name: "a", Success: 1, Total: 2,
+			Latencies: []time.Duration{40 * time.Millisecond},
+			Timings:   []rpc.Timing{{Proto: "HTTP/1.1", Connect: 3 * time.Millisecond, TLS: 5 * time.Millisecond}},
+			Errors:    format.ErrorCounts{rpc.CategoryTimeout: 1},
+		}},
+		{Policy: rpc.PolicyWarm, TestResult: format.TestResult{
+			Name: "a", Success: 1, Total: 1,
+			Latencies: []time.Duration{15 * time.Millisecond},
+			Timings:   []rpc.Timing{{Proto: "HTTP/1.1", Reused: true}},
+		}},
+		{Policy: rpc.PolicyHTTP2, Skipped: "needs https", TestResult: format.TestResult{Name: "a"}},
+	}, 2, policies)
+
+	if len(report.Results) != 3 || len(report.Policies) != 2 {
+		t.Fatalf("report = %+v", report)
+	}
+	cold, warm := report.Results[0], report.Results[1]
+	if cold.Policy != rpc.PolicyCold || cold.NewConnections != 1 || cold.HandshakeP50US != 8000 || cold.Errors["timeout"] != 1 {
+		t.Fatalf("cold = %+v", cold)
+	}
+	if warm.NewConnections != 0 || warm.HandshakeP50US != 0 || warm.Proto != "HTTP/1.1" || warm.Errors != nil {
+		t.Fatalf("warm = %+v", warm)
+	}
+	if h2 := report.Results[2]; h2.Skipped != "needs https" || h2.Total != 0 || h2.LatenciesMS != nil {
+		t.Fatalf("skipped http2 = %+v", h2)
+	}
+}
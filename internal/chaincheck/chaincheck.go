@@ -0,0 +1,118 @@
+// =============================================================================
+// FILE: internal/chaincheck/chaincheck.go
+// ROLE: Startup Gate — Drop (or Refuse) Providers on the Wrong Chain
+// =============================================================================
+//
+// SYSTEM CONTEXT
+// ==============
+// Every command calls Enforce right after config.Load. When providers.yaml
+// has a `chain:` section, each provider is asked for its chain ID and
+// genesis hash (rpc.Client.VerifyChain) concurrently, before the command
+// does anything else:
+//
+//   config.Load() ──▶ chaincheck.Enforce() ──▶ command runs on cfg.Providers
+//                          │
+//                          ├─ match          → kept
+//                          ├─ wrong chain    → removed, warning on stderr
+//                          │                   (strict: the command fails)
+//                          └─ check failed   → kept, note on stderr
+//                                              (strict: the command fails)
+//
+// A provider whose check could not run (timeout, auth, method disabled) is
+// KEPT by default: it is not known to be on the wrong chain, and the
+// command itself will report it failing — which is exactly what `test` and
+// `monitor` exist for. Only a definite mismatch removes a provider.
+//
+// chain.strict promises that no provider runs unverified, so there an
+// unverifiable provider fails the command just like a mismatched one.
+//
+// Warnings go to stderr so `--json` output and piped stdout stay clean.
+// =============================================================================
+
+package chaincheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/dando385/eth-rpc-monitor/internal/config"
+	"github.com/dando385/eth-rpc-monitor/internal/rpc"
+)
+
+// Enforce verifies every provider against cfg.Chain and removes the ones on
+// the wrong chain from cfg.Providers, writing one line per removal (and per
+// provider that could not be checked) to w.
+//
+// It returns an error when cfg.Chain.Strict is set and any provider is on
+// the wrong chain or could not be checked, or when no provider is left.
+// With no chain configured it does nothing.
+func Enforce(cfg *config.Config, w io.Writer) error {
+	if !cfg.Chain.Enabled() || len(cfg.Providers) == 0 {
+		return nil
+	}
+
+	// Two calls per provider at most (eth_chainId, genesis block).
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Defaults.Timeout*2)
+	defer cancel()
+
+	errs := make([]error, len(cfg.Providers))
+	var mu sync.Mutex
+	g, gctx := errgroup.WithContext(ctx)
+	for i, p := range cfg.Providers {
+		i, p := i, p
+		g.Go(func() error {
+			client := rpc.NewClient(p.Name, p.URL, p.Timeout, p.ClientOptions()...)
+			err := client.VerifyChain(gctx, cfg.Chain.ID, cfg.Chain.GenesisHash)
+			mu.Lock()
+			errs[i] = err
+			mu.Unlock()
+			return nil
+		})
+	}
+	g.Wait()
+
+	kept := make([]config.Provider, 0, len(cfg.Providers))
+	var wrong, unverified []string
+	for i, p := range cfg.Providers {
+		var mismatch *rpc.ChainMismatchError
+		switch {
+		case errs[i] == nil:
+			kept = append(kept, p)
+		case errors.As(errs[i], &mismatch):
+			wrong = append(wrong, p.Name)
+			if cfg.Chain.Strict {
+				fmt.Fprintf(w, "Error: provider %q: %v\n", p.Name, errs[i])
+			} else {
+				fmt.Fprintf(w, "Warning: excluding provider %q: %v\n", p.Name, errs[i])
+			}
+		case cfg.Chain.Strict:
+			unverified = append(unverified, p.Name)
+			fmt.Fprintf(w, "Error: could not verify chain of provider %q: %v\n", p.Name, errs[i])
+		default:
+			kept = append(kept, p)
+			fmt.Fprintf(w, "Warning: could not verify chain of provider %q: %v\n", p.Name, errs[i])
+		}
+	}
+
+	if cfg.Chain.Strict {
+		switch {
+		case len(wrong) > 0 && len(unverified) > 0:
+			return fmt.Errorf("chain.strict is set and %d provider(s) are on the wrong chain: %v; %d could not be verified: %v",
+				len(wrong), wrong, len(unverified), unverified)
+		case len(wrong) > 0:
+			return fmt.Errorf("chain.strict is set and %d provider(s) are on the wrong chain: %v", len(wrong), wrong)
+		case len(unverified) > 0:
+			return fmt.Errorf("chain.strict is set and %d provider(s) could not be verified: %v", len(unverified), unverified)
+		}
+	}
+	if len(kept) == 0 {
+		return fmt.Errorf("no provider is on the configured chain")
+	}
+	cfg.Providers = kept
+	return nil
+}
@@ -0,0 +1,83 @@
+package chaincheck
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dando385/eth-rpc-monitor/internal/config"
+	"github.com/dando385/eth-rpc-monitor/internal/rpctest"
+)
+
+// chainServer serves a simulated chain with chainID; with chainID 0,
+// eth_chainId is disabled.
+func chainServer(t *testing.T, chainID uint64) string {
+	t.Helper()
+	b := rpctest.Behavior{}
+	if chainID == 0 {
+		chainID, b.Disabled = 1, []string{"eth_chainId"}
+	}
+	srv := httptest.NewServer(rpctest.NewProvider("p", rpctest.NewChain(rpctest.ChainOptions{ChainID: chainID, Length: 1}), b))
+	t.Cleanup(srv.Close)
+	return srv.URL
+}
+
+func testConfig(t *testing.T, strict bool) *config.Config {
+	t.Helper()
+	return &config.Config{
+		Chain:    config.Chain{ID: 1, Strict: strict},
+		Defaults: config.Defaults{Timeout: 2 * time.Second},
+		Providers: []config.Provider{
+			{Name: "mainnet", URL: chainServer(t, 1), Timeout: 2 * time.Second},
+			{Name: "sepolia", URL: chainServer(t, 11155111), Timeout: 2 * time.Second},
+			{Name: "locked", URL: chainServer(t, 0), Timeout: 2 * time.Second},
+		},
+	}
+}
+
+func TestEnforce_excludesWrongChain(t *testing.T) {
+	cfg := testConfig(t, false)
+	var out bytes.Buffer
+	if err := Enforce(cfg, &out); err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.Providers) != 2 || cfg.Providers[0].Name != "mainnet" || cfg.Providers[1].Name != "locked" {
+		t.Fatalf("providers = %+v", cfg.Providers)
+	}
+	if !strings.Contains(out.String(), `excluding provider "sepolia": wrong chain: chain ID is 11155111, expected 1`) ||
+		!strings.Contains(out.String(), `could not verify chain of provider "locked"`) {
+		t.Fatalf("output:\n%s", out.String())
+	}
+}
+
+func TestEnforce_strict(t *testing.T) {
+	cfg := testConfig(t, true)
+	var out bytes.Buffer
+	if err := Enforce(cfg, &out); err == nil || !strings.Contains(err.Error(), "sepolia") || !strings.Contains(err.Error(), "locked") {
+		t.Fatalf("err = %v", err)
+	}
+}
+
+func TestEnforce_strictUnverifiable(t *testing.T) {
+	cfg := testConfig(t, true)
+	cfg.Providers = []config.Provider{cfg.Providers[0], cfg.Providers[2]} // mainnet, locked
+	var out bytes.Buffer
+	err := Enforce(cfg, &out)
+	if err == nil || !strings.Contains(err.Error(), "could not be verified: [locked]") {
+		t.Fatalf("err = %v", err)
+	}
+	if !strings.Contains(out.String(), `Error: could not verify chain of provider "locked"`) {
+		t.Fatalf("output:\n%s", out.String())
+	}
+}
+
+func TestEnforce_disabled(t *testing.T) {
+	cfg := testConfig(t, false)
+	cfg.Chain = config.Chain{}
+	var out bytes.Buffer
+	if err := Enforce(cfg, &out); err != nil || len(cfg.Providers) != 3 || out.Len() != 0 {
+		t.Fatalf("err = %v, providers = %d, output %q", err, len(cfg.Providers), out.String())
+	}
+}
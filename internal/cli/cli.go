@@ -0,0 +1,105 @@
+// =============================================================================
+// FILE: internal/cli/cli.go
+// ROLE: Command Startup — The Flags and Steps Every Provider Command Shares
+// =============================================================================
+//
+// SYSTEM CONTEXT
+// ==============
+// Every command that talks to the configured providers starts the same way:
+//
+//   flag.Parse()
+//       │
+//       ▼
+//   config.Load(--config)
+//       │
+//       ▼
+//   cfg.UseTransport(command, --transport)  ← connection policy
+//       │
+//       ▼
+//   cfg.UseCassette(--record, --replay, --replay-latency)
+//       │
+//       ▼
+//   chaincheck.Enforce()                    ← drop wrong-chain providers
+//       │
+//       ▼
+//   the command's own work on cfg.Providers
+//
+// Register adds the flags behind those steps and Setup runs them, so the
+// order (the cassette must be attached before chaincheck's first call, or
+// a replay would reach the network) lives in one place. Each command still
+// registers and validates its own flags next to these.
+//
+// Setup returns the cassette so main can `defer cassette.Close()`. The
+// recorder writes each exchange as it happens, so an os.Exit that skips the
+// deferred Close loses nothing; Close only releases the file.
+// =============================================================================
+
+package cli
+
+import (
+	"flag"
+	"os"
+
+	"github.com/dando385/eth-rpc-monitor/internal/chaincheck"
+	"github.com/dando385/eth-rpc-monitor/internal/config"
+	"github.com/dando385/eth-rpc-monitor/internal/rpc"
+)
+
+// DefaultTransportUsage is the --transport help for commands that take a
+// single policy.
+const DefaultTransportUsage = "Connection policy: cold, warm, http1 or http2 (empty = config, then built-in default)"
+
+// Flags holds the shared flags' values after flag parsing.
+type Flags struct {
+	Config        *string // --config: providers.yaml path
+	Transport     *string // --transport: connection policy
+	Record        *string // --record: cassette file to write
+	Replay        *string // --replay: cassette file to answer from
+	ReplayLatency *bool   // --replay-latency: wait each recorded latency
+}
+
+// Register defines --config, --transport, --record, --replay and
+// --replay-latency on fs. transportUsage replaces the --transport help for
+// commands whose default or syntax differs; "" uses DefaultTransportUsage.
+func Register(fs *flag.FlagSet, transportUsage string) *Flags {
+	if transportUsage == "" {
+		transportUsage = DefaultTransportUsage
+	}
+	return &Flags{
+		Config:        fs.String("config", "config/providers.yaml", "Config file path"),
+		Transport:     fs.String("transport", "", transportUsage),
+		Record:        fs.String("record", "", "Record every RPC exchange to this cassette file (JSON lines)"),
+		Replay:        fs.String("replay", "", "Answer every RPC call from this cassette file instead of the network"),
+		ReplayLatency: fs.Bool("replay-latency", false, "With --replay, wait each exchange's recorded latency"),
+	}
+}
+
+// Setup loads the config and applies the shared flags in order:
+//
+//  1. config.Load reads --config
+//  2. UseTransport applies --transport under the command's name (warnings
+//     on stderr for providers it cannot apply to)
+//  3. UseCassette attaches the --record or --replay cassette
+//  4. chaincheck.Enforce drops providers on the wrong chain (warnings on
+//     stderr)
+//
+// The cassette is nil when neither --record nor --replay is set; Close is
+// safe to call on it either way.
+func (f *Flags) Setup(command string) (*config.Config, *rpc.Cassette, error) {
+	cfg, err := config.Load(*f.Config)
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, err := cfg.UseTransport(command, *f.Transport, os.Stderr); err != nil {
+		return nil, nil, err
+	}
+	cassette, err := cfg.UseCassette(*f.Record, *f.Replay, *f.ReplayLatency)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := chaincheck.Enforce(cfg, os.Stderr); err != nil {
+		cassette.Close()
+		return nil, nil, err
+	}
+	return cfg, cassette, nil
+}
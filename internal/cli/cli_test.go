@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRegister(t *testing.T) {
+	fs := flag.NewFlagSet("x", flag.ContinueOnError)
+	f := Register(fs, "")
+	if err := fs.Parse([]string{"--config", "p.yaml", "--transport", "warm", "--replay", "t.jsonl", "--replay-latency"}); err != nil {
+		t.Fatal(err)
+	}
+	if *f.Config != "p.yaml" || *f.Transport != "warm" || *f.Replay != "t.jsonl" || !*f.ReplayLatency || *f.Record != "" {
+		t.Fatalf("flags = %+v", f)
+	}
+	if u := fs.Lookup("transport").Usage; u != DefaultTransportUsage {
+		t.Errorf("--transport usage = %q", u)
+	}
+}
+
+func TestSetup(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "providers.yaml")
+	if err := os.WriteFile(path, []byte("providers:\n  - name: a\n    url: http://127.0.0.1:1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	setup := func(args ...string) error {
+		fs := flag.NewFlagSet("x", flag.ContinueOnError)
+		f := Register(fs, "")
+		if err := fs.Parse(append([]string{"--config", path}, args...)); err != nil {
+			t.Fatal(err)
+		}
+		cfg, cassette, err := f.Setup("block")
+		defer cassette.Close()
+		if err == nil && len(cfg.Providers) != 1 {
+			t.Fatalf("providers = %+v", cfg.Providers)
+		}
+		return err
+	}
+
+	if err := setup(); err != nil {
+		t.Fatalf("live run: %v", err)
+	}
+	tape := filepath.Join(dir, "tape.jsonl")
+	if err := setup("--record", tape); err != nil {
+		t.Fatalf("recording: %v", err)
+	}
+	if _, err := os.Stat(tape); err != nil {
+		t.Fatalf("--record should create the cassette: %v", err)
+	}
+	for _, tc := range []struct {
+		args []string
+		want string
+	}{
+		{[]string{"--transport", "lukewarm"}, "lukewarm"},
+		{[]string{"--record", tape, "--replay", tape}, "cannot be combined"},
+		{[]string{"--replay-latency"}, "needs --replay"},
+	} {
+		if err := setup(tc.args...); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("Setup(%v) = %v, want %q", tc.args, err, tc.want)
+		}
+	}
+}
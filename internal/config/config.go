@@ -71,11 +71,15 @@
 package config
 
 import (
+	"fmt"
+	"io"
 	"os"
 	"strings"
 	"time"
 
 	"gopkg.in/yaml.v3"
+
+	"github.com/dando385/eth-rpc-monitor/internal/rpc"
 )
 
 // =============================================================================
@@ -121,8 +125,197 @@ import (
 type Config struct {
 	Providers []Provider `yaml:"providers"` // List of RPC providers to monitor
 	Defaults  Defaults   `yaml:"defaults"`  // Default settings (timeout, samples, interval)
+	Chain     Chain      `yaml:"chain"`     // Expected chain identity (optional)
+	Transport Transport  `yaml:"transport"` // Connection policy per command (optional)
+	Methods   Methods    `yaml:"methods"`   // Catalog probed by `capabilities` (optional)
 }
 
+// Transport maps a command name (or "default") to its connection policy.
+//
+// Example YAML:
+//
+//	transport:
+//	  default: warm        # cold | warm (warm-keepalive) | http1 | http2
+//	  monitor: cold
+//	  snapshot: http2
+//
+// Without an entry, monitor runs cold (every tick pays for a fresh
+// connection, as a periodic poller would) and everything else runs warm. A
+// --transport flag on the command beats the file. See rpc/transport.go for
+// what each policy does.
+type Transport map[string]rpc.Policy
+
+// transportKeys are the keys a transport section may use.
+var transportKeys = []string{"default", "block", "test", "snapshot", "nodeinfo", "logs", "monitor", "account", "archive", "capabilities", "fees"}
+
+// builtinPolicy is the policy a command gets when neither the flag nor the
+// file names one.
+func builtinPolicy(command string) rpc.Policy {
+	if command == "monitor" {
+		return rpc.PolicyCold
+	}
+	return rpc.PolicyWarm
+}
+
+// normalize checks every key and canonicalizes every policy name.
+func (t Transport) normalize() error {
+	for key, name := range t {
+		known := false
+		for _, k := range transportKeys {
+			known = known || k == key
+		}
+		if !known {
+			return fmt.Errorf("transport: unknown command %q (want one of %s)", key, strings.Join(transportKeys, ", "))
+		}
+		p, err := rpc.ParsePolicy(string(name))
+		if err != nil {
+			return fmt.Errorf("transport.%s: %w", key, err)
+		}
+		t[key] = p
+	}
+	return nil
+}
+
+// Methods is the method catalog the `capabilities` command probes. Each
+// entry is a JSON-RPC method and its params; params may use the
+// placeholders described in rpc/capability.go.
+//
+// Example YAML:
+//
+//	methods:
+//	  - method: trace_block
+//	    params: ["{head}"]
+//	  - name: eth_getLogs (50k blocks)
+//	    method: eth_getLogs
+//	    params: [{fromBlock: "{head-49999}", toBlock: "{head}"}]
+//
+// Without the section, rpc.DefaultMethodCatalog is probed.
+type Methods []rpc.MethodProbe
+
+// normalize requires a method on every entry and a unique name per entry,
+// since reports key each row by name. Name defaults to the method.
+func (m Methods) normalize() error {
+	seen := map[string]bool{}
+	for i := range m {
+		if m[i].Method == "" {
+			return fmt.Errorf("methods[%d]: method is required", i)
+		}
+		name := m[i].Label()
+		if seen[name] {
+			return fmt.Errorf("methods[%d]: duplicate name %q (set name: to tell entries apart)", i, name)
+		}
+		seen[name] = true
+		m[i].Name = name
+	}
+	return nil
+}
+
+// Catalog returns the configured methods, or the built-in catalog when the
+// config has none.
+func (m Methods) Catalog() []rpc.MethodProbe {
+	if len(m) == 0 {
+		return rpc.DefaultMethodCatalog()
+	}
+	return m
+}
+
+// UseTransport resolves the connection policy for command — flagValue if
+// non-empty, else transport.<command>, else transport.default, else the
+// built-in default — and makes every provider's ClientOptions carry it.
+// It returns the policy in effect.
+//
+// A provider the policy does not apply to (http2 on an http:// or ipc://
+// URL, see rpc.Policy.Unsupported) gets warm instead: the same pooled
+// connections, with the protocol left to the transport. Each such provider
+// gets a warning on w naming the policy actually used, so its numbers are
+// not read as HTTP/2 ones.
+func (c *Config) UseTransport(command, flagValue string, w io.Writer) (rpc.Policy, error) {
+	policy := builtinPolicy(command)
+	switch {
+	case flagValue != "":
+		p, err := rpc.ParsePolicy(flagValue)
+		if err != nil {
+			return "", err
+		}
+		policy = p
+	case c.Transport[command] != "":
+		policy = c.Transport[command]
+	case c.Transport["default"] != "":
+		policy = c.Transport["default"]
+	}
+	for i := range c.Providers {
+		c.Providers[i].policy = policy
+		if reason := policy.Unsupported(c.Providers[i].URL); reason != "" {
+			c.Providers[i].policy = rpc.PolicyWarm
+			fmt.Fprintf(w, "Warning: provider %q: transport %s %s; using %s\n",
+				c.Providers[i].Name, policy, reason, rpc.PolicyWarm)
+		}
+	}
+	return policy, nil
+}
+
+// UseCassette makes every provider's ClientOptions record to the cassette
+// file at record, or answer from the one at replay (waiting each recorded
+// latency when paced is set). Both empty leaves the clients live. The
+// cassette is returned so the caller can Close it; it is nil when live.
+//
+// Only rpc.Client uses the cassette: WebSocket subscriptions stay live.
+func (c *Config) UseCassette(record, replay string, paced bool) (*rpc.Cassette, error) {
+	var (
+		cassette *rpc.Cassette
+		err      error
+	)
+	switch {
+	case record != "" && replay != "":
+		return nil, fmt.Errorf("--record and --replay cannot be combined")
+	case paced && replay == "":
+		return nil, fmt.Errorf("--replay-latency needs --replay")
+	case record != "":
+		cassette, err = rpc.RecordCassette(record)
+	case replay != "":
+		cassette, err = rpc.ReplayCassette(replay, paced)
+	default:
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	for i := range c.Providers {
+		c.Providers[i].cassette = cassette
+	}
+	return cassette, nil
+}
+
+// Chain is the chain every provider in the file is expected to serve.
+//
+// Example YAML (Ethereum mainnet):
+//
+//	chain:
+//	  id: 1
+//	  genesis_hash: "0xd4e56740f876aef8c010b86a40d5f56745a118d0906a34e69aec8c0db1cb8fa3"
+//	  strict: false
+//
+// WHY BOTH?
+// =========
+// The chain ID alone catches the common mistake — a Sepolia URL pasted into
+// a mainnet list. The genesis hash also catches chains that reuse an ID
+// (private forks, misconfigured devnets, "mainnet" shadow forks), because
+// no two chains share block 0.
+//
+// Both fields are optional; an empty Chain disables the check. When set,
+// every command verifies each provider at startup (see internal/chaincheck)
+// and drops the ones on the wrong chain with a warning. Strict turns that
+// warning into a fatal error instead, and fails on a provider whose check
+// could not run as well, so that no provider runs unverified.
+type Chain struct {
+	ID          uint64 `yaml:"id"`           // Expected eth_chainId; 0 = don't check
+	GenesisHash string `yaml:"genesis_hash"` // Expected block 0 hash; "" = don't check
+	Strict      bool   `yaml:"strict"`       // Fail on mismatched or unverifiable providers
+}
+
+// Enabled reports whether any chain identity check is configured.
+func (c Chain) Enabled() bool { return c.ID != 0 || c.GenesisHash != "" }
+
 // Provider represents a single Ethereum RPC endpoint configuration.
 //
 // Example YAML:
@@ -148,11 +341,155 @@ type Config struct {
 // The Type field ("public", "self_hosted", "enterprise") is informational
 // only — it appears in test output but does NOT change any behavior.
 // It exists for human operators to understand their provider landscape.
+// The `nodeinfo` command shows it next to what each node actually reports.
+//
+// WS_URL FIELD
+// ============
+// WSURL is the provider's WebSocket endpoint (ws:// or wss://). It is only
+// used by `monitor --ws`, which subscribes to newHeads over it. Providers
+// without a ws_url are simply polled, as before.
+//
+// HEADERS AND AUTH
+// ================
+// Headers are sent with every request (HTTP and the WebSocket upgrade);
+// Auth adds one Authorization scheme on top:
+//
+//	headers:
+//	  X-Api-Key: ${VENDOR_KEY}
+//	auth:
+//	  type: bearer            # basic | bearer | jwt
+//	  token: ${GATEWAY_TOKEN}
+//
+//	auth: {type: basic, username: monitor, password: ${PROXY_PASSWORD}}
+//	auth: {type: jwt, jwt_secret_file: /var/lib/geth/jwtsecret}
+//
+// Header values, passwords and tokens are rpc.Secret, which prints as
+// "[redacted]" — a %+v of a Provider is safe to log. The JWT secret file is
+// read (and checked to be 32 hex-encoded bytes) by Load, so a bad path fails
+// at startup rather than on the first request.
+//
+// RATE LIMIT
+// ==========
+// RateLimit keeps every command inside a metered provider's quota, so the
+// tool's own request rate never turns into 429s in the results:
+//
+//	rate_limit: {rps: 5, burst: 10}
+//
+// Load builds ONE rpc.RateLimiter per provider and ClientOptions hands the
+// same one to every Client, so concurrent clients share the quota. Time a
+// call spends waiting for it is reported as Queue, apart from latency.
 type Provider struct {
-	Name    string        `yaml:"name"`              // Identifier (e.g., "alchemy", "infura")
-	URL     string        `yaml:"url"`               // Full RPC endpoint URL (env vars expanded)
-	Type    string        `yaml:"type"`              // Informational: "public", "self_hosted", "enterprise"
-	Timeout time.Duration `yaml:"timeout,omitempty"` // Per-provider timeout override; 0 = use default
+	Name    string                `yaml:"name"`              // Identifier (e.g., "alchemy", "infura")
+	URL     string                `yaml:"url"`               // Full RPC endpoint URL (env vars expanded); ipc:///path for a Unix socket
+	WSURL   string                `yaml:"ws_url,omitempty"`  // Optional WebSocket endpoint for push-based heads
+	Type    string                `yaml:"type"`              // Informational: "public", "self_hosted", "enterprise"
+	Timeout time.Duration         `yaml:"timeout,omitempty"` // Per-provider timeout override; 0 = use default
+	Headers map[string]rpc.Secret `yaml:"headers,omitempty"` // Extra request headers (values redacted)
+	Auth    Auth                  `yaml:"auth,omitempty"`    // Authorization scheme (optional)
+
+	RateLimit RateLimit `yaml:"rate_limit,omitempty"` // Client-side request quota (optional)
+
+	// Built by Load from Headers and Auth. Kept behind a pointer: fmt cannot
+	// call String() on unexported fields, but it prints nested pointers as
+	// addresses, so the JWT secret inside never reaches a %+v.
+	rpcAuth *rpc.Auth
+
+	policy   rpc.Policy       // Connection policy, set by Config.UseTransport
+	limiter  *rpc.RateLimiter // Built by Load from RateLimit; shared by all of this provider's clients
+	cassette *rpc.Cassette    // Record/replay tape, set by Config.UseCassette; shared by every provider
+}
+
+// RateLimit caps how fast requests are sent to one provider.
+type RateLimit struct {
+	RPS   float64 `yaml:"rps"`   // Sustained requests per second; 0 = unlimited
+	Burst int     `yaml:"burst"` // Requests allowed back to back; 0 = 1
+}
+
+// Auth selects how a provider authenticates. Only the fields of the chosen
+// Type are used.
+type Auth struct {
+	Type          string     `yaml:"type"`            // "basic", "bearer", "jwt"; "" = none
+	Username      string     `yaml:"username"`        // basic
+	Password      rpc.Secret `yaml:"password"`        // basic
+	Token         rpc.Secret `yaml:"token"`           // bearer
+	JWTSecretFile string     `yaml:"jwt_secret_file"` // jwt: hex-encoded 32-byte secret (node's jwtsecret)
+}
+
+// ClientOptions returns the rpc.Client (and rpc.WSClient) options that apply
+// this provider's headers, authentication and transport policy, followed by
+// extra. Pass them to the constructor:
+//
+//	rpc.NewClient(p.Name, p.URL, p.Timeout, p.ClientOptions()...)
+//
+// Options apply in order, so an extra rpc.WithPolicy overrides the policy
+// chosen by Config.UseTransport.
+func (p Provider) ClientOptions(extra ...rpc.Option) []rpc.Option {
+	var opts []rpc.Option
+	if p.rpcAuth != nil {
+		opts = append(opts, rpc.WithAuth(p.rpcAuth))
+	}
+	if p.policy != "" {
+		opts = append(opts, rpc.WithPolicy(p.policy))
+	}
+	if p.limiter != nil {
+		opts = append(opts, rpc.WithRateLimiter(p.limiter))
+	}
+	if p.cassette != nil {
+		opts = append(opts, rpc.WithCassette(p.cassette))
+	}
+	return append(opts, extra...)
+}
+
+// prepareRateLimit validates the rate_limit section and builds the limiter
+// that ClientOptions hands out.
+func (p *Provider) prepareRateLimit() error {
+	r := p.RateLimit
+	if r.RPS < 0 || r.Burst < 0 {
+		return fmt.Errorf("provider %q: rate_limit rps and burst must not be negative", p.Name)
+	}
+	if r.RPS == 0 {
+		if r.Burst != 0 {
+			return fmt.Errorf("provider %q: rate_limit burst needs rps", p.Name)
+		}
+		return nil
+	}
+	p.limiter = rpc.NewRateLimiter(r.RPS, r.Burst)
+	return nil
+}
+
+// prepareAuth validates the auth section, loads the JWT secret, and builds
+// the rpc.Auth that ClientOptions hands out.
+func (p *Provider) prepareAuth() error {
+	a := &rpc.Auth{Headers: p.Headers}
+	switch p.Auth.Type {
+	case "":
+		if len(p.Headers) == 0 {
+			return nil
+		}
+	case "basic":
+		if p.Auth.Username == "" {
+			return fmt.Errorf("provider %q: auth type basic needs a username", p.Name)
+		}
+		a.Username, a.Password = p.Auth.Username, p.Auth.Password
+	case "bearer":
+		if p.Auth.Token == "" {
+			return fmt.Errorf("provider %q: auth type bearer needs a token", p.Name)
+		}
+		a.BearerToken = p.Auth.Token
+	case "jwt":
+		if p.Auth.JWTSecretFile == "" {
+			return fmt.Errorf("provider %q: auth type jwt needs jwt_secret_file", p.Name)
+		}
+		secret, err := rpc.LoadJWTSecret(p.Auth.JWTSecretFile)
+		if err != nil {
+			return fmt.Errorf("provider %q: %w", p.Name, err)
+		}
+		a.JWTSecret = secret
+	default:
+		return fmt.Errorf("provider %q: unknown auth type %q (want basic, bearer or jwt)", p.Name, p.Auth.Type)
+	}
+	p.rpcAuth = a
+	return nil
 }
 
 // Defaults holds the default settings shared across all commands.
@@ -174,11 +511,20 @@ type Defaults struct {
 
 // Load reads a YAML configuration file and returns a fully-populated Config.
 //
-// This function performs three operations in sequence:
-//  1. READ:   Load the raw file bytes from disk
-//  2. EXPAND: Replace ${VAR} patterns with environment variable values
-//  3. PARSE:  Deserialize the YAML text into Go structs
-//  4. DEFAULT: Fill in missing per-provider timeouts from the defaults
+// This function performs these operations in sequence, stopping at the
+// first error:
+//  1. READ:      Load the raw file bytes from disk
+//  2. EXPAND:    Replace ${VAR} patterns with environment variable values
+//  3. PARSE:     Deserialize the YAML text into Go structs
+//  4. CHAIN:     Validate and lowercase chain.genesis_hash
+//  5. TRANSPORT: Check every transport key and canonicalize its policy name
+//  6. METHODS:   Normalize the methods catalog (method required, name
+//     defaults to the method, names unique)
+//  7. DEFAULT:   Fill in missing per-provider timeouts from the defaults
+//  8. AUTH:      Validate each provider's auth section, load JWT secrets
+//     and build its rpc.Auth
+//  9. LIMIT:     Validate each provider's rate_limit section and build its
+//     rpc.RateLimiter
 //
 // RETURN TYPE: (*Config, error)
 // =============================
@@ -238,7 +584,16 @@ type Defaults struct {
 //	       │                               ▲
 //	  &cfg (passed to Unmarshal)       &cfg (same address)
 //
-// Step 4 — Default timeout inheritance:
+// Steps 4–6 — Section normalization:
+//
+//	The chain, transport and methods sections are checked once, here,
+//	so a typo fails at startup with the offending key in the message
+//	instead of surfacing as a confusing result mid-run. Each section's
+//	normalize method also rewrites values into the one form the rest of
+//	the program compares against (a lowercase hash, a canonical policy
+//	name, a probe name).
+//
+// Step 7 — Default timeout inheritance:
 //
 //	After parsing, iterate through providers. Any provider with Timeout == 0
 //	(meaning "not set in YAML") gets the default timeout.
@@ -261,7 +616,14 @@ type Defaults struct {
 //	Using the index form (cfg.Providers[i]) accesses the actual element
 //	in the slice's underlying array.
 //
-// Step 5 — return &cfg, nil:
+// Steps 8–9 — Per-provider auth and rate limiter:
+//
+//	In the same loop, prepareAuth and prepareRateLimit build the values
+//	ClientOptions hands out later. The limiter is built ONCE per provider,
+//	so every client a command creates for that provider shares one token
+//	bucket and the configured rate holds across them.
+//
+// Step 10 — return &cfg, nil:
 //
 //	The `&` takes the address of the local cfg variable. Go's escape analysis
 //	detects that this address is being returned, so cfg is allocated on the
@@ -277,16 +639,46 @@ func Load(path string) (*Config, error) {
 		return nil, err
 	}
 
+	if err := cfg.Chain.normalize(); err != nil {
+		return nil, err
+	}
+	if err := cfg.Transport.normalize(); err != nil {
+		return nil, err
+	}
+	if err := cfg.Methods.normalize(); err != nil {
+		return nil, err
+	}
+
 	// Apply default timeout to any provider that doesn't specify one.
 	// Uses index-based iteration to modify the original slice elements.
 	for i := range cfg.Providers {
 		if cfg.Providers[i].Timeout == 0 {
 			cfg.Providers[i].Timeout = cfg.Defaults.Timeout
 		}
+		if err := cfg.Providers[i].prepareAuth(); err != nil {
+			return nil, err
+		}
+		if err := cfg.Providers[i].prepareRateLimit(); err != nil {
+			return nil, err
+		}
 	}
 	return &cfg, nil
 }
 
+// normalize validates and lowercases the genesis hash, so a typo in the
+// config fails at load time instead of excluding every provider.
+func (c *Chain) normalize() error {
+	if c.GenesisHash == "" {
+		return nil
+	}
+	h := strings.ToLower(strings.TrimSpace(c.GenesisHash))
+	if len(h) != 66 || !strings.HasPrefix(h, "0x") || strings.Trim(h[2:], "0123456789abcdef") != "" {
+		return fmt.Errorf("chain.genesis_hash %q: want 0x followed by 64 hex digits", c.GenesisHash)
+	}
+	c.GenesisHash = h
+	return nil
+}
+
 // =============================================================================
 // SECTION 3: LoadEnv — Loading Environment Variables from .env Files
 // =============================================================================
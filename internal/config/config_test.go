@@ -1,10 +1,16 @@
 package config
 
 import (
+	"bytes"
+	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/dando385/eth-rpc-monitor/internal/rpc"
 )
 
 func TestLoad_minimalAndTimeoutDefault(t *testing.T) {
@@ -68,3 +74,307 @@ func TestLoadEnv_setsVariables(t *testing.T) {
 		t.Fatalf("got %q", os.Getenv("ETH_RPC_MONITOR_LOADENV_K"))
 	}
 }
+
+func TestLoad_chain(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cfg.yaml")
+	content := `chain:
+  id: 1
+  genesis_hash: "0xD4E56740F876AEF8C010B86A40D5F56745A118D0906A34E69AEC8C0DB1CB8FA3"
+  strict: true
+providers:
+  - name: a
+    url: https://example.com
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cfg.Chain.Enabled() || cfg.Chain.ID != 1 || !cfg.Chain.Strict {
+		t.Fatalf("chain: %+v", cfg.Chain)
+	}
+	if cfg.Chain.GenesisHash != "0xd4e56740f876aef8c010b86a40d5f56745a118d0906a34e69aec8c0db1cb8fa3" {
+		t.Fatalf("genesis hash not normalized: %q", cfg.Chain.GenesisHash)
+	}
+}
+
+func TestLoad_chainInvalidGenesisHash(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cfg.yaml")
+	if err := os.WriteFile(path, []byte("chain:\n  genesis_hash: 0xd4e5\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil {
+		t.Fatal("expected error for short genesis hash")
+	}
+}
+
+func TestChain_disabledByDefault(t *testing.T) {
+	if (Chain{}).Enabled() {
+		t.Fatal("zero Chain should be disabled")
+	}
+}
+
+func TestLoad_headersAndAuth(t *testing.T) {
+	t.Setenv("ETH_RPC_MONITOR_TOKEN", "s3cret-token")
+	dir := t.TempDir()
+	secretPath := filepath.Join(dir, "jwtsecret")
+	if err := os.WriteFile(secretPath, []byte(strings.Repeat("0f", 32)+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "cfg.yaml")
+	content := `providers:
+  - name: gateway
+    url: https://example.com
+    headers:
+      X-Api-Key: ${ETH_RPC_MONITOR_TOKEN}
+    auth:
+      type: bearer
+      token: ${ETH_RPC_MONITOR_TOKEN}
+  - name: proxy
+    url: https://example.com
+    auth: {type: basic, username: monitor, password: hunter2}
+  - name: local
+    url: http://localhost:8551
+    auth: {type: jwt, jwt_secret_file: ` + secretPath + `}
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gw := cfg.Providers[0]
+	if string(gw.Headers["X-Api-Key"]) != "s3cret-token" || string(gw.Auth.Token) != "s3cret-token" {
+		t.Fatalf("gateway: headers/token not loaded")
+	}
+	if a := cfg.Providers[2].rpcAuth; a == nil || len(a.JWTSecret) != 32 || a.Scheme() != "jwt" {
+		t.Fatalf("jwt auth not built: %+v", a)
+	}
+	for _, p := range cfg.Providers {
+		if len(p.ClientOptions()) != 1 {
+			t.Fatalf("%s: no client options", p.Name)
+		}
+	}
+
+	dump := fmt.Sprintf("%+v %#v", cfg.Providers, cfg.Providers)
+	for _, secret := range []string{"s3cret-token", "hunter2", "\x0f\x0f"} {
+		if strings.Contains(dump, secret) {
+			t.Fatalf("secret %q printed: %s", secret, dump)
+		}
+	}
+}
+
+func TestLoad_authErrors(t *testing.T) {
+	for _, auth := range []string{
+		"{type: bearer}",
+		"{type: basic, password: x}",
+		"{type: jwt}",
+		"{type: jwt, jwt_secret_file: /nonexistent/jwtsecret}",
+		"{type: digest}",
+	} {
+		path := filepath.Join(t.TempDir(), "cfg.yaml")
+		content := "providers:\n  - name: p\n    url: https://example.com\n    auth: " + auth + "\n"
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Load(path); err == nil {
+			t.Fatalf("auth %s: expected error", auth)
+		}
+	}
+}
+
+func TestLoad_transport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cfg.yaml")
+	content := `transport:
+  default: warm-keepalive
+  snapshot: HTTP2
+providers:
+  - name: a
+    url: https://a
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		command, flag string
+		want          rpc.Policy
+	}{
+		{"snapshot", "", rpc.PolicyHTTP2},
+		{"block", "", rpc.PolicyWarm},
+		{"monitor", "", rpc.PolicyWarm}, // default: beats the built-in cold
+		{"snapshot", "cold", rpc.PolicyCold},
+	}
+	for _, tt := range tests {
+		got, err := cfg.UseTransport(tt.command, tt.flag, io.Discard)
+		if err != nil || got != tt.want || cfg.Providers[0].policy != tt.want {
+			t.Errorf("UseTransport(%q, %q) = %q, %v; want %q", tt.command, tt.flag, got, err, tt.want)
+		}
+	}
+	if _, err := cfg.UseTransport("test", "quic", io.Discard); err == nil {
+		t.Fatal("unknown flag policy should fail")
+	}
+}
+
+func TestUseTransport_http2NeedsHTTPS(t *testing.T) {
+	cfg := &Config{Providers: []Provider{{Name: "tls", URL: "https://a"}, {Name: "local", URL: "http://127.0.0.1:8545"}, {Name: "ipc", URL: "ipc:///tmp/geth.ipc"}}}
+	var warnings bytes.Buffer
+	if p, err := cfg.UseTransport("test", "http2", &warnings); err != nil || p != rpc.PolicyHTTP2 {
+		t.Fatalf("UseTransport = %q, %v", p, err)
+	}
+	if want := "Warning: provider \"local\": transport http2 needs https; using warm\n" +
+		"Warning: provider \"ipc\": transport http2 needs https; using warm\n"; warnings.String() != want {
+		t.Fatalf("warnings:\n%s", warnings.String())
+	}
+	for i, want := range []rpc.Policy{rpc.PolicyHTTP2, rpc.PolicyWarm, rpc.PolicyWarm} {
+		if got := cfg.Providers[i].policy; got != want {
+			t.Errorf("%s: policy %q, want %q", cfg.Providers[i].Name, got, want)
+		}
+	}
+}
+
+func TestUseTransport_builtinDefaults(t *testing.T) {
+	cfg := &Config{Providers: []Provider{{Name: "a"}}}
+	if p, _ := cfg.UseTransport("monitor", "", io.Discard); p != rpc.PolicyCold {
+		t.Fatalf("monitor = %q, want cold", p)
+	}
+	if p, _ := cfg.UseTransport("test", "", io.Discard); p != rpc.PolicyWarm {
+		t.Fatalf("test = %q, want warm", p)
+	}
+	if n := len(cfg.Providers[0].ClientOptions()); n != 1 {
+		t.Fatalf("ClientOptions carries %d options, want the policy", n)
+	}
+}
+
+func TestUseCassette(t *testing.T) {
+	cfg := &Config{Providers: []Provider{{Name: "a"}, {Name: "b"}}}
+	if c, err := cfg.UseCassette("", "", false); c != nil || err != nil {
+		t.Fatalf("no flags = %v, %v; want live clients", c, err)
+	}
+	if _, err := cfg.UseCassette("x", "y", false); err == nil {
+		t.Error("--record with --replay should fail")
+	}
+	if _, err := cfg.UseCassette("", "", true); err == nil {
+		t.Error("--replay-latency without --replay should fail")
+	}
+	if _, err := cfg.UseCassette("", filepath.Join(t.TempDir(), "missing.jsonl"), false); err == nil {
+		t.Error("replaying a missing cassette should fail")
+	}
+
+	c, err := cfg.UseCassette(filepath.Join(t.TempDir(), "tape.jsonl"), "", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	for _, p := range cfg.Providers {
+		if n := len(p.ClientOptions()); n != 1 {
+			t.Errorf("%s: ClientOptions carries %d options, want the cassette", p.Name, n)
+		}
+	}
+}
+
+func TestLoad_transportErrors(t *testing.T) {
+	for _, content := range []string{
+		"transport:\n  monitr: cold\n",
+		"transport:\n  monitor: lukewarm\n",
+	} {
+		path := filepath.Join(t.TempDir(), "cfg.yaml")
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Load(path); err == nil {
+			t.Errorf("Load(%q) should fail", content)
+		}
+	}
+}
+
+func TestLoad_rateLimit(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cfg.yaml")
+	content := `providers:
+  - name: metered
+    url: https://a
+    rate_limit: {rps: 2.5, burst: 5}
+  - name: strict
+    url: https://b
+    rate_limit: {rps: 1}
+  - name: free
+    url: https://c
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l := cfg.Providers[0].limiter; l == nil || l.String() != "2.5 rps, burst 5" {
+		t.Fatalf("metered limiter = %v", l)
+	}
+	if l := cfg.Providers[1].limiter; l == nil || l.String() != "1 rps, burst 1" {
+		t.Fatalf("strict limiter = %v", l)
+	}
+	if cfg.Providers[2].limiter != nil || len(cfg.Providers[2].ClientOptions()) != 0 {
+		t.Fatal("provider without rate_limit should be unlimited")
+	}
+	if n := len(cfg.Providers[0].ClientOptions()); n != 1 {
+		t.Fatalf("ClientOptions carries %d options, want the limiter", n)
+	}
+
+	for _, bad := range []string{"{rps: -1}", "{rps: 1, burst: -2}", "{burst: 3}"} {
+		content := "providers:\n  - name: p\n    url: https://example.com\n    rate_limit: " + bad + "\n"
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Load(path); err == nil {
+			t.Errorf("rate_limit %s: expected error", bad)
+		}
+	}
+}
+
+func TestLoad_methods(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cfg.yaml")
+	content := `methods:
+  - method: trace_block
+    params: ["{head}"]
+  - name: eth_getLogs (50k blocks)
+    method: eth_getLogs
+    params: [{fromBlock: "{head-49999}", toBlock: "{head}"}]
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := cfg.Methods.Catalog()
+	if len(m) != 2 || m[0].Name != "trace_block" || m[1].Name != "eth_getLogs (50k blocks)" {
+		t.Fatalf("methods = %+v", m)
+	}
+	filter, ok := m[1].Params[0].(map[string]interface{})
+	if !ok || filter["fromBlock"] != "{head-49999}" {
+		t.Fatalf("params = %#v", m[1].Params)
+	}
+	if len((&Config{}).Methods.Catalog()) == 0 {
+		t.Fatal("empty section should fall back to the built-in catalog")
+	}
+
+	for _, bad := range []string{
+		"methods:\n  - name: x\n",
+		"methods:\n  - method: txpool_status\n  - method: txpool_status\n",
+	} {
+		if err := os.WriteFile(path, []byte(bad), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Load(path); err == nil {
+			t.Errorf("Load(%q) should fail", bad)
+		}
+	}
+}
@@ -0,0 +1,199 @@
+// =============================================================================
+// FILE: internal/format/account.go
+// ROLE: Account State Display — Proven Balance, Nonce, Code and Storage
+// =============================================================================
+//
+// SYSTEM CONTEXT
+// ==============
+// This file renders the output of the `account` command: one account's state
+// at one block, as every provider reports it, and whether each provider's
+// eth_getProof answer verifies against the stateRoot of that block
+// (rpc/proof.go).
+//
+//   Account 0xd8da6bf26964af9d7eed9e03e53415d37aa96045 at block 19,000,000
+//
+//   Provider       Latency  Balance              Nonce    Code           Proof
+//   ──────────────────────────────────────────────────────────────────────────────
+//   alchemy        43ms     1,234.5 ETH          1,204    none           ✓ verified
+//   badcache       39ms     1,000 ETH            1,204    none           ✗ UNVERIFIED
+//                   └ account: returned nonce, balance, storageHash or codeHash differ ...
+//
+//   Storage slot 0x0
+//     alchemy        0x2a                                                ✓
+//
+//   ✗ 1 of 2 providers returned state that does not verify
+//
+// A verified row is proven against the stateRoot of the header the same
+// provider returned, and that header against its hash. Two providers can
+// both verify and still differ — if they are on different forks of the
+// block — so differing state roots and differing values are listed too.
+// =============================================================================
+
+package format
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/dando385/eth-rpc-monitor/internal/rpc"
+)
+
+// AccountResult is one provider's answer for the account.
+type AccountResult struct {
+	Provider string
+	Latency  time.Duration // eth_getProof round trip
+	Error    error         // Block or proof fetch failed
+
+	BlockHash string
+	StateRoot string
+	Header    rpc.HashCheck
+	Proof     *rpc.AccountProof
+	Check     rpc.ProofCheck
+}
+
+// Verified reports whether the header hashed correctly and the proof
+// verified against its stateRoot.
+func (r AccountResult) Verified() bool {
+	return r.Error == nil && r.Header.OK() && r.Check.OK()
+}
+
+// Problems lists why the result is not verified, one line each.
+func (r AccountResult) Problems() []string {
+	var out []string
+	if p := r.Header.Problem(); p != "" {
+		out = append(out, p)
+	}
+	return append(out, r.Check.Problems()...)
+}
+
+// values is the account state r reports, normalised so that "0x01" and
+// "0x1" compare equal. Used to spot providers that disagree.
+func (r AccountResult) values() string {
+	p := r.Proof
+	parts := []string{
+		rpc.ParseHexBigInt(p.Balance).String(),
+		rpc.ParseHexBigInt(p.Nonce).String(),
+		strings.ToLower(p.CodeHash),
+		strings.ToLower(p.StorageHash),
+	}
+	for _, s := range p.StorageProof {
+		parts = append(parts, rpc.ParseHexBigInt(s.Value).String())
+	}
+	return strings.Join(parts, "|")
+}
+
+// FormatAccount renders the account table, the storage slots and the verdict.
+func FormatAccount(w io.Writer, address string, number uint64, slots []string, results []AccountResult) {
+	fmt.Fprintf(w, "\n%s\n\n", Bold(fmt.Sprintf("Account %s at block %s", address, rpc.FormatNumber(number))))
+	fmt.Fprintf(w, "%s %s  %s %s %s %s\n",
+		Bold(fmt.Sprintf("%-14s", "Provider")),
+		Bold(fmt.Sprintf("%-7s", "Latency")),
+		Bold(fmt.Sprintf("%-20s", "Balance")),
+		Bold(fmt.Sprintf("%-8s", "Nonce")),
+		Bold(fmt.Sprintf("%-14s", "Code")),
+		Bold("Proof"))
+	fmt.Fprintln(w, strings.Repeat("─", 78))
+
+	failures := ErrorCounts{}
+	for _, r := range results {
+		if r.Error != nil {
+			failures.Add(r.Error)
+			fmt.Fprintf(w, "%-14s %s  %s %s\n", r.Provider, padRight(Dim("—"), 7), Red("ERROR:"), ErrorLabel(r.Error))
+			continue
+		}
+		nonce, _ := rpc.ParseHexUint64(r.Proof.Nonce)
+		proof := Green("✓ verified")
+		if !r.Verified() {
+			proof = Red("✗ UNVERIFIED")
+		}
+		fmt.Fprintf(w, "%-14s %s  %s %s %s %s\n",
+			r.Provider,
+			padRight(ColorLatency(r.Latency.Milliseconds()), 7),
+			padRight(rpc.FormatEther(rpc.ParseHexBigInt(r.Proof.Balance)), 20),
+			padRight(rpc.FormatNumber(nonce), 8),
+			padRight(codeLabel(r.Proof.CodeHash), 14),
+			proof)
+		for _, p := range r.Problems() {
+			fmt.Fprintf(w, "%-16s%s %s\n", "", Dim("└"), p)
+		}
+	}
+
+	if failures.Total() == len(results) {
+		slots = nil // Nobody answered; empty slot sections would only add noise
+	}
+	for i, slot := range slots {
+		fmt.Fprintf(w, "\n%s\n", Bold(fmt.Sprintf("Storage slot 0x%x", rpc.ParseHexBigInt(slot))))
+		for _, r := range results {
+			if r.Error != nil || i >= len(r.Proof.StorageProof) {
+				continue
+			}
+			mark := Green("✓")
+			if i >= len(r.Check.Slots) || r.Check.Slots[i] != nil || r.Check.Account != nil || !r.Header.OK() {
+				mark = Red("✗")
+			}
+			fmt.Fprintf(w, "  %-14s %-66s %s\n", r.Provider, r.Proof.StorageProof[i].Value, mark)
+		}
+	}
+
+	// --- Verdict ---
+	fmt.Fprintln(w)
+	if failures.Total() > 0 {
+		fmt.Fprintf(w, "%s %d of %d providers failed: %s\n", Red("✗"), failures.Total(), len(results), failures.Summary())
+	}
+	answered, unverified := 0, 0
+	rootGroups := make(map[string][]string)
+	valueGroups := make(map[string][]string)
+	var valueOrder []string
+	for _, r := range results {
+		if r.Error != nil {
+			continue
+		}
+		answered++
+		if !r.Verified() {
+			unverified++
+		}
+		root := strings.ToLower(r.StateRoot)
+		rootGroups[root] = append(rootGroups[root], r.Provider)
+		v := r.values()
+		if _, seen := valueGroups[v]; !seen {
+			valueOrder = append(valueOrder, v)
+		}
+		valueGroups[v] = append(valueGroups[v], r.Provider)
+	}
+	switch {
+	case unverified > 0:
+		fmt.Fprintf(w, "%s %d of %d providers returned state that does not verify\n", Red("✗"), unverified, answered)
+	case answered > 0:
+		fmt.Fprintf(w, "%s All %d providers returned state proven against their block's stateRoot\n", Green("✓"), answered)
+	}
+	if len(rootGroups) > 1 {
+		fmt.Fprintln(w, Yellow("⚠"), Bold("STATE ROOT MISMATCH:"), "providers returned different headers for this block")
+		for root, providers := range rootGroups {
+			fmt.Fprintf(w, "  %s  →  %v\n", shortHash(root, 18)+"...", providers)
+		}
+	}
+	if len(valueGroups) > 1 {
+		fmt.Fprintln(w, Yellow("⚠"), Bold("PROVIDERS DISAGREE ON THE ACCOUNT:"))
+		for _, v := range valueOrder {
+			fmt.Fprintf(w, "  %v\n", valueGroups[v])
+		}
+	}
+}
+
+// codeLabel summarises a code hash: "none" for an account without code.
+func codeLabel(codeHash string) string {
+	if isEmptyCode(codeHash) {
+		return Dim("none")
+	}
+	return shortHex(codeHash)
+}
+
+// isEmptyCode reports whether codeHash means "no code": keccak256 of empty
+// input, or zero (what some nodes report for accounts that do not exist).
+func isEmptyCode(codeHash string) bool {
+	h := strings.ToLower(codeHash)
+	return h == "0xc5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470" ||
+		strings.Trim(strings.TrimPrefix(h, "0x"), "0") == ""
+}
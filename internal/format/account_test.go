@@ -0,0 +1,88 @@
+package format
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dando385/eth-rpc-monitor/internal/rpc"
+)
+
+func TestFormatAccount(t *testing.T) {
+	root := "0x" + strings.Repeat("aa", 32)
+	hash := "0x" + strings.Repeat("ab", 32)
+	slot := "0x" + strings.Repeat("0", 63) + "1"
+	good := AccountResult{
+		Provider: "good", Latency: 40 * time.Millisecond, BlockHash: hash, StateRoot: root,
+		Header: rpc.HashCheck{Layout: "Cancun", Claimed: hash, Computed: hash},
+		Proof: &rpc.AccountProof{
+			Balance: "0xde0b6b3a7640000", Nonce: "0x4b4",
+			CodeHash:     "0xc5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470",
+			StorageProof: []rpc.StorageProof{{Key: slot, Value: "0x2a"}},
+		},
+		Check: rpc.ProofCheck{StateRoot: root, Slots: []error{nil}},
+	}
+	bad := good
+	bad.Provider = "cached"
+	bad.Proof = &rpc.AccountProof{
+		Balance: "0x0", Nonce: "0x4b4", CodeHash: good.Proof.CodeHash,
+		StorageProof: []rpc.StorageProof{{Key: slot, Value: "0x2b"}},
+	}
+	bad.Check = rpc.ProofCheck{
+		StateRoot: root,
+		Account:   errors.New("returned nonce, balance, storageHash or codeHash differ from the proven account"),
+		Slots:     []error{errors.New("returned value 0x2b, proven 0x2a")},
+	}
+	down := AccountResult{Provider: "down", Error: errors.New("connection refused")}
+
+	var buf bytes.Buffer
+	FormatAccount(&buf, "0xabc", 19000000, []string{slot}, []AccountResult{good, bad, down})
+	out := stripANSI(buf.String())
+	if !containsAll(out, []string{
+		"Account 0xabc at block 19,000,000",
+		"1 ETH", "1,204", "none", "✓ verified", "✗ UNVERIFIED",
+		"└ account: returned nonce, balance",
+		"└ slot 0: returned value 0x2b, proven 0x2a",
+		"Storage slot 0x1",
+		"down", "ERROR:",
+		"✗ 1 of 3 providers failed",
+		"✗ 1 of 2 providers returned state that does not verify",
+		"PROVIDERS DISAGREE ON THE ACCOUNT",
+	}) {
+		t.Fatalf("output:\n%s", out)
+	}
+	if strings.Contains(out, "STATE ROOT MISMATCH") {
+		t.Fatalf("same state root reported as a mismatch:\n%s", out)
+	}
+
+	// Verified everywhere, and the same value written two ways.
+	buf.Reset()
+	other := good
+	other.Provider = "other"
+	other.Proof = &rpc.AccountProof{
+		Balance: "0x0de0b6b3a7640000", Nonce: "0x4b4", CodeHash: good.Proof.CodeHash,
+		StorageProof: []rpc.StorageProof{{Key: "0x1", Value: "0x02a"}},
+	}
+	FormatAccount(&buf, "0xabc", 1, []string{slot}, []AccountResult{good, other})
+	out = stripANSI(buf.String())
+	if !strings.Contains(out, "✓ All 2 providers returned state proven against their block's stateRoot") ||
+		strings.Contains(out, "DISAGREE") {
+		t.Fatalf("output:\n%s", out)
+	}
+}
+
+func TestCodeLabel(t *testing.T) {
+	for _, h := range []string{
+		"0xc5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470",
+		"0x" + strings.Repeat("00", 32),
+	} {
+		if got := stripANSI(codeLabel(h)); got != "none" {
+			t.Fatalf("codeLabel(%s) = %q", h, got)
+		}
+	}
+	if got := codeLabel("0x" + strings.Repeat("12", 32)); got == "none" || !strings.HasPrefix(got, "0x1212") {
+		t.Fatalf("codeLabel of contract = %q", got)
+	}
+}
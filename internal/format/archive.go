@@ -0,0 +1,91 @@
+// =============================================================================
+// FILE: internal/format/archive.go
+// ROLE: Archive Depth Display — How Much Historical State Each Provider Serves
+// =============================================================================
+//
+// SYSTEM CONTEXT
+// ==============
+// This file renders the output of the `archive` command: for every provider,
+// the oldest block whose state it still serves (rpc/archive.go), and the
+// error it gave one block further back.
+//
+//   Provider       Head          Oldest State   Depth            Calls  Time
+//   ─────────────────────────────────────────────────────────────────────────────
+//   alchemy        21,234,567    0              archive             50  2.1s
+//   publicnode     21,234,567    21,234,440     127 blocks          30  1.4s
+//                   └ 21,234,439: missing trie node 8d1e… (path )
+//   llamanodes     —             ERROR: [timeout] context deadline exceeded
+//
+//   ✓ 1 of 3 providers serve state back to genesis: [alchemy]
+//
+// Depth is counted back from each provider's own head, so a provider that
+// lags shows the window it keeps rather than a number skewed by the lag.
+// =============================================================================
+
+package format
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/dando385/eth-rpc-monitor/internal/rpc"
+)
+
+// ArchiveResult is one provider's archive probe.
+type ArchiveResult struct {
+	Provider string
+	Probe    *rpc.ArchiveProbe // May be partly filled when Error is set
+	Error    error
+}
+
+// FormatArchive renders the archive depth table and a verdict.
+func FormatArchive(w io.Writer, results []ArchiveResult) {
+	fmt.Fprintf(w, "\n%s %s %s %s %s  %s\n",
+		Bold(fmt.Sprintf("%-14s", "Provider")),
+		Bold(fmt.Sprintf("%-13s", "Head")),
+		Bold(fmt.Sprintf("%-14s", "Oldest State")),
+		Bold(fmt.Sprintf("%-16s", "Depth")),
+		Bold(fmt.Sprintf("%5s", "Calls")),
+		Bold("Time"))
+	fmt.Fprintln(w, strings.Repeat("─", 77))
+
+	failures := ErrorCounts{}
+	var archives []string
+	for _, r := range results {
+		if r.Error != nil {
+			failures.Add(r.Error)
+			fmt.Fprintf(w, "%-14s %s %s %s\n", r.Provider, padRight(Dim("—"), 13), Red("ERROR:"), ErrorLabel(r.Error))
+			continue
+		}
+		p := r.Probe
+		depth := Yellow(rpc.FormatNumber(p.Depth()) + " blocks")
+		if p.Archive {
+			depth = Green("archive")
+			archives = append(archives, r.Provider)
+		}
+		fmt.Fprintf(w, "%-14s %-13s %-14s %s %5d  %s\n",
+			r.Provider,
+			rpc.FormatNumber(p.Head),
+			rpc.FormatNumber(p.Oldest),
+			padRight(depth, 16),
+			p.Calls,
+			Dim(p.Elapsed.Round(100*time.Millisecond).String()))
+		if !p.Archive {
+			fmt.Fprintf(w, "%-16s%s %s: %s\n", "", Dim("└"), rpc.FormatNumber(p.Boundary), Dim(truncate(p.Message, 70)))
+		}
+	}
+
+	fmt.Fprintln(w)
+	if failures.Total() > 0 {
+		fmt.Fprintf(w, "%s %d of %d providers failed: %s\n", Red("✗"), failures.Total(), len(results), failures.Summary())
+	}
+	probed := len(results) - failures.Total()
+	switch {
+	case len(archives) > 0:
+		fmt.Fprintf(w, "%s %d of %d providers serve state back to genesis: %v\n", Green("✓"), len(archives), probed, archives)
+	case probed > 0:
+		fmt.Fprintf(w, "%s None of %d providers serves state back to genesis\n", Yellow("⚠"), probed)
+	}
+}
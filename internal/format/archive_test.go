@@ -0,0 +1,41 @@
+package format
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dando385/eth-rpc-monitor/internal/rpc"
+)
+
+func TestFormatArchive(t *testing.T) {
+	results := []ArchiveResult{
+		{Provider: "alchemy", Probe: &rpc.ArchiveProbe{Head: 21234567, Archive: true, Calls: 50, Elapsed: 2100 * time.Millisecond}},
+		{Provider: "publicnode", Probe: &rpc.ArchiveProbe{
+			Head: 21234567, Oldest: 21234440, Boundary: 21234439, Calls: 30,
+			Pattern: "missing trie node", Message: "RPC error -32000: missing trie node 8d1e (path )",
+		}},
+		{Provider: "llamanodes", Error: errors.New("head: context deadline exceeded")},
+	}
+	var buf bytes.Buffer
+	FormatArchive(&buf, results)
+	out := stripANSI(buf.String())
+	if !containsAll(out, []string{
+		"Oldest State", "archive", "2.1s",
+		"21,234,440", "127 blocks",
+		"└ 21,234,439: RPC error -32000: missing trie node 8d1e (path )",
+		"llamanodes", "ERROR:",
+		"✗ 1 of 3 providers failed",
+		"✓ 1 of 2 providers serve state back to genesis: [alchemy]",
+	}) {
+		t.Fatalf("output:\n%s", out)
+	}
+
+	buf.Reset()
+	FormatArchive(&buf, results[1:2])
+	if !strings.Contains(stripANSI(buf.String()), "⚠ None of 1 providers serves state back to genesis") {
+		t.Fatalf("output:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,133 @@
+// =============================================================================
+// FILE: internal/format/batch.go
+// ROLE: Batch Latency Display — How Providers Treat JSON-RPC Batches
+// =============================================================================
+//
+// SYSTEM CONTEXT
+// ==============
+// This file renders the output of `test --batch`. Where FormatTest answers
+// "how fast is one request?", FormatBatchTest answers "what happens when we
+// send N requests in one HTTP POST?" — the question that matters for any
+// service that fans many reads into a single round-trip.
+//
+// DATA FLOW
+// =========
+//
+//   cmd/test/main.go
+//       │
+//       │  testBatch() runs N batch samples per provider per batch size
+//       │
+//       ▼
+//   []BatchTestResult (this file)
+//       │
+//       │  FormatBatchTest() renders one row per (provider, size)
+//       │
+//       ▼
+//   Terminal output:
+//   ┌───────────────────────────────────────────────────────────────────────┐
+//   │ Provider        Size   OK Partial Rejected P50    P95    P99    Max    /req│
+//   │ ───────────────────────────────────────────────────────────────────── │
+//   │ alchemy           10   30       0        0  31ms   40ms   52ms   60ms  3ms │
+//   │ alchemy          100   30       0        0  88ms   97ms   120ms  130ms 0ms │
+//   │ llamanodes       100    0       0       30  —      —      —      —     —   │
+//   │   └ [protocol] batch rejected: RPC error -32600: batch too large      │
+//   └───────────────────────────────────────────────────────────────────────┘
+//
+// READING THE TABLE
+// =================
+//   - OK:       Every element in the batch returned a result.
+//   - Partial:  The batch went through but some elements errored — a sign of
+//               per-element rate limiting or a silent size cap.
+//   - Rejected: The provider refused the batch as a whole (HTTP error or a
+//               single error object instead of an array).
+//   - /req:     P50 batch latency divided by batch size. If this does not
+//               shrink as the size grows, the provider is serializing the
+//               batch internally and batching buys you nothing.
+// =============================================================================
+
+package format
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// BatchTestResult holds the statistics for one provider at one batch size.
+type BatchTestResult struct {
+	Name       string          // Provider name
+	Size       int             // Number of requests in each batch
+	Success    int             // Batches where every element returned a result
+	Partial    int             // Batches that returned, but with element errors
+	Rejected   int             // Batches refused as a whole
+	Total      int             // Total batches attempted
+	Latencies  []time.Duration // Latency of each batch that was not rejected
+	LastError  string          // Most recent batch- or element-level error (for diagnosis)
+	IDMismatch int             // Elements answered with an id we never sent
+}
+
+// PerRequestP50 returns the median batch latency divided by the batch size —
+// the effective cost of one request when sent inside a batch of this size.
+func (r BatchTestResult) PerRequestP50() time.Duration {
+	if r.Size <= 0 || len(r.Latencies) == 0 {
+		return 0
+	}
+	return CalculateTailLatency(r.Latencies).P50 / time.Duration(r.Size)
+}
+
+// FormatBatchTest renders one row per (provider, batch size) combination,
+// followed by the most recent error for any row that was not fully clean.
+func FormatBatchTest(w io.Writer, results []BatchTestResult) {
+	fmt.Fprintf(w, "%s %s %s %s %s %s  %s  %s  %s  %s\n",
+		Bold(fmt.Sprintf("%-14s", "Provider")),
+		Bold(fmt.Sprintf("%5s", "Size")),
+		Bold(fmt.Sprintf("%4s", "OK")),
+		Bold(fmt.Sprintf("%7s", "Partial")),
+		Bold(fmt.Sprintf("%8s", "Rejected")),
+		Bold(fmt.Sprintf("%-5s", "P50")),
+		Bold(fmt.Sprintf("%-5s", "P95")),
+		Bold(fmt.Sprintf("%-5s", "P99")),
+		Bold(fmt.Sprintf("%-5s", "Max")),
+		Bold(fmt.Sprintf("%-5s", "/req")))
+	fmt.Fprintln(w, strings.Repeat("─", 90))
+
+	for _, r := range results {
+		okStr := fmt.Sprintf("%4d", r.Success)
+		if r.Success < r.Total {
+			okStr = Yellow(okStr)
+		}
+		partialStr := fmt.Sprintf("%7d", r.Partial)
+		if r.Partial > 0 {
+			partialStr = Yellow(partialStr)
+		}
+		rejectedStr := fmt.Sprintf("%8d", r.Rejected)
+		if r.Rejected > 0 {
+			rejectedStr = Red(rejectedStr)
+		}
+
+		if len(r.Latencies) == 0 {
+			fmt.Fprintf(w, "%-14s %5d %s %s %s  %s  %s  %s  %s  %s\n",
+				r.Name, r.Size, okStr, partialStr, rejectedStr,
+				padRight(Dim("—"), 5), padRight(Dim("—"), 5),
+				padRight(Dim("—"), 5), padRight(Dim("—"), 5), padRight(Dim("—"), 5))
+		} else {
+			tail := CalculateTailLatency(r.Latencies)
+			fmt.Fprintf(w, "%-14s %5d %s %s %s  %s  %s  %s  %s  %s\n",
+				r.Name, r.Size, okStr, partialStr, rejectedStr,
+				padRight(ColorLatency(tail.P50.Milliseconds()), 5),
+				padRight(ColorLatency(tail.P95.Milliseconds()), 5),
+				padRight(ColorLatency(tail.P99.Milliseconds()), 5),
+				padRight(ColorLatency(tail.Max.Milliseconds()), 5),
+				padRight(Dim(fmt.Sprintf("%dms", r.PerRequestP50().Milliseconds())), 5))
+		}
+
+		if r.LastError != "" && r.Success < r.Total {
+			fmt.Fprintf(w, "  %s %s\n", Dim("└"), Red(r.LastError))
+		}
+		if r.IDMismatch > 0 {
+			fmt.Fprintf(w, "  %s %s\n", Dim("└"), Red(fmt.Sprintf("%d element(s) answered with a foreign response id", r.IDMismatch)))
+		}
+	}
+	fmt.Fprintln(w)
+}
@@ -0,0 +1,29 @@
+package format
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestBatchTestResult_PerRequestP50(t *testing.T) {
+	r := BatchTestResult{Size: 10, Latencies: []time.Duration{100 * time.Millisecond}}
+	if got := r.PerRequestP50(); got != 10*time.Millisecond {
+		t.Fatalf("got %v", got)
+	}
+	if got := (BatchTestResult{Size: 10}).PerRequestP50(); got != 0 {
+		t.Fatalf("empty latencies: got %v", got)
+	}
+}
+
+func TestFormatBatchTest_rejectedRowShowsError(t *testing.T) {
+	var buf bytes.Buffer
+	FormatBatchTest(&buf, []BatchTestResult{
+		{Name: "ok", Size: 10, Success: 2, Total: 2, Latencies: []time.Duration{time.Millisecond, 2 * time.Millisecond}},
+		{Name: "strict", Size: 100, Rejected: 2, Total: 2, LastError: "batch rejected: too large"},
+	})
+	out := buf.String()
+	if !containsAll(out, []string{"ok", "strict", "batch rejected: too large"}) {
+		t.Fatalf("output: %s", out)
+	}
+}
@@ -25,11 +25,16 @@
 //                                     ┌──────────────────────────────────┐
 //                                     │ Block #21,234,567               │
 //                                     │ ════════════════════════════════ │
-//                                     │   Hash:     0xa1b2c3d4...       │
+//                                     │   Hash:     0xa1b2c3d4... ✓     │
 //                                     │   Parent:   0x9876fedc...       │
 //                                     │   Timestamp: 2024-01-15...      │
 //                                     │   Gas:      29,847,293 / 30M    │
 //                                     │   Base Fee: 25.43 gwei          │
+//                                     │   Blob Gas: 3 blobs (target 6)  │
+//                                     │   Blob Fee: 0.000000023 gwei    │
+//                                     │   Withdrawals: 16 (0.52 ETH)    │
+//                                     │   Beacon Root: 0x5f3e...        │
+//                                     │   Requests: 0xe3b0...           │
 //                                     │   Transactions: 342             │
 //                                     │   Provider: alchemy (45ms)      │
 //                                     └──────────────────────────────────┘
@@ -53,6 +58,11 @@
 // 1. How raw block data flows through Parsed() into formatted output
 // 2. The io.Writer pattern and why it enables testability
 // 3. How pointer parameters avoid copying large structs
+//
+// The lines between Base Fee and Transactions appear only on blocks from
+// the fork that added them: withdrawals from Shanghai, blob gas and the
+// beacon root from Cancun, the requests hash from Prague. A pre-London
+// block shows just the original fields.
 // =============================================================================
 
 package format
@@ -60,6 +70,7 @@ package format
 import (
 	"fmt"
 	"io"
+	"math/big"
 	"time"
 
 	"github.com/dando385/eth-rpc-monitor/internal/rpc"
@@ -107,7 +118,10 @@ import (
 //   - Relative timestamps ("12s ago") via rpc.FormatTimestamp
 //   - Gas percentage to show block utilization
 //   - Dimmed secondary info (latency, gas percentage)
-func FormatBlock(w io.Writer, block *rpc.Block, provider string, latency time.Duration) {
+//
+// chainID picks the blob schedule (rpc.BlobScheduleAt); 0 = unknown, and
+// the blob fee is then labelled as computed under an assumed schedule.
+func FormatBlock(w io.Writer, block *rpc.Block, chainID uint64, provider string, latency time.Duration) {
 	// Call Parsed() to convert hex strings to native Go types.
 	// block.Parsed() is called on the *Block pointer. Go automatically
 	// dereferences the pointer to call the method — block.Parsed() is
@@ -115,7 +129,7 @@ func FormatBlock(w io.Writer, block *rpc.Block, provider string, latency time.Du
 	//
 	// The returned ParsedBlock `p` is a VALUE (not a pointer), stored on
 	// the stack. It contains typed fields ready for display formatting.
-	p := block.Parsed()
+	p := block.ParsedOn(chainID)
 
 	// Render the header: "Block #21,234,567"
 	// Bold() wraps text with ANSI bold escape codes.
@@ -126,7 +140,18 @@ func FormatBlock(w io.Writer, block *rpc.Block, provider string, latency time.Du
 	// Render block identity: hash and parent hash.
 	// These are the 32-byte (64 hex character) identifiers that uniquely
 	// identify each block and link it to its parent, forming the blockchain.
-	fmt.Fprintf(w, "  %s     %s\n", Bold("Hash:"), p.Hash)
+	//
+	// The hash is recomputed from the header fields the provider returned
+	// (rpc.VerifyBlockHash), so a provider serving a header that does not
+	// belong to its hash is called out right here instead of trusted.
+	check := rpc.VerifyBlockHash(block)
+	if check.OK() {
+		fmt.Fprintf(w, "  %s     %s %s\n", Bold("Hash:"), p.Hash,
+			Green("✓")+Dim(fmt.Sprintf(" verified (%s header)", check.Layout)))
+	} else {
+		fmt.Fprintf(w, "  %s     %s %s\n", Bold("Hash:"), p.Hash, Red("✗ UNVERIFIED"))
+		fmt.Fprintf(w, "            %s\n", Red(check.Problem()))
+	}
 	fmt.Fprintf(w, "  %s   %s\n", Bold("Parent:"), p.ParentHash)
 
 	// Render timestamp with human-readable "ago" suffix.
@@ -150,6 +175,36 @@ func FormatBlock(w io.Writer, block *rpc.Block, provider string, latency time.Du
 	// and converts wei to gwei for readability.
 	fmt.Fprintf(w, "  %s %s\n", Bold("Base Fee:"), rpc.FormatGwei(p.BaseFeePerGas))
 
+	// Render the blob fee market (Cancun and later). The blob base fee is
+	// not in the header: ParsedOn() computes it from excessBlobGas with the
+	// update fraction of the chain's fork at the block's timestamp, or of
+	// the fork the header layout implies when the chain is unknown
+	// (rpc/blob.go). The schedule is named so a reader knows which
+	// parameters were used, and whether they were assumed.
+	if g := p.BlobGas; g != nil {
+		fmt.Fprintf(w, "  %s %d blobs, %s blob gas %s\n",
+			Bold("Blob Gas:"),
+			g.Blobs,
+			rpc.FormatNumber(g.Used),
+			Dim(fmt.Sprintf("(target %d, max %d; excess %s)", g.Schedule.Target, g.Schedule.Max, rpc.FormatNumber(g.Excess))))
+		fmt.Fprintf(w, "  %s %s %s\n", Bold("Blob Fee:"), rpc.FormatGwei(g.BaseFee),
+			Dim("("+blobScheduleLabel(g)+")"))
+	}
+
+	// Render withdrawals (Shanghai and later): validator balances moving
+	// from the beacon chain to the execution layer. Amounts are gwei on the
+	// wire; FormatEther wants wei.
+	if p.WithdrawalsRoot != "" {
+		total := new(big.Int).Mul(new(big.Int).SetUint64(p.WithdrawalsGwei), big.NewInt(1e9))
+		fmt.Fprintf(w, "  %s %d %s\n", Bold("Withdrawals:"), p.Withdrawals, Dim("("+rpc.FormatEther(total)+")"))
+	}
+	if p.ParentBeaconBlockRoot != "" {
+		fmt.Fprintf(w, "  %s %s\n", Bold("Beacon Root:"), p.ParentBeaconBlockRoot)
+	}
+	if p.RequestsHash != "" {
+		fmt.Fprintf(w, "  %s %s\n", Bold("Requests:"), p.RequestsHash)
+	}
+
 	// Render transaction count.
 	// p.TxCount is derived from len(block.Transactions) in the Parsed() method.
 	fmt.Fprintf(w, "  %s %d\n", Bold("Transactions:"), p.TxCount)
@@ -161,3 +216,13 @@ func FormatBlock(w io.Writer, block *rpc.Block, provider string, latency time.Du
 	fmt.Fprintf(w, "  %s %s %s\n", Bold("Provider:"), provider, Dim(fmt.Sprintf("(%dms)", latency.Milliseconds())))
 	fmt.Fprintln(w)
 }
+
+// blobScheduleLabel names the blob schedule a fee was computed under, and
+// says so when it was inferred from the header layout (rpc.BlobGas.Assumed)
+// instead of read from the chain's fork timetable.
+func blobScheduleLabel(g *rpc.BlobGas) string {
+	if g.Assumed {
+		return g.Schedule.Fork + " schedule assumed from header layout"
+	}
+	return g.Schedule.Fork + " schedule"
+}
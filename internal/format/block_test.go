@@ -0,0 +1,56 @@
+package format
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/dando385/eth-rpc-monitor/internal/rpc"
+)
+
+func TestFormatBlock_flagsUnverifiableHash(t *testing.T) {
+	var buf bytes.Buffer
+	FormatBlock(&buf, &rpc.Block{Number: "0x1", Hash: "0xaa", GasLimit: "0x1"}, 0, "p", time.Millisecond)
+	out := stripANSI(buf.String())
+	if !containsAll(out, []string{"0xaa ✗ UNVERIFIED", "cannot verify header: missing parentHash"}) {
+		t.Fatalf("output: %s", out)
+	}
+}
+
+func TestFormatBlock_forkFields(t *testing.T) {
+	block := &rpc.Block{
+		Number: "0x1", Hash: "0xaa", GasLimit: "0x1",
+		WithdrawalsRoot:       "0xw",
+		Withdrawals:           []rpc.Withdrawal{{Amount: "0x77359400"}}, // 2 ETH in gwei
+		BlobGasUsed:           "0x60000",
+		ExcessBlobGas:         "0x0",
+		ParentBeaconBlockRoot: "0xbeac",
+		RequestsHash:          "0xreq",
+	}
+	var buf bytes.Buffer
+	FormatBlock(&buf, block, 0, "p", time.Millisecond)
+	out := stripANSI(buf.String())
+	if !containsAll(out, []string{
+		"Blob Gas: 3 blobs, 393,216 blob gas (target 6, max 9; excess 0)",
+		"Blob Fee: 0.000000001 gwei (Prague schedule assumed from header layout)",
+		"Withdrawals: 1 (2 ETH)",
+		"Beacon Root: 0xbeac",
+		"Requests: 0xreq",
+	}) {
+		t.Fatalf("output: %s", out)
+	}
+
+	// On mainnet after BPO2 the same layout is priced under BPO2.
+	block.Timestamp = "0x6b49d200"
+	buf.Reset()
+	FormatBlock(&buf, block, 1, "p", time.Millisecond)
+	if out := stripANSI(buf.String()); !containsAll(out, []string{"(target 14, max 21; excess 0)", "(BPO2 schedule)"}) {
+		t.Fatalf("mainnet output: %s", out)
+	}
+
+	buf.Reset()
+	FormatBlock(&buf, &rpc.Block{Number: "0x1", Hash: "0xaa", GasLimit: "0x1"}, 0, "p", time.Millisecond)
+	if out := stripANSI(buf.String()); containsAll(out, []string{"Blob"}) || containsAll(out, []string{"Withdrawals"}) {
+		t.Fatalf("a pre-Shanghai block has no fork fields: %s", out)
+	}
+}
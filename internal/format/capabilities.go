@@ -0,0 +1,154 @@
+// =============================================================================
+// FILE: internal/format/capabilities.go
+// ROLE: Capability Matrix — Which Methods Each Provider Serves
+// =============================================================================
+//
+// SYSTEM CONTEXT
+// ==============
+// This file renders the output of the `capabilities` command: one row per
+// catalog method, one column per provider, each cell the outcome of one
+// call (rpc/capability.go):
+//
+//   Method                     alchemy        infura         publicnode
+//   ──────────────────────────────────────────────────────────────────────
+//   eth_getBlockReceipts       ✓ 48ms         ✓ 95ms         ✓ 210ms
+//   eth_getLogs (10k blocks)   ⚠ restricted   ✓ 1320ms       ⚠ restricted
+//   debug_traceTransaction     ⚠ restricted   ✗ missing      ✗ missing
+//   txpool_status              ✗ missing      ✗ missing      ! broken
+//   ──────────────────────────────────────────────────────────────────────
+//   Supported                  9 of 12        8 of 12        7 of 12
+//
+//   ✓ supported  ✗ missing (method not found)  ⚠ restricted (auth, plan or limit)
+//   ! broken (other error)  – skipped (placeholder could not be filled)
+//
+// A details list follows with the error behind every restricted, broken or
+// skipped cell, since "restricted" alone does not say which plan or limit.
+// Providers that failed before probing (no head) get no column; they are
+// listed under the matrix instead.
+// =============================================================================
+
+package format
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/dando385/eth-rpc-monitor/internal/rpc"
+)
+
+// capabilityCellWidth is the visible width of the widest cell, "⚠ restricted".
+const capabilityCellWidth = 12
+
+// CapabilityResult is one provider's probe of the whole catalog.
+type CapabilityResult struct {
+	Provider string
+	Target   rpc.ProbeTarget
+	Methods  []rpc.MethodResult // Index-aligned with the catalog
+	Error    error              // Set when the provider could not be probed at all
+}
+
+// Supported counts the catalog entries r answered.
+func (r CapabilityResult) Supported() int {
+	n := 0
+	for _, m := range r.Methods {
+		if m.Status == rpc.CapSupported {
+			n++
+		}
+	}
+	return n
+}
+
+// FormatCapabilities renders the method × provider matrix, a legend, the
+// errors behind non-supported cells, and providers that failed outright.
+func FormatCapabilities(w io.Writer, catalog []rpc.MethodProbe, results []CapabilityResult) {
+	var probed []CapabilityResult
+	failures := ErrorCounts{}
+	for _, r := range results {
+		if r.Error != nil {
+			failures.Add(r.Error)
+			continue
+		}
+		probed = append(probed, r)
+	}
+
+	nameWidth := len("Supported")
+	for _, p := range catalog {
+		if n := len(p.Label()); n > nameWidth {
+			nameWidth = n
+		}
+	}
+	widths := make([]int, len(probed))
+	total := nameWidth + 3
+	for i, r := range probed {
+		widths[i] = len(r.Provider)
+		if widths[i] < capabilityCellWidth {
+			widths[i] = capabilityCellWidth
+		}
+		total += widths[i] + 3
+	}
+
+	if len(probed) > 0 {
+		fmt.Fprintf(w, "\n%s", Bold(fmt.Sprintf("%-*s   ", nameWidth, "Method")))
+		for i, r := range probed {
+			fmt.Fprintf(w, "%s", Bold(fmt.Sprintf("%-*s   ", widths[i], r.Provider)))
+		}
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, strings.Repeat("─", total))
+		for row, p := range catalog {
+			fmt.Fprintf(w, "%-*s   ", nameWidth, p.Label())
+			for i, r := range probed {
+				fmt.Fprintf(w, "%s   ", padRight(capabilityCell(r.Methods[row]), widths[i]))
+			}
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintln(w, strings.Repeat("─", total))
+		fmt.Fprintf(w, "%-*s   ", nameWidth, "Supported")
+		for i, r := range probed {
+			fmt.Fprintf(w, "%-*s   ", widths[i], fmt.Sprintf("%d of %d", r.Supported(), len(catalog)))
+		}
+		fmt.Fprintln(w)
+
+		fmt.Fprintf(w, "\n%s supported  %s missing (method not found)  %s restricted (auth, plan or limit)\n%s broken (other error)  %s skipped (placeholder could not be filled)\n",
+			Green("✓"), Dim("✗"), Yellow("⚠"), Red("!"), Dim("–"))
+	}
+
+	var details []string
+	for _, r := range probed {
+		for _, m := range r.Methods {
+			switch m.Status {
+			case rpc.CapRestricted, rpc.CapBroken, rpc.CapSkipped:
+				details = append(details, fmt.Sprintf("  %-14s %-*s   %s", r.Provider, nameWidth, m.Probe.Label(), Dim(truncate(m.Error.Error(), 80))))
+			}
+		}
+	}
+	if len(details) > 0 {
+		fmt.Fprintf(w, "\n%s\n%s\n", Bold("Details:"), strings.Join(details, "\n"))
+	}
+
+	if failures.Total() > 0 {
+		fmt.Fprintln(w)
+		for _, r := range results {
+			if r.Error != nil {
+				fmt.Fprintf(w, "%-14s %s %s\n", r.Provider, Red("ERROR:"), ErrorLabel(r.Error))
+			}
+		}
+		fmt.Fprintf(w, "%s %d of %d providers failed: %s\n", Red("✗"), failures.Total(), len(results), failures.Summary())
+	}
+}
+
+// capabilityCell is one matrix cell: a mark and, when supported, the latency.
+func capabilityCell(m rpc.MethodResult) string {
+	switch m.Status {
+	case rpc.CapSupported:
+		return Green("✓") + " " + ColorLatency(m.Latency.Milliseconds())
+	case rpc.CapUnsupported:
+		return Dim("✗ missing")
+	case rpc.CapRestricted:
+		return Yellow("⚠ restricted")
+	case rpc.CapSkipped:
+		return Dim("– skipped")
+	default:
+		return Red("! broken")
+	}
+}
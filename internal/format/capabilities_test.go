@@ -0,0 +1,52 @@
+package format
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dando385/eth-rpc-monitor/internal/rpc"
+)
+
+func TestFormatCapabilities(t *testing.T) {
+	catalog := []rpc.MethodProbe{
+		{Method: "eth_getBlockReceipts"},
+		{Name: "eth_getLogs (10k blocks)", Method: "eth_getLogs"},
+		{Method: "trace_transaction"},
+	}
+	results := []CapabilityResult{
+		{Provider: "alchemy", Methods: []rpc.MethodResult{
+			{Probe: catalog[0], Status: rpc.CapSupported, Latency: 48 * time.Millisecond},
+			{Probe: catalog[1], Status: rpc.CapRestricted, Error: &rpc.RPCError{Code: -32005, Message: "block range exceeds 2000"}},
+			{Probe: catalog[2], Status: rpc.CapUnsupported, Error: &rpc.RPCError{Code: -32601, Message: "method not found"}},
+		}},
+		{Provider: "publicnode", Methods: []rpc.MethodResult{
+			{Probe: catalog[0], Status: rpc.CapSupported, Latency: 210 * time.Millisecond},
+			{Probe: catalog[1], Status: rpc.CapSupported, Latency: 1320 * time.Millisecond},
+			{Probe: catalog[2], Status: rpc.CapSkipped, Error: errors.New("no transaction in the 16 blocks up to 97")},
+		}},
+		{Provider: "llamanodes", Error: errors.New("head: context deadline exceeded")},
+	}
+	var buf bytes.Buffer
+	FormatCapabilities(&buf, catalog, results)
+	out := stripANSI(buf.String())
+	if !containsAll(out, []string{
+		"Method                     alchemy        publicnode",
+		"eth_getBlockReceipts       ✓ 48ms         ✓ 210ms",
+		"eth_getLogs (10k blocks)   ⚠ restricted   ✓ 1320ms",
+		"trace_transaction          ✗ missing      – skipped",
+		"Supported                  1 of 3         2 of 3",
+		"Details:",
+		"alchemy        eth_getLogs (10k blocks)   RPC error -32005: block range exceeds 2000",
+		"publicnode     trace_transaction          no transaction in the 16 blocks",
+		"llamanodes     ERROR:",
+		"✗ 1 of 3 providers failed",
+	}) {
+		t.Fatalf("output:\n%s", out)
+	}
+	if strings.Contains(out, "RPC error -32601") {
+		t.Fatalf("unsupported cells should not be listed in details:\n%s", out)
+	}
+}
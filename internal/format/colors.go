@@ -68,6 +68,7 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/fatih/color"
 )
@@ -173,11 +174,12 @@ func stripANSI(str string) string {
 //	// Renders as: "45ms      " (10 visible characters, aligned)
 //
 // Algorithm:
-//  1. Strip ANSI codes and measure the visible length
+//  1. Strip ANSI codes and measure the visible length in runes (so a
+//     multi-byte placeholder like "—" counts as one column, not three)
 //  2. If visible length < desired width, append spaces
 //  3. If visible length >= desired width, return unchanged (no truncation)
 func padRight(str string, width int) string {
-	visibleLen := len(stripANSI(str))
+	visibleLen := utf8.RuneCountInString(stripANSI(str))
 	if visibleLen < width {
 		return str + strings.Repeat(" ", width-visibleLen)
 	}
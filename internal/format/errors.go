@@ -0,0 +1,110 @@
+// =============================================================================
+// FILE: internal/format/errors.go
+// ROLE: Failure Breakdown — Counting Errors by Category
+// =============================================================================
+//
+// SYSTEM CONTEXT
+// ==============
+// rpc.Classify (internal/rpc/errors.go) sorts every failure into a category:
+// timeout, rate-limited, auth, server, protocol, not-found, network. This
+// file counts those categories and renders them, so `test`, `snapshot` and
+// `monitor` can say "2 timeouts and 5 rate limits" instead of "93% success".
+//
+//   Failures by category
+//   Provider        timeout rate-limited server
+//   ─────────────────────────────────────────────
+//   infura                2            5      —
+//   llamanodes            —            —      1
+//
+// Only categories that actually occurred get a column, so a clean run
+// prints nothing at all and a noisy one stays narrow.
+// =============================================================================
+
+package format
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/dando385/eth-rpc-monitor/internal/rpc"
+)
+
+// ErrorCounts counts failures per category. The zero value is nil; use
+// make(ErrorCounts) (or ErrorCounts{}) before calling Add.
+type ErrorCounts map[rpc.ErrorCategory]int
+
+// Add classifies err and increments its category. nil errors are ignored.
+func (c ErrorCounts) Add(err error) {
+	if err != nil {
+		c[rpc.Classify(err)]++
+	}
+}
+
+// Total returns the number of failures across all categories.
+func (c ErrorCounts) Total() int {
+	n := 0
+	for _, v := range c {
+		n += v
+	}
+	return n
+}
+
+// Summary renders the counts as "timeout×2 rate-limited×5" in the fixed
+// rpc.Categories order, or "" when there are no failures.
+func (c ErrorCounts) Summary() string {
+	var parts []string
+	for _, cat := range rpc.Categories {
+		if n := c[cat]; n > 0 {
+			parts = append(parts, fmt.Sprintf("%s×%d", cat, n))
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// ErrorLabel renders one error as "[category] message" for error rows.
+func ErrorLabel(err error) string {
+	return fmt.Sprintf("[%s] %v", rpc.Classify(err), err)
+}
+
+// FormatErrorBreakdown renders a per-provider table of failure counts, with
+// one column per category that occurred anywhere. names and counts are
+// parallel slices. Nothing is printed when there were no failures.
+func FormatErrorBreakdown(w io.Writer, names []string, counts []ErrorCounts) {
+	var cols []rpc.ErrorCategory
+	for _, cat := range rpc.Categories {
+		for _, c := range counts {
+			if c[cat] > 0 {
+				cols = append(cols, cat)
+				break
+			}
+		}
+	}
+	if len(cols) == 0 {
+		return
+	}
+
+	header := Bold(fmt.Sprintf("%-14s", "Provider"))
+	for _, cat := range cols {
+		header += " " + Bold(fmt.Sprintf("%12s", cat))
+	}
+	fmt.Fprintln(w, Bold("Failures by category"))
+	fmt.Fprintln(w, header)
+	fmt.Fprintln(w, strings.Repeat("─", 15+13*len(cols)))
+
+	for i, c := range counts {
+		if c.Total() == 0 {
+			continue
+		}
+		row := fmt.Sprintf("%-14s", names[i])
+		for _, cat := range cols {
+			if n := c[cat]; n > 0 {
+				row += " " + Red(fmt.Sprintf("%12d", n))
+			} else {
+				row += " " + strings.Repeat(" ", 11) + Dim("—")
+			}
+		}
+		fmt.Fprintln(w, row)
+	}
+	fmt.Fprintln(w)
+}
@@ -0,0 +1,49 @@
+package format
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/dando385/eth-rpc-monitor/internal/rpc"
+)
+
+func TestErrorCounts_summaryOrder(t *testing.T) {
+	c := ErrorCounts{}
+	c.Add(&rpc.HTTPError{StatusCode: 503})
+	c.Add(&rpc.HTTPError{StatusCode: 429})
+	c.Add(&rpc.HTTPError{StatusCode: 429})
+	c.Add(nil)
+	if c.Total() != 3 {
+		t.Fatalf("total %d", c.Total())
+	}
+	if got := c.Summary(); got != "rate-limited×2 server×1" {
+		t.Fatalf("summary %q", got)
+	}
+}
+
+func TestFormatErrorBreakdown_onlyOccurringColumns(t *testing.T) {
+	var buf bytes.Buffer
+	FormatErrorBreakdown(&buf, []string{"a", "b"}, []ErrorCounts{nil, nil})
+	if buf.Len() != 0 {
+		t.Fatalf("expected no output without failures: %q", buf.String())
+	}
+
+	FormatErrorBreakdown(&buf, []string{"a", "b"}, []ErrorCounts{{rpc.CategoryTimeout: 2}, nil})
+	out := buf.String()
+	if !containsAll(out, []string{"Failures by category", "timeout", "a"}) || strings.Contains(out, "rate-limited") {
+		t.Fatalf("output: %s", out)
+	}
+}
+
+func TestFormatTest_idMismatchWarning(t *testing.T) {
+	var buf bytes.Buffer
+	FormatTest(&buf, []TestResult{
+		{Name: "proxied", Success: 1, Total: 3, IDMismatch: 2, Errors: ErrorCounts{rpc.CategoryProtocol: 2}},
+		{Name: "clean", Success: 3, Total: 3},
+	})
+	out := buf.String()
+	if !containsAll(out, []string{"RESPONSE ID MISMATCH DETECTED", "proxied (2)"}) || strings.Contains(out, "clean (") {
+		t.Fatalf("output: %s", out)
+	}
+}
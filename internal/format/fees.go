@@ -0,0 +1,265 @@
+// =============================================================================
+// FILE: internal/format/fees.go
+// ROLE: Fee Market Display — How Far Apart Providers' Fee Advice Is
+// =============================================================================
+//
+// SYSTEM CONTEXT
+// ==============
+// This file renders the output of the `fees` command: each provider's answer
+// to the three fee methods (rpc/fees.go), then how far apart those answers
+// are, metric by metric.
+//
+//   Provider       Gas Price        Priority Fee     Next Base Fee    Tip p50          Next Blob Fee    Latency
+//   ─────────────────────────────────────────────────────────────────────────────────────────────────────────
+//   alchemy        13.10 gwei       1.00 gwei        12.06 gwei       0.50 gwei        0.000000001 gwei 142ms
+//   infura         12.51 gwei       0.0004 gwei ⚠    12.06 gwei       0.50 gwei        0.000000001 gwei 98ms
+//   publicnode     13.06 gwei       1.00 gwei        12.06 gwei       0.50 gwei        —                76ms
+//
+//   Spread across providers
+//   Metric                 Min              Median           Max              Spread
+//   eth_gasPrice           12.51 gwei       13.06 gwei       13.10 gwei       4.5%
+//   maxPriorityFeePerGas   0.0004 gwei      1.00 gwei        1.00 gwei        100.0%
+//   ...
+//
+//   ⚠ 1 outlier(s), more than 25% from the median:
+//     infura         maxPriorityFeePerGas   0.0004 gwei   (-100.0%)
+//
+// There is one column per metric (FeeMetrics): the two suggestions, the next
+// block's base fee and blob base fee as eth_feeHistory computes them, and,
+// per requested percentile, the median across the history's blocks of the
+// tip paid at that percentile. A cell that stands out from the other
+// providers by more than the outlier threshold is marked ⚠.
+//
+// OUTLIERS
+// ========
+// A value is an outlier when it is further than the threshold from the
+// median of all providers' values for that metric. It takes three values
+// for the median to say which side is odd, so with fewer providers only the
+// spread is shown. A median of zero (tips in empty blocks) has no relative
+// distance, so that metric reports no outliers.
+// =============================================================================
+
+package format
+
+import (
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/dando385/eth-rpc-monitor/internal/rpc"
+)
+
+// feeCellWidth fits "0.000000001 gwei" and a following ⚠.
+const feeCellWidth = 18
+
+// FeeResult is one provider's fee quote.
+type FeeResult struct {
+	Provider string
+	Quote    *rpc.FeeQuote
+	Latency  time.Duration
+	Error    error
+}
+
+// FeeMetric is one compared value: its name and how to read it from a quote
+// (nil when the quote does not have it).
+type FeeMetric struct {
+	Name   string
+	Header string // Column header in the provider table
+	Value  func(q *rpc.FeeQuote) *big.Int
+}
+
+// FeeMetrics lists the compared values, with one median tip per percentile.
+func FeeMetrics(percentiles []float64) []FeeMetric {
+	history := func(f func(h *rpc.FeeHistory) *big.Int) func(q *rpc.FeeQuote) *big.Int {
+		return func(q *rpc.FeeQuote) *big.Int {
+			if q.History == nil {
+				return nil
+			}
+			return f(q.History)
+		}
+	}
+	metrics := []FeeMetric{
+		{Name: "eth_gasPrice", Header: "Gas Price", Value: func(q *rpc.FeeQuote) *big.Int { return q.GasPrice }},
+		{Name: "maxPriorityFeePerGas", Header: "Priority Fee", Value: func(q *rpc.FeeQuote) *big.Int { return q.PriorityFee }},
+		{Name: "next base fee", Header: "Next Base Fee", Value: history((*rpc.FeeHistory).NextBaseFee)},
+	}
+	for i, p := range percentiles {
+		i, label := i, fmt.Sprintf("p%g", p)
+		metrics = append(metrics, FeeMetric{
+			Name:   "tip " + label,
+			Header: "Tip " + label,
+			Value:  history(func(h *rpc.FeeHistory) *big.Int { return h.MedianReward(i) }),
+		})
+	}
+	return append(metrics, FeeMetric{Name: "next blob base fee", Header: "Next Blob Fee", Value: history((*rpc.FeeHistory).NextBlobBaseFee)})
+}
+
+// FeeOutlier is one provider's value that is far from the median.
+type FeeOutlier struct {
+	Provider     string
+	Value        *big.Int
+	DeviationPct float64 // Signed distance from the median, in percent of it
+}
+
+// FeeSpread is how one metric varies across the providers that reported it.
+type FeeSpread struct {
+	Metric    string
+	Providers int      // How many reported a value
+	Min       *big.Int // nil when none did
+	Median    *big.Int
+	Max       *big.Int
+	SpreadPct float64 // (Max-Min) in percent of Median; 0 when Median is 0
+	Outliers  []FeeOutlier
+}
+
+// FeeSpreads compares every metric across results. outlierPct is the
+// distance from the median, in percent, beyond which a value is an outlier.
+func FeeSpreads(results []FeeResult, metrics []FeeMetric, outlierPct float64) []FeeSpread {
+	spreads := make([]FeeSpread, len(metrics))
+	for m, metric := range metrics {
+		s := FeeSpread{Metric: metric.Name}
+		var values []*big.Int
+		var providers []string
+		for _, r := range results {
+			if r.Error != nil || r.Quote == nil {
+				continue
+			}
+			if v := metric.Value(r.Quote); v != nil {
+				values = append(values, v)
+				providers = append(providers, r.Provider)
+			}
+		}
+		s.Providers = len(values)
+		if len(values) == 0 {
+			spreads[m] = s
+			continue
+		}
+		s.Min, s.Max, s.Median = values[0], values[0], rpc.MedianBig(values)
+		for _, v := range values {
+			if v.Cmp(s.Min) < 0 {
+				s.Min = v
+			}
+			if v.Cmp(s.Max) > 0 {
+				s.Max = v
+			}
+		}
+		if s.Median.Sign() > 0 {
+			s.SpreadPct = percentOf(new(big.Int).Sub(s.Max, s.Min), s.Median)
+			if len(values) >= 3 {
+				for i, v := range values {
+					dev := percentOf(new(big.Int).Sub(v, s.Median), s.Median)
+					if dev > outlierPct || dev < -outlierPct {
+						s.Outliers = append(s.Outliers, FeeOutlier{Provider: providers[i], Value: v, DeviationPct: dev})
+					}
+				}
+			}
+		}
+		spreads[m] = s
+	}
+	return spreads
+}
+
+// percentOf returns a / b × 100. b must be positive.
+func percentOf(a, b *big.Int) float64 {
+	f, _ := new(big.Float).Quo(new(big.Float).SetInt(a), new(big.Float).SetInt(b)).Float64()
+	return f * 100
+}
+
+// FormatFees renders the per-provider fee table, the spread per metric and
+// the outliers. blocks is the eth_feeHistory window that was asked for.
+func FormatFees(w io.Writer, results []FeeResult, metrics []FeeMetric, spreads []FeeSpread, blocks uint64, outlierPct float64) {
+	width := 15 + (feeCellWidth+1)*len(metrics) + 7
+	outlier := map[string]bool{} // metric + "/" + provider
+	total := 0
+	for _, s := range spreads {
+		for _, o := range s.Outliers {
+			outlier[s.Metric+"/"+o.Provider] = true
+			total++
+		}
+	}
+
+	fmt.Fprintf(w, "\n%s", Bold(fmt.Sprintf("%-14s", "Provider")))
+	for _, m := range metrics {
+		fmt.Fprintf(w, " %s", Bold(fmt.Sprintf("%-*s", feeCellWidth, m.Header)))
+	}
+	fmt.Fprintf(w, " %s\n", Bold("Latency"))
+	fmt.Fprintln(w, strings.Repeat("─", width))
+
+	failures := ErrorCounts{}
+	var methodErrors []string
+	for _, r := range results {
+		if r.Error != nil {
+			failures.Add(r.Error)
+			fmt.Fprintf(w, "%-14s %s %s\n", r.Provider, Red("ERROR:"), ErrorLabel(r.Error))
+			continue
+		}
+		fmt.Fprintf(w, "%-14s", r.Provider)
+		for _, m := range metrics {
+			cell := Dim("—")
+			if v := m.Value(r.Quote); v != nil {
+				cell = rpc.FormatGwei(v)
+				if outlier[m.Name+"/"+r.Provider] {
+					cell = Yellow(cell + " ⚠")
+				}
+			}
+			fmt.Fprintf(w, " %s", padRight(cell, feeCellWidth))
+		}
+		fmt.Fprintf(w, " %s\n", ColorLatency(r.Latency.Milliseconds()))
+		for _, method := range rpc.FeeMethods {
+			if err := r.Quote.Err(method); err != nil {
+				methodErrors = append(methodErrors, fmt.Sprintf("  %-14s %-26s %s", r.Provider, method, truncate(ErrorLabel(err), 70)))
+			}
+		}
+	}
+	fmt.Fprintln(w)
+	if blocks > 0 {
+		fmt.Fprintln(w, Dim(fmt.Sprintf("Tips are the median across the last %d blocks of eth_feeHistory; base fees are for the next block.", blocks)))
+		fmt.Fprintln(w)
+	}
+
+	fmt.Fprintln(w, Bold("Spread across providers"))
+	fmt.Fprintf(w, "  %s %s %s %s %s\n",
+		Bold(fmt.Sprintf("%-22s", "Metric")),
+		Bold(fmt.Sprintf("%-*s", feeCellWidth, "Min")),
+		Bold(fmt.Sprintf("%-*s", feeCellWidth, "Median")),
+		Bold(fmt.Sprintf("%-*s", feeCellWidth, "Max")),
+		Bold("Spread"))
+	for _, s := range spreads {
+		if s.Providers == 0 {
+			fmt.Fprintf(w, "  %-22s %s\n", s.Metric, Dim("no provider reported it"))
+			continue
+		}
+		spread := fmt.Sprintf("%.1f%%", s.SpreadPct)
+		switch {
+		case s.Providers < 2:
+			spread = Dim("1 provider")
+		case s.SpreadPct > outlierPct:
+			spread = Yellow(spread)
+		}
+		fmt.Fprintf(w, "  %-22s %-*s %-*s %-*s %s\n", s.Metric,
+			feeCellWidth, rpc.FormatGwei(s.Min), feeCellWidth, rpc.FormatGwei(s.Median), feeCellWidth, rpc.FormatGwei(s.Max), spread)
+	}
+	fmt.Fprintln(w)
+
+	if total > 0 {
+		fmt.Fprintln(w, Yellow(fmt.Sprintf("⚠ %d outlier(s), more than %g%% from the median:", total, outlierPct)))
+		for _, s := range spreads {
+			for _, o := range s.Outliers {
+				fmt.Fprintf(w, "  %-14s %-22s %-*s (%+.1f%%)\n", o.Provider, s.Metric, feeCellWidth, rpc.FormatGwei(o.Value), o.DeviationPct)
+			}
+		}
+		fmt.Fprintln(w)
+	}
+	if len(methodErrors) > 0 {
+		fmt.Fprintln(w, Bold("Failed methods:"))
+		for _, line := range methodErrors {
+			fmt.Fprintln(w, line)
+		}
+		fmt.Fprintln(w)
+	}
+	if failures.Total() > 0 {
+		fmt.Fprintf(w, "%s %d of %d providers failed: %s\n\n", Red("✗"), failures.Total(), len(results), failures.Summary())
+	}
+}
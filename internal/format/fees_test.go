@@ -0,0 +1,104 @@
+package format
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/dando385/eth-rpc-monitor/internal/rpc"
+)
+
+const gwei = 1_000_000_000
+
+func feeResult(provider string, gasPrice, tip, nextBase int64) FeeResult {
+	return FeeResult{
+		Provider: provider,
+		Quote: &rpc.FeeQuote{
+			GasPrice:    big.NewInt(gasPrice),
+			PriorityFee: big.NewInt(tip),
+			History: &rpc.FeeHistory{
+				Percentiles:  []float64{50},
+				BaseFees:     []*big.Int{big.NewInt(nextBase), big.NewInt(nextBase)},
+				GasUsedRatio: []float64{0.5},
+				Rewards:      [][]*big.Int{{big.NewInt(0)}},
+			},
+			Errors: map[string]error{},
+		},
+	}
+}
+
+func TestFeeSpreads(t *testing.T) {
+	results := []FeeResult{
+		feeResult("a", 13*gwei, 1*gwei, 12*gwei),
+		feeResult("b", 12*gwei, 400_000, 12*gwei),
+		feeResult("c", 13*gwei, 1*gwei, 12*gwei),
+		{Provider: "d", Error: errors.New("down")},
+	}
+	metrics := FeeMetrics([]float64{50})
+	spreads := FeeSpreads(results, metrics, 25)
+	if len(spreads) != 5 {
+		t.Fatalf("%d spreads for metrics %v", len(spreads), metrics)
+	}
+
+	gas := spreads[0]
+	if gas.Providers != 3 || gas.Min.Int64() != 12*gwei || gas.Median.Int64() != 13*gwei || len(gas.Outliers) != 0 {
+		t.Fatalf("gas price = %+v", gas)
+	}
+	if gas.SpreadPct < 7.6 || gas.SpreadPct > 7.7 {
+		t.Errorf("gas price spread = %.2f%%, want 1/13", gas.SpreadPct)
+	}
+
+	tip := spreads[1]
+	if len(tip.Outliers) != 1 || tip.Outliers[0].Provider != "b" || tip.Outliers[0].DeviationPct > -99 {
+		t.Fatalf("priority fee outliers = %+v", tip.Outliers)
+	}
+	if p50 := spreads[3]; p50.Metric != "tip p50" || p50.Median.Sign() != 0 || p50.SpreadPct != 0 || p50.Outliers != nil {
+		t.Errorf("all-zero tips = %+v", p50)
+	}
+	if blob := spreads[4]; blob.Providers != 0 || blob.Min != nil {
+		t.Errorf("no provider reported a blob fee, got %+v", blob)
+	}
+}
+
+func TestFeeSpreads_twoProvidersNoOutliers(t *testing.T) {
+	spreads := FeeSpreads([]FeeResult{
+		feeResult("a", 10*gwei, 1*gwei, 9*gwei),
+		feeResult("b", 30*gwei, 1*gwei, 9*gwei),
+	}, FeeMetrics(nil), 25)
+	if spreads[0].SpreadPct != 200 || spreads[0].Outliers != nil {
+		t.Fatalf("two providers: %+v", spreads[0])
+	}
+}
+
+func TestFormatFees(t *testing.T) {
+	results := []FeeResult{
+		feeResult("a", 13*gwei, 1*gwei, 12*gwei),
+		feeResult("b", 12*gwei, 400_000, 12*gwei),
+		feeResult("c", 13*gwei, 1*gwei, 12*gwei),
+		{Provider: "d", Error: &rpc.HTTPError{StatusCode: 429}},
+		feeResult("e", 13*gwei, 0, 12*gwei),
+	}
+	results[4].Quote.Errors[rpc.MethodMaxPriorityFee] = errors.New("the method eth_maxPriorityFeePerGas does not exist")
+	results[4].Quote.PriorityFee = nil
+	metrics := FeeMetrics([]float64{50})
+
+	var buf bytes.Buffer
+	FormatFees(&buf, results, metrics, FeeSpreads(results, metrics, 10), 20, 10)
+	out := stripANSI(buf.String())
+	if !containsAll(out, []string{
+		"Gas Price", "Priority Fee", "Tip p50", "Next Blob Fee",
+		"0.0004 gwei ⚠", "last 20 blocks",
+		"Spread across providers", "eth_gasPrice", "12.00 gwei", "13.00 gwei",
+		"no provider reported it",
+		"⚠ 1 outlier(s), more than 10% from the median:", "(-100.0%)",
+		"Failed methods:", "eth_maxPriorityFeePerGas",
+		"1 of 5 providers failed",
+	}) {
+		t.Fatalf("output:\n%s", out)
+	}
+	if strings.Contains(out, "12.00 gwei ⚠") {
+		t.Errorf("the lower gas price is 7.7%% off the median, inside the 10%% threshold:\n%s", out)
+	}
+}
@@ -0,0 +1,228 @@
+// =============================================================================
+// FILE: internal/format/logs.go
+// ROLE: Log Set Comparison & Display — Who Is Missing or Adding Logs?
+// =============================================================================
+//
+// SYSTEM CONTEXT
+// ==============
+// This file renders the output of the `logs` command, which runs the same
+// eth_getLogs filter against every provider. snapshot.go asks "do providers
+// agree on this block?"; this file asks "do providers return the same EVENTS
+// for this range?" — a much easier thing to get subtly wrong, because a
+// provider that drops logs still answers quickly and successfully.
+//
+//   Provider        Logs  Requests  Latency  Result
+//   ──────────────────────────────────────────────────────────────
+//   alchemy        1,234         4    812ms  ✓ matches consensus
+//   infura         1,200         1    320ms  ✗ missing 34
+//   publicnode         —         —        —  [timeout] context deadline exceeded
+//     alchemy: range limit hit — split 3 time(s), widest accepted span 2,500 blocks
+//
+//   ✗ LOG SET MISMATCH DETECTED (consensus: 1,234 logs)
+//     infura is missing 34 log(s):
+//       block 0x12a0…9f3c  tx 0x5c50…2060  logIndex 17
+//       … and 31 more
+//
+// LOG IDENTITY AND CONSENSUS
+// ==========================
+// A log is identified by (blockHash, transactionHash, logIndex): the block it
+// is in, the transaction that emitted it, and its position in the block.
+// Comparing identities rather than counts catches a provider that returns the
+// right NUMBER of logs but from the wrong fork.
+//
+// The reference set is every log returned by AT LEAST HALF of the providers
+// that answered. Against it:
+//
+//   missing = in the reference set, not returned by this provider
+//   extra   = returned by this provider, not in the reference set
+//
+// "At least half" (rather than a strict majority) matters with two
+// providers: truncation is far more common than invention, so when one
+// provider has a log and the other does not, the one without it is the
+// suspect.
+// =============================================================================
+
+package format
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/dando385/eth-rpc-monitor/internal/rpc"
+)
+
+// maxKeysListed caps how many missing/extra logs are printed per provider.
+const maxKeysListed = 5
+
+// LogsResult is one provider's answer to the shared eth_getLogs query.
+type LogsResult struct {
+	Provider string
+	Logs     []rpc.Log
+	Requests int    // eth_getLogs calls needed (range splitting makes this > 1)
+	Splits   int    // Times the range was rejected as too wide
+	MaxSpan  uint64 // Widest block range the provider accepted
+	Latency  time.Duration
+	Error    error
+}
+
+// LogKey identifies one log across providers.
+type LogKey struct {
+	BlockHash string
+	TxHash    string
+	LogIndex  uint64
+}
+
+// KeyOf returns the identity of a log. Hashes are compared case-insensitively.
+func KeyOf(l *rpc.Log) LogKey {
+	idx, _ := rpc.ParseHexUint64(l.LogIndex)
+	return LogKey{
+		BlockHash: strings.ToLower(l.BlockHash),
+		TxHash:    strings.ToLower(l.TransactionHash),
+		LogIndex:  idx,
+	}
+}
+
+// LogDiff is one provider's disagreement with the reference set. Keys are in
+// the order the logs were first seen (block order for well-behaved providers).
+type LogDiff struct {
+	Missing []LogKey
+	Extra   []LogKey
+}
+
+// CompareLogs computes the reference set (see LOG IDENTITY AND CONSENSUS) and
+// each provider's diff against it. diffs is parallel to results; entries for
+// failed providers are empty. consensus is the size of the reference set.
+func CompareLogs(results []LogsResult) (consensus int, diffs []LogDiff) {
+	diffs = make([]LogDiff, len(results))
+
+	var order []LogKey
+	seenBy := make(map[LogKey]int)
+	sets := make([]map[LogKey]bool, len(results))
+	answered := 0
+	for i, r := range results {
+		if r.Error != nil {
+			continue
+		}
+		answered++
+		sets[i] = make(map[LogKey]bool, len(r.Logs))
+		for j := range r.Logs {
+			k := KeyOf(&r.Logs[j])
+			if sets[i][k] {
+				continue // Duplicates within one response count once
+			}
+			sets[i][k] = true
+			if seenBy[k] == 0 {
+				order = append(order, k)
+			}
+			seenBy[k]++
+		}
+	}
+
+	inReference := func(k LogKey) bool { return seenBy[k]*2 >= answered }
+	for _, k := range order {
+		if inReference(k) {
+			consensus++
+		}
+	}
+
+	for i, set := range sets {
+		if set == nil {
+			continue
+		}
+		for _, k := range order {
+			switch {
+			case inReference(k) && !set[k]:
+				diffs[i].Missing = append(diffs[i].Missing, k)
+			case !inReference(k) && set[k]:
+				diffs[i].Extra = append(diffs[i].Extra, k)
+			}
+		}
+	}
+	return consensus, diffs
+}
+
+// FormatLogs renders the per-provider table and any mismatch details.
+func FormatLogs(w io.Writer, results []LogsResult) {
+	consensus, diffs := CompareLogs(results)
+
+	fmt.Fprintf(w, "%s %s %s %s  %s\n",
+		Bold(fmt.Sprintf("%-14s", "Provider")),
+		Bold(fmt.Sprintf("%6s", "Logs")),
+		Bold(fmt.Sprintf("%9s", "Requests")),
+		Bold(fmt.Sprintf("%8s", "Latency")),
+		Bold("Result"))
+	fmt.Fprintln(w, strings.Repeat("─", 70))
+
+	failed := 0
+	mismatched := 0
+	for i, r := range results {
+		if r.Error != nil {
+			failed++
+			fmt.Fprintf(w, "%-14s %6s %9s %8s  %s\n", r.Provider, Dim("—"), Dim("—"), Dim("—"),
+				Red(truncate(ErrorLabel(r.Error), 60)))
+			continue
+		}
+
+		verdict := Green("✓ matches consensus")
+		if d := diffs[i]; len(d.Missing) > 0 || len(d.Extra) > 0 {
+			mismatched++
+			var parts []string
+			if len(d.Missing) > 0 {
+				parts = append(parts, fmt.Sprintf("missing %d", len(d.Missing)))
+			}
+			if len(d.Extra) > 0 {
+				parts = append(parts, fmt.Sprintf("extra %d", len(d.Extra)))
+			}
+			verdict = Red("✗ " + strings.Join(parts, ", "))
+		}
+		fmt.Fprintf(w, "%-14s %6s %9d %8s  %s\n", r.Provider,
+			rpc.FormatNumber(uint64(len(r.Logs))), r.Requests,
+			fmt.Sprintf("%dms", r.Latency.Milliseconds()), verdict)
+	}
+
+	for _, r := range results {
+		if r.Error == nil && r.Splits > 0 {
+			fmt.Fprintf(w, "  %s\n", Dim(fmt.Sprintf("%s: range limit hit — split %d time(s), widest accepted span %s blocks",
+				r.Provider, r.Splits, rpc.FormatNumber(r.MaxSpan))))
+		}
+	}
+	fmt.Fprintln(w)
+
+	if failed == len(results) {
+		fmt.Fprintln(w, Red("✗ No provider answered the query"))
+		fmt.Fprintln(w)
+		return
+	}
+
+	if mismatched == 0 {
+		fmt.Fprintln(w, Green(fmt.Sprintf("✓ All responding providers returned the same %s log(s)",
+			rpc.FormatNumber(uint64(consensus)))))
+		fmt.Fprintln(w)
+		return
+	}
+
+	fmt.Fprintln(w, Red(fmt.Sprintf("✗ LOG SET MISMATCH DETECTED (consensus: %s logs)", rpc.FormatNumber(uint64(consensus)))))
+	for i, r := range results {
+		d := diffs[i]
+		writeLogKeys(w, fmt.Sprintf("%s is missing %d log(s)", Bold(r.Provider), len(d.Missing)), d.Missing)
+		writeLogKeys(w, fmt.Sprintf("%s returned %d log(s) not in the consensus set", Bold(r.Provider), len(d.Extra)), d.Extra)
+	}
+	fmt.Fprintln(w)
+}
+
+// writeLogKeys prints a heading and up to maxKeysListed keys below it.
+func writeLogKeys(w io.Writer, heading string, keys []LogKey) {
+	if len(keys) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "  %s:\n", heading)
+	for n, k := range keys {
+		if n == maxKeysListed {
+			fmt.Fprintf(w, "    %s\n", Dim(fmt.Sprintf("… and %d more", len(keys)-maxKeysListed)))
+			break
+		}
+		fmt.Fprintf(w, "    block %s  tx %s  logIndex %d\n", shortHex(k.BlockHash), shortHex(k.TxHash), k.LogIndex)
+	}
+}
@@ -0,0 +1,83 @@
+package format
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/dando385/eth-rpc-monitor/internal/rpc"
+)
+
+func logsFor(blocks ...int) []rpc.Log {
+	var logs []rpc.Log
+	for _, b := range blocks {
+		logs = append(logs, rpc.Log{
+			BlockHash:       fmt.Sprintf("0xB%d", b),
+			TransactionHash: fmt.Sprintf("0xt%d", b),
+			LogIndex:        "0x0",
+		})
+	}
+	return logs
+}
+
+func TestCompareLogs(t *testing.T) {
+	results := []LogsResult{
+		{Provider: "a", Logs: logsFor(1, 2, 3)},
+		{Provider: "b", Logs: logsFor(1, 2)},       // truncated
+		{Provider: "c", Logs: logsFor(1, 2, 3, 9)}, // adds a log nobody else has
+		{Provider: "d", Error: errors.New("down")},
+	}
+	consensus, diffs := CompareLogs(results)
+	if consensus != 3 {
+		t.Fatalf("consensus = %d", consensus)
+	}
+	if len(diffs[0].Missing)+len(diffs[0].Extra) != 0 {
+		t.Fatalf("a: %+v", diffs[0])
+	}
+	if len(diffs[1].Missing) != 1 || diffs[1].Missing[0].BlockHash != "0xb3" {
+		t.Fatalf("b: %+v", diffs[1])
+	}
+	if len(diffs[2].Extra) != 1 || diffs[2].Extra[0].TxHash != "0xt9" {
+		t.Fatalf("c: %+v", diffs[2])
+	}
+}
+
+func TestCompareLogs_twoProvidersTruncationBlamesShorter(t *testing.T) {
+	_, diffs := CompareLogs([]LogsResult{
+		{Provider: "full", Logs: logsFor(1, 2, 3)},
+		{Provider: "short", Logs: logsFor(1)},
+	})
+	if len(diffs[0].Extra) != 0 || len(diffs[1].Missing) != 2 {
+		t.Fatalf("diffs: %+v", diffs)
+	}
+}
+
+func TestFormatLogs(t *testing.T) {
+	var buf bytes.Buffer
+	FormatLogs(&buf, []LogsResult{
+		{Provider: "alchemy", Logs: logsFor(1, 2, 3), Requests: 4, Splits: 3, MaxSpan: 2500},
+		{Provider: "infura", Logs: logsFor(1, 2), Requests: 1},
+	})
+	out := stripANSI(buf.String())
+	for _, want := range []string{
+		"✓ matches consensus", "✗ missing 1",
+		"alchemy: range limit hit — split 3 time(s), widest accepted span 2,500 blocks",
+		"LOG SET MISMATCH DETECTED (consensus: 3 logs)",
+		"infura is missing 1 log(s)", "tx 0xt3",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("missing %q in:\n%s", want, out)
+		}
+	}
+
+	buf.Reset()
+	FormatLogs(&buf, []LogsResult{
+		{Provider: "a", Logs: logsFor(1)},
+		{Provider: "b", Logs: logsFor(1)},
+	})
+	if !strings.Contains(stripANSI(buf.String()), "same 1 log(s)") {
+		t.Fatalf("agreement output:\n%s", buf.String())
+	}
+}
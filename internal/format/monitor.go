@@ -43,6 +43,18 @@
 //   └──────────────────────────────────────────────────────┘
 //      ↑ This entire display is REPLACED each cycle
 //
+// THREE HEADS, NOT ONE
+// ====================
+// Since the Merge a node tracks three heads: latest (may still be
+// reorged), safe (justified) and finalized (cannot be reverted). Each gets
+// its own height and lag column, plus Gap — how far this provider's
+// finalized block trails its latest. Finality normally trails by two to
+// three epochs (64–96 blocks on mainnet); a growing Gap on every provider
+// means the chain has stopped finalizing, while a lag on one provider's
+// finalized column — with none on latest — means that provider has stopped
+// following finality. The second is easy to miss and matters most to
+// anything that settles on finalized blocks, so it gets a warning line.
+//
 // CS CONCEPTS: TERMINAL CONTROL WITH ESCAPE CODES
 // ================================================
 // Modern terminals are stateful devices. They maintain:
@@ -84,6 +96,8 @@ import (
 	"io"
 	"strings"
 	"time"
+
+	"github.com/dando385/eth-rpc-monitor/internal/rpc"
 )
 
 // =============================================================================
@@ -105,7 +119,43 @@ type WatchResult struct {
 	Provider    string        // Provider name
 	BlockHeight uint64        // Latest block number from this provider
 	Latency     time.Duration // Round-trip time for the eth_blockNumber call
+	Timing      rpc.Timing    // Phase breakdown of that call (cold policy: includes handshakes)
 	Error       error         // nil on success; non-nil on failure
+	Push        *PushStatus   // WebSocket newHeads state (`monitor --ws`); nil = not subscribed
+	Failures    ErrorCounts   // Failures by category since the monitor started (cumulative)
+	Safe        TagHead       // "safe" head; zero when not fetched
+	Finalized   TagHead       // "finalized" head; zero when not fetched
+}
+
+// TagHead is the height a block tag ("safe", "finalized") pointed at this
+// tick. A failure here does not fail the row: nodes before the Merge, and
+// chains without beacon finality, do not know the tags at all.
+type TagHead struct {
+	Height uint64
+	Error  error
+}
+
+// ok reports whether the tag was fetched successfully.
+func (h TagHead) ok() bool { return h.Error == nil && h.Height > 0 }
+
+// PushStatus describes what a provider's eth_subscribe("newHeads") stream
+// has delivered so far. It is filled by `monitor --ws` and rendered in extra
+// columns next to the polling numbers.
+//
+// DELAY vs. AGE
+// =============
+//   - Delay: how long after the FIRST provider announced this same block
+//     height this provider announced it. 0 means "this provider was first".
+//     This is the push-based propagation metric.
+//   - Age: how long ago the last head arrived. A growing age on one provider
+//     while others keep receiving heads means its stream has stalled.
+type PushStatus struct {
+	Height     uint64        // Latest pushed block height (0 = nothing received yet)
+	Delay      time.Duration // Arrival delay behind the first provider for Height
+	Age        time.Duration // Time since the last head arrived
+	Reconnects int           // Number of WebSocket reconnects so far
+	StrayIDs   int           // Responses whose id matched no pending call
+	Error      error         // Subscription failure (connect/subscribe), if any
 }
 
 // =============================================================================
@@ -187,6 +237,22 @@ func FormatMonitor(w io.Writer, results []WatchResult, interval time.Duration, c
 		}
 	}
 
+	// The safe and finalized columns have their own reference points, and
+	// appear only when at least one provider reported either tag.
+	var heads tagHeads
+	for _, r := range results {
+		if r.Error != nil {
+			continue
+		}
+		if r.Safe.ok() && r.Safe.Height > heads.safe {
+			heads.safe = r.Safe.Height
+		}
+		if r.Finalized.ok() && r.Finalized.Height > heads.finalized {
+			heads.finalized = r.Finalized.Height
+		}
+	}
+	showTags := heads.safe > 0 || heads.finalized > 0
+
 	// Render the dashboard header with interval info.
 	// The Dim() wrapper makes the interval and exit instruction secondary
 	// to the main "Monitoring N providers" message.
@@ -194,13 +260,67 @@ func FormatMonitor(w io.Writer, results []WatchResult, interval time.Duration, c
 		len(results),
 		Dim(fmt.Sprintf("(interval: %s, Ctrl+C to exit)", interval)))
 
+	// Push columns are only shown when at least one provider is subscribed
+	// (`monitor --ws`), so the plain polling dashboard is unchanged.
+	showPush := false
+	for _, r := range results {
+		if r.Push != nil {
+			showPush = true
+			break
+		}
+	}
+
+	// The Queue column appears only when a provider's rate limit actually
+	// held a call back this tick; that wait is not part of Latency.
+	showQueue := false
+	for _, r := range results {
+		if r.Timing.Queue > 0 {
+			showQueue = true
+			break
+		}
+	}
+
 	// Render the column headers.
-	fmt.Fprintf(w, "%s %s %s %s\n",
+	//
+	// DNS/Conn/TLS/TTFB/Body break the Latency column down by phase. The
+	// monitor runs cold by default (no keep-alive), so handshakes show up
+	// here on every frame — that is the "cold poll" cost being measured.
+	// Under a warm transport policy they drop to "—" after the first tick.
+	header := fmt.Sprintf("%s %s %s %s",
 		Bold(fmt.Sprintf("%-14s", "Provider")),
 		Bold(fmt.Sprintf("%12s", "Block Height")),
 		Bold(fmt.Sprintf("%7s", "Latency")),
 		Bold(fmt.Sprintf("%3s", "Lag")))
-	fmt.Fprintln(w, strings.Repeat("─", 60))
+	width := 95
+	if showTags {
+		header += fmt.Sprintf(" %s %s %s %s %s",
+			Bold(fmt.Sprintf("%12s", "Safe")),
+			Bold(fmt.Sprintf("%3s", "Lag")),
+			Bold(fmt.Sprintf("%12s", "Finalized")),
+			Bold(fmt.Sprintf("%3s", "Lag")),
+			Bold(fmt.Sprintf("%5s", "Gap")))
+		width += 40
+	}
+	header += fmt.Sprintf("  %s %s %s %s %s",
+		Bold(fmt.Sprintf("%-6s", "DNS")),
+		Bold(fmt.Sprintf("%-6s", "Conn")),
+		Bold(fmt.Sprintf("%-6s", "TLS")),
+		Bold(fmt.Sprintf("%-7s", "TTFB")),
+		Bold(fmt.Sprintf("%-6s", "Body")))
+	if showQueue {
+		header += " " + Bold("Queue ")
+		width += 7
+	}
+	if showPush {
+		header += fmt.Sprintf("   %s %s %s %s",
+			Bold(fmt.Sprintf("%12s", "Push Head")),
+			Bold(fmt.Sprintf("%-8s", "Delay")),
+			Bold(fmt.Sprintf("%-8s", "Age")),
+			Bold("Reconn"))
+		width += 40
+	}
+	fmt.Fprintln(w, header)
+	fmt.Fprintln(w, strings.Repeat("─", width))
 
 	// Render one row per provider.
 	for _, r := range results {
@@ -208,11 +328,14 @@ func FormatMonitor(w io.Writer, results []WatchResult, interval time.Duration, c
 			// Provider failed — show ERROR in red with dashes for missing data.
 			// The `continue` keyword skips the rest of this iteration and
 			// moves to the next provider. This avoids nested if/else blocks.
-			fmt.Fprintf(w, "%-14s %12s %7s %3s\n",
+			fmt.Fprintf(w, "%-14s %12s %7s %3s%s  %s%s\n",
 				r.Provider,
 				padRight(Red("ERROR"), 12),
 				padRight(Dim("—"), 7),
-				padRight(Dim("—"), 3))
+				padRight(Dim("—"), 3),
+				tagColumns(r, heads, showTags),
+				phaseCells(r.Timing)+queueColumn(r.Timing, showQueue),
+				pushColumns(r.Push, showPush))
 			continue
 		}
 
@@ -220,11 +343,154 @@ func FormatMonitor(w io.Writer, results []WatchResult, interval time.Duration, c
 		// Since `highest` is the maximum and `r.BlockHeight` is at most equal
 		// to `highest`, this subtraction is safe (no underflow for uint64).
 		lag := highest - r.BlockHeight
-		fmt.Fprintf(w, "%-14s %12d %7s %3s\n",
+		fmt.Fprintf(w, "%-14s %12d %7s %3s%s  %s%s\n",
 			r.Provider,
 			r.BlockHeight,
 			padRight(ColorLatency(r.Latency.Milliseconds()), 7),
-			padRight(ColorLag(lag), 3))
+			padRight(ColorLag(lag), 3),
+			tagColumns(r, heads, showTags),
+			phaseCells(r.Timing)+queueColumn(r.Timing, showQueue),
+			pushColumns(r.Push, showPush))
 	}
 	fmt.Fprintln(w)
+
+	// --- Stuck Finality ---
+	//
+	// A provider that keeps up on latest but falls behind on safe or
+	// finalized is still serving blocks, so nothing else on the dashboard
+	// looks wrong — yet anything that waits for finality stalls on it.
+	warned := false
+	for _, r := range results {
+		if r.Error != nil {
+			continue
+		}
+		lag := highest - r.BlockHeight
+		for _, t := range []struct {
+			name    string
+			head    TagHead
+			highest uint64
+		}{{"safe", r.Safe, heads.safe}, {"finalized", r.Finalized, heads.finalized}} {
+			if !t.head.ok() || t.highest-t.head.Height <= lag {
+				continue
+			}
+			fmt.Fprintf(w, "%s %s is %s on latest but %d blocks behind on %s\n",
+				Yellow("⚠"), r.Provider, lagLabel(lag), t.highest-t.head.Height, t.name)
+			warned = true
+		}
+	}
+	if warned {
+		fmt.Fprintln(w)
+	}
+
+	// --- Session Failure Footer ---
+	//
+	// The table shows only THIS tick. Failures are also accumulated across
+	// the whole session (by cmd/monitor) and listed here by category, along
+	// with the current error if the provider is failing right now — so a
+	// provider that flaps between OK and rate-limited stays visible.
+	footer := false
+	for _, r := range results {
+		if r.Failures.Total() == 0 {
+			continue
+		}
+		if !footer {
+			fmt.Fprintln(w, Bold("Failures since start"))
+			footer = true
+		}
+		line := fmt.Sprintf("  %-14s %s", r.Provider, Red(r.Failures.Summary()))
+		if r.Error != nil {
+			line += "  " + Dim(truncate(ErrorLabel(r.Error), 70))
+		}
+		fmt.Fprintln(w, line)
+	}
+	if footer {
+		fmt.Fprintln(w)
+	}
+}
+
+// tagHeads holds the highest safe and finalized heights across providers,
+// the reference points for those columns' lag.
+type tagHeads struct {
+	safe, finalized uint64
+}
+
+// tagColumns renders the Safe, Lag, Finalized, Lag and Gap cells for one
+// row, or "" when the dashboard has no tag columns. A tag the provider
+// could not answer shows "n/a"; Gap needs both latest and finalized.
+func tagColumns(r WatchResult, heads tagHeads, show bool) string {
+	if !show {
+		return ""
+	}
+	cells := func(h TagHead, highest uint64) string {
+		switch {
+		case r.Error != nil || (h.Error == nil && h.Height == 0):
+			return fmt.Sprintf(" %12s %3s", padRight(Dim("—"), 12), padRight(Dim("—"), 3))
+		case h.Error != nil:
+			return fmt.Sprintf(" %12s %3s", padRight(Yellow("n/a"), 12), padRight(Dim("—"), 3))
+		}
+		return fmt.Sprintf(" %12d %3s", h.Height, padRight(ColorLag(highest-h.Height), 3))
+	}
+	gap := padRight(Dim("—"), 5)
+	if r.Error == nil && r.Finalized.ok() && r.Finalized.Height <= r.BlockHeight {
+		gap = fmt.Sprintf("%5d", r.BlockHeight-r.Finalized.Height)
+	}
+	return cells(r.Safe, heads.safe) + cells(r.Finalized, heads.finalized) + " " + gap
+}
+
+// lagLabel describes a latest-head lag in words for the warning line.
+func lagLabel(lag uint64) string {
+	if lag == 0 {
+		return "current"
+	}
+	return fmt.Sprintf("%d behind", lag)
+}
+
+// truncate shortens s to at most n runes, marking the cut with "…".
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-1]) + "…"
+}
+
+// pushColumns renders the WebSocket columns for one row, or "" when the
+// dashboard has no push columns at all.
+//
+// Delay is colored like latency (it IS a latency — propagation latency
+// relative to the fastest provider); Age turns yellow once it exceeds one
+// mainnet slot (12s), since by then a healthy stream should have delivered
+// a newer head.
+func pushColumns(p *PushStatus, show bool) string {
+	if !show {
+		return ""
+	}
+	if p == nil {
+		return fmt.Sprintf("   %12s %s %s %s",
+			padRight(Dim("—"), 12), padRight(Dim("—"), 8), padRight(Dim("—"), 8), Dim("—"))
+	}
+	if p.Error != nil {
+		return fmt.Sprintf("   %s %v", Red("WS ERROR:"), p.Error)
+	}
+	if p.Height == 0 {
+		return fmt.Sprintf("   %12s %s %s %d",
+			padRight(Dim("waiting"), 12), padRight(Dim("—"), 8), padRight(Dim("—"), 8), p.Reconnects)
+	}
+
+	age := Dim(p.Age.Truncate(time.Second).String())
+	if p.Age > 12*time.Second {
+		age = Yellow(p.Age.Truncate(time.Second).String())
+	}
+	reconn := fmt.Sprintf("%d", p.Reconnects)
+	if p.Reconnects > 0 {
+		reconn = Yellow(reconn)
+	}
+	if p.StrayIDs > 0 {
+		reconn += "  " + Red(fmt.Sprintf("%d stray id(s)", p.StrayIDs))
+	}
+	return fmt.Sprintf("   %12d %s %s %s",
+		p.Height,
+		padRight(ColorLatency(p.Delay.Milliseconds()), 8),
+		padRight(age, 8),
+		reconn)
 }
@@ -0,0 +1,75 @@
+package format
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFormatMonitor_pushColumnsOnlyWhenSubscribed(t *testing.T) {
+	rows := []WatchResult{{Provider: "alchemy", BlockHeight: 100, Latency: 40 * time.Millisecond}}
+
+	var buf bytes.Buffer
+	FormatMonitor(&buf, rows, 30*time.Second, false)
+	if strings.Contains(buf.String(), "Push Head") {
+		t.Fatalf("push columns shown without --ws: %s", buf.String())
+	}
+
+	rows[0].Push = &PushStatus{Height: 100, Delay: 120 * time.Millisecond, Age: 3 * time.Second, Reconnects: 1, StrayIDs: 2}
+	rows = append(rows, WatchResult{Provider: "infura", BlockHeight: 100, Push: &PushStatus{Error: errors.New("dial refused")}})
+	buf.Reset()
+	FormatMonitor(&buf, rows, 30*time.Second, false)
+	if !containsAll(buf.String(), []string{"Push Head", "120ms", "3s", "WS ERROR:", "dial refused", "2 stray id(s)"}) {
+		t.Fatalf("output: %s", buf.String())
+	}
+}
+
+func TestFormatMonitor_queueColumnOnlyWhenQueued(t *testing.T) {
+	rows := []WatchResult{{Provider: "alchemy", BlockHeight: 100, Latency: 40 * time.Millisecond}}
+	var buf bytes.Buffer
+	FormatMonitor(&buf, rows, 30*time.Second, false)
+	if strings.Contains(buf.String(), "Queue") {
+		t.Fatalf("queue column without queued calls: %s", buf.String())
+	}
+
+	rows[0].Timing.Queue = 250 * time.Millisecond
+	buf.Reset()
+	FormatMonitor(&buf, rows, 30*time.Second, false)
+	if !containsAll(buf.String(), []string{"Queue", "250ms"}) {
+		t.Fatalf("output: %s", buf.String())
+	}
+}
+
+func TestFormatMonitor_safeAndFinalized(t *testing.T) {
+	rows := []WatchResult{{Provider: "alchemy", BlockHeight: 100, Latency: 40 * time.Millisecond}}
+	var buf bytes.Buffer
+	FormatMonitor(&buf, rows, 30*time.Second, false)
+	if strings.Contains(buf.String(), "Finalized") {
+		t.Fatalf("tag columns without tag heads: %s", buf.String())
+	}
+
+	rows[0].Safe = TagHead{Height: 68}
+	rows[0].Finalized = TagHead{Height: 36}
+	rows = append(rows,
+		WatchResult{Provider: "stuck", BlockHeight: 100, Safe: TagHead{Height: 68}, Finalized: TagHead{Height: 4}},
+		WatchResult{Provider: "l2", BlockHeight: 99, Safe: TagHead{Error: errors.New("unknown block")}, Finalized: TagHead{Error: errors.New("unknown block")}},
+		WatchResult{Provider: "down", Error: errors.New("timeout")},
+	)
+	buf.Reset()
+	FormatMonitor(&buf, rows, 30*time.Second, false)
+	out := stripANSI(buf.String())
+	if !containsAll(out, []string{
+		"Safe", "Finalized", "Gap",
+		"36 —      64",
+		"4 -32    96",
+		"n/a",
+		"⚠ stuck is current on latest but 32 blocks behind on finalized",
+	}) {
+		t.Fatalf("output:\n%s", out)
+	}
+	if strings.Contains(out, "alchemy is") || strings.Contains(out, "l2 is") {
+		t.Fatalf("warning for a provider that is not stuck:\n%s", out)
+	}
+}
@@ -0,0 +1,209 @@
+// =============================================================================
+// FILE: internal/format/nodeinfo.go
+// ROLE: Node Metadata Display — Client Versions, Chain IDs, Client Diversity
+// =============================================================================
+//
+// SYSTEM CONTEXT
+// ==============
+// This file renders the output of the `nodeinfo` command: what software each
+// provider is really running, which chain it is on, and whether it is synced.
+//
+//   Provider       Type         Client      Version          Chain     Network  Sync       Peers
+//   ────────────────────────────────────────────────────────────────────────────────────────────
+//   alchemy        enterprise   geth        1.14.8-stable    1         1        synced     —
+//   infura         enterprise   geth        1.14.7-stable    1         1        synced     50
+//   publicnode     public       erigon      2.60.6           1         1        synced     32
+//   self           self_hosted  reth        1.0.5-603e39ab   1         1        -1,204     98
+//
+//   Client diversity (4 provider(s) reporting a client):
+//     geth        2  ██████████          50%
+//     erigon      1  █████               25%
+//     reth        1  █████               25%
+//
+// WHY CLIENT DIVERSITY MATTERS
+// ============================
+// Several providers do not add up to redundancy if they all run the same
+// execution client. A consensus bug in that client (it has happened to geth,
+// nethermind and besu) hits every one of them at the same block, and the
+// `snapshot` command will happily report them all in agreement — on the
+// wrong chain. The summary therefore flags any client that two thirds or
+// more of the providers share, the same threshold the Ethereum community
+// uses for network-wide client diversity.
+// =============================================================================
+
+package format
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dando385/eth-rpc-monitor/internal/rpc"
+)
+
+// NodeInfoResult is one provider's answer to the metadata probe.
+type NodeInfoResult struct {
+	Provider string
+	Type     string // config.Provider.Type, shown next to what the node reports
+	Info     *rpc.NodeInfo
+	Latency  time.Duration
+	Error    error
+}
+
+// ClientShare is one implementation's share of the responding providers.
+type ClientShare struct {
+	Client    string   // clientName: rpc.ClientGeth, ..., or the node's own name
+	Providers []string // In config order
+	Percent   float64  // Of providers that reported a client version
+}
+
+// ClientDiversity groups the providers that answered web3_clientVersion by
+// implementation, largest share first (ties broken by name).
+func ClientDiversity(results []NodeInfoResult) []ClientShare {
+	byClient := make(map[string][]string)
+	total := 0
+	for _, r := range results {
+		if r.Error != nil || r.Info == nil || r.Info.Err(rpc.MethodClientVersion) != nil {
+			continue
+		}
+		client := clientName(r.Info.Version)
+		byClient[client] = append(byClient[client], r.Provider)
+		total++
+	}
+
+	shares := make([]ClientShare, 0, len(byClient))
+	for client, providers := range byClient {
+		shares = append(shares, ClientShare{
+			Client:    client,
+			Providers: providers,
+			Percent:   float64(len(providers)) * 100 / float64(total),
+		})
+	}
+	sort.Slice(shares, func(i, j int) bool {
+		if len(shares[i].Providers) != len(shares[j].Providers) {
+			return len(shares[i].Providers) > len(shares[j].Providers)
+		}
+		return shares[i].Client < shares[j].Client
+	})
+	return shares
+}
+
+// clientName is the client a provider is listed under, in both the table
+// and the diversity summary. A recognized client keeps its canonical name;
+// any other node is grouped by the lowercased name it reported, so three
+// providers running the same unrecognized software still count as one
+// client, and rpc.ClientUnknown is left for a version string with no name.
+func clientName(v rpc.ClientVersion) string {
+	if v.Known() || v.Name == "" {
+		return v.Client
+	}
+	return strings.ToLower(v.Name)
+}
+
+// FormatNodeInfo renders the per-provider metadata table, a chain ID
+// agreement check and the client diversity summary.
+func FormatNodeInfo(w io.Writer, results []NodeInfoResult) {
+	fmt.Fprintf(w, "%s %s %s %s %s %s %s %s\n",
+		Bold(fmt.Sprintf("%-14s", "Provider")),
+		Bold(fmt.Sprintf("%-12s", "Type")),
+		Bold(fmt.Sprintf("%-11s", "Client")),
+		Bold(fmt.Sprintf("%-22s", "Version")),
+		Bold(fmt.Sprintf("%-9s", "Chain")),
+		Bold(fmt.Sprintf("%-8s", "Network")),
+		Bold(fmt.Sprintf("%-10s", "Sync")),
+		Bold("Peers"))
+	fmt.Fprintln(w, strings.Repeat("─", 99))
+
+	chains := make(map[uint64][]string)
+	var chainOrder []uint64
+	for _, r := range results {
+		if r.Error != nil {
+			fmt.Fprintf(w, "%-14s %-12s %s\n", r.Provider, r.Type, Red(truncate(ErrorLabel(r.Error), 68)))
+			continue
+		}
+		info := r.Info
+
+		client, version := Dim("—"), Dim("—")
+		if info.Err(rpc.MethodClientVersion) == nil {
+			client = clientName(info.Version)
+			if !info.Version.Known() {
+				client = Yellow(truncate(client, 11))
+			}
+			version = truncate(info.Version.Version, 21)
+		}
+
+		chain := Dim("—")
+		if info.Err(rpc.MethodChainID) == nil {
+			chain = fmt.Sprintf("%d", info.ChainID)
+			if _, seen := chains[info.ChainID]; !seen {
+				chainOrder = append(chainOrder, info.ChainID)
+			}
+			chains[info.ChainID] = append(chains[info.ChainID], r.Provider)
+		}
+
+		network := Dim("—")
+		if info.Err(rpc.MethodNetVersion) == nil {
+			network = truncate(info.NetworkID, 8)
+		}
+
+		sync := Dim("—")
+		if info.Err(rpc.MethodSyncing) == nil {
+			switch {
+			case !info.Sync.Syncing:
+				sync = Green("synced")
+			case info.Sync.HighestBlock == 0:
+				sync = Yellow("syncing")
+			default:
+				sync = Yellow("-" + rpc.FormatNumber(info.Sync.Remaining()))
+			}
+		}
+
+		peers := Dim("—")
+		if info.Err(rpc.MethodPeerCount) == nil {
+			peers = fmt.Sprintf("%d", info.PeerCount)
+		}
+
+		fmt.Fprintf(w, "%s %s %s %s %s %s %s %s\n",
+			padRight(r.Provider, 14), padRight(r.Type, 12), padRight(client, 11), padRight(version, 22),
+			padRight(chain, 9), padRight(network, 8), padRight(sync, 10), peers)
+	}
+	fmt.Fprintln(w)
+
+	if len(chainOrder) > 1 {
+		fmt.Fprintln(w, Red("✗ CHAIN ID MISMATCH DETECTED:"))
+		for _, id := range chainOrder {
+			fmt.Fprintf(w, "  chainId %-10d →  %v\n", id, chains[id])
+		}
+		fmt.Fprintln(w)
+	}
+
+	shares := ClientDiversity(results)
+	if len(shares) == 0 {
+		fmt.Fprintln(w, Dim("No provider reported a client version."))
+		fmt.Fprintln(w)
+		return
+	}
+	reporting := 0
+	for _, s := range shares {
+		reporting += len(s.Providers)
+	}
+	fmt.Fprintf(w, "%s\n", Bold(fmt.Sprintf("Client diversity (%d provider(s) reporting a client):", reporting)))
+	for _, s := range shares {
+		bar := strings.Repeat("█", int(s.Percent/5+0.5))
+		fmt.Fprintf(w, "  %-11s %2d  %-20s %3.0f%%\n", s.Client, len(s.Providers), bar, s.Percent)
+	}
+	top := shares[0]
+	switch {
+	case top.Client == rpc.ClientUnknown || reporting < 2:
+		// Nothing to say about an unidentified client or a lone provider.
+	case len(shares) == 1:
+		fmt.Fprintln(w, Red(fmt.Sprintf("⚠ Every reporting provider runs %s — one client bug takes them all down together", top.Client)))
+	case len(top.Providers)*3 >= reporting*2:
+		// Two thirds: the share at which a single client bug can finalize
+		// the wrong chain on Ethereum.
+		fmt.Fprintln(w, Yellow(fmt.Sprintf("⚠ %s runs on %.0f%% of providers (supermajority)", top.Client, top.Percent)))
+	}
+	fmt.Fprintln(w)
+}
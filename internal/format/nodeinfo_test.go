@@ -0,0 +1,97 @@
+package format
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/dando385/eth-rpc-monitor/internal/rpc"
+)
+
+func nodeResult(provider, clientVersion string, chainID uint64) NodeInfoResult {
+	return NodeInfoResult{
+		Provider: provider,
+		Info: &rpc.NodeInfo{
+			Version: rpc.ParseClientVersion(clientVersion),
+			ChainID: chainID,
+			Errors:  map[string]error{},
+		},
+	}
+}
+
+func TestClientDiversity(t *testing.T) {
+	noVersion := nodeResult("d", "", 1)
+	noVersion.Info.Errors[rpc.MethodClientVersion] = errors.New("disabled")
+
+	shares := ClientDiversity([]NodeInfoResult{
+		nodeResult("a", "erigon/2.60.6/linux-amd64/go1.22.5", 1),
+		nodeResult("b", "Geth/v1.14.8-stable/linux-amd64/go1.22.6", 1),
+		nodeResult("c", "Geth/v1.14.7-stable/linux-amd64/go1.22.6", 1),
+		noVersion,
+		{Provider: "e", Error: errors.New("down")},
+	})
+	if len(shares) != 2 {
+		t.Fatalf("shares = %+v", shares)
+	}
+	if shares[0].Client != rpc.ClientGeth || len(shares[0].Providers) != 2 || shares[0].Providers[0] != "b" {
+		t.Fatalf("top = %+v", shares[0])
+	}
+	if shares[1].Client != rpc.ClientErigon || shares[1].Percent < 33 || shares[1].Percent > 34 {
+		t.Fatalf("second = %+v", shares[1])
+	}
+}
+
+func TestFormatNodeInfo_supermajorityAndChainMismatch(t *testing.T) {
+	var buf bytes.Buffer
+	FormatNodeInfo(&buf, []NodeInfoResult{
+		nodeResult("a", "Geth/v1.14.8-stable/linux-amd64/go1.22.6", 1),
+		nodeResult("b", "Geth/v1.14.8-stable/linux-amd64/go1.22.6", 1),
+		nodeResult("c", "Nethermind/v1.28.0/linux-x64/dotnet8.0.8", 11155111),
+	})
+	out := stripANSI(buf.String())
+	for _, want := range []string{"CHAIN ID MISMATCH", "chainId 11155111", "geth runs on 67% of providers", "nethermind"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("missing %q in:\n%s", want, out)
+		}
+	}
+}
+
+func TestFormatNodeInfo_singleClient(t *testing.T) {
+	var buf bytes.Buffer
+	FormatNodeInfo(&buf, []NodeInfoResult{
+		nodeResult("a", "reth/v1.0.5/x86_64-unknown-linux-gnu", 1),
+		nodeResult("b", "reth/v1.0.6/x86_64-unknown-linux-gnu", 1),
+	})
+	out := stripANSI(buf.String())
+	if !strings.Contains(out, "Every reporting provider runs reth") || strings.Contains(out, "MISMATCH") {
+		t.Fatalf("unexpected output:\n%s", out)
+	}
+}
+
+func TestFormatNodeInfo_unrecognizedClientMatchesSummary(t *testing.T) {
+	results := []NodeInfoResult{
+		nodeResult("a", "rpctest/v1.0.0/a", 1),
+		nodeResult("b", "rpctest/v1.0.0/b", 1),
+		nodeResult("c", "RPCTest/v1.0.0/c", 1),
+	}
+	shares := ClientDiversity(results)
+	if len(shares) != 1 || shares[0].Client != "rpctest" || len(shares[0].Providers) != 3 {
+		t.Fatalf("shares = %+v", shares)
+	}
+
+	var buf bytes.Buffer
+	FormatNodeInfo(&buf, results)
+	out := stripANSI(buf.String())
+	if strings.Contains(out, rpc.ClientUnknown) {
+		t.Fatalf("summary and table disagree on the client:\n%s", out)
+	}
+	for _, want := range []string{"rpctest      3", "Every reporting provider runs rpctest"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("missing %q in:\n%s", want, out)
+		}
+	}
+	if strings.Count(out, "rpctest") != 5 {
+		t.Fatalf("want rpctest in 3 table rows, the share and the warning:\n%s", out)
+	}
+}
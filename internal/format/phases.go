@@ -0,0 +1,191 @@
+// =============================================================================
+// FILE: internal/format/phases.go
+// ROLE: Latency Attribution Display — Where Did the Milliseconds Go?
+// =============================================================================
+//
+// SYSTEM CONTEXT
+// ==============
+// rpc.Timing (internal/rpc/trace.go) splits each HTTP exchange into DNS,
+// TCP connect, TLS handshake, TTFB (server time + one round trip) and body
+// transfer. This file turns those per-call breakdowns into terminal output:
+//
+//   - FormatPhases: a second table under `test` results, one row per
+//     provider, showing P50/P95 of every phase across all samples.
+//   - phaseCells:   compact per-row cells used by the `monitor` dashboard.
+//
+// READING THE PHASE TABLE
+// =======================
+//
+//   Provider        Reused  DNS      Connect  TLS      TTFB       Body
+//   ───────────────────────────────────────────────────────────────────────
+//   alchemy          30/30  —        —        —        21/35ms    0.1/0.3ms
+//   publicnode       12/30  4.0/9.1ms 11/14ms 24/31ms  88/310ms   0.2/1.4ms
+//
+//   - "21/35ms" is P50/P95 for that phase. Values under 10ms keep one
+//     decimal ("0.4/1.2ms") because local DNS and body reads are often
+//     sub-millisecond.
+//   - "—" means the phase never happened in any sample (e.g. no DNS because
+//     every sample reused a pooled connection).
+//   - Reused well below Total on a warm client means the provider (or a
+//     proxy in front of it) is closing keep-alive connections, so samples
+//     keep paying for handshakes.
+//   - High TTFB with small handshakes = the provider's node is slow; the
+//     reverse = the network path or TLS termination is the problem.
+//   - A Queue column appears when a provider's rate limit (rate_limit in
+//     providers.yaml) held samples back. Queue time is spent BEFORE the
+//     exchange and is not part of any latency figure.
+// =============================================================================
+
+package format
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/dando385/eth-rpc-monitor/internal/rpc"
+)
+
+// PhaseStats holds the percentile summary of every phase across samples.
+//
+// Percentiles are computed per phase independently, so the P95 DNS and the
+// P95 TTFB may come from different samples — the table answers "how bad does
+// each phase get?", not "what did the P95 request look like?".
+type PhaseStats struct {
+	DNS, Connect, TLS, TTFB, Body TailLatency
+	Queue                         TailLatency // Rate-limiter wait, outside the exchange
+	Reused                        int         // Samples that reused a pooled connection
+	Samples                       int         // Samples with timing data
+}
+
+// CalculatePhaseStats summarizes a slice of per-call timings.
+//
+// Zero-valued phases (phases that did not happen) are left out of that
+// phase's percentiles; otherwise a 30-sample warm run with one cold
+// handshake would report a DNS P50 of 0 instead of "not observed".
+func CalculatePhaseStats(timings []rpc.Timing) PhaseStats {
+	var dns, connect, tlsHS, ttfb, body, queue []time.Duration
+	reused := 0
+	for _, t := range timings {
+		if t.Reused {
+			reused++
+		}
+		dns = appendNonZero(dns, t.DNS)
+		connect = appendNonZero(connect, t.Connect)
+		tlsHS = appendNonZero(tlsHS, t.TLS)
+		ttfb = appendNonZero(ttfb, t.TTFB)
+		body = appendNonZero(body, t.Body)
+		queue = appendNonZero(queue, t.Queue)
+	}
+	return PhaseStats{
+		DNS:     CalculateTailLatency(dns),
+		Connect: CalculateTailLatency(connect),
+		TLS:     CalculateTailLatency(tlsHS),
+		TTFB:    CalculateTailLatency(ttfb),
+		Body:    CalculateTailLatency(body),
+		Queue:   CalculateTailLatency(queue),
+		Reused:  reused,
+		Samples: len(timings),
+	}
+}
+
+func appendNonZero(s []time.Duration, d time.Duration) []time.Duration {
+	if d > 0 {
+		return append(s, d)
+	}
+	return s
+}
+
+// FormatPhases renders the per-phase breakdown table for `test`. Providers
+// without any timing data (every sample failed) are skipped.
+func FormatPhases(w io.Writer, results []TestResult) {
+	showQueue := false
+	for _, r := range results {
+		if CalculatePhaseStats(r.Timings).Queue.Max > 0 {
+			showQueue = true
+			break
+		}
+	}
+
+	header := fmt.Sprintf("%s %s  %s %s %s %s %s",
+		Bold(fmt.Sprintf("%-14s", "Provider")),
+		Bold(fmt.Sprintf("%7s", "Reused")),
+		Bold(fmt.Sprintf("%-10s", "DNS")),
+		Bold(fmt.Sprintf("%-10s", "Connect")),
+		Bold(fmt.Sprintf("%-10s", "TLS")),
+		Bold(fmt.Sprintf("%-12s", "TTFB")),
+		Bold(fmt.Sprintf("%-10s", "Body")))
+	if showQueue {
+		header += " " + Bold("Queue")
+	}
+	fmt.Fprintln(w, strings.TrimRight(header, " "))
+	fmt.Fprintln(w, strings.Repeat("─", 90))
+
+	for _, r := range results {
+		if len(r.Timings) == 0 {
+			continue
+		}
+		s := CalculatePhaseStats(r.Timings)
+		row := fmt.Sprintf("%-14s %7s  %s %s %s %s %s",
+			r.Name,
+			fmt.Sprintf("%d/%d", s.Reused, s.Samples),
+			padRight(phasePair(s.DNS), 10),
+			padRight(phasePair(s.Connect), 10),
+			padRight(phasePair(s.TLS), 10),
+			padRight(phasePair(s.TTFB), 12),
+			padRight(phasePair(s.Body), 10))
+		if showQueue {
+			row += " " + phasePair(s.Queue)
+		}
+		fmt.Fprintln(w, strings.TrimRight(row, " "))
+	}
+	fmt.Fprintln(w, Dim("  phases shown as P50/P95; — = phase never occurred (connection reused)"))
+	fmt.Fprintln(w)
+}
+
+// phasePair renders "P50/P95ms" for one phase, or a dim dash if the phase
+// was never observed.
+func phasePair(t TailLatency) string {
+	if t.Max == 0 {
+		return Dim("—")
+	}
+	return fmt.Sprintf("%s/%sms", phaseMs(t.P50), phaseMs(t.P95))
+}
+
+// phaseMs formats a duration in milliseconds without the unit, keeping one
+// decimal below 10ms so sub-millisecond phases don't all collapse to "0".
+func phaseMs(d time.Duration) string {
+	if d < 10*time.Millisecond {
+		return fmt.Sprintf("%.1f", float64(d)/float64(time.Millisecond))
+	}
+	return fmt.Sprintf("%d", d.Milliseconds())
+}
+
+// phaseCell renders a single phase duration for the monitor dashboard, or a
+// dim dash when the phase did not happen.
+func phaseCell(d time.Duration) string {
+	if d <= 0 {
+		return Dim("—")
+	}
+	return phaseMs(d) + "ms"
+}
+
+// queueColumn renders the monitor's Queue cell (time held back by the
+// provider's rate limiter), or nothing when the column is hidden.
+func queueColumn(t rpc.Timing, show bool) string {
+	if !show {
+		return ""
+	}
+	return " " + padRight(phaseCell(t.Queue), 6)
+}
+
+// phaseCells renders the DNS/Conn/TLS/TTFB/Body cells for one monitor row.
+func phaseCells(t rpc.Timing) string {
+	return fmt.Sprintf("%s %s %s %s %s",
+		padRight(phaseCell(t.DNS), 6),
+		padRight(phaseCell(t.Connect), 6),
+		padRight(phaseCell(t.TLS), 6),
+		padRight(phaseCell(t.TTFB), 7),
+		padRight(phaseCell(t.Body), 6))
+}
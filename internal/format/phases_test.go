@@ -0,0 +1,71 @@
+package format
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/dando385/eth-rpc-monitor/internal/rpc"
+)
+
+func TestCalculatePhaseStats_skipsPhasesThatDidNotHappen(t *testing.T) {
+	s := CalculatePhaseStats([]rpc.Timing{
+		{DNS: 4 * time.Millisecond, Connect: 10 * time.Millisecond, TTFB: 30 * time.Millisecond},
+		{Reused: true, TTFB: 20 * time.Millisecond},
+		{Reused: true, TTFB: 25 * time.Millisecond},
+	})
+	if s.Samples != 3 || s.Reused != 2 {
+		t.Fatalf("counts: %+v", s)
+	}
+	if s.DNS.P50 != 4*time.Millisecond || s.Connect.Max != 10*time.Millisecond {
+		t.Fatalf("handshake phases should ignore reused samples: %+v", s)
+	}
+	if s.TTFB.P50 != 25*time.Millisecond || s.TLS.Max != 0 {
+		t.Fatalf("ttfb/tls: %+v", s)
+	}
+}
+
+func TestPhaseMs(t *testing.T) {
+	cases := map[time.Duration]string{
+		400 * time.Microsecond:  "0.4",
+		9500 * time.Microsecond: "9.5",
+		42 * time.Millisecond:   "42",
+	}
+	for d, want := range cases {
+		if got := phaseMs(d); got != want {
+			t.Errorf("phaseMs(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
+
+func TestFormatTest_phaseTableOnlyWithTimings(t *testing.T) {
+	var buf bytes.Buffer
+	r := TestResult{Name: "a", Success: 1, Total: 1, Latencies: []time.Duration{time.Millisecond}}
+	FormatTest(&buf, []TestResult{r})
+	if containsAll(buf.String(), []string{"TTFB"}) {
+		t.Fatalf("phase table without timings: %s", buf.String())
+	}
+
+	buf.Reset()
+	r.Timings = []rpc.Timing{{Reused: true, TTFB: 12 * time.Millisecond}}
+	FormatTest(&buf, []TestResult{r})
+	if !containsAll(buf.String(), []string{"TTFB", "1/1", "12/12ms"}) {
+		t.Fatalf("output: %s", buf.String())
+	}
+}
+
+func TestFormatPhases_queueColumnOnlyWhenRateLimited(t *testing.T) {
+	r := TestResult{Name: "a", Timings: []rpc.Timing{{Reused: true, TTFB: 12 * time.Millisecond}}}
+	var buf bytes.Buffer
+	FormatPhases(&buf, []TestResult{r})
+	if containsAll(buf.String(), []string{"Queue"}) {
+		t.Fatalf("queue column without a rate limit: %s", buf.String())
+	}
+
+	r.Timings = append(r.Timings, rpc.Timing{Reused: true, TTFB: 14 * time.Millisecond, Queue: 180 * time.Millisecond})
+	buf.Reset()
+	FormatPhases(&buf, []TestResult{r})
+	if !containsAll(buf.String(), []string{"Queue", "180/180ms"}) {
+		t.Fatalf("output: %s", buf.String())
+	}
+}
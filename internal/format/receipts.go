@@ -0,0 +1,147 @@
+// =============================================================================
+// FILE: internal/format/receipts.go
+// ROLE: Receipt Summary Renderer — `block --receipts`
+// =============================================================================
+//
+// SYSTEM CONTEXT
+// ==============
+// Where FormatBlock describes a block and FormatTransactions lists what went
+// in, FormatReceipts summarizes what came out: how many transactions
+// reverted, what was actually paid, how many events were emitted — and,
+// most importantly for a monitoring tool, whether the provider's receipt
+// list is complete (rpc.VerifyReceipts).
+//
+//   Receipts (342 via eth_getBlockReceipts, 120ms)
+//     Status:     340 succeeded, 2 failed
+//     Gas used:   14,999,040
+//     Price:      min 12.10 gwei · median 13.40 gwei · max 250.00 gwei
+//     Fees paid:  0.193412 ETH
+//     Logs:       1,234 from 280 transactions
+//     Contracts:  2 created
+//     Failed:
+//       #12    0x9a1f…77d0  gas used 45,000
+//       #201   0x04be…e9a1  gas used 31,200
+//     ✓ Complete: 342 receipts match the block's 342 transactions
+//
+// When the provider has no eth_getBlockReceipts, a dim line above the
+// summary says so and how the receipts were fetched instead.
+// =============================================================================
+
+package format
+
+import (
+	"fmt"
+	"io"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/dando385/eth-rpc-monitor/internal/rpc"
+)
+
+// maxListed caps how many failed transactions or problems are listed
+// individually; the rest are summarized as "… and N more".
+const maxListed = 10
+
+// FormatReceipts renders the receipt summary for one block. problems is the
+// output of rpc.VerifyReceipts for the same block.
+func FormatReceipts(w io.Writer, br *rpc.BlockReceipts, latency time.Duration, problems []string) {
+	receipts := br.Receipts
+	fmt.Fprintf(w, "%s %s\n", Bold(fmt.Sprintf("Receipts (%d via %s)", len(receipts), br.Method)),
+		Dim(fmt.Sprintf("(%dms)", latency.Milliseconds())))
+	if br.FallbackErr != nil {
+		fmt.Fprintf(w, "  %s\n", Dim(fmt.Sprintf("eth_getBlockReceipts unavailable: %s — fetched one receipt per transaction",
+			ErrorLabel(br.FallbackErr))))
+	}
+
+	var (
+		succeeded, failed, unknown int
+		gasUsed                    uint64
+		logs, withLogs, created    int
+		prices                     []*big.Int
+		fees                       = new(big.Int)
+		failures                   []int
+	)
+	for i := range receipts {
+		r := &receipts[i]
+		p := r.Parsed()
+		switch {
+		case r.Succeeded():
+			succeeded++
+		case r.Failed():
+			failed++
+			failures = append(failures, i)
+		default:
+			unknown++
+		}
+		gasUsed += p.GasUsed
+		logs += p.LogCount
+		if p.LogCount > 0 {
+			withLogs++
+		}
+		if r.ContractAddress != "" {
+			created++
+		}
+		if p.EffectiveGasPrice != nil {
+			prices = append(prices, p.EffectiveGasPrice)
+		}
+		if fee := r.Fee(); fee != nil {
+			fees.Add(fees, fee)
+		}
+	}
+
+	status := Green(fmt.Sprintf("%d succeeded", succeeded))
+	if failed > 0 {
+		status += ", " + Red(fmt.Sprintf("%d failed", failed))
+	} else {
+		status += ", 0 failed"
+	}
+	if unknown > 0 {
+		status += ", " + Dim(fmt.Sprintf("%d without status (pre-Byzantium)", unknown))
+	}
+	fmt.Fprintf(w, "  %s     %s\n", Bold("Status:"), status)
+	fmt.Fprintf(w, "  %s   %s\n", Bold("Gas used:"), rpc.FormatNumber(gasUsed))
+	fmt.Fprintf(w, "  %s      %s\n", Bold("Price:"), priceRange(prices))
+	fmt.Fprintf(w, "  %s  %s\n", Bold("Fees paid:"), rpc.FormatEther(fees))
+	fmt.Fprintf(w, "  %s       %s from %d transactions\n", Bold("Logs:"), rpc.FormatNumber(uint64(logs)), withLogs)
+	if created > 0 {
+		fmt.Fprintf(w, "  %s  %d created\n", Bold("Contracts:"), created)
+	}
+
+	if len(failures) > 0 {
+		fmt.Fprintf(w, "  %s\n", Bold("Failed:"))
+		for n, i := range failures {
+			if n == maxListed {
+				fmt.Fprintf(w, "    %s\n", Dim(fmt.Sprintf("… and %d more", len(failures)-maxListed)))
+				break
+			}
+			p := receipts[i].Parsed()
+			fmt.Fprintf(w, "    #%-5d %s  gas used %s\n", i, shortHex(p.TransactionHash), rpc.FormatNumber(p.GasUsed))
+		}
+	}
+
+	if len(problems) == 0 {
+		fmt.Fprintf(w, "  %s\n", Green(fmt.Sprintf("✓ Complete: %d receipts match the block's transactions", len(receipts))))
+	} else {
+		fmt.Fprintf(w, "  %s\n", Red(fmt.Sprintf("✗ INCOMPLETE OR INCONSISTENT RECEIPTS (%d problem(s))", len(problems))))
+		for n, p := range problems {
+			if n == maxListed {
+				fmt.Fprintf(w, "    %s\n", Dim(fmt.Sprintf("… and %d more", len(problems)-maxListed)))
+				break
+			}
+			fmt.Fprintf(w, "    - %s\n", p)
+		}
+	}
+	fmt.Fprintln(w)
+}
+
+// priceRange renders "min X · median Y · max Z" for effective gas prices.
+func priceRange(prices []*big.Int) string {
+	if len(prices) == 0 {
+		return Dim("—")
+	}
+	sorted := append([]*big.Int(nil), prices...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Cmp(sorted[j]) < 0 })
+	return fmt.Sprintf("min %s · median %s · max %s",
+		rpc.FormatGwei(sorted[0]), rpc.FormatGwei(sorted[len(sorted)/2]), rpc.FormatGwei(sorted[len(sorted)-1]))
+}
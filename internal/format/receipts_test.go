@@ -0,0 +1,56 @@
+package format
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dando385/eth-rpc-monitor/internal/rpc"
+)
+
+func TestFormatReceipts(t *testing.T) {
+	br := &rpc.BlockReceipts{
+		Method:      "eth_getTransactionReceipt",
+		FallbackErr: &rpc.RPCError{Code: -32601, Message: "method not found"},
+		Receipts: []rpc.Receipt{
+			{TransactionHash: "0xaa", Status: "0x1", GasUsed: "0x5208", EffectiveGasPrice: "0x3b9aca00",
+				Logs: []rpc.Log{{}, {}}},
+			{TransactionHash: "0x9a1f000000000000000000000000000077d0", Status: "0x0", GasUsed: "0xafc8",
+				EffectiveGasPrice: "0x77359400", ContractAddress: "0xc0"},
+		},
+	}
+	var buf bytes.Buffer
+	FormatReceipts(&buf, br, 42*time.Millisecond, nil)
+	out := stripANSI(buf.String())
+	for _, want := range []string{
+		"Receipts (2 via eth_getTransactionReceipt)", "eth_getBlockReceipts unavailable",
+		"1 succeeded, 1 failed", "66,000", // 21,000 + 45,000 gas
+		"min 1.00 gwei", "max 2.00 gwei",
+		"0.000111 ETH", // 21,000 × 1 gwei + 45,000 × 2 gwei
+		"2 from 1 transactions", "1 created",
+		"#1", "0x9a1f…77d0", "gas used 45,000",
+		"✓ Complete",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("missing %q in:\n%s", want, out)
+		}
+	}
+}
+
+func TestFormatReceipts_problems(t *testing.T) {
+	br := &rpc.BlockReceipts{Method: "eth_getBlockReceipts"}
+	var problems []string
+	for i := 0; i < 12; i++ {
+		problems = append(problems, "receipt mismatch")
+	}
+	var buf bytes.Buffer
+	FormatReceipts(&buf, br, 0, problems)
+	out := stripANSI(buf.String())
+	if !strings.Contains(out, "INCOMPLETE OR INCONSISTENT RECEIPTS (12") || !strings.Contains(out, "… and 2 more") {
+		t.Fatalf("output:\n%s", out)
+	}
+	if strings.Contains(out, "unavailable") {
+		t.Fatalf("no fallback note expected:\n%s", out)
+	}
+}
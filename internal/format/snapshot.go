@@ -31,9 +31,9 @@
 //   ┌──────────────────────────────────────────────────────────────────┐
 //   │ Provider       Latency   Block Height   Block Hash              │
 //   │ ──────────────────────────────────────────────────────────────── │
-//   │ alchemy          43ms       21234567   0xa1b2c3d4...            │
-//   │ infura           39ms       21234567   0xa1b2c3d4...            │
-//   │ llamanodes      167ms       21234566   0x9876fedc...            │
+//   │ alchemy          43ms       21234567   0xa1b2c3d4... ✓          │
+//   │ infura           39ms       21234567   0xa1b2c3d4... ✓          │
+//   │ llamanodes      167ms       21234566   0x9876fedc... ✓          │
 //   │                                                                  │
 //   │ ⚠ BLOCK HEIGHT MISMATCH DETECTED:                               │
 //   │   Height 21234567  →  [alchemy infura]                          │
@@ -63,6 +63,19 @@
 // 3. STALE CACHES: Some RPC providers cache block data aggressively. If their
 //    cache hasn't been updated, they might serve outdated hashes or heights.
 //
+// 4. LATER FORKS' FIELDS: withdrawals (Shanghai), blob gas and the parent
+//    beacon root (Cancun) and the requests hash (Prague) are compared among
+//    providers at the same height. A provider on an older client may leave
+//    one out, and the withdrawal list and the blob base fee computed from
+//    excessBlobGas are not covered by the hash check below.
+//
+// 5. A HEADER THAT DOES NOT MATCH ITS HASH: every row is also checked on
+//    its own — the hash is recomputed from the header fields the provider
+//    returned (rpc/header.go). ✓ means they match; ✗ rows are listed under
+//    the table with the reason. Unlike the cases above this needs no second
+//    provider to notice, so it also catches every provider behind one bad
+//    cache agreeing on the same wrong answer.
+//
 // For trading applications, using stale data can be catastrophic — executing
 // a trade based on a block that gets reorganized away means the trade never
 // actually happened, but your internal state thinks it did.
@@ -70,7 +83,8 @@
 // WHAT A READER SHOULD UNDERSTAND
 // ================================
 // 1. Why multiple providers might disagree on block data
-// 2. The difference between height mismatches and hash mismatches
+// 2. The difference between height mismatches and hash mismatches, and
+//    why a hash can be checked against its own header
 // 3. How maps are used for grouping/aggregation in Go
 // 4. How the error interface works in Go struct fields
 // =============================================================================
@@ -80,8 +94,11 @@ package format
 import (
 	"fmt"
 	"io"
+	"sort"
 	"strings"
 	"time"
+
+	"github.com/dando385/eth-rpc-monitor/internal/rpc"
 )
 
 // =============================================================================
@@ -127,6 +144,102 @@ type SnapshotResult struct {
 	Height   uint64        // Block height returned by this provider (0 on error)
 	Latency  time.Duration // Time taken for the RPC call
 	Error    error         // nil on success; non-nil describes the failure
+
+	// HashCheck is the hash recomputed from the provider's own header
+	// (rpc.VerifyBlockHash); nil when not checked.
+	HashCheck *rpc.HashCheck
+
+	// Block is the provider's block, parsed; nil on error. Its post-London
+	// fields are compared across providers at the same height (forkFields).
+	Block *rpc.ParsedBlock
+}
+
+// forkField is one of the fields later forks added to a block, as compared
+// across providers: its name and its value rendered for display ("" when
+// the provider's block does not have it).
+type forkField struct {
+	Name  string
+	Value func(p *rpc.ParsedBlock) string
+}
+
+// forkFields lists the compared fields. Withdrawals are compared by count
+// and total because the body list is long; the blob base fee is compared
+// on its own since two providers can agree on excessBlobGas and still
+// differ in the fee if one reports a different header layout.
+var forkFields = []forkField{
+	{"withdrawalsRoot", func(p *rpc.ParsedBlock) string { return p.WithdrawalsRoot }},
+	{"withdrawals", func(p *rpc.ParsedBlock) string {
+		if p.WithdrawalsRoot == "" {
+			return ""
+		}
+		return fmt.Sprintf("%d totalling %s gwei", p.Withdrawals, rpc.FormatNumber(p.WithdrawalsGwei))
+	}},
+	{"blobGasUsed", func(p *rpc.ParsedBlock) string {
+		return blobField(p, func(g *rpc.BlobGas) string { return rpc.FormatNumber(g.Used) })
+	}},
+	{"excessBlobGas", func(p *rpc.ParsedBlock) string {
+		return blobField(p, func(g *rpc.BlobGas) string { return rpc.FormatNumber(g.Excess) })
+	}},
+	{"blob base fee", func(p *rpc.ParsedBlock) string {
+		return blobField(p, func(g *rpc.BlobGas) string { return g.BaseFee.String() + " wei (" + blobScheduleLabel(g) + ")" })
+	}},
+	{"parentBeaconBlockRoot", func(p *rpc.ParsedBlock) string { return p.ParentBeaconBlockRoot }},
+	{"requestsHash", func(p *rpc.ParsedBlock) string { return p.RequestsHash }},
+}
+
+// blobField renders a blob gas value, or "" before Cancun.
+func blobField(p *rpc.ParsedBlock, f func(g *rpc.BlobGas) string) string {
+	if p.BlobGas == nil {
+		return ""
+	}
+	return f(p.BlobGas)
+}
+
+// forkFieldMismatch is one field on which providers at one height disagree.
+type forkFieldMismatch struct {
+	Field  string
+	Height uint64
+	Groups map[string][]string // value → providers; "" is "absent"
+}
+
+// forkFieldMismatches compares every fork field across the providers that
+// returned the same height; comparing different blocks would only restate
+// the height mismatch. compared reports whether any height had two
+// providers with at least one of the fields to compare.
+func forkFieldMismatches(results []SnapshotResult) (mismatches []forkFieldMismatch, compared bool) {
+	byHeight := make(map[uint64][]SnapshotResult)
+	var heights []uint64
+	for _, r := range results {
+		if r.Error != nil || r.Block == nil {
+			continue
+		}
+		if byHeight[r.Height] == nil {
+			heights = append(heights, r.Height)
+		}
+		byHeight[r.Height] = append(byHeight[r.Height], r)
+	}
+	sort.Slice(heights, func(i, j int) bool { return heights[i] > heights[j] })
+
+	for _, h := range heights {
+		group := byHeight[h]
+		if len(group) < 2 {
+			continue
+		}
+		for _, f := range forkFields {
+			values := make(map[string][]string)
+			for _, r := range group {
+				v := f.Value(r.Block)
+				values[v] = append(values[v], r.Provider)
+			}
+			if _, absent := values[""]; !absent || len(values) > 1 {
+				compared = true
+			}
+			if len(values) > 1 {
+				mismatches = append(mismatches, forkFieldMismatch{Field: f.Name, Height: h, Groups: values})
+			}
+		}
+	}
+	return mismatches, compared
 }
 
 // =============================================================================
@@ -169,21 +282,22 @@ func FormatSnapshot(w io.Writer, results []SnapshotResult) {
 			// Provider failed — show error instead of data.
 			// Dim dashes replace the missing values to maintain column alignment.
 			// The `%v` verb prints the error using its Error() method.
-			fmt.Fprintf(w, "%-14s %s        %s   %s %v\n",
+			fmt.Fprintf(w, "%-14s %s        %s   %s %s\n",
 				r.Provider,
 				padRight(Dim("—"), 7),
 				padRight(Dim("—"), 12),
 				Red("ERROR:"),
-				r.Error)
+				ErrorLabel(r.Error))
 		} else {
 			// Provider succeeded — show block data.
 			// The hash is dimmed because it's long and secondary to the
 			// height information. Latency is color-coded by speed.
-			fmt.Fprintf(w, "%-14s %s        %12d   %s\n",
+			fmt.Fprintf(w, "%-14s %s        %12d   %s%s\n",
 				r.Provider,
 				padRight(ColorLatency(r.Latency.Milliseconds()), 7),
 				r.Height,
-				Dim(r.Hash))
+				Dim(r.Hash),
+				hashMark(r.HashCheck))
 		}
 	}
 
@@ -214,6 +328,18 @@ func FormatSnapshot(w io.Writer, results []SnapshotResult) {
 
 	fmt.Fprintln(w)
 
+	// Failure summary: one fetch per provider means each failing provider
+	// contributes exactly one count, so "2 of 5 failed: rate-limited×2" tells
+	// at a glance whether the failures share a cause.
+	failures := ErrorCounts{}
+	for _, r := range results {
+		failures.Add(r.Error)
+	}
+	if failures.Total() > 0 {
+		fmt.Fprintf(w, "%s %d of %d providers failed: %s\n\n",
+			Red("✗"), failures.Total(), len(results), failures.Summary())
+	}
+
 	// Height mismatch: providers report different block numbers.
 	// This usually means some providers are lagging behind the network tip.
 	// Common cause: propagation delay, overloaded nodes, or rate limiting.
@@ -247,6 +373,66 @@ func FormatSnapshot(w io.Writer, results []SnapshotResult) {
 		// All providers agree — show a reassuring green checkmark.
 		fmt.Fprintln(w, Green("✓"), "All providers agree on block hash")
 	}
+
+	// Later forks' fields: withdrawals, blob gas and the beacon root,
+	// requests. Most are in the header, so a provider that disagrees with a
+	// hash it shares with the others is also caught by the check below;
+	// the withdrawal list and the computed blob fee are not.
+	mismatches, compared := forkFieldMismatches(results)
+	if len(mismatches) > 0 {
+		if len(hashGroups) == 1 {
+			fmt.Fprintln(w) // The hash mismatch section already ends with one
+		}
+		fmt.Fprintln(w, Yellow("⚠"), Bold("BLOCK FIELD MISMATCH DETECTED:"))
+		for _, m := range mismatches {
+			fmt.Fprintf(w, "  %s at height %d\n", m.Field, m.Height)
+			values := make([]string, 0, len(m.Groups))
+			for v := range m.Groups {
+				values = append(values, v)
+			}
+			sort.Strings(values)
+			for _, v := range values {
+				shown := v
+				if v == "" {
+					shown = Dim("absent")
+				}
+				fmt.Fprintf(w, "    %s  →  %v\n", shown, m.Groups[v])
+			}
+		}
+		fmt.Fprintln(w)
+	} else if compared && len(hashGroups) == 1 {
+		fmt.Fprintln(w, Green("✓"), "All providers agree on withdrawals, blob gas, beacon root and requests hash")
+	}
+
+	// Header verification: agreement is not enough — providers behind one
+	// shared cache can agree on a wrong answer. Any provider whose header
+	// does not hash to the hash it returned is listed with the reason.
+	unverified := false
+	for _, r := range results {
+		if r.Error != nil || r.HashCheck == nil || r.HashCheck.OK() {
+			continue
+		}
+		if !unverified {
+			if len(hashGroups) == 1 && len(mismatches) == 0 {
+				fmt.Fprintln(w) // A mismatch section already ends with one
+			}
+			fmt.Fprintln(w, Red("✗"), Bold("HEADER DOES NOT MATCH RETURNED HASH:"))
+			unverified = true
+		}
+		fmt.Fprintf(w, "  %-14s %s\n", r.Provider, r.HashCheck.Problem())
+	}
+}
+
+// hashMark renders the verification mark after a snapshot row's hash.
+func hashMark(c *rpc.HashCheck) string {
+	switch {
+	case c == nil:
+		return ""
+	case c.OK():
+		return " " + Green("✓")
+	default:
+		return " " + Red("✗")
+	}
 }
 
 // shortHash returns s if len(s) <= n, otherwise the first n bytes of s (safe for ASCII hex hashes).
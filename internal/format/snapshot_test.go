@@ -6,6 +6,8 @@ import (
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/dando385/eth-rpc-monitor/internal/rpc"
 )
 
 func TestFormatSnapshot_shortHashesNoPanic(t *testing.T) {
@@ -49,3 +51,54 @@ func TestFormatSnapshot_errorRow(t *testing.T) {
 		t.Fatalf("output: %s", out)
 	}
 }
+
+func TestFormatSnapshot_headerVerification(t *testing.T) {
+	var buf bytes.Buffer
+	FormatSnapshot(&buf, []SnapshotResult{
+		{Provider: "good", Hash: "0xaa", Height: 1, HashCheck: &rpc.HashCheck{Claimed: "0xaa", Computed: "0xaa"}},
+		{Provider: "cached", Hash: "0xaa", Height: 1, HashCheck: &rpc.HashCheck{Claimed: "0xaa", Computed: "0xbb"}},
+	})
+	out := stripANSI(buf.String())
+	if !containsAll(out, []string{"0xaa ✓", "0xaa ✗", "All providers agree", "HEADER DOES NOT MATCH RETURNED HASH",
+		"cached", "header hashes to 0xbb, not the returned 0xaa"}) {
+		t.Fatalf("output: %s", out)
+	}
+}
+
+func TestFormatSnapshot_forkFields(t *testing.T) {
+	cancun := func(excess string) *rpc.ParsedBlock {
+		p := (&rpc.Block{Number: "0x1", WithdrawalsRoot: "0xw", BlobGasUsed: "0x20000", ExcessBlobGas: excess, ParentBeaconBlockRoot: "0xbeac"}).Parsed()
+		return &p
+	}
+	results := []SnapshotResult{
+		{Provider: "a", Hash: "0xaa", Height: 1, Block: cancun("0x0")},
+		{Provider: "b", Hash: "0xaa", Height: 1, Block: cancun("0x0")},
+	}
+	var buf bytes.Buffer
+	FormatSnapshot(&buf, results)
+	if out := stripANSI(buf.String()); !strings.Contains(out, "All providers agree on withdrawals, blob gas") {
+		t.Fatalf("output: %s", out)
+	}
+
+	stale := (&rpc.Block{Number: "0x1"}).Parsed()
+	results = append(results,
+		SnapshotResult{Provider: "c", Hash: "0xaa", Height: 1, Block: cancun("0xa00000")},
+		SnapshotResult{Provider: "old", Hash: "0xaa", Height: 1, Block: &stale},
+		SnapshotResult{Provider: "behind", Hash: "0x99", Height: 0, Block: &stale}, // Alone at its height
+	)
+	buf.Reset()
+	FormatSnapshot(&buf, results)
+	out := stripANSI(buf.String())
+	if !containsAll(out, []string{
+		"BLOCK FIELD MISMATCH DETECTED:",
+		"excessBlobGas at height 1", "0  →  [a b]", "10,485,760  →  [c]",
+		"blob base fee at height 1", "1 wei (Cancun schedule assumed from header layout)  →  [a b]", "23 wei (Cancun schedule assumed from header layout)  →  [c]",
+		"parentBeaconBlockRoot at height 1", "absent  →  [old]",
+	}) {
+		t.Fatalf("output: %s", out)
+	}
+	fields := out[strings.Index(out, "BLOCK FIELD MISMATCH"):]
+	if strings.Contains(fields, "behind") || strings.Contains(out, "All providers agree on withdrawals") {
+		t.Fatalf("only providers at the same height are compared:\n%s", out)
+	}
+}
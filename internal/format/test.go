@@ -83,6 +83,8 @@ import (
 	"sort"
 	"strings"
 	"time"
+
+	"github.com/dando385/eth-rpc-monitor/internal/rpc"
 )
 
 // =============================================================================
@@ -119,6 +121,9 @@ type TestResult struct {
 	Success     int             // Count of successful RPC calls
 	Total       int             // Total number of RPC calls attempted
 	Latencies   []time.Duration // Latency of each SUCCESSFUL call
+	Timings     []rpc.Timing    // Phase breakdown of each SUCCESSFUL call (parallel to Latencies)
+	Errors      ErrorCounts     // Failed calls by category (see errors.go); nil if none
+	IDMismatch  int             // Replies whose id answered some other request (subset of Errors)
 	BlockHeight uint64          // Last observed block height from this provider
 }
 
@@ -338,4 +343,43 @@ func FormatTest(w io.Writer, results []TestResult) {
 		}
 		fmt.Fprintln(w)
 	}
+
+	// --- Response ID Mismatches ---
+	//
+	// Unlike a timeout or a rate limit, a mismatched id means the provider
+	// (or a proxy in front of it) returned an answer to a DIFFERENT request.
+	// Any data it served could belong to someone else, so call it out on its
+	// own rather than leaving it inside the "protocol" count.
+	var mismatched []string
+	for _, r := range results {
+		if r.IDMismatch > 0 {
+			mismatched = append(mismatched, fmt.Sprintf("%s (%d)", r.Name, r.IDMismatch))
+		}
+	}
+	if len(mismatched) > 0 {
+		fmt.Fprintln(w, Red("✗"), Bold("RESPONSE ID MISMATCH DETECTED:"), strings.Join(mismatched, ", "))
+		fmt.Fprintln(w, Dim("  replies carried another request's id — responses may be crossed between clients"))
+		fmt.Fprintln(w)
+	}
+
+	// --- Failure Breakdown ---
+	//
+	// Success% says how often a provider failed; this says HOW it failed.
+	names := make([]string, len(results))
+	counts := make([]ErrorCounts, len(results))
+	for i, r := range results {
+		names[i], counts[i] = r.Name, r.Errors
+	}
+	FormatErrorBreakdown(w, names, counts)
+
+	// --- Phase Breakdown ---
+	//
+	// When samples were traced (rpc.WithTiming), show where the latency was
+	// spent. See phases.go.
+	for _, r := range results {
+		if len(r.Timings) > 0 {
+			FormatPhases(w, results)
+			break
+		}
+	}
 }
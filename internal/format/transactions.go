@@ -0,0 +1,101 @@
+// =============================================================================
+// FILE: internal/format/transactions.go
+// ROLE: Transaction Table Renderer — Per-Transaction Rows for `block --full`
+// =============================================================================
+//
+// SYSTEM CONTEXT
+// ==============
+// FormatBlock (block.go) summarizes a block; this file lists what is inside
+// it. It consumes the hydrated transactions from client.GetFullBlock():
+//
+//   Transactions (3)
+//   #    Hash           Type     From           To             Value                    Price          Gas
+//   ──────────────────────────────────────────────────────────────────────────────────────────────────────
+//   0    0x5c50…2060    legacy   0x32be…2d88    0xdac1…1ec7    0 ETH                    25.00 gwei     60,000
+//   1    0x9a1f…77d0    eip1559  0x9696…b3b5    (create)       1.5 ETH                  3.00 gwei      1,048,576
+//   2    0x04be…e9a1    eip4844  0x5050…f14c    0xff00…0000    0 ETH +2 blob(s)         3.00 gwei      21,000
+//
+// READING THE TABLE
+// =================
+//   - Price is the EFFECTIVE gas price — for EIP-1559 style transactions
+//     min(maxFee, baseFee + tip), computed from the block's base fee (see
+//     rpc.Transaction.EffectiveGasPrice). The fee actually charged is
+//     Price × gas USED, which needs the receipt; Gas here is the LIMIT the
+//     sender set, so Price × Gas is an upper bound on the fee.
+//   - Blob transactions note their blob count after the value, and EIP-7702
+//     transactions their authorization count — the parts of those types
+//     that the fee columns do not show.
+// =============================================================================
+
+package format
+
+import (
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+
+	"github.com/dando385/eth-rpc-monitor/internal/rpc"
+)
+
+// FormatTransactions renders one row per hydrated transaction. baseFee is
+// the block's base fee (nil before London) and is used to compute each
+// transaction's effective gas price.
+func FormatTransactions(w io.Writer, txs []rpc.Transaction, baseFee *big.Int) {
+	fmt.Fprintf(w, "%s\n", Bold(fmt.Sprintf("Transactions (%d)", len(txs))))
+	if len(txs) == 0 {
+		fmt.Fprintln(w, Dim("  (empty block)"))
+		fmt.Fprintln(w)
+		return
+	}
+
+	fmt.Fprintf(w, "%s %s %s %s %s %s %s %s\n",
+		Bold(fmt.Sprintf("%-4s", "#")),
+		Bold(fmt.Sprintf("%-14s", "Hash")),
+		Bold(fmt.Sprintf("%-8s", "Type")),
+		Bold(fmt.Sprintf("%-14s", "From")),
+		Bold(fmt.Sprintf("%-14s", "To")),
+		Bold(fmt.Sprintf("%-24s", "Value")),
+		Bold(fmt.Sprintf("%-14s", "Price")),
+		Bold("Gas"))
+	fmt.Fprintln(w, strings.Repeat("─", 110))
+
+	for i := range txs {
+		tx := &txs[i]
+		p := tx.Parsed()
+
+		to := shortHex(p.To)
+		if p.To == "" {
+			to = Yellow("(create)")
+		}
+
+		value := rpc.FormatEther(p.Value)
+		switch {
+		case p.BlobCount > 0:
+			value += Dim(fmt.Sprintf(" +%d blob(s)", p.BlobCount))
+		case p.AuthCount > 0:
+			value += Dim(fmt.Sprintf(" +%d auth(s)", p.AuthCount))
+		}
+
+		fmt.Fprintf(w, "%-4d %s %s %s %s %s %s %s\n",
+			i,
+			padRight(shortHex(p.Hash), 14),
+			padRight(p.Type.String(), 8),
+			padRight(shortHex(p.From), 14),
+			padRight(to, 14),
+			padRight(value, 24),
+			padRight(rpc.FormatGwei(tx.EffectiveGasPrice(baseFee)), 14),
+			rpc.FormatNumber(p.Gas))
+	}
+	fmt.Fprintln(w, Dim("  Price = effective gas price; Gas = gas limit (actual fee needs the receipt)"))
+	fmt.Fprintln(w)
+}
+
+// shortHex abbreviates a hash or address to "0x5c50…2060". Short strings
+// pass through unchanged.
+func shortHex(s string) string {
+	if len(s) <= 13 {
+		return s
+	}
+	return s[:6] + "…" + s[len(s)-4:]
+}
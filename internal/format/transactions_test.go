@@ -0,0 +1,46 @@
+package format
+
+import (
+	"bytes"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/dando385/eth-rpc-monitor/internal/rpc"
+)
+
+func TestFormatTransactions(t *testing.T) {
+	txs := []rpc.Transaction{
+		{Hash: "0x5c504ed432cb51138bcf09aa5e8a410dd4a1e204ef84bfed1be16dfba1b22060",
+			From: "0x32be343b94f860124dc4fee278fdcbd38c102d88", To: "0xdac17f958d2ee523a2206206994597c13d831ec7",
+			Gas: "0xea60", Value: "0xde0b6b3a7640000", GasPrice: "0x5d21dba00"},
+		{Hash: "0x9a1f", Type: "0x2", From: "0x9696", Gas: "0x100000", Value: "0x0",
+			MaxFeePerGas: "0x6fc23ac00", MaxPriorityFeePerGas: "0x3b9aca00"},
+		{Hash: "0x04be", Type: "0x3", From: "0x5050", To: "0xff00", Gas: "0x5208", Value: "0x0",
+			MaxFeePerGas: "0x6fc23ac00", MaxPriorityFeePerGas: "0x3b9aca00",
+			BlobVersionedHashes: []string{"0x01aa", "0x01bb"}},
+	}
+	var buf bytes.Buffer
+	FormatTransactions(&buf, txs, big.NewInt(2_000_000_000))
+	out := stripANSI(buf.String())
+
+	for _, want := range []string{
+		"Transactions (3)",
+		"0x5c50…2060", "0x32be…2d88", "0xdac1…1ec7", // abbreviated hash/addresses
+		"legacy", "1 ETH", "25.00 gwei", "60,000",
+		"eip1559", "(create)", "3.00 gwei", // base 2 + tip 1, under the 30 gwei cap
+		"eip4844", "+2 blob(s)",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("missing %q in:\n%s", want, out)
+		}
+	}
+}
+
+func TestFormatTransactions_empty(t *testing.T) {
+	var buf bytes.Buffer
+	FormatTransactions(&buf, nil, nil)
+	if !strings.Contains(buf.String(), "empty block") {
+		t.Fatalf("output: %s", buf.String())
+	}
+}
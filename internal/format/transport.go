@@ -0,0 +1,162 @@
+// =============================================================================
+// FILE: internal/format/transport.go
+// ROLE: Transport Policy Display — What Does Connection Setup Cost?
+// =============================================================================
+//
+// SYSTEM CONTEXT
+// ==============
+// This file renders `test --transport cold,warm,http1,http2`: the same
+// provider sampled once per transport policy (rpc/transport.go), one row
+// per (provider, policy), so the cost of each connection strategy can be
+// read off directly:
+//
+//   Provider       Policy Proto     Success P50    P95    P99    New conn Handshake +P50
+//   ─────────────────────────────────────────────────────────────────────────────────────
+//   alchemy        cold   HTTP/2.0  100%    52ms   71ms   80ms   30/30    27ms      +31ms
+//   alchemy        warm   HTTP/2.0  100%    21ms   29ms   33ms   0/30     —         fastest
+//   alchemy        http1  HTTP/1.1  100%    22ms   30ms   35ms   0/30     —         +1ms
+//   llamanodes     http2  —         0%      —      —      —      —        —         —
+//     └ network×30
+//
+// READING THE TABLE
+// =================
+//   - New conn:  samples that opened a connection instead of reusing one.
+//                Cold should be N/N; on a warm policy anything above 0
+//                means the provider (or its proxy) is closing keep-alives.
+//   - Handshake: P50 of DNS + TCP connect + TLS over those new-connection
+//                samples — the per-call price of not reusing connections.
+//   - +P50:      how much slower this policy's P50 is than the provider's
+//                fastest policy. For cold vs warm this is the end-to-end
+//                handshake overhead a production client would pay.
+//   - A forced protocol the endpoint does not speak shows up as failed
+//     samples on that row, not as a silent fallback.
+// =============================================================================
+
+package format
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/dando385/eth-rpc-monitor/internal/rpc"
+)
+
+// TransportTestResult is one provider's `test` result under one policy.
+type TransportTestResult struct {
+	TestResult
+	Policy rpc.Policy
+
+	// Skipped is why the policy was not run against this provider
+	// (rpc.Policy.Unsupported, e.g. "needs https"); "" when it was.
+	Skipped string
+}
+
+// Proto returns the protocol most successful samples arrived on, or "" when
+// none were traced.
+func (r TransportTestResult) Proto() string {
+	counts := make(map[string]int)
+	best := ""
+	for _, t := range r.Timings {
+		counts[t.Proto]++
+		if counts[t.Proto] > counts[best] {
+			best = t.Proto
+		}
+	}
+	return best
+}
+
+// NewConns counts the samples that did not reuse a pooled connection.
+func (r TransportTestResult) NewConns() int {
+	n := 0
+	for _, t := range r.Timings {
+		if !t.Reused {
+			n++
+		}
+	}
+	return n
+}
+
+// Handshake returns the median DNS + connect + TLS time over the samples
+// that opened a new connection, or 0 when every sample reused one.
+func (r TransportTestResult) Handshake() time.Duration {
+	var hs []time.Duration
+	for _, t := range r.Timings {
+		if !t.Reused {
+			hs = append(hs, t.DNS+t.Connect+t.TLS)
+		}
+	}
+	return CalculateTailLatency(hs).P50
+}
+
+// FormatTransportTest renders one row per (provider, policy). Rows for the
+// same provider are expected to be adjacent, as cmd/test produces them.
+// A policy that does not apply to the provider's URL is shown as n/a with
+// the reason, not as failed samples.
+func FormatTransportTest(w io.Writer, results []TransportTestResult) {
+	fmt.Fprintf(w, "%s %s %s %s %s %s %s %s %s %s\n",
+		Bold(fmt.Sprintf("%-14s", "Provider")),
+		Bold(fmt.Sprintf("%-6s", "Policy")),
+		Bold(fmt.Sprintf("%-9s", "Proto")),
+		Bold(fmt.Sprintf("%-7s", "Success")),
+		Bold(fmt.Sprintf("%-6s", "P50")),
+		Bold(fmt.Sprintf("%-6s", "P95")),
+		Bold(fmt.Sprintf("%-6s", "P99")),
+		Bold(fmt.Sprintf("%-8s", "New conn")),
+		Bold(fmt.Sprintf("%-9s", "Handshake")),
+		Bold("+P50"))
+	fmt.Fprintln(w, strings.Repeat("─", 90))
+
+	// The fastest P50 per provider is the baseline for +P50.
+	fastest := make(map[string]time.Duration)
+	for _, r := range results {
+		if len(r.Latencies) == 0 {
+			continue
+		}
+		p50 := CalculateTailLatency(r.Latencies).P50
+		if best, ok := fastest[r.Name]; !ok || p50 < best {
+			fastest[r.Name] = p50
+		}
+	}
+
+	dash := Dim("—")
+	for _, r := range results {
+		if r.Skipped != "" {
+			fmt.Fprintf(w, "%-14s %-6s %s\n", r.Name, string(r.Policy), Dim("n/a ("+r.Skipped+")"))
+			continue
+		}
+		proto, p50, p95, p99, conns, hs, extra := dash, dash, dash, dash, dash, dash, dash
+		if len(r.Latencies) > 0 {
+			tail := CalculateTailLatency(r.Latencies)
+			p50 = ColorLatency(tail.P50.Milliseconds())
+			p95 = ColorLatency(tail.P95.Milliseconds())
+			p99 = ColorLatency(tail.P99.Milliseconds())
+			if d := tail.P50 - fastest[r.Name]; d > 0 {
+				extra = Yellow("+" + phaseMs(d) + "ms")
+			} else {
+				extra = Green("fastest")
+			}
+		}
+		if len(r.Timings) > 0 {
+			if p := r.Proto(); p != "" {
+				proto = p
+			}
+			conns = fmt.Sprintf("%d/%d", r.NewConns(), len(r.Timings))
+			if h := r.Handshake(); h > 0 {
+				hs = phaseMs(h) + "ms"
+			}
+		}
+		fmt.Fprintf(w, "%-14s %-6s %s %s %s %s %s %s %s %s\n",
+			r.Name, string(r.Policy),
+			padRight(proto, 9),
+			padRight(ColorSuccess(r.Success, r.Total), 7),
+			padRight(p50, 6), padRight(p95, 6), padRight(p99, 6),
+			padRight(conns, 8), padRight(hs, 9), extra)
+		if r.Errors.Total() > 0 {
+			fmt.Fprintf(w, "  %s %s\n", Dim("└"), Red(r.Errors.Summary()))
+		}
+	}
+	fmt.Fprintln(w, Dim("  handshake = P50 of DNS+connect+TLS on new connections; +P50 = P50 above the provider's fastest policy"))
+	fmt.Fprintln(w)
+}
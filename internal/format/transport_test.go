@@ -0,0 +1,46 @@
+package format
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dando385/eth-rpc-monitor/internal/rpc"
+)
+
+func TestTransportTestResult_connectionStats(t *testing.T) {
+	r := TransportTestResult{TestResult: TestResult{Timings: []rpc.Timing{
+		{Proto: "HTTP/2.0", Connect: 10 * time.Millisecond, TLS: 20 * time.Millisecond},
+		{Proto: "HTTP/2.0", Reused: true},
+		{Proto: "HTTP/1.1", DNS: 2 * time.Millisecond, Connect: 10 * time.Millisecond},
+	}}}
+	if r.Proto() != "HTTP/2.0" || r.NewConns() != 2 {
+		t.Fatalf("proto %q, new conns %d", r.Proto(), r.NewConns())
+	}
+	// Nearest-rank P50 of {12ms, 30ms} is the lower one.
+	if got := r.Handshake(); got != 12*time.Millisecond {
+		t.Fatalf("handshake = %v", got)
+	}
+}
+
+func TestFormatTransportTest(t *testing.T) {
+	ms := func(n int) []time.Duration { return []time.Duration{time.Duration(n) * time.Millisecond} }
+	failed := TransportTestResult{Policy: rpc.PolicyHTTP2, TestResult: TestResult{Name: "a", Total: 1, Errors: ErrorCounts{rpc.CategoryNetwork: 1}}}
+
+	var buf bytes.Buffer
+	FormatTransportTest(&buf, []TransportTestResult{
+		{Policy: rpc.PolicyCold, TestResult: TestResult{Name: "a", Success: 1, Total: 1, Latencies: ms(50),
+			Timings: []rpc.Timing{{Proto: "HTTP/1.1", Connect: 9 * time.Millisecond}}}},
+		{Policy: rpc.PolicyWarm, TestResult: TestResult{Name: "a", Success: 1, Total: 1, Latencies: ms(20),
+			Timings: []rpc.Timing{{Proto: "HTTP/1.1", Reused: true}}}},
+		failed,
+		{Policy: rpc.PolicyHTTP2, Skipped: "needs https", TestResult: TestResult{Name: "local"}},
+	})
+	out := stripANSI(buf.String())
+	for _, want := range []string{"+30ms", "fastest", "1/1", "9.0ms", "0/1", "network×1", "local          http2  n/a (needs https)"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("missing %q in:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+// =============================================================================
+// FILE: internal/format/verify.go
+// ROLE: Body Verification Display — Does Each Provider's Block Match Its Header?
+// =============================================================================
+//
+// SYSTEM CONTEXT
+// ==============
+// `block --verify` fetches the same block, with full transaction objects,
+// from every provider and checks each copy against itself:
+//
+//   Header        the hash recomputed from the header fields (rpc/header.go)
+//   Transactions  transactionsRoot rebuilt from the transactions (rpc/body.go)
+//   Withdrawals   withdrawalsRoot rebuilt from the withdrawals (Shanghai on)
+//   Receipts      receiptsRoot rebuilt from the block's receipts (rpc/receipt.go)
+//   Bloom         every receipt's logsBloom, and the header's, recomputed from
+//                 the logs (rpc/bloom.go)
+//
+// Each check needs only the one response, so a provider is judged on its own
+// — a cache that serves a truncated transaction list or stale receipts
+// under the right header is caught even when every provider sits behind it.
+//
+//   Provider       Latency  Block Hash            Header        Transactions  Withdrawals  Receipts  Bloom
+//   ────────────────────────────────────────────────────────────────────────────────────────────────────────
+//   alchemy        43ms     0xa1b2c3d4e5f6a7b8…   ✓ Cancun      ✓ 152         ✓ 16         ✓ 152     ✓
+//   infura         39ms     0xa1b2c3d4e5f6a7b8…   ✓ Cancun      ✗ 151         ✓ 16         ✓ 152     ✓
+//                   └ transactionsRoot: 151 items hash to 0x…, header has 0x…
+//
+//   ✗ 1 of 2 providers returned a block that does not match its header
+// =============================================================================
+
+package format
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/dando385/eth-rpc-monitor/internal/rpc"
+)
+
+// VerifyResult holds one provider's copy of a block and the checks run on it.
+type VerifyResult struct {
+	Provider string
+	Latency  time.Duration
+	Error    error // Fetch failed; no checks ran
+
+	Hash         string
+	Header       rpc.HashCheck
+	Transactions rpc.RootCheck
+	Withdrawals  *rpc.RootCheck  // nil before Shanghai
+	Receipts     *rpc.RootCheck  // nil when receipts were not checked
+	Bloom        *rpc.BloomCheck // nil when receipts were not checked or could not be fetched
+}
+
+// OK reports whether the fetch succeeded and every check passed.
+func (r VerifyResult) OK() bool {
+	return r.Error == nil && r.Header.OK() && r.Transactions.OK() &&
+		(r.Withdrawals == nil || r.Withdrawals.OK()) &&
+		(r.Receipts == nil || r.Receipts.OK()) &&
+		(r.Bloom == nil || r.Bloom.OK())
+}
+
+// Problems lists one line per failed check.
+func (r VerifyResult) Problems() []string {
+	var out []string
+	if p := r.Header.Problem(); p != "" {
+		out = append(out, p)
+	}
+	if p := r.Transactions.Problem(); p != "" {
+		out = append(out, p)
+	}
+	for _, c := range []*rpc.RootCheck{r.Withdrawals, r.Receipts} {
+		if c == nil {
+			continue
+		}
+		if p := c.Problem(); p != "" {
+			out = append(out, p)
+		}
+	}
+	if r.Bloom != nil {
+		if p := r.Bloom.Problem(); p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// FormatVerification renders the per-provider verification table for one
+// block, with the reason under every failing row and a one-line verdict.
+func FormatVerification(w io.Writer, number uint64, results []VerifyResult) {
+	fmt.Fprintf(w, "\n%s\n\n", Bold(fmt.Sprintf("Body Verification — Block %d", number)))
+	fmt.Fprintf(w, "%s %s  %s %s %s %s %s %s\n",
+		Bold(fmt.Sprintf("%-14s", "Provider")),
+		Bold(fmt.Sprintf("%7s", "Latency")),
+		Bold(fmt.Sprintf("%-21s", "Block Hash")),
+		Bold(fmt.Sprintf("%-13s", "Header")),
+		Bold(fmt.Sprintf("%-13s", "Transactions")),
+		Bold(fmt.Sprintf("%-12s", "Withdrawals")),
+		Bold(fmt.Sprintf("%-9s", "Receipts")),
+		Bold("Bloom"))
+	fmt.Fprintln(w, strings.Repeat("─", 104))
+
+	failures := ErrorCounts{}
+	mismatched := 0
+	for _, r := range results {
+		if r.Error != nil {
+			failures.Add(r.Error)
+			fmt.Fprintf(w, "%-14s %s  %s %s\n", r.Provider, padRight(Dim("—"), 7), Red("ERROR:"), ErrorLabel(r.Error))
+			continue
+		}
+
+		hash := shortHash(r.Hash, 18)
+		if len(hash) < len(r.Hash) {
+			hash += "…"
+		}
+		fmt.Fprintf(w, "%-14s %s  %s %s %s %s %s %s\n",
+			r.Provider,
+			padRight(ColorLatency(r.Latency.Milliseconds()), 7),
+			padRight(Dim(hash), 21),
+			padRight(checkCell(r.Header.OK(), r.Header.Layout), 13),
+			padRight(rootCell(&r.Transactions), 13),
+			padRight(rootCell(r.Withdrawals), 12),
+			padRight(rootCell(r.Receipts), 9),
+			bloomCell(r.Bloom))
+
+		problems := r.Problems()
+		if len(problems) > 0 {
+			mismatched++
+		}
+		for _, p := range problems {
+			fmt.Fprintf(w, "%-16s%s %s\n", "", Dim("└"), p)
+		}
+	}
+
+	fmt.Fprintln(w)
+	if failures.Total() > 0 {
+		fmt.Fprintf(w, "%s %d of %d providers failed: %s\n", Red("✗"), failures.Total(), len(results), failures.Summary())
+	}
+	checked := len(results) - failures.Total()
+	switch {
+	case mismatched > 0:
+		fmt.Fprintf(w, "%s %d of %d providers returned a block that does not match its header\n", Red("✗"), mismatched, checked)
+	case checked > 0:
+		fmt.Fprintf(w, "%s All %d providers returned a block matching its header\n", Green("✓"), checked)
+	}
+}
+
+// rootCell renders a root check as ✓/✗ and the item count, or a dim dash
+// when it did not apply. A list that could not be fetched has no count.
+func rootCell(c *rpc.RootCheck) string {
+	switch {
+	case c == nil:
+		return Dim("—")
+	case c.Err != nil && c.Count == 0:
+		return Red("✗")
+	}
+	return checkCell(c.OK(), fmt.Sprint(c.Count))
+}
+
+// bloomCell renders a bloom check as a bare ✓/✗.
+func bloomCell(c *rpc.BloomCheck) string {
+	switch {
+	case c == nil:
+		return Dim("—")
+	case c.OK():
+		return Green("✓")
+	}
+	return Red("✗")
+}
+
+// checkCell renders a ✓/✗ mark followed by detail.
+func checkCell(ok bool, detail string) string {
+	if ok {
+		return Green("✓") + " " + detail
+	}
+	return Red("✗") + " " + detail
+}
@@ -0,0 +1,55 @@
+package format
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dando385/eth-rpc-monitor/internal/rpc"
+)
+
+func TestFormatVerification(t *testing.T) {
+	root := "0x56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421"
+	hash := "0x" + strings.Repeat("ab", 32)
+	ok := VerifyResult{
+		Provider: "good", Latency: 40 * time.Millisecond, Hash: hash,
+		Header:       rpc.HashCheck{Layout: "Shanghai", Claimed: hash, Computed: hash},
+		Transactions: rpc.RootCheck{Field: "transactionsRoot", Claimed: root, Computed: root, Count: 3},
+		Withdrawals:  &rpc.RootCheck{Field: "withdrawalsRoot", Claimed: root, Computed: root, Count: 16},
+	}
+	bad := ok
+	bad.Provider = "cached"
+	bad.Transactions = rpc.RootCheck{Field: "transactionsRoot", Claimed: root, Computed: "0xdead", Count: 2}
+	bad.Receipts = &rpc.RootCheck{Field: "receiptsRoot", Claimed: root, Err: errors.New("fetching receipts: timeout")}
+	ok.Receipts = &rpc.RootCheck{Field: "receiptsRoot", Claimed: root, Computed: root, Count: 3}
+	ok.Bloom = &rpc.BloomCheck{Receipts: 3, BlockOK: true}
+	down := VerifyResult{Provider: "down", Error: errors.New("connection refused")}
+
+	var buf bytes.Buffer
+	FormatVerification(&buf, 100, []VerifyResult{ok, bad, down})
+	out := stripANSI(buf.String())
+	if !containsAll(out, []string{
+		"Body Verification — Block 100",
+		"0xabababababababab…",
+		"✓ Shanghai", "✓ 3", "✓ 16", "✗ 2",
+		"└ transactionsRoot: 2 items hash to 0xdead, header has " + root,
+		"└ cannot verify receiptsRoot: fetching receipts: timeout",
+		"✓ 16         ✓ 3       ✓",
+		"down", "ERROR:",
+		"✗ 1 of 3 providers failed",
+		"✗ 1 of 2 providers returned a block that does not match its header",
+	}) {
+		t.Fatalf("output:\n%s", out)
+	}
+
+	buf.Reset()
+	pre := ok
+	pre.Withdrawals = nil
+	FormatVerification(&buf, 1, []VerifyResult{pre})
+	out = stripANSI(buf.String())
+	if !containsAll(out, []string{"✓ 3           —", "✓ All 1 providers returned a block matching its header"}) || strings.Contains(out, "└") {
+		t.Fatalf("pre-Shanghai output:\n%s", out)
+	}
+}
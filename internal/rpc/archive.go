@@ -0,0 +1,217 @@
+// =============================================================================
+// FILE: internal/rpc/archive.go
+// ROLE: Historical State — How Far Back Can This Provider Answer?
+// =============================================================================
+//
+// SYSTEM CONTEXT
+// ==============
+// Every node can return old BLOCKS; only some can return old STATE — an
+// account's balance or a contract's storage as of a past block. A full node
+// prunes state after a window (geth: 128 blocks, reth: ~10,000, others
+// configurable), an archive node keeps all of it back to genesis, and hosted
+// providers sell "archive access" as a separate tier. Which one sits behind
+// a URL is rarely documented, and it matters to anything that reconciles
+// balances as of a past block.
+//
+// ProbeArchive finds out by asking. At each probed block it calls
+// eth_getBalance and eth_getStorageAt; the block counts as servable when
+// both answer. Full-node state is a contiguous window ending at the head,
+// so the oldest servable block can be found in a logarithmic number of
+// calls:
+//
+//   1. EXPONENTIAL   step back 16, 32, 64, ... blocks from the head until
+//                    a block fails (or genesis answers: an archive node)
+//
+//        head-16 ✓   head-32 ✓   head-64 ✓   head-128 ✓   head-256 ✗
+//
+//   2. BINARY        between the last ✓ and the first ✗, halve the gap
+//                    until they are adjacent
+//
+//        head-256 ✗ ─── head-192 ✗ ─── head-160 ✗ ... ─── head-128 ✓
+//
+//   ≈ 2·log2(depth) probed blocks — about 30 calls for a 128-block window,
+//   about 50 to confirm an archive node on mainnet.
+//
+// WHICH FAILURES MEAN "PRUNED"
+// ============================
+// Only an error that says the state is gone ends a step. The phrasings
+// differ by client (StateUnavailable lists them); the two everyone meets are
+//
+//   "missing trie node 0x… (path …)"   geth family: the state trie was pruned
+//   "header not found"                 the node has no header for that number
+//
+// Any other failure — a timeout, a rate limit, a 5xx — says nothing about
+// the state, so it aborts the probe instead of being mistaken for its edge.
+// =============================================================================
+
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// probeStart is the first step back from the head. Doubling from here
+// brackets geth's 128-block window on the fourth probe.
+const probeStart = 16
+
+// GetBalance calls eth_getBalance for address at blockNum.
+func (c *Client) GetBalance(ctx context.Context, address, blockNum string) (*big.Int, time.Duration, error) {
+	resp, latency, err := c.Call(ctx, "eth_getBalance", address, blockNum)
+	if err != nil {
+		return nil, latency, err
+	}
+	var hexStr string
+	if err := json.Unmarshal(resp.Result, &hexStr); err != nil {
+		return nil, latency, fmt.Errorf("unmarshal getBalance result: %w", err)
+	}
+	v, ok := hexQuantity(hexStr)
+	if !ok {
+		return nil, latency, fmt.Errorf("parse balance hex %q", hexStr)
+	}
+	return v, latency, nil
+}
+
+// GetStorageAt calls eth_getStorageAt for one slot of address at blockNum
+// and returns the 32-byte word as hex.
+func (c *Client) GetStorageAt(ctx context.Context, address, slot, blockNum string) (string, time.Duration, error) {
+	resp, latency, err := c.Call(ctx, "eth_getStorageAt", address, slot, blockNum)
+	if err != nil {
+		return "", latency, err
+	}
+	var word string
+	if err := json.Unmarshal(resp.Result, &word); err != nil {
+		return "", latency, fmt.Errorf("unmarshal getStorageAt result: %w", err)
+	}
+	return word, latency, nil
+}
+
+// StateUnavailable reports whether err is a node saying it no longer has the
+// state (or header) of the requested block, and returns the matched pattern.
+// It returns "" for every other error, nil included.
+//
+// Known phrasings include:
+//
+//	"missing trie node 8d1e…(path )"              geth, and clients built on it
+//	"header not found"                             geth: no header for that number
+//	"required historical state unavailable"        geth with --history.state
+//	"state at block #123 is pruned"                reth
+//	"state not available" / "state is not available"
+//	"distance to target block exceeds maximum"     reth, beyond its proof window
+func StateUnavailable(err error) string {
+	var rpcErr *RPCError
+	if !errors.As(err, &rpcErr) {
+		return ""
+	}
+	msg := strings.ToLower(rpcErr.Message)
+	for _, p := range []string{
+		"missing trie node", "header not found", "historical state unavailable",
+		"is pruned", "state not available", "state is not available",
+		"state unavailable", "distance to target block exceeds",
+	} {
+		if strings.Contains(msg, p) {
+			return p
+		}
+	}
+	return ""
+}
+
+// ArchiveProbe is the result of ProbeArchive.
+type ArchiveProbe struct {
+	Head     uint64        // Block the probe started from
+	Oldest   uint64        // Oldest block whose state was served
+	Archive  bool          // State was served at genesis: a full archive
+	Calls    int           // eth_getBalance + eth_getStorageAt calls made
+	Elapsed  time.Duration // Wall time of the whole probe
+	Boundary uint64        // Newest block that failed (Oldest-1); 0 when Archive
+	Pattern  string        // StateUnavailable pattern the boundary failed with
+	Message  string        // The node's error message at the boundary
+}
+
+// Depth is how many blocks of state behind the head the provider serves.
+func (p *ArchiveProbe) Depth() uint64 { return p.Head - p.Oldest }
+
+// ProbeArchive finds the oldest block at or below head whose state the
+// provider still serves, asking for the balance and storage slot 0 of
+// address. See the file header for the search.
+func (c *Client) ProbeArchive(ctx context.Context, head uint64, address string) (*ArchiveProbe, error) {
+	start := time.Now()
+	p := &ArchiveProbe{Head: head}
+
+	// servable reports whether block n's state is available. A failure that
+	// is not a pruning pattern is returned as an error.
+	servable := func(n uint64) (bool, error) {
+		blockNum := fmt.Sprintf("0x%x", n)
+		p.Calls++
+		_, _, err := c.GetBalance(ctx, address, blockNum)
+		if err == nil {
+			p.Calls++
+			_, _, err = c.GetStorageAt(ctx, address, "0x0", blockNum)
+		}
+		if err == nil {
+			return true, nil
+		}
+		if pattern := StateUnavailable(err); pattern != "" {
+			p.Boundary, p.Pattern, p.Message = n, pattern, err.Error()
+			return false, nil
+		}
+		return false, fmt.Errorf("block %d: %w", n, err)
+	}
+	done := func(err error) (*ArchiveProbe, error) {
+		p.Elapsed = time.Since(start)
+		return p, err
+	}
+
+	ok, err := servable(head)
+	if err != nil {
+		return done(err)
+	}
+	if !ok {
+		return done(fmt.Errorf("no state at the head block %d: %s", head, p.Message))
+	}
+
+	// Exponential: good is the oldest block known to be servable, bad the
+	// newest known not to be.
+	good, bad := head, uint64(0)
+	for step := uint64(probeStart); ; step *= 2 {
+		n := uint64(0)
+		if step < head {
+			n = head - step
+		}
+		ok, err := servable(n)
+		if err != nil {
+			return done(err)
+		}
+		if !ok {
+			bad = n
+			break
+		}
+		good = n
+		if n == 0 {
+			p.Oldest, p.Archive = 0, true
+			return done(nil)
+		}
+	}
+
+	// Binary: invariant bad ✗, good ✓. Every failure is newer than the one
+	// before, so the boundary servable last recorded is bad's.
+	for good-bad > 1 {
+		mid := bad + (good-bad)/2
+		ok, err := servable(mid)
+		if err != nil {
+			return done(err)
+		}
+		if ok {
+			good = mid
+		} else {
+			bad = mid
+		}
+	}
+	p.Oldest = good
+	return done(nil)
+}
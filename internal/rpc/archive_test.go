@@ -0,0 +1,112 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// stateServer answers eth_getBalance and eth_getStorageAt for blocks at or
+// above oldest and fails older ones with errMsg. Calls to the block named by
+// failAt fail with a server error instead.
+func stateServer(oldest uint64, errMsg string, failAt uint64) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     uint64        `json:"id"`
+			Method string        `json:"method"`
+			Params []interface{} `json:"params"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		n, _ := ParseHexUint64(req.Params[len(req.Params)-1].(string))
+		var body string
+		switch {
+		case failAt != 0 && n == failAt:
+			body = `"error":{"code":-32603,"message":"internal error"}`
+		case n < oldest:
+			body = fmt.Sprintf(`"error":{"code":-32000,"message":%q}`, errMsg)
+		case req.Method == "eth_getBalance":
+			body = `"result":"0x1"`
+		default:
+			body = `"result":"0x` + strings.Repeat("0", 64) + `"`
+		}
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%d,%s}`, req.ID, body)
+	}))
+}
+
+func TestProbeArchive(t *testing.T) {
+	tests := []struct {
+		name         string
+		head, oldest uint64
+		errMsg       string
+		wantArchive  bool
+		wantOldest   uint64
+		wantPattern  string
+		wantMaxCalls int
+	}{
+		{"geth full node", 20_000_000, 20_000_000 - 127, "missing trie node 8d1e (path )", false, 20_000_000 - 127, "missing trie node", 40},
+		{"reth full node", 20_000_000, 20_000_000 - 10_064, "state at block #19989935 is pruned", false, 20_000_000 - 10_064, "is pruned", 60},
+		{"header pruned", 1000, 900, "header not found", false, 900, "header not found", 40},
+		{"archive", 20_000_000, 0, "", true, 0, "", 60},
+		{"genesis only missing", 1000, 1, "missing trie node", false, 1, "missing trie node", 60},
+	}
+	for _, tc := range tests {
+		srv := stateServer(tc.oldest, tc.errMsg, 0)
+		c := NewClient("t", srv.URL, 2*time.Second)
+		p, err := c.ProbeArchive(context.Background(), tc.head, "0x"+strings.Repeat("00", 20))
+		srv.Close()
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if p.Archive != tc.wantArchive || p.Oldest != tc.wantOldest || p.Pattern != tc.wantPattern {
+			t.Fatalf("%s: archive=%v oldest=%d pattern=%q", tc.name, p.Archive, p.Oldest, p.Pattern)
+		}
+		if !tc.wantArchive && p.Boundary != p.Oldest-1 {
+			t.Fatalf("%s: boundary %d, oldest %d", tc.name, p.Boundary, p.Oldest)
+		}
+		if p.Calls > tc.wantMaxCalls || p.Depth() != tc.head-tc.wantOldest {
+			t.Fatalf("%s: %d calls, depth %d", tc.name, p.Calls, p.Depth())
+		}
+	}
+}
+
+func TestProbeArchive_otherErrorsAbort(t *testing.T) {
+	// A server error in the middle of the search is not a pruning boundary.
+	srv := stateServer(500, "missing trie node", 984)
+	defer srv.Close()
+	c := NewClient("t", srv.URL, 2*time.Second)
+	_, err := c.ProbeArchive(context.Background(), 1000, "0x"+strings.Repeat("00", 20))
+	var rpcErr *RPCError
+	if !errors.As(err, &rpcErr) || !strings.Contains(err.Error(), "block 984") {
+		t.Fatalf("err = %v", err)
+	}
+
+	// No state even at the head.
+	srv2 := stateServer(2000, "header not found", 0)
+	defer srv2.Close()
+	c = NewClient("t", srv2.URL, 2*time.Second)
+	if _, err := c.ProbeArchive(context.Background(), 1000, "0x"+strings.Repeat("00", 20)); err == nil || !strings.Contains(err.Error(), "no state at the head") {
+		t.Fatalf("err = %v", err)
+	}
+}
+
+func TestStateUnavailable(t *testing.T) {
+	for msg, want := range map[string]string{
+		"missing trie node 0c5a0b2b8c (path )":               "missing trie node",
+		"header not found":                                   "header not found",
+		"required historical state unavailable (reexec=128)": "historical state unavailable",
+		"execution reverted":                                 "",
+	} {
+		if got := StateUnavailable(&RPCError{Code: -32000, Message: msg}); got != want {
+			t.Fatalf("StateUnavailable(%q) = %q want %q", msg, got, want)
+		}
+	}
+	if StateUnavailable(errors.New("missing trie node")) != "" || StateUnavailable(nil) != "" {
+		t.Fatal("non-RPC error matched")
+	}
+}
@@ -0,0 +1,169 @@
+package rpc
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSecret_neverFormatted(t *testing.T) {
+	s := Secret("hunter2")
+	wrapped := struct{ Password Secret }{s}
+	for _, out := range []string{
+		fmt.Sprint(s), fmt.Sprintf("%s %v %q %x %X %d", s, s, s, s, s, s),
+		fmt.Sprintf("%+v %#v", wrapped, wrapped),
+	} {
+		if strings.Contains(out, "hunter2") || strings.Contains(out, "68756e74657232") {
+			t.Fatalf("secret leaked: %s", out)
+		}
+	}
+	raw, _ := json.Marshal(wrapped)
+	if strings.Contains(string(raw), "hunter2") {
+		t.Fatalf("secret leaked in JSON: %s", raw)
+	}
+	if string(s) != "hunter2" {
+		t.Fatal("conversion to string must still yield the value")
+	}
+}
+
+// headerServer records the headers of the last request.
+func headerServer(t *testing.T) (*httptest.Server, *http.Header) {
+	t.Helper()
+	var got http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+		_, _ = w.Write(echoIDs(r, `{"jsonrpc":"2.0","id":1,"result":"0x1"}`))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &got
+}
+
+func TestClient_auth(t *testing.T) {
+	tests := []struct {
+		name string
+		auth *Auth
+		want string // Authorization header
+	}{
+		{"basic", &Auth{Username: "monitor", Password: "pw"}, "Basic " + base64.StdEncoding.EncodeToString([]byte("monitor:pw"))},
+		{"bearer", &Auth{BearerToken: "tok"}, "Bearer tok"},
+		{"headers only", &Auth{Headers: map[string]Secret{"X-Api-Key": "k"}}, ""},
+	}
+	for _, tt := range tests {
+		srv, got := headerServer(t)
+		if _, _, err := NewClient("t", srv.URL, 2*time.Second, WithAuth(tt.auth)).BlockNumber(context.Background()); err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if a := got.Get("Authorization"); a != tt.want {
+			t.Fatalf("%s: Authorization = %q, want %q", tt.name, a, tt.want)
+		}
+		if got.Get("Content-Type") != "application/json" {
+			t.Fatalf("%s: Content-Type lost", tt.name)
+		}
+	}
+
+	srv, got := headerServer(t)
+	auth := &Auth{Headers: map[string]Secret{"X-Api-Key": "k"}, BearerToken: "tok"}
+	// The canned reply is not a batch array, so CallBatch fails; only the
+	// request headers matter here.
+	_, _, _ = NewClient("t", srv.URL, 2*time.Second, WithAuth(auth)).CallBatch(context.Background(), []BatchElem{{Method: "eth_chainId"}})
+	if got.Get("X-Api-Key") != "k" || got.Get("Authorization") != "Bearer tok" {
+		t.Fatalf("batch headers = %v", *got)
+	}
+}
+
+func TestClient_authJWT(t *testing.T) {
+	secret := []byte("0123456789abcdef0123456789abcdef")
+	srv, got := headerServer(t)
+	before := time.Now().Unix()
+	if _, _, err := NewClient("t", srv.URL, 2*time.Second, WithAuth(&Auth{JWTSecret: Secret(secret)})).BlockNumber(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	token := strings.TrimPrefix(got.Get("Authorization"), "Bearer ")
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		t.Fatalf("token = %q", token)
+	}
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(parts[0] + "." + parts[1]))
+	if base64.RawURLEncoding.EncodeToString(mac.Sum(nil)) != parts[2] {
+		t.Fatal("bad HS256 signature")
+	}
+
+	var header struct{ Alg, Typ string }
+	var claims struct{ Iat int64 }
+	h, _ := base64.RawURLEncoding.DecodeString(parts[0])
+	c, _ := base64.RawURLEncoding.DecodeString(parts[1])
+	if json.Unmarshal(h, &header) != nil || header.Alg != "HS256" || header.Typ != "JWT" {
+		t.Fatalf("header = %s", h)
+	}
+	if json.Unmarshal(c, &claims) != nil || claims.Iat < before || claims.Iat > time.Now().Unix() {
+		t.Fatalf("claims = %s", c)
+	}
+}
+
+func TestLoadJWTSecret(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	hexKey := strings.Repeat("ab", 32)
+
+	s, err := LoadJWTSecret(write("ok", "0x"+hexKey+"\n"))
+	if err != nil || len(s) != 32 || s[0] != 0xab {
+		t.Fatalf("s = %d bytes, err = %v", len(s), err)
+	}
+	if _, err := LoadJWTSecret(write("short", "abcd")); err == nil {
+		t.Fatal("short secret accepted")
+	}
+	_, err = LoadJWTSecret(write("nothex", "zz"+hexKey[2:]))
+	if err == nil || strings.Contains(err.Error(), hexKey[2:]) {
+		t.Fatalf("err = %v", err)
+	}
+	if _, err := LoadJWTSecret(filepath.Join(dir, "missing")); err == nil {
+		t.Fatal("missing file accepted")
+	}
+}
+
+func TestWSClient_authOnUpgrade(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer tok" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		conn, brw, _ := w.(http.Hijacker).Hijack()
+		fmt.Fprintf(brw, "HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\nSec-WebSocket-Accept: %s\r\n\r\n",
+			wsAcceptKey(r.Header.Get("Sec-WebSocket-Key")))
+		brw.Flush()
+		conn.Close()
+	}))
+	defer srv.Close()
+	url := "ws" + strings.TrimPrefix(srv.URL, "http")
+
+	plain := NewWSClient("t", url, 2*time.Second)
+	defer plain.Close()
+	if err := plain.Connect(context.Background()); Classify(err) != CategoryAuth {
+		t.Fatalf("without auth: err = %v", err)
+	}
+
+	authed := NewWSClient("t", url, 2*time.Second, WithAuth(&Auth{BearerToken: "tok"}))
+	authed.PingInterval = 0
+	defer authed.Close()
+	if err := authed.Connect(context.Background()); err != nil {
+		t.Fatalf("with auth: %v", err)
+	}
+}
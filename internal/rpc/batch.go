@@ -0,0 +1,187 @@
+// =============================================================================
+// FILE: internal/rpc/batch.go
+// ROLE: Network Layer — JSON-RPC 2.0 Batch Requests
+// =============================================================================
+//
+// SYSTEM CONTEXT
+// ==============
+// JSON-RPC 2.0 allows a client to send an ARRAY of requests in one HTTP POST
+// and receive an ARRAY of responses back. Providers treat batches very
+// differently: some execute them happily, some charge each element against a
+// rate limit, some cap the batch size, and some reject batches outright.
+// CallBatch exists so the `test` command can measure exactly that.
+//
+//   Single call (client.go):            Batch call (THIS FILE):
+//
+//   POST {"id":1,"method":...}          POST [{"id":1,...},{"id":2,...},...]
+//        ◀── {"id":1,"result":...}           ◀── [{"id":2,...},{"id":1,...},...]
+//                                                  ↑ order NOT guaranteed
+//
+// MATCHING RESPONSES TO REQUESTS
+// ==============================
+// The spec says the server MAY return batch responses in any order, and
+// each response carries the "id" of the request it answers. So we number
+// the requests 1..N and use a map from ID to request index to put each
+// response back in its slot:
+//
+//   Request order:   [ id=1 eth_blockNumber, id=2 eth_chainId, id=3 ... ]
+//   Response order:  [ id=3, id=1, id=2 ]
+//                         │     │     │
+//                         ▼     ▼     ▼
+//   results[]:       [ slot0 ← id=1, slot1 ← id=2, slot2 ← id=3 ]
+//
+// Anything that can't be matched — a missing ID, a duplicate, an ID we never
+// sent — becomes a per-element error instead of silently shifting results.
+//
+// TWO LEVELS OF FAILURE
+// =====================
+//  1. BATCH-LEVEL: The whole exchange failed (network error, HTTP 4xx/5xx,
+//     or the provider answered with a single error OBJECT instead of an
+//     array — the usual way "batch not supported / too large" is reported).
+//     CallBatch returns a non-nil error and no results.
+//  2. ELEMENT-LEVEL: The batch went through, but individual elements failed
+//     (RPC error, or no response for that ID). CallBatch returns a nil error
+//     and the failures live in BatchResult.Error.
+// =============================================================================
+
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// BatchElem describes one request inside a batch.
+//
+// Params follows the same convention as Call's variadic params: nil is sent
+// as an empty JSON array.
+type BatchElem struct {
+	Method string        // RPC method name, e.g., "eth_blockNumber"
+	Params []interface{} // Method arguments (nil = no params)
+}
+
+// BatchResult holds the outcome of one BatchElem, at the same index as the
+// request it answers.
+//
+// Exactly one of Response and Error is non-nil.
+type BatchResult struct {
+	Response *Response // Matched response (nil on element-level failure)
+	Error    error     // Element-level failure (RPC error or missing response)
+}
+
+// CallBatch sends elems as a single JSON-RPC batch and returns one
+// BatchResult per element, in request order, plus the round-trip latency of
+// the whole batch.
+//
+// The returned latency covers serialization-to-decoding for the entire
+// array, the same span Call measures for a single request, so per-element
+// cost is simply latency / len(elems).
+func (c *Client) CallBatch(ctx context.Context, elems []BatchElem) ([]BatchResult, time.Duration, error) {
+	if len(elems) == 0 {
+		return nil, 0, errors.New("empty batch")
+	}
+
+	// Build the request array with fresh unique IDs and remember where each
+	// ID lives.
+	reqs := make([]Request, len(elems))
+	index := make(map[uint64]int, len(elems))
+	for i, e := range elems {
+		params := e.Params
+		if params == nil {
+			params = []interface{}{}
+		}
+		id := c.ids.take()
+		reqs[i] = Request{JSONRPC: "2.0", Method: e.Method, Params: params, ID: id}
+		index[id] = i
+	}
+
+	body, err := json.Marshal(reqs)
+	if err != nil {
+		return nil, 0, fmt.Errorf("marshal rpc batch: %w", err)
+	}
+
+	// One token per element: providers meter a batch by its contents.
+	queued, err := c.throttle(ctx, len(elems))
+	defer recordQueue(ctx, queued)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	start := time.Now()
+	raw, err := c.post(ctx, body)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// A provider that refuses the batch typically answers with ONE error
+	// object (not an array). Detect that before attempting array decoding so
+	// the caller sees the provider's actual reason ("batch too large", etc.).
+	trimmed := bytes.TrimSpace(raw)
+	if len(trimmed) > 0 && trimmed[0] == '{' {
+		var single Response
+		if err := json.Unmarshal(trimmed, &single); err != nil {
+			return nil, 0, &ProtocolError{Msg: "decode rpc batch response", Err: err}
+		}
+		if single.Error != nil {
+			return nil, 0, fmt.Errorf("batch rejected: %w", single.Error)
+		}
+		return nil, 0, &ProtocolError{Msg: "batch rejected: provider returned a single object instead of an array"}
+	}
+
+	var resps []Response
+	if err := json.Unmarshal(trimmed, &resps); err != nil {
+		return nil, 0, &ProtocolError{Msg: "decode rpc batch response", Err: err}
+	}
+	latency := time.Since(start)
+
+	// Put each response back into the slot of the request it answers.
+	results := make([]BatchResult, len(elems))
+	var foreign string // First ID we never sent, if any
+	for i := range resps {
+		r := &resps[i]
+		id, parsed := parseID(r.ID)
+		slot, ok := index[id]
+		if !parsed || !ok {
+			// Unknown or duplicate ID: the request it claims to answer was
+			// already matched (or never sent). Ignore it; the affected slot
+			// is reported below if nothing else fills it.
+			if foreign == "" && !sent(reqs, id) {
+				foreign = string(r.ID)
+			}
+			continue
+		}
+		delete(index, id)
+		if r.Error != nil {
+			results[slot].Error = r.Error
+			continue
+		}
+		results[slot].Response = r
+	}
+
+	// Whatever is still in the index never got a response. If the reply
+	// contained IDs we never sent, the likeliest story is that those were
+	// meant as answers to ours — report that as an ID mismatch.
+	for id, slot := range index {
+		if foreign != "" {
+			results[slot].Error = &IDMismatchError{Sent: id, Got: foreign}
+			continue
+		}
+		results[slot].Error = &ProtocolError{Msg: fmt.Sprintf("no response for id %d in batch", id)}
+	}
+	return results, latency, nil
+}
+
+// sent reports whether id was one of the IDs in reqs — i.e. whether a
+// second response carrying it is a duplicate rather than a foreign ID.
+func sent(reqs []Request, id uint64) bool {
+	for i := range reqs {
+		if reqs[i].ID == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,73 @@
+package rpc
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClient_CallBatch_outOfOrderAndPartialError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(echoIDs(r, `[
+			{"jsonrpc":"2.0","id":3,"result":"0x3"},
+			{"jsonrpc":"2.0","id":1,"result":"0x1"},
+			{"jsonrpc":"2.0","id":2,"error":{"code":-32000,"message":"boom"}}
+		]`))
+	}))
+	defer srv.Close()
+
+	c := NewClient("t", srv.URL, 2*time.Second)
+	elems := []BatchElem{{Method: "eth_blockNumber"}, {Method: "eth_chainId"}, {Method: "eth_blockNumber"}}
+	res, _, err := c.CallBatch(context.Background(), elems)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(res[0].Response.Result) != `"0x1"` || string(res[2].Response.Result) != `"0x3"` {
+		t.Fatalf("results not matched by id: %+v", res)
+	}
+	if res[1].Error == nil || !strings.Contains(res[1].Error.Error(), "boom") {
+		t.Fatalf("expected element error, got %+v", res[1])
+	}
+}
+
+func TestClient_CallBatch_missingResponse(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(echoIDs(r, `[{"jsonrpc":"2.0","id":1,"result":"0x1"},{"jsonrpc":"2.0","id":1,"result":"0x9"}]`))
+	}))
+	defer srv.Close()
+
+	c := NewClient("t", srv.URL, 2*time.Second)
+	res, _, err := c.CallBatch(context.Background(), []BatchElem{{Method: "a"}, {Method: "b"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res[0].Response == nil || string(res[0].Response.Result) != `"0x1"` {
+		t.Fatalf("slot 0: %+v", res[0])
+	}
+	if res[1].Error == nil || !strings.Contains(res[1].Error.Error(), "no response for id") {
+		t.Fatalf("slot 1: %+v", res[1])
+	}
+}
+
+func TestClient_CallBatch_rejected(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":null,"error":{"code":-32600,"message":"batch too large"}}`))
+	}))
+	defer srv.Close()
+
+	c := NewClient("t", srv.URL, 2*time.Second)
+	_, _, err := c.CallBatch(context.Background(), []BatchElem{{Method: "a"}})
+	if err == nil || !strings.Contains(err.Error(), "batch too large") {
+		t.Fatalf("expected rejection, got %v", err)
+	}
+}
+
+func TestClient_CallBatch_empty(t *testing.T) {
+	c := NewClient("t", "http://127.0.0.1:0", time.Second)
+	if _, _, err := c.CallBatch(context.Background(), nil); err == nil {
+		t.Fatal("expected error for empty batch")
+	}
+}
@@ -0,0 +1,168 @@
+// =============================================================================
+// FILE: internal/rpc/blob.go
+// ROLE: Blob Gas — The Second Fee Market, From a Header's excessBlobGas
+// =============================================================================
+//
+// SYSTEM CONTEXT
+// ==============
+// Cancun (EIP-4844) gave blobs — the data rollups post — their own gas and
+// their own base fee, separate from the EIP-1559 one. The header carries
+// the two inputs, not the fee itself:
+//
+//   blobGasUsed     blob gas in this block: 131072 (2^17) per blob
+//   excessBlobGas   how far blob usage has run above the target, summed
+//                   over past blocks; it grows when blocks carry more than
+//                   the target and shrinks when they carry less
+//
+// and every client derives the blob base fee the same way:
+//
+//   blobBaseFee = fake_exponential(1 wei, excessBlobGas, updateFraction)
+//
+// fake_exponential is an integer Taylor series for 1 × e^(excess/fraction):
+// the fee is 1 wei until the excess builds up, then multiplies by e for
+// every updateFraction of excess. The fraction is a fork parameter, chosen
+// so the fee moves at most about 12.5% per block:
+//
+//   Fork     target  max   updateFraction
+//   Cancun     3      6      3338477       EIP-4844
+//   Prague     6      9      5007716       EIP-7691
+//   Osaka      6      9      5007716       unchanged
+//   BPO1      10     15      8346193       EIP-7892 blob-parameter-only fork
+//   BPO2      14     21     11684671       EIP-7892 blob-parameter-only fork
+//
+// WHICH FORK'S PARAMETERS
+// =======================
+// The header alone cannot say: Osaka and the blob-parameter-only (BPO)
+// forks after it keep Prague's header layout. So the schedule comes from
+// the chain's fork timetable when the chain ID is known:
+//
+//   chain ID + block timestamp ──▶ blobTimetables ──▶ the newest fork
+//                                                     activated at that time
+//
+// Only mainnet's timetable is built in. For any other chain, or when the
+// chain ID is unknown (0), the header layout decides as a fallback —
+// requestsHash means Prague, otherwise Cancun — and BlobGas.Assumed is set,
+// so a display labels the fee as computed under an assumed schedule rather
+// than presenting it as the chain's.
+// =============================================================================
+
+package rpc
+
+import "math/big"
+
+// BlobGasPerBlob is the blob gas one blob uses (EIP-4844 GAS_PER_BLOB).
+const BlobGasPerBlob = 1 << 17
+
+// MinBlobBaseFee is the blob base fee with no excess, in wei
+// (EIP-4844 MIN_BASE_FEE_PER_BLOB_GAS).
+const MinBlobBaseFee = 1
+
+// BlobSchedule is one fork's blob parameters.
+type BlobSchedule struct {
+	Fork           string
+	Target         int    // Blobs per block the fee steers toward
+	Max            int    // Blobs per block allowed
+	UpdateFraction uint64 // Excess blob gas that multiplies the fee by e
+}
+
+// The blob schedules of each fork.
+var (
+	CancunBlobs = BlobSchedule{Fork: "Cancun", Target: 3, Max: 6, UpdateFraction: 3338477}
+	PragueBlobs = BlobSchedule{Fork: "Prague", Target: 6, Max: 9, UpdateFraction: 5007716}
+	OsakaBlobs  = BlobSchedule{Fork: "Osaka", Target: 6, Max: 9, UpdateFraction: 5007716}
+	BPO1Blobs   = BlobSchedule{Fork: "BPO1", Target: 10, Max: 15, UpdateFraction: 8346193}
+	BPO2Blobs   = BlobSchedule{Fork: "BPO2", Target: 14, Max: 21, UpdateFraction: 11684671}
+)
+
+// blobActivation is the moment a chain switched to a blob schedule.
+type blobActivation struct {
+	Time     uint64 // Unix seconds of the first block under Schedule
+	Schedule BlobSchedule
+}
+
+// blobTimetables lists each known chain's blob schedules, oldest first.
+var blobTimetables = map[uint64][]blobActivation{
+	1: { // Mainnet
+		{1710338135, CancunBlobs},
+		{1746612311, PragueBlobs},
+		{1764798551, OsakaBlobs},
+		{1765290071, BPO1Blobs},
+		{1767747671, BPO2Blobs},
+	},
+}
+
+// BlobScheduleAt returns the blob schedule chainID ran at timestamp. ok is
+// false when the chain's timetable is not built in, or the timestamp is
+// before its Cancun.
+func BlobScheduleAt(chainID, timestamp uint64) (schedule BlobSchedule, ok bool) {
+	for _, a := range blobTimetables[chainID] {
+		if timestamp < a.Time {
+			break
+		}
+		schedule, ok = a.Schedule, true
+	}
+	return schedule, ok
+}
+
+// BlobGas is a block's blob gas accounting.
+type BlobGas struct {
+	Used     uint64   // blobGasUsed
+	Excess   uint64   // excessBlobGas
+	Blobs    int      // Used / BlobGasPerBlob
+	BaseFee  *big.Int // Blob base fee in wei, from Excess and Schedule
+	Schedule BlobSchedule
+	Assumed  bool // Schedule inferred from the header layout, not the chain's timetable
+}
+
+// blobGas reads the block's blob fields; nil before Cancun. chainID picks
+// the fork timetable (0 = unknown; see WHICH FORK'S PARAMETERS).
+func (b *Block) blobGas(chainID uint64) *BlobGas {
+	if b.BlobGasUsed == "" && b.ExcessBlobGas == "" {
+		return nil
+	}
+	used, _ := ParseHexUint64(b.BlobGasUsed)
+	excess, _ := ParseHexUint64(b.ExcessBlobGas)
+	ts, _ := ParseHexUint64(b.Timestamp)
+	schedule, known := BlobScheduleAt(chainID, ts)
+	if !known {
+		schedule = CancunBlobs
+		if b.RequestsHash != "" {
+			schedule = PragueBlobs
+		}
+	}
+	return &BlobGas{
+		Used:     used,
+		Excess:   excess,
+		Blobs:    int(used / BlobGasPerBlob),
+		BaseFee:  BlobBaseFee(excess, schedule.UpdateFraction),
+		Schedule: schedule,
+		Assumed:  !known,
+	}
+}
+
+// BlobBaseFee returns the blob base fee in wei for an excessBlobGas under
+// the given update fraction, exactly as EIP-4844's fake_exponential
+// computes it:
+//
+//	i, output, acc = 1, 0, factor × denominator
+//	while acc > 0:
+//	    output += acc
+//	    acc = acc × numerator / (denominator × i)
+//	    i += 1
+//	return output / denominator
+//
+// Integer division truncates at every step, which is part of the
+// definition: a float e^x would be off by a wei often enough to matter.
+func BlobBaseFee(excessBlobGas, updateFraction uint64) *big.Int {
+	numerator := new(big.Int).SetUint64(excessBlobGas)
+	denominator := new(big.Int).SetUint64(updateFraction)
+	output := new(big.Int)
+	acc := new(big.Int).Mul(big.NewInt(MinBlobBaseFee), denominator)
+	divisor := new(big.Int)
+	for i := int64(1); acc.Sign() > 0; i++ {
+		output.Add(output, acc)
+		acc.Mul(acc, numerator)
+		acc.Quo(acc, divisor.Mul(denominator, big.NewInt(i)))
+	}
+	return output.Quo(output, denominator)
+}
@@ -0,0 +1,97 @@
+package rpc
+
+import "testing"
+
+func TestBlobBaseFee(t *testing.T) {
+	// Vectors from the EIP-4844 reference tests, Cancun fraction.
+	tests := []struct {
+		excess uint64
+		want   int64
+	}{
+		{0, 1},
+		{2314057, 1},
+		{2314058, 2},
+		{10 * 1024 * 1024, 23},
+	}
+	for _, tc := range tests {
+		if got := BlobBaseFee(tc.excess, CancunBlobs.UpdateFraction); got.Int64() != tc.want {
+			t.Errorf("BlobBaseFee(%d) = %v, want %d", tc.excess, got, tc.want)
+		}
+	}
+	if BlobBaseFee(10*1024*1024, PragueBlobs.UpdateFraction).Int64() >= 23 {
+		t.Error("Prague's larger fraction should price the same excess lower")
+	}
+}
+
+func TestParsed_forkFields(t *testing.T) {
+	b := &Block{
+		Number: "0x1", GasUsed: "0x0", GasLimit: "0x1c9c380",
+		WithdrawalsRoot: "0xaa",
+		Withdrawals: []Withdrawal{
+			{Index: "0x1", ValidatorIndex: "0x2", Address: "0x01", Amount: "0x10"},
+			{Index: "0x2", ValidatorIndex: "0x3", Address: "0x02", Amount: "0x20"},
+		},
+		BlobGasUsed:           "0x60000",
+		ExcessBlobGas:         "0xa00000",
+		ParentBeaconBlockRoot: "0xbb",
+	}
+	p := b.Parsed()
+	if p.WithdrawalsRoot != "0xaa" || p.Withdrawals != 2 || p.WithdrawalsGwei != 0x30 || p.ParentBeaconBlockRoot != "0xbb" {
+		t.Fatalf("withdrawals / beacon root = %+v", p)
+	}
+	g := p.BlobGas
+	if g == nil || g.Used != 0x60000 || g.Blobs != 3 || g.Excess != 10*1024*1024 || g.Schedule.Fork != "Cancun" || g.BaseFee.Int64() != 23 {
+		t.Fatalf("Cancun blob gas = %+v", g)
+	}
+
+	b.RequestsHash = "0xcc"
+	if p = b.Parsed(); p.RequestsHash != "0xcc" || p.BlobGas.Schedule != PragueBlobs {
+		t.Fatalf("a header with requestsHash should use Prague's blob schedule: %+v", p.BlobGas)
+	}
+
+	if p := (&Block{Number: "0x1"}).Parsed(); p.BlobGas != nil || p.Withdrawals != 0 || p.WithdrawalsRoot != "" {
+		t.Fatalf("pre-Shanghai block = %+v", p)
+	}
+}
+
+func TestBlobScheduleAt(t *testing.T) {
+	tests := []struct {
+		chainID, timestamp uint64
+		want               BlobSchedule
+		ok                 bool
+	}{
+		{1, 1710338134, BlobSchedule{}, false}, // The second before Cancun
+		{1, 1710338135, CancunBlobs, true},
+		{1, 1746612311, PragueBlobs, true},
+		{1, 1765000000, OsakaBlobs, true},
+		{1, 1765290071, BPO1Blobs, true},
+		{1, 1800000000, BPO2Blobs, true},
+		{11155111, 1800000000, BlobSchedule{}, false}, // No built-in timetable
+		{0, 1800000000, BlobSchedule{}, false},
+	}
+	for _, tc := range tests {
+		if got, ok := BlobScheduleAt(tc.chainID, tc.timestamp); got != tc.want || ok != tc.ok {
+			t.Errorf("BlobScheduleAt(%d, %d) = %+v, %v", tc.chainID, tc.timestamp, got, ok)
+		}
+	}
+}
+
+func TestParsedOn_blobSchedule(t *testing.T) {
+	// A mainnet block after BPO2 has Prague's layout.
+	b := &Block{Number: "0x1", Timestamp: "0x6b49d200", BlobGasUsed: "0x0", ExcessBlobGas: "0x4000000", RequestsHash: "0xcc"}
+
+	layout := b.Parsed().BlobGas
+	if layout.Schedule != PragueBlobs || !layout.Assumed {
+		t.Fatalf("without a chain ID = %+v", layout)
+	}
+	g := b.ParsedOn(1).BlobGas
+	if g.Schedule != BPO2Blobs || g.Assumed {
+		t.Fatalf("mainnet = %+v", g)
+	}
+	if want := BlobBaseFee(0x4000000, BPO2Blobs.UpdateFraction); g.BaseFee.Cmp(want) != 0 || g.BaseFee.Cmp(layout.BaseFee) >= 0 {
+		t.Fatalf("fee %v, want %v (below the Prague-priced %v)", g.BaseFee, want, layout.BaseFee)
+	}
+	if g := b.ParsedOn(17000).BlobGas; g.Schedule != PragueBlobs || !g.Assumed {
+		t.Fatalf("unknown chain = %+v", g)
+	}
+}
@@ -0,0 +1,137 @@
+// =============================================================================
+// FILE: internal/rpc/bloom.go
+// ROLE: Logs Bloom — Recompute Receipt and Block Blooms From the Logs
+// =============================================================================
+//
+// SYSTEM CONTEXT
+// ==============
+// Every receipt carries a 2048-bit bloom filter of its logs, and the header's
+// logsBloom is the OR of all of them. eth_getLogs and light clients use the
+// header bloom to skip blocks that cannot contain a matching event, so a
+// wrong bloom makes events invisible (a bit missing) or lookups slow (bits
+// set that should not be). Both are derived data — nothing stops a provider
+// from serving a bloom that does not match the logs next to it.
+//
+// THE FILTER
+// ==========
+// Each log adds its address and every topic. For each value v:
+//
+//   h = keccak256(v)
+//   for the byte pairs (h[0],h[1]), (h[2],h[3]), (h[4],h[5]):
+//       bit = ((pair[0] << 8) | pair[1]) & 2047       11 bits → 0..2047
+//       set bit `bit`, counting from the LAST byte of the 256-byte filter
+//
+// The log data is not included, so two logs with the same address and
+// topics set the same bits.
+// =============================================================================
+
+package rpc
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// bloomBytes is the size of a logs bloom.
+const bloomBytes = 256
+
+// BloomCheck is the result of recomputing the blooms of a block's receipts
+// and the block's logsBloom from the logs.
+type BloomCheck struct {
+	Receipts    int   // Receipts checked
+	BadReceipts []int // Indexes of receipts whose logsBloom does not match their logs
+	BlockOK     bool  // The header's logsBloom is the union of the receipts' logs
+	Err         error // Why the blooms could not be computed
+}
+
+// OK reports whether every receipt bloom and the block bloom matched.
+func (c BloomCheck) OK() bool {
+	return c.Err == nil && len(c.BadReceipts) == 0 && c.BlockOK
+}
+
+// Problem describes a failed check in one line, or returns "" when OK.
+func (c BloomCheck) Problem() string {
+	if c.Err != nil {
+		return "cannot verify logsBloom: " + c.Err.Error()
+	}
+	var parts []string
+	if len(c.BadReceipts) > 0 {
+		idx := make([]string, 0, len(c.BadReceipts))
+		for i, n := range c.BadReceipts {
+			if i == 5 {
+				idx = append(idx, fmt.Sprintf("and %d more", len(c.BadReceipts)-5))
+				break
+			}
+			idx = append(idx, strconv.Itoa(n))
+		}
+		parts = append(parts, fmt.Sprintf("%d receipts carry a bloom that does not match their logs (%s)",
+			len(c.BadReceipts), strings.Join(idx, ", ")))
+	}
+	if !c.BlockOK {
+		parts = append(parts, "header logsBloom is not the bloom of the receipts' logs")
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return "logsBloom: " + strings.Join(parts, "; ")
+}
+
+// VerifyLogsBloom recomputes each receipt's bloom from its logs, and the
+// block's logsBloom from all of them.
+func VerifyLogsBloom(block *Block, receipts []Receipt) BloomCheck {
+	check := BloomCheck{Receipts: len(receipts)}
+	claimed, err := decodeHexField("logsBloom", block.LogsBloom)
+	if err != nil {
+		check.Err = err
+		return check
+	}
+
+	union := make([]byte, bloomBytes)
+	for i := range receipts {
+		computed, err := LogsBloom(receipts[i].Logs)
+		if err != nil {
+			check.Err = fmt.Errorf("receipt %d: %w", i, err)
+			return check
+		}
+		for j := range union {
+			union[j] |= computed[j]
+		}
+		got, err := decodeHexField("logsBloom", receipts[i].LogsBloom)
+		if err != nil || !bytes.Equal(got, computed) {
+			check.BadReceipts = append(check.BadReceipts, i)
+		}
+	}
+	check.BlockOK = bytes.Equal(claimed, union)
+	return check
+}
+
+// LogsBloom returns the 256-byte bloom of logs.
+func LogsBloom(logs []Log) ([]byte, error) {
+	bloom := make([]byte, bloomBytes)
+	for _, l := range logs {
+		addr, err := decodeHexField("log address", l.Address)
+		if err != nil {
+			return nil, err
+		}
+		bloomAdd(bloom, addr)
+		for _, t := range l.Topics {
+			topic, err := decodeHexField("log topic", t)
+			if err != nil {
+				return nil, err
+			}
+			bloomAdd(bloom, topic)
+		}
+	}
+	return bloom, nil
+}
+
+// bloomAdd sets v's three bits in bloom.
+func bloomAdd(bloom, v []byte) {
+	h := Keccak256(v)
+	for i := 0; i < 6; i += 2 {
+		bit := (uint(h[i])<<8 | uint(h[i+1])) & 2047
+		bloom[bloomBytes-1-bit/8] |= 1 << (bit % 8)
+	}
+}
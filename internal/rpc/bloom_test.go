@@ -0,0 +1,74 @@
+package rpc
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestBloomAdd(t *testing.T) {
+	// keccak256("") = c5d2 4601 86f7 ...: bits 1490, 1537 and 1783, counted
+	// from the last byte.
+	bloom := make([]byte, bloomBytes)
+	bloomAdd(bloom, nil)
+	want := make([]byte, bloomBytes)
+	want[255-1490/8] = 1 << (1490 % 8)
+	want[255-1537/8] = 1 << (1537 % 8)
+	want[255-1783/8] = 1 << (1783 % 8)
+	if !bytes.Equal(bloom, want) {
+		t.Fatalf("bloom = %x", bloom)
+	}
+}
+
+// transferLog is an ERC-20 Transfer log with two indexed topics.
+func transferLog() Log {
+	return Log{
+		Address: "0x" + strings.Repeat("aa", 20),
+		Topics: []string{
+			"0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef",
+			"0x000000000000000000000000" + strings.Repeat("11", 20),
+		},
+		Data: "0x01",
+	}
+}
+
+func TestVerifyLogsBloom(t *testing.T) {
+	l := transferLog()
+	bloom, err := LogsBloom([]Log{l})
+	if err != nil {
+		t.Fatal(err)
+	}
+	bloomHex := "0x" + hex.EncodeToString(bloom)
+	receipts := []Receipt{
+		{LogsBloom: zeroBloom},
+		{LogsBloom: bloomHex, Logs: []Log{l}},
+	}
+	block := &Block{LogsBloom: bloomHex}
+	if c := VerifyLogsBloom(block, receipts); !c.OK() || c.Receipts != 2 || c.Problem() != "" {
+		t.Fatalf("matching blooms: %+v", c)
+	}
+
+	// A receipt bloom with its bits dropped, and a header bloom that no
+	// longer covers the logs.
+	receipts[1].LogsBloom = zeroBloom
+	block.LogsBloom = zeroBloom
+	c := VerifyLogsBloom(block, receipts)
+	if c.OK() || len(c.BadReceipts) != 1 || c.BadReceipts[0] != 1 || c.BlockOK {
+		t.Fatalf("zeroed blooms: %+v", c)
+	}
+	if p := c.Problem(); !strings.Contains(p, "1 receipts carry a bloom that does not match their logs (1)") ||
+		!strings.Contains(p, "header logsBloom is not the bloom") {
+		t.Fatalf("problem = %q", p)
+	}
+
+	// Log data does not affect the bloom.
+	l.Data = "0x02"
+	if other, _ := LogsBloom([]Log{l}); !bytes.Equal(other, bloom) {
+		t.Fatal("log data changed the bloom")
+	}
+
+	if c := VerifyLogsBloom(&Block{}, receipts); c.Err == nil || !strings.Contains(c.Problem(), "missing logsBloom") {
+		t.Fatalf("no header bloom: %q", c.Problem())
+	}
+}
@@ -0,0 +1,215 @@
+// =============================================================================
+// FILE: internal/rpc/body.go
+// ROLE: Body Verification — transactionsRoot and withdrawalsRoot From the Lists
+// =============================================================================
+//
+// SYSTEM CONTEXT
+// ==============
+// header.go proves a header belongs to its hash. The header in turn commits
+// to the block BODY through trie roots (trie.go):
+//
+//   transactionsRoot ══ trie of the consensus encoding of every transaction
+//   withdrawalsRoot  ══ trie of every withdrawal (Shanghai and later)
+//
+// Re-encoding the transaction objects a provider returned and rebuilding the
+// root catches a list that was truncated, reordered, padded or altered —
+// failures no amount of comparing hashes between providers would show when
+// the header itself is fine. `block --verify` runs these checks.
+//
+// CONSENSUS ENCODING OF A TRANSACTION
+// ===================================
+// What goes into the trie is the transaction exactly as signed and gossiped
+// (its keccak256 is the transaction hash):
+//
+//   legacy   rlp([nonce, gasPrice, gas, to, value, input, v, r, s])
+//   0x1      0x01 || rlp([chainId, nonce, gasPrice, gas, to, value, input,
+//                         accessList, yParity, r, s])               EIP-2930
+//   0x2      0x02 || rlp([chainId, nonce, maxPriorityFeePerGas, maxFeePerGas,
+//                         gas, to, value, input, accessList,
+//                         yParity, r, s])                           EIP-1559
+//   0x3      0x03 || rlp([... as 0x2 ..., accessList, maxFeePerBlobGas,
+//                         blobVersionedHashes, yParity, r, s])      EIP-4844
+//   0x4      0x04 || rlp([... as 0x2 ..., accessList, authorizationList,
+//                         yParity, r, s])                           EIP-7702
+//
+// `to` is the empty string for a contract creation, an access list entry is
+// [address, [storageKey, ...]], an authorization is [chainId, address, nonce,
+// yParity, r, s]. Typed transactions are stored in the trie as the raw
+// "type || payload" bytes, not wrapped in another RLP string.
+//
+// A withdrawal is rlp([index, validatorIndex, address, amount]), amount in
+// gwei.
+// =============================================================================
+
+package rpc
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Withdrawal is one validator withdrawal from the beacon chain (EIP-4895).
+type Withdrawal struct {
+	Index          string `json:"index"`
+	ValidatorIndex string `json:"validatorIndex"`
+	Address        string `json:"address"`
+	Amount         string `json:"amount"` // Gwei, not wei
+}
+
+// RootCheck is the result of rebuilding one of the header's trie roots from
+// the list it commits to.
+type RootCheck struct {
+	Field    string // Header field checked, e.g. "transactionsRoot"
+	Claimed  string // Root in the header
+	Computed string // Root rebuilt from the list; "" when Err is set
+	Count    int    // Items in the list
+	Err      error  // Why the list could not be encoded
+}
+
+// OK reports whether the rebuilt root equals the header's.
+func (r RootCheck) OK() bool {
+	return r.Err == nil && strings.EqualFold(r.Claimed, r.Computed)
+}
+
+// Problem describes a failed check in one line, or returns "" when OK.
+func (r RootCheck) Problem() string {
+	switch {
+	case r.Err != nil:
+		return fmt.Sprintf("cannot verify %s: %v", r.Field, r.Err)
+	case !r.OK():
+		return fmt.Sprintf("%s: %d items hash to %s, header has %s", r.Field, r.Count, r.Computed, r.Claimed)
+	}
+	return ""
+}
+
+// VerifyTransactionsRoot rebuilds transactionsRoot from b's transaction
+// objects. b must have been fetched hydrated (GetFullBlock).
+func VerifyTransactionsRoot(b *Block) RootCheck {
+	check := RootCheck{Field: "transactionsRoot", Claimed: b.TransactionsRoot, Count: len(b.Transactions)}
+	if b.FullTransactions == nil && len(b.Transactions) > 0 {
+		check.Err = fmt.Errorf("block was fetched without transaction objects")
+		return check
+	}
+	items := make([][]byte, len(b.FullTransactions))
+	for i := range b.FullTransactions {
+		enc, err := encodeTransaction(&b.FullTransactions[i])
+		if err != nil {
+			check.Err = fmt.Errorf("transaction %d: %w", i, err)
+			return check
+		}
+		items[i] = enc
+	}
+	check.Computed = OrderedTrieRoot(items)
+	return check
+}
+
+// VerifyWithdrawalsRoot rebuilds withdrawalsRoot from b's withdrawals. ok is
+// false for blocks before Shanghai, which have neither.
+func VerifyWithdrawalsRoot(b *Block) (check RootCheck, ok bool) {
+	if b.WithdrawalsRoot == "" {
+		return RootCheck{}, false
+	}
+	check = RootCheck{Field: "withdrawalsRoot", Claimed: b.WithdrawalsRoot, Count: len(b.Withdrawals)}
+	items := make([][]byte, len(b.Withdrawals))
+	for i, w := range b.Withdrawals {
+		d := fieldDecoder{}
+		items[i] = rlpList(
+			d.quantity("index", w.Index),
+			d.quantity("validatorIndex", w.ValidatorIndex),
+			d.fixed("address", w.Address, 20),
+			d.quantity("amount", w.Amount),
+		)
+		if d.err != nil {
+			check.Err = fmt.Errorf("withdrawal %d: %w", i, d.err)
+			return check, true
+		}
+	}
+	check.Computed = OrderedTrieRoot(items)
+	return check, true
+}
+
+// encodeTransaction returns tx's consensus encoding (see the file header).
+func encodeTransaction(tx *Transaction) ([]byte, error) {
+	d := fieldDecoder{}
+	typ := tx.TxType()
+
+	to := rlpBytes(nil) // Contract creation
+	if tx.To != "" {
+		to = d.fixed("to", tx.To, 20)
+	}
+
+	// Typed transactions carry the signature's y parity; most nodes also
+	// send it as v, some only as v.
+	yParity := tx.YParity
+	if yParity == "" {
+		yParity = tx.V
+	}
+	signature := [][]byte{d.quantity("yParity", yParity), d.quantity("r", tx.R), d.quantity("s", tx.S)}
+
+	var fields [][]byte
+	switch typ {
+	case TxLegacy:
+		fields = [][]byte{
+			d.quantity("nonce", tx.Nonce), d.quantity("gasPrice", tx.GasPrice), d.quantity("gas", tx.Gas),
+			to, d.quantity("value", tx.Value), d.bytes("input", tx.Input),
+			d.quantity("v", tx.V), d.quantity("r", tx.R), d.quantity("s", tx.S),
+		}
+	case TxAccessList:
+		fields = [][]byte{
+			d.quantity("chainId", tx.ChainID), d.quantity("nonce", tx.Nonce), d.quantity("gasPrice", tx.GasPrice),
+			d.quantity("gas", tx.Gas), to, d.quantity("value", tx.Value), d.bytes("input", tx.Input),
+			d.accessList(tx.AccessList),
+		}
+		fields = append(fields, signature...)
+	case TxDynamicFee, TxBlob, TxSetCode:
+		fields = [][]byte{
+			d.quantity("chainId", tx.ChainID), d.quantity("nonce", tx.Nonce),
+			d.quantity("maxPriorityFeePerGas", tx.MaxPriorityFeePerGas), d.quantity("maxFeePerGas", tx.MaxFeePerGas),
+			d.quantity("gas", tx.Gas), to, d.quantity("value", tx.Value), d.bytes("input", tx.Input),
+			d.accessList(tx.AccessList),
+		}
+		switch typ {
+		case TxBlob:
+			hashes := make([][]byte, len(tx.BlobVersionedHashes))
+			for i, h := range tx.BlobVersionedHashes {
+				hashes[i] = d.fixed("blobVersionedHash", h, 32)
+			}
+			fields = append(fields, d.quantity("maxFeePerBlobGas", tx.MaxFeePerBlobGas), rlpList(hashes...))
+		case TxSetCode:
+			auths := make([][]byte, len(tx.AuthorizationList))
+			for i, a := range tx.AuthorizationList {
+				auths[i] = rlpList(
+					d.quantity("authorization chainId", a.ChainID), d.fixed("authorization address", a.Address, 20),
+					d.quantity("authorization nonce", a.Nonce), d.quantity("authorization yParity", a.YParity),
+					d.quantity("authorization r", a.R), d.quantity("authorization s", a.S),
+				)
+			}
+			fields = append(fields, rlpList(auths...))
+		}
+		fields = append(fields, signature...)
+	default:
+		return nil, fmt.Errorf("unsupported transaction type %s", typ)
+	}
+
+	if d.err != nil {
+		return nil, d.err
+	}
+	payload := rlpList(fields...)
+	if typ == TxLegacy {
+		return payload, nil
+	}
+	return append([]byte{byte(typ)}, payload...), nil
+}
+
+// accessList encodes an EIP-2930 access list.
+func (d *fieldDecoder) accessList(list []AccessTuple) []byte {
+	tuples := make([][]byte, len(list))
+	for i, t := range list {
+		keys := make([][]byte, len(t.StorageKeys))
+		for j, k := range t.StorageKeys {
+			keys[j] = d.fixed("storageKey", k, 32)
+		}
+		tuples[i] = rlpList(d.fixed("accessList address", t.Address, 20), rlpList(keys...))
+	}
+	return rlpList(tuples...)
+}
@@ -0,0 +1,166 @@
+package rpc
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+// eip155Example is the signed example transaction from EIP-155.
+func eip155Example() Transaction {
+	return Transaction{
+		Hash:  "0x33469b22e9f636356c4160a87eb19df52b7412e8eac32a4a55ffe88ea8350788",
+		Nonce: "0x9", GasPrice: "0x4a817c800", Gas: "0x5208",
+		To: "0x3535353535353535353535353535353535353535", Value: "0xde0b6b3a7640000", Input: "0x",
+		V: "0x25",
+		R: "0x28ef61340bd939bc2195fe537567866003e1a15d3c71ff63e1590620aa636276",
+		S: "0x67cbe9d8997f761aecb703304b3800ccf555c9f3dc64214b297fb1966a3b6d83",
+	}
+}
+
+func TestEncodeTransaction_legacy(t *testing.T) {
+	tx := eip155Example()
+	enc, err := encodeTransaction(&tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "f86c098504a817c800825208943535353535353535353535353535353535353535880de0b6b3a76400008025a028ef61340bd939bc2195fe537567866003e1a15d3c71ff63e1590620aa636276a067cbe9d8997f761aecb703304b3800ccf555c9f3dc64214b297fb1966a3b6d83"
+	if got := hex.EncodeToString(enc); got != want {
+		t.Fatalf("encoding:\n got %s\nwant %s", got, want)
+	}
+	if got := "0x" + hex.EncodeToString(Keccak256(enc)); got != tx.Hash {
+		t.Fatalf("hash = %s, want %s", got, tx.Hash)
+	}
+}
+
+func TestEncodeTransaction_typed(t *testing.T) {
+	base := Transaction{
+		ChainID: "0x1", Nonce: "0x0", Gas: "0x5208", Value: "0x0", Input: "0x",
+		GasPrice: "0x1", MaxFeePerGas: "0x2", MaxPriorityFeePerGas: "0x1",
+		AccessList: []AccessTuple{{Address: "0x" + strings.Repeat("11", 20), StorageKeys: []string{zeroHash}}},
+		V:          "0x1", R: "0x1", S: "0x1",
+	}
+	tests := []struct {
+		typ    string
+		mutate func(*Transaction)
+		fields int
+	}{
+		{"0x1", func(*Transaction) {}, 11},
+		{"0x2", func(*Transaction) {}, 12},
+		{"0x3", func(tx *Transaction) {
+			tx.To = "0x" + strings.Repeat("22", 20)
+			tx.MaxFeePerBlobGas = "0x1"
+			tx.BlobVersionedHashes = []string{"0x01" + strings.Repeat("00", 31)}
+		}, 14},
+		{"0x4", func(tx *Transaction) {
+			tx.To = "0x" + strings.Repeat("22", 20)
+			tx.AuthorizationList = []Authorization{{ChainID: "0x1", Address: "0x" + strings.Repeat("33", 20), Nonce: "0x0", YParity: "0x0", R: "0x1", S: "0x1"}}
+		}, 13},
+	}
+	for _, tt := range tests {
+		tx := base
+		tx.Type = tt.typ
+		tt.mutate(&tx)
+		enc, err := encodeTransaction(&tx)
+		if err != nil {
+			t.Fatalf("type %s: %v", tt.typ, err)
+		}
+		if enc[0] != byte(tx.TxType()) {
+			t.Fatalf("type %s: prefix byte %#x", tt.typ, enc[0])
+		}
+		if n := rlpListLen(t, enc[1:]); n != tt.fields {
+			t.Fatalf("type %s: %d fields, want %d", tt.typ, n, tt.fields)
+		}
+	}
+
+	// yParity falls back to v, and is used over it when present.
+	tx := base
+	tx.Type = "0x2"
+	withV, _ := encodeTransaction(&tx)
+	tx.YParity, tx.V = "0x1", ""
+	withParity, _ := encodeTransaction(&tx)
+	if string(withV) != string(withParity) {
+		t.Fatal("yParity and v encoded differently")
+	}
+}
+
+func TestEncodeTransaction_errors(t *testing.T) {
+	tx := eip155Example()
+	tx.Type = "0x7e" // OP-stack deposit transaction
+	if _, err := encodeTransaction(&tx); err == nil || !strings.Contains(err.Error(), "unsupported transaction type") {
+		t.Fatalf("deposit tx: %v", err)
+	}
+
+	tx = eip155Example()
+	tx.To = "0x1234"
+	if _, err := encodeTransaction(&tx); err == nil || !strings.Contains(err.Error(), "to is 2 bytes") {
+		t.Fatalf("short to: %v", err)
+	}
+
+	tx = eip155Example()
+	tx.To = "" // Contract creation encodes to as the empty string
+	if _, err := encodeTransaction(&tx); err != nil {
+		t.Fatalf("creation: %v", err)
+	}
+}
+
+func TestVerifyTransactionsRoot(t *testing.T) {
+	tx := eip155Example()
+	enc, _ := encodeTransaction(&tx)
+	b := &Block{
+		TransactionsRoot: OrderedTrieRoot([][]byte{enc}),
+		Transactions:     []string{tx.Hash},
+		FullTransactions: []Transaction{tx},
+	}
+	if c := VerifyTransactionsRoot(b); !c.OK() || c.Count != 1 || c.Problem() != "" {
+		t.Fatalf("matching list: %+v", c)
+	}
+
+	b.FullTransactions[0].Value = "0x1"
+	c := VerifyTransactionsRoot(b)
+	if c.OK() || !strings.Contains(c.Problem(), "transactionsRoot: 1 items hash to") {
+		t.Fatalf("altered tx: %+v %q", c, c.Problem())
+	}
+
+	// Hash-only blocks cannot be verified.
+	b.FullTransactions = nil
+	if c := VerifyTransactionsRoot(b); c.OK() || !strings.Contains(c.Problem(), "without transaction objects") {
+		t.Fatalf("hash-only block: %q", c.Problem())
+	}
+
+	// An empty block verifies against the empty trie.
+	if c := VerifyTransactionsRoot(mainnetBlock1()); !c.OK() || c.Count != 0 {
+		t.Fatalf("empty block: %+v", c)
+	}
+}
+
+func TestVerifyWithdrawalsRoot(t *testing.T) {
+	if _, ok := VerifyWithdrawalsRoot(mainnetBlock1()); ok {
+		t.Fatal("pre-Shanghai block reported a withdrawals check")
+	}
+
+	w := Withdrawal{Index: "0x0", ValidatorIndex: "0x1", Address: "0x" + strings.Repeat("ab", 20), Amount: "0x3b9aca00"}
+	enc := rlpList(rlpBytes(nil), rlpUint(1), rlpBytes(mustHex(strings.Repeat("ab", 20))), rlpUint(1_000_000_000))
+	b := &Block{WithdrawalsRoot: OrderedTrieRoot([][]byte{enc}), Withdrawals: []Withdrawal{w}}
+	if c, ok := VerifyWithdrawalsRoot(b); !ok || !c.OK() || c.Count != 1 {
+		t.Fatalf("matching list: %+v", c)
+	}
+
+	b.Withdrawals = nil
+	if c, _ := VerifyWithdrawalsRoot(b); c.OK() || !strings.Contains(c.Problem(), "withdrawalsRoot: 0 items hash to "+emptyTrie) {
+		t.Fatalf("dropped withdrawals: %q", c.Problem())
+	}
+
+	b.Withdrawals = []Withdrawal{{Index: "0x0", ValidatorIndex: "0x1", Address: "0x12", Amount: "0x1"}}
+	if c, _ := VerifyWithdrawalsRoot(b); !strings.Contains(c.Problem(), "cannot verify withdrawalsRoot: withdrawal 0: address is 1 bytes") {
+		t.Fatalf("bad address: %q", c.Problem())
+	}
+}
+
+func mustHex(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
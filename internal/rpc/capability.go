@@ -0,0 +1,268 @@
+// =============================================================================
+// FILE: internal/rpc/capability.go
+// ROLE: Method Capabilities — Which RPC Methods Does This Provider Really Serve?
+// =============================================================================
+//
+// SYSTEM CONTEXT
+// ==============
+// Every provider answers eth_blockNumber. Beyond that they differ, usually
+// without saying so: debug_* and trace_* are off on most public endpoints,
+// eth_getBlockReceipts is missing on older nodes, txpool_* is disabled
+// behind load balancers, and eth_getLogs is capped at whatever range the
+// plan allows. ProbeMethods calls each method of a catalog once, with real
+// arguments, and sorts the outcome into one of four answers:
+//
+//   supported    the call returned a result
+//   unsupported  "method not found" (-32601) or the like: not on this node
+//   restricted   an auth, plan or range error: available, but not to us
+//   broken       anything else — a timeout, a 5xx, a null result, an
+//                error the node should not give for a valid call
+//
+// ARGUMENTS
+// =========
+// A catalog entry's params are sent as written, except for these strings,
+// filled in per provider from the block the probe runs against:
+//
+//   "{head}"       that block's number (hex)
+//   "{head-N}"     N blocks before it (hex), e.g. "{head-9999}" for a
+//                  10,000-block eth_getLogs range
+//   "{head_hash}"  its hash
+//   "{tx_hash}"    a transaction in it (or in a recent block, if it is empty)
+//
+// The block is a few blocks behind the provider's head (probeMargin), so an
+// endpoint that load-balances across nodes a block or two apart still has
+// it on every node.
+// =============================================================================
+
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// probeMargin is how far behind its head each provider is probed.
+const probeMargin = 3
+
+// txSearchDepth bounds how many blocks back ProbeTarget looks for a
+// transaction when the probed block is empty.
+const txSearchDepth = 16
+
+// MethodProbe is one catalog entry: a method and the arguments to call it
+// with. It is also the YAML shape of the config's `methods` section.
+type MethodProbe struct {
+	Name   string        `yaml:"name" json:"name"`     // Label in reports; defaults to Method
+	Method string        `yaml:"method" json:"method"` // JSON-RPC method, e.g. "trace_block"
+	Params []interface{} `yaml:"params" json:"params"` // Arguments; may use the placeholders above
+}
+
+// DefaultMethodCatalog is probed when the config has no `methods` section:
+// the methods providers most often disable or restrict.
+func DefaultMethodCatalog() []MethodProbe {
+	callTracer := map[string]interface{}{"tracer": "callTracer"}
+	catalog := []MethodProbe{
+		{Method: "eth_getBlockReceipts", Params: []interface{}{"{head}"}},
+		{Name: "eth_getLogs (1k blocks)", Method: "eth_getLogs", Params: []interface{}{
+			map[string]interface{}{"fromBlock": "{head-999}", "toBlock": "{head}"}}},
+		{Name: "eth_getLogs (10k blocks)", Method: "eth_getLogs", Params: []interface{}{
+			map[string]interface{}{"fromBlock": "{head-9999}", "toBlock": "{head}"}}},
+		{Method: "eth_getProof", Params: []interface{}{"0x0000000000000000000000000000000000000000", []interface{}{}, "{head}"}},
+		{Method: "eth_feeHistory", Params: []interface{}{"0x4", "{head}", []interface{}{25, 75}}},
+		{Method: "eth_maxPriorityFeePerGas"},
+		{Method: "eth_blobBaseFee"},
+		{Method: "debug_traceBlockByNumber", Params: []interface{}{"{head}", callTracer}},
+		{Method: "debug_traceTransaction", Params: []interface{}{"{tx_hash}", callTracer}},
+		{Method: "trace_block", Params: []interface{}{"{head}"}},
+		{Method: "trace_transaction", Params: []interface{}{"{tx_hash}"}},
+		{Method: "txpool_status"},
+	}
+	for i := range catalog {
+		catalog[i].Name = catalog[i].Label()
+	}
+	return catalog
+}
+
+// Label is the name shown for p: Name, or the method when Name is empty.
+func (p MethodProbe) Label() string {
+	if p.Name != "" {
+		return p.Name
+	}
+	return p.Method
+}
+
+// =============================================================================
+// SECTION 1: Classification
+// =============================================================================
+
+// Capability is the outcome of probing one method on one provider.
+type Capability string
+
+const (
+	CapSupported   Capability = "supported"
+	CapUnsupported Capability = "unsupported"
+	CapRestricted  Capability = "restricted"
+	CapBroken      Capability = "broken"
+	CapSkipped     Capability = "skipped" // A placeholder could not be filled
+)
+
+// ClassifyCapability sorts a probe's error into a Capability. The rules,
+// in order:
+//
+//	nil                                              → supported
+//	message mentions upgrade/tier/plan/premium/paid  → restricted  (before the next
+//	                                                   rule: "not available on the
+//	                                                   Free tier" is a plan limit)
+//	-32601, or "not found"/"not supported"/...       → unsupported
+//	auth category, or a range/result-size limit      → restricted
+//	everything else                                  → broken
+func ClassifyCapability(err error) Capability {
+	if err == nil {
+		return CapSupported
+	}
+	var rpcErr *RPCError
+	msg := ""
+	if errors.As(err, &rpcErr) {
+		msg = strings.ToLower(rpcErr.Message)
+	}
+	switch {
+	case containsAny(msg, "upgrade", " tier", "your plan", "paid plan", "premium", "subscription"):
+		return CapRestricted
+	case rpcErr != nil && (rpcErr.Code == -32601 || containsAny(msg,
+		"method not found", "does not exist", "not supported", "unsupported method",
+		"not available", "is not enabled", "method disabled")):
+		return CapUnsupported
+	case Classify(err) == CategoryAuth || IsRangeLimitError(err):
+		return CapRestricted
+	default:
+		return CapBroken
+	}
+}
+
+// =============================================================================
+// SECTION 2: Probing
+// =============================================================================
+
+// ProbeTarget is the block a provider's probes run against; it fills the
+// placeholders in catalog params.
+type ProbeTarget struct {
+	Head     uint64 // Number of the probed block (head minus probeMargin)
+	HeadHash string
+	TxHash   string // "" when no recent block had a transaction
+}
+
+// MethodResult is the outcome of one catalog entry on one provider.
+type MethodResult struct {
+	Probe   MethodProbe
+	Status  Capability
+	Latency time.Duration
+	Error   error // nil when supported
+}
+
+// ProbeTarget picks the block to probe: a few blocks behind the head, and
+// the first transaction found at or below it.
+func (c *Client) ProbeTarget(ctx context.Context) (ProbeTarget, error) {
+	head, _, err := c.BlockNumber(ctx)
+	if err != nil {
+		return ProbeTarget{}, fmt.Errorf("head: %w", err)
+	}
+	if head > probeMargin {
+		head -= probeMargin
+	}
+	var t ProbeTarget
+	for n := head; n+txSearchDepth > head && n <= head; n-- {
+		block, _, err := c.GetBlock(ctx, fmt.Sprintf("0x%x", n))
+		if err != nil {
+			return ProbeTarget{}, fmt.Errorf("block %d: %w", n, err)
+		}
+		if n == head {
+			t = ProbeTarget{Head: head, HeadHash: block.Hash}
+		}
+		if len(block.Transactions) > 0 {
+			t.TxHash = block.Transactions[0]
+			break
+		}
+	}
+	return t, nil
+}
+
+// ProbeMethods calls every catalog entry once, in order, and classifies
+// each outcome. Entries whose placeholders cannot be filled are skipped.
+func (c *Client) ProbeMethods(ctx context.Context, catalog []MethodProbe, t ProbeTarget) []MethodResult {
+	results := make([]MethodResult, len(catalog))
+	for i, p := range catalog {
+		results[i].Probe = p
+		params, err := t.fill(p.Params)
+		if err != nil {
+			results[i].Status, results[i].Error = CapSkipped, err
+			continue
+		}
+		resp, latency, err := c.Call(ctx, p.Method, params...)
+		if err == nil && isNullResult(resp.Result) {
+			err = &NotFoundError{Method: p.Method, Arg: "null result"}
+		}
+		results[i].Status, results[i].Latency, results[i].Error = ClassifyCapability(err), latency, err
+	}
+	return results
+}
+
+// fill returns params with every placeholder replaced, recursing into
+// arrays and objects.
+func (t ProbeTarget) fill(params []interface{}) ([]interface{}, error) {
+	out := make([]interface{}, len(params))
+	for i, v := range params {
+		filled, err := t.fillValue(v)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = filled
+	}
+	return out, nil
+}
+
+func (t ProbeTarget) fillValue(v interface{}) (interface{}, error) {
+	switch v := v.(type) {
+	case string:
+		return t.fillString(v)
+	case []interface{}:
+		return t.fill(v)
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			filled, err := t.fillValue(e)
+			if err != nil {
+				return nil, err
+			}
+			out[k] = filled
+		}
+		return out, nil
+	}
+	return v, nil
+}
+
+func (t ProbeTarget) fillString(s string) (interface{}, error) {
+	switch {
+	case s == "{head}":
+		return fmt.Sprintf("0x%x", t.Head), nil
+	case s == "{head_hash}":
+		return t.HeadHash, nil
+	case s == "{tx_hash}":
+		if t.TxHash == "" {
+			return nil, fmt.Errorf("no transaction in the %d blocks up to %d", txSearchDepth, t.Head)
+		}
+		return t.TxHash, nil
+	case strings.HasPrefix(s, "{head-") && strings.HasSuffix(s, "}"):
+		n, err := strconv.ParseUint(s[len("{head-"):len(s)-1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("bad placeholder %q", s)
+		}
+		if n > t.Head {
+			n = t.Head
+		}
+		return fmt.Sprintf("0x%x", t.Head-n), nil
+	}
+	return s, nil
+}
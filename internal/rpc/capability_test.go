@@ -0,0 +1,134 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClassifyCapability(t *testing.T) {
+	tests := []struct {
+		err  error
+		want Capability
+	}{
+		{nil, CapSupported},
+		{&RPCError{Code: -32601, Message: "the method trace_block does not exist/is not available"}, CapUnsupported},
+		{&RPCError{Code: -32000, Message: "method not found"}, CapUnsupported},
+		{&RPCError{Code: -32600, Message: "trace_block is not available on the Free tier - upgrade to Growth"}, CapRestricted},
+		{&RPCError{Code: -32000, Message: "unauthorized"}, CapRestricted},
+		{&RPCError{Code: -32005, Message: "query returned more than 10000 results"}, CapRestricted},
+		{&RPCError{Code: -32000, Message: "execution timeout"}, CapBroken},
+		{&HTTPError{StatusCode: 502}, CapBroken},
+		{&NotFoundError{Method: "debug_traceTransaction", Arg: "null result"}, CapBroken},
+		{errors.New("dial tcp: connection refused"), CapBroken},
+	}
+	for _, tt := range tests {
+		if got := ClassifyCapability(tt.err); got != tt.want {
+			t.Errorf("ClassifyCapability(%v) = %s, want %s", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestProbeTarget_fill(t *testing.T) {
+	tgt := ProbeTarget{Head: 1000, HeadHash: "0xabc", TxHash: "0xdef"}
+	got, err := tgt.fill([]interface{}{
+		"{head}", "{head_hash}", "{tx_hash}", "latest", true,
+		map[string]interface{}{"fromBlock": "{head-999}", "toBlock": "{head}", "topics": []interface{}{"{head-5000}"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []interface{}{
+		"0x3e8", "0xabc", "0xdef", "latest", true,
+		map[string]interface{}{"fromBlock": "0x1", "toBlock": "0x3e8", "topics": []interface{}{"0x0"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("fill = %#v", got)
+	}
+
+	if _, err := (ProbeTarget{Head: 1000}).fill([]interface{}{"{tx_hash}"}); err == nil {
+		t.Fatal("missing tx hash should fail")
+	}
+	if _, err := tgt.fill([]interface{}{"{head-x}"}); err == nil {
+		t.Fatal("malformed placeholder should fail")
+	}
+}
+
+// capabilityServer serves a node at head 0x64 whose blocks below 0x60 hold
+// a transaction, and answers methods from replies (a JSON body fragment,
+// "result":… or "error":…); other methods get -32601.
+func capabilityServer(replies map[string]string, calls *[]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     uint64        `json:"id"`
+			Method string        `json:"method"`
+			Params []interface{} `json:"params"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		var body string
+		switch req.Method {
+		case "eth_blockNumber":
+			body = `"result":"0x64"`
+		case "eth_getBlockByNumber":
+			n, _ := ParseHexUint64(req.Params[0].(string))
+			txs := `[]`
+			if n < 0x60 {
+				txs = `["0xt` + fmt.Sprint(n) + `"]`
+			}
+			body = fmt.Sprintf(`"result":{"number":"0x%x","hash":"0xh%d","timestamp":"0x1","transactions":%s}`, n, n, txs)
+		default:
+			*calls = append(*calls, fmt.Sprint(req.Method, req.Params))
+			body = replies[req.Method]
+			if body == "" {
+				body = `"error":{"code":-32601,"message":"the method ` + req.Method + ` does not exist/is not available"}`
+			}
+		}
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%d,%s}`, req.ID, body)
+	}))
+}
+
+func TestClient_ProbeMethods(t *testing.T) {
+	var calls []string
+	srv := capabilityServer(map[string]string{
+		"eth_getBlockReceipts": `"result":[]`,
+		"trace_transaction":    `"result":null`,
+		"eth_getLogs":          `"error":{"code":-32005,"message":"query exceeds max block range 1000"}`,
+	}, &calls)
+	defer srv.Close()
+	c := NewClient("t", srv.URL, 2*time.Second)
+	ctx := context.Background()
+
+	tgt, err := c.ProbeTarget(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Head 100 minus the margin is 97; 97 down to 96 are empty, 95 has a tx.
+	if tgt.Head != 97 || tgt.HeadHash != "0xh97" || tgt.TxHash != "0xt95" {
+		t.Fatalf("target = %+v", tgt)
+	}
+
+	catalog := []MethodProbe{
+		{Method: "eth_getBlockReceipts", Params: []interface{}{"{head}"}},
+		{Name: "logs", Method: "eth_getLogs", Params: []interface{}{map[string]interface{}{"fromBlock": "{head-9}", "toBlock": "{head}"}}},
+		{Method: "trace_transaction", Params: []interface{}{"{tx_hash}"}},
+		{Method: "txpool_status"},
+		{Method: "debug_traceTransaction", Params: []interface{}{"{head-x}"}},
+	}
+	results := c.ProbeMethods(ctx, catalog, tgt)
+	want := []Capability{CapSupported, CapRestricted, CapBroken, CapUnsupported, CapSkipped}
+	for i, r := range results {
+		if r.Status != want[i] {
+			t.Errorf("%s: %s (%v), want %s", r.Probe.Label(), r.Status, r.Error, want[i])
+		}
+	}
+	if got := strings.Join(calls[:3], " "); got != "eth_getBlockReceipts[0x61] eth_getLogs[map[fromBlock:0x58 toBlock:0x61]] trace_transaction[0xt95]" {
+		t.Fatalf("calls = %s", got)
+	}
+}
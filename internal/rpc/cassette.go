@@ -0,0 +1,345 @@
+// =============================================================================
+// FILE: internal/rpc/cassette.go
+// ROLE: Record and Replay — Every Exchange on Tape, Playable Offline
+// =============================================================================
+//
+// SYSTEM CONTEXT
+// ==============
+// A snapshot that shows two providers disagreeing on a hash is evidence
+// that is gone a block later. A cassette keeps it: in RECORD mode every
+// exchange a Client makes — request, response body or error, latency — is
+// appended to a file as one JSON line, as it happens. In REPLAY mode the
+// Client never touches the network; each request is answered from the
+// cassette, so the same command reproduces the same output offline, and a
+// bug report can carry the cassette instead of a description.
+//
+//   record:  Client ── post ──▶ provider         ──▶ response
+//                         └──▶ cassette.jsonl  (one line per exchange)
+//
+//   replay:  Client ── post ──▶ cassette.jsonl   ──▶ recorded response
+//                               (matched by provider + request)
+//
+// Recording hooks into post, below Call and CallBatch, so single calls,
+// batches, HTTP and IPC are all captured, and a recorded failure replays
+// as the same error type: an *HTTPError keeps its status, body and
+// Retry-After, a transport failure keeps its message and whether it was a
+// timeout — Classify gives the same category on replay.
+//
+// MATCHING
+// ========
+// Request IDs are random per client (ids.go), so a replayed run never sends
+// the IDs that were recorded. Requests are matched on provider name plus
+// the request with every "id" removed; identical requests (the 30
+// eth_blockNumber samples of `test`) are answered in recorded order. The
+// recorded response's IDs are rewritten to the new request's, so ID
+// checking still passes. A request the cassette has no (more) answers for
+// fails with a transport error saying so.
+//
+// LATENCY
+// =======
+// Replay answers immediately by default, and every latency comes out near
+// zero. Paced replay waits each exchange's recorded latency first, so
+// latency tables, percentiles and timeouts come out as they were recorded.
+// =============================================================================
+
+package rpc
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Exchange is one recorded request and its outcome: one line of a cassette.
+type Exchange struct {
+	Provider     string          `json:"provider"`
+	Method       string          `json:"method"`    // For reading; a batch lists its methods, comma-separated
+	OffsetUS     int64           `json:"offset_us"` // Start time, relative to the first recorded exchange
+	LatencyUS    int64           `json:"latency_us"`
+	Request      json.RawMessage `json:"request"`
+	Response     json.RawMessage `json:"response,omitempty"`      // Body, when it was JSON
+	ResponseText string          `json:"response_text,omitempty"` // Body, when it was not
+	Error        *ExchangeError  `json:"error,omitempty"`
+}
+
+// ExchangeError is a recorded failure of the exchange itself. JSON-RPC
+// errors are not failures at this level: they are in Response.
+type ExchangeError struct {
+	Status       int    `json:"status,omitempty"` // Set for an *HTTPError
+	Body         string `json:"body,omitempty"`
+	RetryAfterMS int64  `json:"retry_after_ms,omitempty"`
+	Message      string `json:"message,omitempty"` // Any other failure
+	Timeout      bool   `json:"timeout,omitempty"`
+}
+
+// Cassette records a Client's exchanges or replays them. Create one with
+// RecordCassette/NewRecorder or ReplayCassette/NewReplayer and hand it to
+// clients with WithCassette; one cassette may serve many clients at once.
+type Cassette struct {
+	mu     sync.Mutex
+	replay bool
+	paced  bool
+
+	// Record mode.
+	w      io.Writer
+	closer io.Closer
+	start  time.Time
+
+	// Replay mode: unanswered exchanges per request key, in recorded order.
+	queues map[string][]*Exchange
+}
+
+// WithCassette records every exchange to c, or answers every call from it,
+// depending on how c was created.
+func WithCassette(c *Cassette) Option { return func(o *options) { o.cassette = c } }
+
+// NewRecorder returns a recording cassette that writes to w.
+func NewRecorder(w io.Writer) *Cassette {
+	return &Cassette{w: w}
+}
+
+// RecordCassette creates (or truncates) the file at path and records to it.
+func RecordCassette(path string) (*Cassette, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("cassette: %w", err)
+	}
+	c := NewRecorder(f)
+	c.closer = f
+	return c, nil
+}
+
+// NewReplayer reads a cassette from r for replay. With paced set, every
+// answer waits its recorded latency.
+func NewReplayer(r io.Reader, paced bool) (*Cassette, error) {
+	c := &Cassette{replay: true, paced: paced, queues: map[string][]*Exchange{}}
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 64*1024*1024) // Full blocks and receipts make long lines
+	for line := 1; sc.Scan(); line++ {
+		if len(bytes.TrimSpace(sc.Bytes())) == 0 {
+			continue
+		}
+		var e Exchange
+		if err := json.Unmarshal(sc.Bytes(), &e); err != nil {
+			return nil, fmt.Errorf("cassette line %d: %w", line, err)
+		}
+		key, _, _ := requestKey(e.Provider, e.Request)
+		c.queues[key] = append(c.queues[key], &e)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("cassette: %w", err)
+	}
+	return c, nil
+}
+
+// ReplayCassette loads the cassette file at path for replay.
+func ReplayCassette(path string, paced bool) (*Cassette, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("cassette: %w", err)
+	}
+	defer f.Close()
+	return NewReplayer(f, paced)
+}
+
+// Close closes the file a recording cassette writes to. Every exchange is
+// written when it completes, so a cassette that is never closed (a command
+// that exits with os.Exit) is still complete. Close on a nil cassette (a
+// live run) does nothing.
+func (c *Cassette) Close() error {
+	if c == nil || c.closer == nil {
+		return nil
+	}
+	return c.closer.Close()
+}
+
+// =============================================================================
+// SECTION 1: The Hook in post
+// =============================================================================
+
+// exchange is post with a cassette attached: send and record, or replay.
+// A replayed exchange never reaches the network, so a traced replay reports
+// no phases.
+func (c *Cassette) exchange(ctx context.Context, client *Client, body []byte, tracer *phaseTracer) ([]byte, error) {
+	if c.replay {
+		return c.answer(ctx, client.name, body)
+	}
+	start := time.Now()
+	raw, err := client.send(ctx, body, tracer)
+	c.record(client.name, body, raw, err, start, time.Since(start))
+	return raw, err
+}
+
+// record appends one exchange. A failed write is dropped: the call itself
+// succeeded or failed on its own, and recording must not change that.
+func (c *Cassette) record(provider string, body, raw []byte, err error, start time.Time, latency time.Duration) {
+	_, _, method := requestKey(provider, body)
+	e := Exchange{Provider: provider, Method: method, LatencyUS: latency.Microseconds(), Request: body}
+	switch {
+	case err != nil:
+		e.Error = recordError(err)
+	case json.Valid(raw):
+		e.Response = raw
+	default:
+		e.ResponseText = string(raw)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.start.IsZero() {
+		c.start = start
+	}
+	e.OffsetUS = start.Sub(c.start).Microseconds()
+	line, _ := json.Marshal(e)
+	c.w.Write(append(line, '\n'))
+}
+
+// answer replays the next recorded exchange for body.
+func (c *Cassette) answer(ctx context.Context, provider string, body []byte) ([]byte, error) {
+	key, ids, method := requestKey(provider, body)
+	c.mu.Lock()
+	var e *Exchange
+	if q := c.queues[key]; len(q) > 0 {
+		e, c.queues[key] = q[0], q[1:]
+	}
+	c.mu.Unlock()
+	if e == nil {
+		return nil, &TransportError{Err: fmt.Errorf("cassette has no recorded answer for %s %s", provider, method)}
+	}
+
+	if c.paced && e.LatencyUS > 0 {
+		select {
+		case <-time.After(time.Duration(e.LatencyUS) * time.Microsecond):
+		case <-ctx.Done():
+			return nil, &TransportError{Err: ctx.Err()}
+		}
+	}
+	if e.Error != nil {
+		return nil, e.Error.replay()
+	}
+	if e.Response == nil {
+		return []byte(e.ResponseText), nil
+	}
+	_, recorded, _ := requestKey(provider, e.Request)
+	return rewriteIDs(e.Response, recorded, ids), nil
+}
+
+// =============================================================================
+// SECTION 2: Errors on Tape
+// =============================================================================
+
+// recordError keeps what Classify needs from a failed exchange.
+func recordError(err error) *ExchangeError {
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		return &ExchangeError{Status: httpErr.StatusCode, Body: httpErr.Body, RetryAfterMS: httpErr.RetryAfter.Milliseconds()}
+	}
+	e := &ExchangeError{Message: err.Error()}
+	var te *TransportError
+	if errors.As(err, &te) {
+		e.Timeout = te.Timeout()
+	}
+	return e
+}
+
+// replay rebuilds the recorded failure with the type it had.
+func (e *ExchangeError) replay() error {
+	if e.Status != 0 {
+		return &HTTPError{StatusCode: e.Status, Body: e.Body, RetryAfter: time.Duration(e.RetryAfterMS) * time.Millisecond}
+	}
+	return &TransportError{Err: replayedError{msg: e.Message, timeout: e.Timeout}}
+}
+
+// replayedError is a recorded transport failure. It is a net.Error so
+// TransportError.Timeout sees a recorded timeout as one.
+type replayedError struct {
+	msg     string
+	timeout bool
+}
+
+var _ net.Error = replayedError{}
+
+func (e replayedError) Error() string   { return e.msg }
+func (e replayedError) Timeout() bool   { return e.timeout }
+func (e replayedError) Temporary() bool { return false }
+
+// =============================================================================
+// SECTION 3: Matching
+// =============================================================================
+
+// requestKey returns the replay key of a request body (provider plus the
+// body with every id removed, re-encoded with sorted keys), the ids in
+// request order, and its method names.
+func requestKey(provider string, body []byte) (key string, ids []json.RawMessage, method string) {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return provider + "\n" + string(body), nil, "?"
+	}
+	elems, batch := v.([]interface{})
+	if !batch {
+		elems = []interface{}{v}
+	}
+	var methods []string
+	for _, elem := range elems {
+		obj, ok := elem.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		id, _ := json.Marshal(obj["id"])
+		ids = append(ids, id)
+		delete(obj, "id")
+		if m, ok := obj["method"].(string); ok {
+			methods = append(methods, m)
+		}
+	}
+	canonical, _ := json.Marshal(v)
+	return provider + "\n" + string(canonical), ids, strings.Join(methods, ",")
+}
+
+// rewriteIDs replaces, in a response body, each id from[i] with to[i].
+// A body that is not JSON is returned unchanged.
+func rewriteIDs(resp []byte, from, to []json.RawMessage) []byte {
+	mapping := map[string]json.RawMessage{}
+	for i := range from {
+		if i < len(to) {
+			mapping[string(from[i])] = to[i]
+		}
+	}
+	dec := json.NewDecoder(bytes.NewReader(resp))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return resp
+	}
+	fix := func(elem interface{}) {
+		if obj, ok := elem.(map[string]interface{}); ok {
+			id, _ := json.Marshal(obj["id"])
+			if newID, ok := mapping[string(id)]; ok {
+				obj["id"] = newID
+			}
+		}
+	}
+	if elems, ok := v.([]interface{}); ok {
+		for _, elem := range elems {
+			fix(elem)
+		}
+	} else {
+		fix(v)
+	}
+	out, err := json.Marshal(v)
+	if err != nil {
+		return resp
+	}
+	return out
+}
@@ -0,0 +1,123 @@
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// recordFixture records a few exchanges against a live server and returns
+// the cassette bytes: two identical eth_blockNumber calls answered 0x1 then
+// 0x2, a batch, a 429 and a timeout.
+func recordFixture(t *testing.T) []byte {
+	t.Helper()
+	var n atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch n.Add(1) {
+		case 1:
+			w.Write(echoIDs(r, `{"jsonrpc":"2.0","id":1,"result":"0x1"}`))
+		case 2:
+			time.Sleep(30 * time.Millisecond)
+			w.Write(echoIDs(r, `{"jsonrpc":"2.0","id":1,"result":"0x2"}`))
+		case 3:
+			w.Write(echoIDs(r, `[{"jsonrpc":"2.0","id":2,"result":"0x1"},{"jsonrpc":"2.0","id":1,"result":"0xa"}]`))
+		case 4:
+			w.Header().Set("Retry-After", "2")
+			http.Error(w, "slow down", http.StatusTooManyRequests)
+		default:
+			time.Sleep(200 * time.Millisecond)
+		}
+	}))
+	defer srv.Close()
+
+	var tape bytes.Buffer
+	c := NewClient("live", srv.URL, 100*time.Millisecond, WithCassette(NewRecorder(&tape)))
+	ctx := context.Background()
+	c.Call(ctx, "eth_blockNumber")
+	c.Call(ctx, "eth_blockNumber")
+	c.CallBatch(ctx, []BatchElem{{Method: "eth_getBlockByNumber", Params: []interface{}{"0xa", false}}, {Method: "eth_chainId"}})
+	c.Call(ctx, "eth_gasPrice")
+	if _, _, err := c.Call(ctx, "eth_syncing"); Classify(err) != CategoryTimeout {
+		t.Fatalf("fixture: want a timeout, got %v", err)
+	}
+	if lines := strings.Count(tape.String(), "\n"); lines != 5 {
+		t.Fatalf("recorded %d exchanges, want 5:\n%s", lines, tape.String())
+	}
+	return tape.Bytes()
+}
+
+func TestCassette_replay(t *testing.T) {
+	tape := recordFixture(t)
+	cassette, err := NewReplayer(bytes.NewReader(tape), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A fresh client draws different request IDs; the URL is never dialed.
+	c := NewClient("live", "http://127.0.0.1:1", time.Second, WithCassette(cassette))
+	ctx := context.Background()
+
+	for _, want := range []string{`"0x1"`, `"0x2"`} {
+		resp, _, err := c.Call(ctx, "eth_blockNumber")
+		if err != nil || string(resp.Result) != want {
+			t.Fatalf("eth_blockNumber = %v, %v; want %s in recorded order", resp, err, want)
+		}
+	}
+
+	res, _, err := c.CallBatch(ctx, []BatchElem{{Method: "eth_getBlockByNumber", Params: []interface{}{"0xa", false}}, {Method: "eth_chainId"}})
+	if err != nil || res[0].Error != nil || res[1].Error != nil {
+		t.Fatalf("batch: %+v, %v", res, err)
+	}
+	if string(res[0].Response.Result) != `"0xa"` || string(res[1].Response.Result) != `"0x1"` {
+		t.Fatalf("batch results not matched to the new ids: %s, %s", res[0].Response.Result, res[1].Response.Result)
+	}
+
+	_, _, err = c.Call(ctx, "eth_gasPrice")
+	var httpErr *HTTPError
+	if !errors.As(err, &httpErr) || httpErr.StatusCode != 429 || httpErr.RetryAfter != 2*time.Second {
+		t.Fatalf("eth_gasPrice = %v, want the recorded 429 with Retry-After", err)
+	}
+	if _, _, err := c.Call(ctx, "eth_syncing"); Classify(err) != CategoryTimeout {
+		t.Fatalf("eth_syncing = %v, want the recorded timeout", err)
+	}
+
+	_, _, err = c.Call(ctx, "eth_blockNumber")
+	if err == nil || !strings.Contains(err.Error(), "no recorded answer for live eth_blockNumber") {
+		t.Fatalf("exhausted cassette = %v", err)
+	}
+}
+
+func TestCassette_replayMatchesProviderAndParams(t *testing.T) {
+	tape := recordFixture(t)
+	cassette, _ := NewReplayer(bytes.NewReader(tape), false)
+	other := NewClient("other", "http://127.0.0.1:1", time.Second, WithCassette(cassette))
+	if _, _, err := other.Call(context.Background(), "eth_blockNumber"); err == nil {
+		t.Error("another provider's exchanges should not answer")
+	}
+	live := NewClient("live", "http://127.0.0.1:1", time.Second, WithCassette(cassette))
+	if _, _, err := live.CallBatch(context.Background(), []BatchElem{{Method: "eth_getBlockByNumber", Params: []interface{}{"0xb", false}}, {Method: "eth_chainId"}}); err == nil {
+		t.Error("a request with other params should not match")
+	}
+}
+
+func TestCassette_pacedReplay(t *testing.T) {
+	tape := recordFixture(t)
+	cassette, _ := NewReplayer(bytes.NewReader(tape), true)
+	c := NewClient("live", "http://127.0.0.1:1", time.Second, WithCassette(cassette))
+	c.Call(context.Background(), "eth_blockNumber")
+	_, latency, err := c.Call(context.Background(), "eth_blockNumber")
+	if err != nil || latency < 30*time.Millisecond {
+		t.Fatalf("paced replay took %v (%v), want at least the recorded 30ms", latency, err)
+	}
+}
+
+func TestNewReplayer_badLine(t *testing.T) {
+	if _, err := NewReplayer(strings.NewReader("{\"provider\":\"a\"}\nnot json\n"), false); err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Fatalf("err = %v, want the bad line number", err)
+	}
+}
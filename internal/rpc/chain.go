@@ -0,0 +1,89 @@
+// =============================================================================
+// FILE: internal/rpc/chain.go
+// ROLE: Chain Identity — Is This Provider on the Chain We Think It Is?
+// =============================================================================
+//
+// SYSTEM CONTEXT
+// ==============
+// Every comparison this tool makes assumes all providers serve the SAME
+// chain. A Sepolia URL in a mainnet list breaks that silently: it answers
+// quickly, its blocks parse fine, and `snapshot` reports a "hash mismatch"
+// that is really a configuration mistake.
+//
+// Two values pin down a chain:
+//
+//   eth_chainId                      → 0x1 (mainnet), 0xaa36a7 (Sepolia), ...
+//   eth_getBlockByNumber("0x0").hash → the genesis block; unique per chain
+//
+// VerifyChain checks whichever of the two the caller supplies and returns a
+// *ChainMismatchError when one differs. internal/chaincheck runs it for
+// every provider at command startup.
+// =============================================================================
+
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ChainMismatchError means a provider answered, but for a different chain
+// than the one configured.
+type ChainMismatchError struct {
+	Field string // "chain ID" or "genesis hash"
+	Want  string
+	Got   string
+}
+
+func (e *ChainMismatchError) Error() string {
+	return fmt.Sprintf("wrong chain: %s is %s, expected %s", e.Field, e.Got, e.Want)
+}
+func (e *ChainMismatchError) Category() ErrorCategory { return CategoryWrongChain }
+
+// ChainID calls eth_chainId and returns the chain ID as a number.
+func (c *Client) ChainID(ctx context.Context) (uint64, time.Duration, error) {
+	resp, latency, err := c.Call(ctx, "eth_chainId")
+	if err != nil {
+		return 0, latency, err
+	}
+
+	var hexStr string
+	if err := json.Unmarshal(resp.Result, &hexStr); err != nil {
+		return 0, latency, fmt.Errorf("unmarshal chainId result: %w", err)
+	}
+
+	id, err := ParseHexUint64(hexStr)
+	if err != nil {
+		return 0, latency, fmt.Errorf("parse chainId hex %q: %w", hexStr, err)
+	}
+	return id, latency, nil
+}
+
+// VerifyChain checks the provider's chain ID (when wantID != 0) and genesis
+// block hash (when wantGenesis != ""). It returns a *ChainMismatchError for
+// a definite mismatch, or the underlying error when a check could not run.
+func (c *Client) VerifyChain(ctx context.Context, wantID uint64, wantGenesis string) error {
+	if wantID != 0 {
+		got, _, err := c.ChainID(ctx)
+		if err != nil {
+			return err
+		}
+		if got != wantID {
+			return &ChainMismatchError{Field: "chain ID", Want: fmt.Sprint(wantID), Got: fmt.Sprint(got)}
+		}
+	}
+
+	if wantGenesis != "" {
+		genesis, _, err := c.GetBlock(ctx, "0x0")
+		if err != nil {
+			return fmt.Errorf("genesis block: %w", err)
+		}
+		if !strings.EqualFold(genesis.Hash, wantGenesis) {
+			return &ChainMismatchError{Field: "genesis hash", Want: wantGenesis, Got: genesis.Hash}
+		}
+	}
+	return nil
+}
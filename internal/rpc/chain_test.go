@@ -0,0 +1,54 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+const testGenesis = "0xd4e56740f876aef8c010b86a40d5f56745a118d0906a34e69aec8c0db1cb8fa3"
+
+func chainServer(t *testing.T, chainID, genesis string) *Client {
+	t.Helper()
+	srv := nodeServer(t, map[string]string{
+		"eth_chainId":          `"` + chainID + `"`,
+		"eth_getBlockByNumber": `{"number":"0x0","hash":"` + genesis + `","transactions":[]}`,
+	})
+	t.Cleanup(srv.Close)
+	return NewClient("t", srv.URL, 2*time.Second)
+}
+
+func TestClient_VerifyChain(t *testing.T) {
+	c := chainServer(t, "0x1", "0xD4E56740F876AEF8C010B86A40D5F56745A118D0906A34E69AEC8C0DB1CB8FA3")
+	if err := c.VerifyChain(context.Background(), 1, testGenesis); err != nil {
+		t.Fatalf("matching chain: %v", err)
+	}
+
+	var mismatch *ChainMismatchError
+	err := c.VerifyChain(context.Background(), 11155111, "")
+	if !errors.As(err, &mismatch) || mismatch.Field != "chain ID" || mismatch.Got != "1" {
+		t.Fatalf("chain ID: err = %v", err)
+	}
+	if Classify(err) != CategoryWrongChain {
+		t.Fatalf("category = %s", Classify(err))
+	}
+}
+
+func TestClient_VerifyChain_genesis(t *testing.T) {
+	c := chainServer(t, "0x1", "0x25a5cc106eea7138acab33231d7160d69cb777ee0c2c553fcddf5138993e6dd9")
+	var mismatch *ChainMismatchError
+	if err := c.VerifyChain(context.Background(), 1, testGenesis); !errors.As(err, &mismatch) || mismatch.Field != "genesis hash" {
+		t.Fatalf("err = %v", err)
+	}
+}
+
+func TestClient_VerifyChain_checkFailed(t *testing.T) {
+	srv := nodeServer(t, nil)
+	defer srv.Close()
+	err := NewClient("t", srv.URL, 2*time.Second).VerifyChain(context.Background(), 1, "")
+	var mismatch *ChainMismatchError
+	if err == nil || errors.As(err, &mismatch) {
+		t.Fatalf("err = %v", err)
+	}
+}
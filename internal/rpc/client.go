@@ -43,6 +43,8 @@
 // 2. NO CONNECTION POOLING: We create a new Client per provider per operation.
 //    For a monitoring tool making a few requests per cycle, connection pooling
 //    adds complexity without meaningful benefit.
+//    How a Client's own connections are made and reused is its transport
+//    policy (cold, warm, http1, http2 — see transport.go).
 //
 // 3. LATENCY INCLUDES EVERYTHING: The measured latency spans from sending
 //    the HTTP request to fully reading the response body. This is the
@@ -125,9 +127,15 @@ import (
 // causing subtle concurrency bugs. The pointer ensures all references
 // point to the same underlying client.
 type Client struct {
-	name       string       // Human-readable provider name (e.g., "alchemy", "infura")
-	url        string       // Full RPC endpoint URL (e.g., "https://eth-mainnet.g.alchemy.com/v2/...")
-	httpClient *http.Client // Go's HTTP client with configured timeout
+	name       string        // Human-readable provider name (e.g., "alchemy", "infura")
+	url        string        // Full RPC endpoint URL (e.g., "https://eth-mainnet.g.alchemy.com/v2/...")
+	httpClient *http.Client  // Go's HTTP client with configured timeout
+	ids        *idSource     // Unique request IDs (see ids.go)
+	auth       *Auth         // Headers and credentials for every request; nil = none (see auth.go)
+	policy     Policy        // Connection policy; "" = shared default transport (see transport.go)
+	limiter    *RateLimiter  // Provider-wide request quota; nil = unlimited (see ratelimit.go)
+	ipc        *ipcTransport // Set for ipc:// URLs; replaces HTTP entirely (see ipc.go)
+	cassette   *Cassette     // Records or replays every exchange; nil = live (see cassette.go)
 }
 
 // =============================================================================
@@ -186,14 +194,55 @@ type Client struct {
 //     struct should be too, to avoid accidental copies.
 //  3. Convention: constructors in Go typically return pointers when the type
 //     has pointer-receiver methods or contains reference types.
-func NewClient(name, url string, timeout time.Duration) *Client {
-	return &Client{
+//
+// OPTIONS
+// =======
+// Anything beyond name, URL and timeout is passed as an Option, so the
+// common case stays a three-argument call:
+//
+//	rpc.NewClient("local", url, 5*time.Second, rpc.WithAuth(&rpc.Auth{...}))
+//	rpc.NewClient("local", url, 5*time.Second, rpc.WithPolicy(rpc.PolicyCold))
+//	rpc.NewClient("local", url, 5*time.Second, rpc.WithRateLimiter(limiter))
+func NewClient(name, url string, timeout time.Duration, opts ...Option) *Client {
+	o := applyOptions(opts)
+	c := &Client{
 		name:       name,
 		url:        url,
-		httpClient: &http.Client{Timeout: timeout},
+		httpClient: &http.Client{Timeout: timeout, Transport: o.policy.transport()},
+		ids:        newIDSource(),
+		auth:       o.auth,
+		policy:     o.policy,
+		limiter:    o.limiter,
+		cassette:   o.cassette,
+	}
+	if IsIPC(url) {
+		c.ipc = newIPCTransport(url, timeout, o.policy)
+	}
+	return c
+}
+
+// Option configures a Client or WSClient at construction time.
+type Option func(*options)
+
+// options collects everything Options can set.
+type options struct {
+	auth     *Auth
+	policy   Policy
+	limiter  *RateLimiter
+	cassette *Cassette
+}
+
+func applyOptions(opts []Option) options {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
 	}
+	return o
 }
 
+// WithAuth sends a's headers and credentials with every request.
+func WithAuth(a *Auth) Option { return func(o *options) { o.auth = a } }
+
 // Name returns the human-readable provider name for this client.
 //
 // POINTER RECEIVER: (c *Client)
@@ -271,6 +320,8 @@ func (c *Client) Name() string { return c.name }
 //
 // This is the latency that matters for real applications — it includes
 // network overhead, TLS handshake (on first request), and server processing.
+// To see HOW that latency splits into DNS, connect, TLS, server time and body
+// transfer, attach a Timing to ctx with WithTiming (see trace.go).
 func (c *Client) Call(ctx context.Context, method string, params ...interface{}) (*Response, time.Duration, error) {
 	// Ensure params is never nil in the JSON output.
 	// JSON serializes nil slices as `null`, but the JSON-RPC spec requires
@@ -283,101 +334,66 @@ func (c *Client) Call(ctx context.Context, method string, params ...interface{})
 	// json.Marshal converts the Request struct into a []byte of JSON.
 	// The _ discards the error because marshaling a known-good struct
 	// with simple types (string, int, []interface{}) cannot fail in practice.
+	id := c.ids.take()
 	body, err := json.Marshal(Request{
 		JSONRPC: "2.0",
 		Method:  method,
 		Params:  params,
-		ID:      1,
+		ID:      id,
 	})
 	if err != nil {
 		return nil, 0, fmt.Errorf("marshal rpc request: %w", err)
 	}
 
+	// Wait for the provider's rate limiter, if any, BEFORE the timer
+	// starts: time spent queued is our own doing, not the provider's
+	// latency, and is reported separately in Timing.Queue.
+	queued, err := c.throttle(ctx, 1)
+	defer recordQueue(ctx, queued)
+	if err != nil {
+		return nil, 0, err
+	}
+
 	// START the latency timer.
 	// time.Now() captures the current monotonic clock reading.
 	// "Monotonic" means it always moves forward — it's not affected by
 	// system clock adjustments (like NTP corrections or daylight saving).
 	start := time.Now()
 
-	// Create an HTTP request with the context attached.
-	// http.NewRequestWithContext ties the request to our context, so if
-	// the context is cancelled (e.g., Ctrl+C), the HTTP request is aborted.
-	//
-	// bytes.NewReader(body) wraps the JSON bytes in an io.Reader interface.
-	// This doesn't copy the data — it creates a thin wrapper that reads
-	// from the existing byte slice.
-	//
-	// The _ discards the error because NewRequestWithContext only fails if
-	// the method is invalid or the URL can't be parsed — both are programmer
-	// errors, not runtime errors, and our inputs are validated at config time.
-	req, err := http.NewRequestWithContext(ctx, "POST", c.url, bytes.NewReader(body))
+	// Send the bytes and read the full reply. post() owns everything
+	// HTTP-specific (headers, status checks, body reading) so that Call and
+	// CallBatch share exactly the same transport and error semantics.
+	raw, err := c.post(ctx, body)
 	if err != nil {
-		return nil, 0, fmt.Errorf("create http request: %w", err)
-	}
-	req.Header.Set("Content-Type", "application/json")
-
-	// Send the HTTP request and wait for the response.
-	// c.httpClient.Do(req) performs the entire HTTP transaction:
-	//   - DNS lookup (if needed)
-	//   - TCP connection (or reuse from pool)
-	//   - TLS handshake (for HTTPS endpoints)
-	//   - Send request headers and body
-	//   - Read response headers
-	// The response body is NOT fully read yet — it streams lazily.
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		// Network errors: DNS failure, connection refused, timeout, TLS error,
-		// context cancellation. Return immediately with zero latency.
 		return nil, 0, err
 	}
-	// defer resp.Body.Close() ensures the response body is closed when this
-	// function returns. This is CRITICAL — unclosed response bodies leak TCP
-	// connections. The `defer` keyword schedules the Close() call to run when
-	// the enclosing function exits, regardless of which return path is taken.
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		snippet, readErr := io.ReadAll(io.LimitReader(resp.Body, 4096))
-		bodyStr := strings.TrimSpace(string(snippet))
-		if readErr != nil {
-			return nil, 0, fmt.Errorf("rpc http status %d: read body: %w", resp.StatusCode, readErr)
-		}
-		if bodyStr == "" {
-			return nil, 0, fmt.Errorf("rpc http status %d: empty body", resp.StatusCode)
-		}
-		return nil, 0, fmt.Errorf("rpc http status %d: %s", resp.StatusCode, bodyStr)
-	}
 
 	// Deserialize the JSON response body into our Response struct.
 	//
 	// IMPORTANT: &rpcResp — the `&` (address-of operator)
 	// ====================================================
-	// json.NewDecoder(...).Decode(&rpcResp) passes the ADDRESS of rpcResp
+	// json.Unmarshal(raw, &rpcResp) passes the ADDRESS of rpcResp
 	// to the decoder. This is necessary because:
 	//
-	//   - Decode needs to WRITE INTO rpcResp (fill in its fields)
+	//   - Unmarshal needs to WRITE INTO rpcResp (fill in its fields)
 	//   - In Go, function arguments are passed by VALUE (copied)
-	//   - If we passed rpcResp (without &), Decode would fill in a COPY,
+	//   - If we passed rpcResp (without &), Unmarshal would fill in a COPY,
 	//     and our original rpcResp would remain empty
-	//   - By passing &rpcResp, we give Decode the memory address where
+	//   - By passing &rpcResp, we give Unmarshal the memory address where
 	//     rpcResp lives, so it can write directly into our variable
 	//
-	// In memory:
-	//
-	//   WRONG (without &):               CORRECT (with &):
-	//   ┌───────────┐                   ┌───────────┐
-	//   │ rpcResp   │ ← stays empty     │ rpcResp   │ ← gets filled in
-	//   └───────────┘                   └───────────┘
-	//                                        ▲
-	//   ┌───────────┐                        │
-	//   │ copy      │ ← Decode fills this   │ Decode writes directly
-	//   └───────────┘   (then discards it)   │ to this address via &
-	//
 	// This is one of the most fundamental patterns in Go: passing a pointer
 	// to a function that needs to modify the caller's data.
 	var rpcResp Response
-	if err := json.NewDecoder(resp.Body).Decode(&rpcResp); err != nil {
-		return nil, 0, fmt.Errorf("decode rpc response: %w", err)
+	if err := json.Unmarshal(raw, &rpcResp); err != nil {
+		return nil, 0, &ProtocolError{Msg: "decode rpc response", Err: err}
+	}
+
+	// Make sure this reply actually answers OUR request. A mismatch takes
+	// precedence over any result or error it carries — both belong to
+	// somebody else's request.
+	if err := checkID(&rpcResp, id); err != nil {
+		return nil, 0, err
 	}
 
 	// Check for RPC-level errors.
@@ -390,9 +406,11 @@ func (c *Client) Call(ctx context.Context, method string, params ...interface{})
 	// points to the parsed RPCError struct.
 	//
 	// The != nil check is a pointer nil check — it asks "does this pointer
-	// point to anything?" If yes, there was an error.
+	// point to anything?" If yes, there was an error. *RPCError implements
+	// the error interface, so it is returned as-is: callers keep the code
+	// and data, and Classify() can categorize it (see errors.go).
 	if rpcResp.Error != nil {
-		return nil, 0, fmt.Errorf("RPC error: %s", rpcResp.Error.Message)
+		return nil, 0, rpcResp.Error
 	}
 
 	// STOP the latency timer and return.
@@ -410,6 +428,97 @@ func (c *Client) Call(ctx context.Context, method string, params ...interface{})
 	return &rpcResp, time.Since(start), nil
 }
 
+// post sends one JSON-RPC payload (a single request or a batch array) as an
+// HTTP POST and returns the complete response body.
+//
+// Call and CallBatch both build on this helper, so they share one definition
+// of "the HTTP exchange failed": network errors, non-200 statuses (with a
+// body snippet for diagnosis), and read errors all surface here before any
+// JSON-RPC decoding happens.
+//
+// Failures come back typed (see errors.go): *TransportError when the
+// exchange never completed, *HTTPError for a non-200 status.
+//
+// The body is read fully with io.ReadAll rather than streamed into a decoder
+// because CallBatch must inspect the first byte to tell an array (a real
+// batch reply) from an object (a provider rejecting the batch as a whole).
+//
+// If the caller attached a *Timing with WithTiming, the exchange is traced
+// and the phase breakdown is written there when post returns — on failure
+// too, so a timeout can still be attributed to the phase it stalled in.
+func (c *Client) post(ctx context.Context, body []byte) ([]byte, error) {
+	var tracer *phaseTracer
+	if t := timingFrom(ctx); t != nil {
+		tracer, ctx = newPhaseTracer(ctx)
+		defer func() { *t = tracer.finish(time.Now()) }()
+	}
+	if c.cassette != nil {
+		return c.cassette.exchange(ctx, c, body, tracer)
+	}
+	return c.send(ctx, body, tracer)
+}
+
+// send is post without a cassette: the exchange itself, over IPC or HTTP.
+func (c *Client) send(ctx context.Context, body []byte, tracer *phaseTracer) ([]byte, error) {
+	if c.ipc != nil {
+		return c.ipc.roundTrip(ctx, body, tracer)
+	}
+
+	// Create an HTTP request with the context attached.
+	// http.NewRequestWithContext ties the request to our context, so if
+	// the context is cancelled (e.g., Ctrl+C), the HTTP request is aborted.
+	//
+	// bytes.NewReader(body) wraps the JSON bytes in an io.Reader interface.
+	// This doesn't copy the data — it creates a thin wrapper that reads
+	// from the existing byte slice.
+	req, err := http.NewRequestWithContext(ctx, "POST", c.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("create http request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	c.auth.apply(req.Header, time.Now())
+
+	// Send the HTTP request and wait for the response headers.
+	// c.httpClient.Do(req) performs the entire HTTP transaction:
+	//   - DNS lookup (if needed)
+	//   - TCP connection (or reuse from pool)
+	//   - TLS handshake (for HTTPS endpoints)
+	//   - Send request headers and body
+	//   - Read response headers
+	// The response body is NOT fully read yet — it streams lazily.
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		// Network errors: DNS failure, connection refused, timeout, TLS error,
+		// context cancellation. Return immediately.
+		return nil, &TransportError{Err: err}
+	}
+	if tracer != nil {
+		tracer.gotProto(resp.Proto)
+	}
+	// defer resp.Body.Close() ensures the response body is closed when this
+	// function returns. This is CRITICAL — unclosed response bodies leak TCP
+	// connections.
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		// A failed snippet read still leaves us the status code, which is
+		// the part that matters for classification.
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, &HTTPError{
+			StatusCode: resp.StatusCode,
+			Body:       strings.TrimSpace(string(snippet)),
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+		}
+	}
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		// The connection broke (or the client timeout fired) mid-body.
+		return nil, &TransportError{Err: fmt.Errorf("read rpc response: %w", err)}
+	}
+	return raw, nil
+}
+
 // =============================================================================
 // SECTION 4: Convenience Methods — Typed Wrappers Around Call
 // =============================================================================
@@ -456,6 +565,30 @@ func (c *Client) BlockNumber(ctx context.Context) (uint64, time.Duration, error)
 	return num, latency, nil
 }
 
+// BlockNumberByTag returns the height of the block a tag points at. It is
+// eth_blockNumber for the other heads the node tracks since the Merge:
+//
+//	"latest"     the head of the canonical chain (may still be reorged)
+//	"safe"       justified by the beacon chain; reorging it needs a
+//	             large share of validators to be slashed
+//	"finalized"  finalized by the beacon chain; cannot be reverted
+//
+// There is no eth_safeBlockNumber, so this fetches the block header
+// (eth_getBlockByNumber without transaction objects) and reads its number.
+// Nodes that predate the Merge, and chains without beacon finality, reject
+// the tags or return null.
+func (c *Client) BlockNumberByTag(ctx context.Context, tag string) (uint64, time.Duration, error) {
+	block, latency, err := c.GetBlock(ctx, tag)
+	if err != nil {
+		return 0, latency, err
+	}
+	num, err := ParseHexUint64(block.Number)
+	if err != nil {
+		return 0, latency, fmt.Errorf("parse %s block number %q: %w", tag, block.Number, err)
+	}
+	return num, latency, nil
+}
+
 // GetBlock calls eth_getBlockByNumber and returns the full block data.
 //
 // Parameters:
@@ -494,11 +627,35 @@ func (c *Client) BlockNumber(ctx context.Context) (uint64, time.Duration, error)
 //	     │                              │
 //	&block (returned)              caller's pointer
 func (c *Client) GetBlock(ctx context.Context, blockNum string) (*Block, time.Duration, error) {
-	resp, latency, err := c.Call(ctx, "eth_getBlockByNumber", blockNum, false)
+	return c.getBlock(ctx, blockNum, false)
+}
+
+// GetFullBlock is GetBlock with hydrated transactions: the node returns a
+// full object per transaction, decoded into Block.FullTransactions (see
+// transaction.go). Block.Transactions still lists the hashes.
+//
+// Expect a much larger response — roughly 1 KB per transaction instead of
+// 66 bytes — so use it when the transaction details are the point.
+func (c *Client) GetFullBlock(ctx context.Context, blockNum string) (*Block, time.Duration, error) {
+	return c.getBlock(ctx, blockNum, true)
+}
+
+// getBlock is the shared implementation of GetBlock and GetFullBlock;
+// full is eth_getBlockByNumber's "hydrated" parameter.
+func (c *Client) getBlock(ctx context.Context, blockNum string, full bool) (*Block, time.Duration, error) {
+	resp, latency, err := c.Call(ctx, "eth_getBlockByNumber", blockNum, full)
 	if err != nil {
 		return nil, latency, err
 	}
 
+	// A block the node doesn't have (e.g. a number past its head) comes back
+	// as a successful response with "result": null. Unmarshaling null into a
+	// struct is a silent no-op, which would hand the caller an all-empty
+	// Block — so report it explicitly instead.
+	if isNullResult(resp.Result) {
+		return nil, latency, &NotFoundError{Method: "eth_getBlockByNumber", Arg: blockNum}
+	}
+
 	// Deserialize the raw JSON result into a Block struct.
 	// &block passes the address so Unmarshal can write into our variable.
 	var block Block
@@ -507,3 +664,9 @@ func (c *Client) GetBlock(ctx context.Context, blockNum string) (*Block, time.Du
 	}
 	return &block, latency, nil
 }
+
+// isNullResult reports whether a JSON-RPC result is absent or JSON null.
+func isNullResult(raw json.RawMessage) bool {
+	trimmed := bytes.TrimSpace(raw)
+	return len(trimmed) == 0 || bytes.Equal(trimmed, []byte("null"))
+}
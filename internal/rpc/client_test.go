@@ -1,18 +1,47 @@
 package rpc
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"regexp"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
 )
 
+var placeholderID = regexp.MustCompile(`"id":(\d+)`)
+
+// echoIDs rewrites the placeholder ids in a canned reply ("id":1 for the
+// first request of a batch, "id":2 for the second, ...) to the ids the
+// client actually sent, since every call now carries a unique id.
+func echoIDs(r *http.Request, reply string) []byte {
+	body, _ := io.ReadAll(r.Body)
+	var reqs []Request
+	if bytes.HasPrefix(bytes.TrimSpace(body), []byte("[")) {
+		json.Unmarshal(body, &reqs)
+	} else {
+		var one Request
+		json.Unmarshal(body, &one)
+		reqs = []Request{one}
+	}
+	return []byte(placeholderID.ReplaceAllStringFunc(reply, func(m string) string {
+		n, _ := strconv.Atoi(placeholderID.FindStringSubmatch(m)[1])
+		if n < 1 || n > len(reqs) {
+			return m
+		}
+		return `"id":` + strconv.FormatUint(reqs[n-1].ID, 10)
+	}))
+}
+
 func TestClient_BlockNumber_success(t *testing.T) {
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
-		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":"0x10"}`))
+		_, _ = w.Write(echoIDs(r, `{"jsonrpc":"2.0","id":1,"result":"0x10"}`))
 	}))
 	defer srv.Close()
 
@@ -25,7 +54,7 @@ func TestClient_BlockNumber_success(t *testing.T) {
 
 func TestClient_Call_rpcError(t *testing.T) {
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"error":{"code":-32600,"message":"bad"}}`))
+		_, _ = w.Write(echoIDs(r, `{"jsonrpc":"2.0","id":1,"error":{"code":-32600,"message":"bad"}}`))
 	}))
 	defer srv.Close()
 
@@ -65,7 +94,7 @@ func TestClient_Call_invalidJSON(t *testing.T) {
 
 func TestClient_BlockNumber_badHex(t *testing.T) {
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":"0xzz"}`))
+		_, _ = w.Write(echoIDs(r, `{"jsonrpc":"2.0","id":1,"result":"0xzz"}`))
 	}))
 	defer srv.Close()
 
@@ -75,3 +104,24 @@ func TestClient_BlockNumber_badHex(t *testing.T) {
 		t.Fatalf("expected parse error, got %v", err)
 	}
 }
+
+func TestClient_BlockNumberByTag(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		reply := `{"jsonrpc":"2.0","id":1,"result":{"number":"0x64","hash":"0xaa"}}`
+		if strings.Contains(string(body), `"safe"`) {
+			reply = `{"jsonrpc":"2.0","id":1,"result":null}` // A node without the tag
+		}
+		_, _ = w.Write(echoIDs(r, reply))
+	}))
+	defer srv.Close()
+
+	c := NewClient("t", srv.URL, 2*time.Second)
+	if n, _, err := c.BlockNumberByTag(context.Background(), "finalized"); err != nil || n != 100 {
+		t.Fatalf("finalized: n=%d err=%v", n, err)
+	}
+	if _, _, err := c.BlockNumberByTag(context.Background(), "safe"); err == nil {
+		t.Fatal("null result accepted")
+	}
+}
@@ -0,0 +1,268 @@
+// =============================================================================
+// FILE: internal/rpc/errors.go
+// ROLE: Failure Taxonomy — Typed, Classified RPC Errors
+// =============================================================================
+//
+// SYSTEM CONTEXT
+// ==============
+// A monitoring tool is only as useful as its explanation of failures. "3 of
+// 30 samples failed" prompts the obvious next question: failed HOW? The
+// answers call for very different reactions:
+//
+//   timeout       → the provider (or the path to it) is slow or overloaded
+//   rate-limited  → we are over our plan's quota — back off or upgrade
+//   auth          → the API key is wrong, expired, or not allowed this method
+//   server        → the provider's node or gateway is failing (5xx, -32603)
+//   protocol      → the reply was not valid JSON-RPC (HTML error page,
+//                   truncated body, malformed envelope)
+//   not-found     → the method or the requested object does not exist
+//   network       → DNS failure, connection refused/reset, TLS failure
+//   wrong-chain   → the provider serves a different chain than configured
+//
+// Every error returned by Client, CallBatch and WSClient is one of the types
+// below (possibly wrapped with extra context via fmt.Errorf("...: %w")), so
+// callers can either inspect details with errors.As, or just ask Classify()
+// for the category.
+//
+// ERROR TYPES
+// ===========
+//
+//   ┌────────────────┬──────────────────────────────────────────────────────┐
+//   │ *TransportError│ The HTTP exchange never completed (net/url errors)  │
+//   │ *HTTPError     │ Non-200 status; carries status, body, Retry-After   │
+//   │ *RPCError      │ JSON-RPC "error" object; carries code, message, data│
+//   │ *ProtocolError │ The reply could not be understood as JSON-RPC       │
+//   │ *NotFoundError │ The call succeeded but the result was null          │
+//   │ *ChainMismatch…│ Wrong chain ID or genesis hash (chain.go)           │
+//   └────────────────┴──────────────────────────────────────────────────────┘
+//
+// Each type has a Category() method; Classify() finds the first error in the
+// wrap chain that has one. The Error() strings intentionally keep the
+// wording the client used before these types existed ("rpc http status
+// 429: ...", "decode rpc response: ..."), so logs and reports read the same.
+// =============================================================================
+
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrorCategory is a coarse classification of why a call failed.
+type ErrorCategory string
+
+const (
+	CategoryTimeout     ErrorCategory = "timeout"
+	CategoryRateLimited ErrorCategory = "rate-limited"
+	CategoryAuth        ErrorCategory = "auth"
+	CategoryServer      ErrorCategory = "server"
+	CategoryProtocol    ErrorCategory = "protocol"
+	CategoryNotFound    ErrorCategory = "not-found"
+	CategoryNetwork     ErrorCategory = "network"
+	CategoryWrongChain  ErrorCategory = "wrong-chain"
+	CategoryUnknown     ErrorCategory = "unknown"
+)
+
+// Categories lists every category in display order. Breakdown tables use it
+// so columns and summaries are stable from run to run.
+var Categories = []ErrorCategory{
+	CategoryTimeout, CategoryRateLimited, CategoryAuth, CategoryServer,
+	CategoryProtocol, CategoryNotFound, CategoryNetwork, CategoryWrongChain, CategoryUnknown,
+}
+
+// categorizer is implemented by every error type in this file.
+type categorizer interface {
+	Category() ErrorCategory
+}
+
+// Classify returns the category of err, or "" for a nil error.
+//
+// It walks the wrap chain (errors.As) looking for one of this package's
+// typed errors. Plain context errors are recognized too, since callers
+// sometimes see them before the client does (e.g. a cancelled errgroup).
+// Anything else is CategoryUnknown.
+func Classify(err error) ErrorCategory {
+	if err == nil {
+		return ""
+	}
+	var c categorizer
+	if errors.As(err, &c) {
+		return c.Category()
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return CategoryTimeout
+	}
+	return CategoryUnknown
+}
+
+// =============================================================================
+// SECTION 1: Transport and HTTP Errors
+// =============================================================================
+
+// TransportError wraps a failure of the HTTP exchange itself: DNS lookup,
+// TCP connect, TLS handshake, client timeout, or context cancellation.
+type TransportError struct {
+	Err error // The underlying error from net/http (usually a *url.Error)
+}
+
+func (e *TransportError) Error() string { return e.Err.Error() }
+func (e *TransportError) Unwrap() error { return e.Err }
+
+// Timeout reports whether the exchange failed because a deadline passed —
+// either http.Client.Timeout or the caller's context deadline.
+func (e *TransportError) Timeout() bool {
+	if errors.Is(e.Err, context.DeadlineExceeded) {
+		return true
+	}
+	var ne net.Error
+	return errors.As(e.Err, &ne) && ne.Timeout()
+}
+
+// Category is CategoryTimeout for deadline failures, CategoryNetwork otherwise.
+func (e *TransportError) Category() ErrorCategory {
+	if e.Timeout() {
+		return CategoryTimeout
+	}
+	return CategoryNetwork
+}
+
+// HTTPError is a non-200 HTTP response.
+//
+// Providers signal most operational trouble at the HTTP layer: 429 for rate
+// limits (often with a Retry-After header), 401/403 for bad keys, 502/503
+// from load balancers when backends are down.
+type HTTPError struct {
+	StatusCode int           // HTTP status code
+	Body       string        // First 4 KiB of the body, trimmed (may be empty)
+	RetryAfter time.Duration // Parsed Retry-After header; 0 if absent or unparseable
+}
+
+func (e *HTTPError) Error() string {
+	if e.Body == "" {
+		return fmt.Sprintf("rpc http status %d: empty body", e.StatusCode)
+	}
+	return fmt.Sprintf("rpc http status %d: %s", e.StatusCode, e.Body)
+}
+
+// Category maps the status code:
+//
+//	429           → rate-limited
+//	401, 403      → auth
+//	404           → not-found (usually a wrong URL path or retired endpoint)
+//	408, 504      → timeout   (the gateway gave up waiting on the node)
+//	other 5xx     → server
+//	anything else → protocol  (e.g. 400 on a request we believe is valid)
+func (e *HTTPError) Category() ErrorCategory {
+	switch {
+	case e.StatusCode == http.StatusTooManyRequests:
+		return CategoryRateLimited
+	case e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden:
+		return CategoryAuth
+	case e.StatusCode == http.StatusNotFound:
+		return CategoryNotFound
+	case e.StatusCode == http.StatusRequestTimeout || e.StatusCode == http.StatusGatewayTimeout:
+		return CategoryTimeout
+	case e.StatusCode >= 500:
+		return CategoryServer
+	default:
+		return CategoryProtocol
+	}
+}
+
+// parseRetryAfter parses a Retry-After header, which may be either a number
+// of seconds ("30") or an HTTP date. Returns 0 when absent or invalid.
+func parseRetryAfter(v string, now time.Time) time.Duration {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil && t.After(now) {
+		return t.Sub(now)
+	}
+	return 0
+}
+
+// =============================================================================
+// SECTION 2: JSON-RPC Level Errors
+// =============================================================================
+
+// Error makes *RPCError usable as a Go error, so Call can return the
+// server's error object as-is (code and data included) instead of a string.
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("RPC error %d: %s", e.Code, e.Message)
+}
+
+// Category classifies a JSON-RPC error object.
+//
+// Codes are only partly standardized across clients and providers, so the
+// message is consulted as well. The rules, in order:
+//
+//	-32005, or message mentions rate/limit/quota     → rate-limited
+//	message mentions unauthorized/forbidden/api key  → auth
+//	-32601 (method not found), "not found" messages  → not-found
+//	-32700, -32600, -32602 (malformed request)       → protocol
+//	everything else (-32603, -32000 execution, ...)  → server
+func (e *RPCError) Category() ErrorCategory {
+	msg := strings.ToLower(e.Message)
+	switch {
+	case e.Code == -32005 || containsAny(msg, "rate limit", "too many requests", "limit exceeded", "quota", "over capacity"):
+		return CategoryRateLimited
+	case containsAny(msg, "unauthorized", "forbidden", "api key", "apikey", "not allowed", "invalid project id", "must be authenticated"):
+		return CategoryAuth
+	case e.Code == -32601 || containsAny(msg, "not found", "does not exist", "not supported", "unsupported method"):
+		return CategoryNotFound
+	case e.Code == -32700 || e.Code == -32600 || e.Code == -32602:
+		return CategoryProtocol
+	default:
+		return CategoryServer
+	}
+}
+
+func containsAny(s string, subs ...string) bool {
+	for _, sub := range subs {
+		if strings.Contains(s, sub) {
+			return true
+		}
+	}
+	return false
+}
+
+// ProtocolError means the provider answered, but not with something we can
+// interpret as JSON-RPC: a non-JSON body (HTML error pages are common), a
+// batch reply of the wrong shape, and similar.
+type ProtocolError struct {
+	Msg string // What we were trying to do ("decode rpc response")
+	Err error  // Underlying decode error, if any
+}
+
+func (e *ProtocolError) Error() string {
+	if e.Err == nil {
+		return e.Msg
+	}
+	return e.Msg + ": " + e.Err.Error()
+}
+func (e *ProtocolError) Unwrap() error           { return e.Err }
+func (e *ProtocolError) Category() ErrorCategory { return CategoryProtocol }
+
+// NotFoundError means the call succeeded but the result was JSON null — the
+// node's way of saying "no such block/transaction" (for example a block
+// number beyond the provider's head).
+type NotFoundError struct {
+	Method string // RPC method that returned null
+	Arg    string // The identifier that was looked up ("0x1312d00", "latest")
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("%s(%s): not found (null result)", e.Method, e.Arg)
+}
+func (e *NotFoundError) Category() ErrorCategory { return CategoryNotFound }
@@ -0,0 +1,138 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func callStatus(t *testing.T, h http.HandlerFunc, timeout time.Duration) error {
+	t.Helper()
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+	_, _, err := NewClient("t", srv.URL, timeout).Call(context.Background(), "eth_blockNumber")
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	return err
+}
+
+func TestClassify_httpStatuses(t *testing.T) {
+	err := callStatus(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}, time.Second)
+	var he *HTTPError
+	if !errors.As(err, &he) || he.StatusCode != 429 || he.RetryAfter != 30*time.Second {
+		t.Fatalf("want *HTTPError 429 with Retry-After, got %#v", err)
+	}
+	if Classify(err) != CategoryRateLimited {
+		t.Fatalf("429: got %s", Classify(err))
+	}
+
+	cases := map[int]ErrorCategory{401: CategoryAuth, 403: CategoryAuth, 404: CategoryNotFound, 502: CategoryServer, 504: CategoryTimeout, 400: CategoryProtocol}
+	for code, want := range cases {
+		err := callStatus(t, func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(code) }, time.Second)
+		if got := Classify(err); got != want {
+			t.Errorf("status %d: got %s, want %s", code, got, want)
+		}
+	}
+}
+
+func TestClassify_rpcErrorKeepsCodeAndData(t *testing.T) {
+	err := callStatus(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write(echoIDs(r, `{"jsonrpc":"2.0","id":1,"error":{"code":-32601,"message":"the method eth_foo does not exist","data":{"x":1}}}`))
+	}, time.Second)
+	var re *RPCError
+	if !errors.As(err, &re) || re.Code != -32601 || string(re.Data) != `{"x":1}` {
+		t.Fatalf("want *RPCError with code and data, got %#v", err)
+	}
+	if Classify(err) != CategoryNotFound {
+		t.Fatalf("got %s", Classify(err))
+	}
+}
+
+func TestRPCError_Category(t *testing.T) {
+	cases := []struct {
+		code int
+		msg  string
+		want ErrorCategory
+	}{
+		{-32005, "limit exceeded", CategoryRateLimited},
+		{429, "Too Many Requests", CategoryRateLimited},
+		{-32000, "Unauthorized: invalid API key", CategoryAuth},
+		{-32602, "invalid argument 0", CategoryProtocol},
+		{-32000, "execution reverted", CategoryServer},
+		{-32000, "header not found", CategoryNotFound},
+	}
+	for _, c := range cases {
+		if got := (&RPCError{Code: c.code, Message: c.msg}).Category(); got != c.want {
+			t.Errorf("%d %q: got %s, want %s", c.code, c.msg, got, c.want)
+		}
+	}
+}
+
+func TestClassify_timeoutAndNetwork(t *testing.T) {
+	err := callStatus(t, func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+	}, 20*time.Millisecond)
+	if Classify(err) != CategoryTimeout {
+		t.Fatalf("slow server: got %s (%v)", Classify(err), err)
+	}
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+	_, _, err = NewClient("t", "http://"+addr, time.Second).Call(context.Background(), "eth_blockNumber")
+	if Classify(err) != CategoryNetwork {
+		t.Fatalf("refused: got %s (%v)", Classify(err), err)
+	}
+}
+
+func TestClassify_protocolAndNotFound(t *testing.T) {
+	err := callStatus(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<html>bad gateway</html>`))
+	}, time.Second)
+	if Classify(err) != CategoryProtocol {
+		t.Fatalf("html body: got %s", Classify(err))
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(echoIDs(r, `{"jsonrpc":"2.0","id":1,"result":null}`))
+	}))
+	defer srv.Close()
+	_, _, err = NewClient("t", srv.URL, time.Second).GetBlock(context.Background(), "0xffffffff")
+	var nf *NotFoundError
+	if !errors.As(err, &nf) || nf.Arg != "0xffffffff" || Classify(err) != CategoryNotFound {
+		t.Fatalf("null block: got %#v", err)
+	}
+}
+
+func TestClassify_nilAndPlain(t *testing.T) {
+	if Classify(nil) != "" {
+		t.Fatal("nil error should have no category")
+	}
+	if Classify(errors.New("x")) != CategoryUnknown {
+		t.Fatal("plain error should be unknown")
+	}
+	if Classify(context.DeadlineExceeded) != CategoryTimeout {
+		t.Fatal("deadline should be timeout")
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	if got := parseRetryAfter("Mon, 01 Jan 2024 00:00:10 GMT", now); got != 10*time.Second {
+		t.Fatalf("http date: got %v", got)
+	}
+	if got := parseRetryAfter("soon", now); got != 0 {
+		t.Fatalf("garbage: got %v", got)
+	}
+}
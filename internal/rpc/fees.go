@@ -0,0 +1,280 @@
+// =============================================================================
+// FILE: internal/rpc/fees.go
+// ROLE: Fee Market — What Each Provider Tells a Wallet to Pay
+// =============================================================================
+//
+// SYSTEM CONTEXT
+// ==============
+// A wallet never picks fees on its own: it asks its RPC provider. Three
+// methods answer, each from the node's own view of recent blocks:
+//
+//   eth_gasPrice              one number: a legacy gas price, usually the
+//                             latest base fee plus a suggested tip
+//   eth_maxPriorityFeePerGas  the suggested EIP-1559 tip alone
+//   eth_feeHistory            the raw material: per block, the base fee, how
+//                             full it was, and the tips paid at the asked
+//                             percentiles of its transactions (by gas)
+//
+// Each client's oracle is its own (geth samples recent blocks' cheapest
+// tips, others ask an upstream service), and hosted providers put caches
+// and custom oracles in front, so "what should I pay" differs by provider
+// — sometimes by an order of magnitude. FeeQuote asks all three on one
+// provider; cmd/fees compares the quotes across providers.
+//
+// eth_feeHistory
+// ==============
+// Asked for N blocks ending at newest, with reward percentiles [10,50,90]:
+//
+//   oldestBlock        newest-N+1
+//   baseFeePerGas      N+1 values: one per block, then the NEXT block's,
+//                      which the protocol fixes from the newest block alone
+//   gasUsedRatio       N values in [0,1]
+//   reward             N rows of one tip per percentile ("0x0" for an empty block)
+//   baseFeePerBlobGas  N+1 values, as baseFeePerGas (Cancun and later)
+//   blobGasUsedRatio   N values (Cancun and later)
+//
+// Nodes may return fewer blocks than asked (geth caps the count at 1024 and
+// serves only what it has), so lengths come from the response.
+// =============================================================================
+
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"time"
+)
+
+// The fee methods, in the order FeeQuote calls them.
+const (
+	MethodGasPrice       = "eth_gasPrice"
+	MethodMaxPriorityFee = "eth_maxPriorityFeePerGas"
+	MethodFeeHistory     = "eth_feeHistory"
+)
+
+// FeeMethods lists FeeQuote's methods in call order.
+var FeeMethods = []string{MethodGasPrice, MethodMaxPriorityFee, MethodFeeHistory}
+
+// FeeHistory is a decoded eth_feeHistory response (see eth_feeHistory).
+type FeeHistory struct {
+	OldestBlock      uint64
+	Percentiles      []float64    // As requested; each Rewards row follows them
+	BaseFees         []*big.Int   // One per block, then the next block's
+	GasUsedRatio     []float64    // One per block
+	Rewards          [][]*big.Int // One row per block; nil without percentiles
+	BlobBaseFees     []*big.Int   // Like BaseFees; empty before Cancun
+	BlobGasUsedRatio []float64
+}
+
+// Blocks is the number of blocks the history covers.
+func (h *FeeHistory) Blocks() int { return len(h.GasUsedRatio) }
+
+// NewestBlock is the number of the last block the history covers.
+func (h *FeeHistory) NewestBlock() uint64 {
+	if h.Blocks() == 0 {
+		return h.OldestBlock
+	}
+	return h.OldestBlock + uint64(h.Blocks()) - 1
+}
+
+// NextBaseFee is the base fee of the block after NewestBlock, or nil.
+func (h *FeeHistory) NextBaseFee() *big.Int { return last(h.BaseFees) }
+
+// NextBlobBaseFee is the blob base fee of the block after NewestBlock, or
+// nil before Cancun.
+func (h *FeeHistory) NextBlobBaseFee() *big.Int { return last(h.BlobBaseFees) }
+
+// MedianReward is the median across blocks of the tip at Percentiles[i], or
+// nil when the history has no rewards for it.
+func (h *FeeHistory) MedianReward(i int) *big.Int {
+	var tips []*big.Int
+	for _, row := range h.Rewards {
+		if i < len(row) && row[i] != nil {
+			tips = append(tips, row[i])
+		}
+	}
+	return MedianBig(tips)
+}
+
+// MeanGasUsedRatio is the average fullness of the covered blocks, in [0,1].
+func (h *FeeHistory) MeanGasUsedRatio() float64 {
+	if len(h.GasUsedRatio) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, r := range h.GasUsedRatio {
+		sum += r
+	}
+	return sum / float64(len(h.GasUsedRatio))
+}
+
+// MedianBig returns the median of values (the lower middle one for an even
+// count), or nil for none. values is not modified.
+func MedianBig(values []*big.Int) *big.Int {
+	if len(values) == 0 {
+		return nil
+	}
+	sorted := append([]*big.Int(nil), values...)
+	sort.Slice(sorted, func(a, b int) bool { return sorted[a].Cmp(sorted[b]) < 0 })
+	return sorted[(len(sorted)-1)/2]
+}
+
+func last(values []*big.Int) *big.Int {
+	if len(values) == 0 {
+		return nil
+	}
+	return values[len(values)-1]
+}
+
+// GasPrice calls eth_gasPrice.
+func (c *Client) GasPrice(ctx context.Context) (*big.Int, time.Duration, error) {
+	return c.callQuantity(ctx, MethodGasPrice)
+}
+
+// MaxPriorityFeePerGas calls eth_maxPriorityFeePerGas.
+func (c *Client) MaxPriorityFeePerGas(ctx context.Context) (*big.Int, time.Duration, error) {
+	return c.callQuantity(ctx, MethodMaxPriorityFee)
+}
+
+// callQuantity calls a parameterless method whose result is a hex quantity.
+func (c *Client) callQuantity(ctx context.Context, method string) (*big.Int, time.Duration, error) {
+	resp, latency, err := c.Call(ctx, method)
+	if err != nil {
+		return nil, latency, err
+	}
+	var hexStr string
+	if err := json.Unmarshal(resp.Result, &hexStr); err != nil {
+		return nil, latency, fmt.Errorf("unmarshal %s result: %w", method, err)
+	}
+	v, ok := hexQuantity(hexStr)
+	if !ok {
+		return nil, latency, fmt.Errorf("parse %s result %q", method, hexStr)
+	}
+	return v, latency, nil
+}
+
+// FeeHistory calls eth_feeHistory for blocks blocks ending at newest (a tag
+// or 0x-hex number), with tips at percentiles (ascending, 0 to 100; may be
+// empty).
+func (c *Client) FeeHistory(ctx context.Context, blocks uint64, newest string, percentiles []float64) (*FeeHistory, time.Duration, error) {
+	if percentiles == nil {
+		percentiles = []float64{} // The spec wants an array, not null
+	}
+	resp, latency, err := c.Call(ctx, MethodFeeHistory, fmt.Sprintf("0x%x", blocks), newest, percentiles)
+	if err != nil {
+		return nil, latency, err
+	}
+	h, err := parseFeeHistory(resp.Result, percentiles)
+	return h, latency, err
+}
+
+// parseFeeHistory decodes an eth_feeHistory result and checks its lengths
+// against each other.
+func parseFeeHistory(raw json.RawMessage, percentiles []float64) (*FeeHistory, error) {
+	var wire struct {
+		OldestBlock       string     `json:"oldestBlock"`
+		BaseFeePerGas     []string   `json:"baseFeePerGas"`
+		GasUsedRatio      []float64  `json:"gasUsedRatio"`
+		Reward            [][]string `json:"reward"`
+		BaseFeePerBlobGas []string   `json:"baseFeePerBlobGas"`
+		BlobGasUsedRatio  []float64  `json:"blobGasUsedRatio"`
+	}
+	if err := json.Unmarshal(raw, &wire); err != nil {
+		return nil, fmt.Errorf("unmarshal %s result: %w", MethodFeeHistory, err)
+	}
+	oldest, err := ParseHexUint64(wire.OldestBlock)
+	if err != nil {
+		return nil, fmt.Errorf("parse %s oldestBlock %q: %w", MethodFeeHistory, wire.OldestBlock, err)
+	}
+	h := &FeeHistory{OldestBlock: oldest, Percentiles: percentiles, GasUsedRatio: wire.GasUsedRatio, BlobGasUsedRatio: wire.BlobGasUsedRatio}
+	n := len(wire.GasUsedRatio)
+
+	if h.BaseFees, err = quantities("baseFeePerGas", wire.BaseFeePerGas); err != nil {
+		return nil, err
+	}
+	if n > 0 && len(h.BaseFees) != n+1 {
+		return nil, fmt.Errorf("%s: %d base fees for %d blocks, want %d", MethodFeeHistory, len(h.BaseFees), n, n+1)
+	}
+	if h.BlobBaseFees, err = quantities("baseFeePerBlobGas", wire.BaseFeePerBlobGas); err != nil {
+		return nil, err
+	}
+	if len(h.BlobBaseFees) > 0 && len(h.BlobBaseFees) != n+1 {
+		return nil, fmt.Errorf("%s: %d blob base fees for %d blocks, want %d", MethodFeeHistory, len(h.BlobBaseFees), n, n+1)
+	}
+
+	if len(wire.Reward) > 0 && len(wire.Reward) != n {
+		return nil, fmt.Errorf("%s: %d reward rows for %d blocks", MethodFeeHistory, len(wire.Reward), n)
+	}
+	for i, row := range wire.Reward {
+		if len(row) != len(percentiles) {
+			return nil, fmt.Errorf("%s: block %d has %d rewards for %d percentiles", MethodFeeHistory, oldest+uint64(i), len(row), len(percentiles))
+		}
+		tips, err := quantities("reward", row)
+		if err != nil {
+			return nil, err
+		}
+		h.Rewards = append(h.Rewards, tips)
+	}
+	return h, nil
+}
+
+// quantities parses a list of hex quantities from field of a response.
+func quantities(field string, hexes []string) ([]*big.Int, error) {
+	out := make([]*big.Int, len(hexes))
+	for i, s := range hexes {
+		v, ok := hexQuantity(s)
+		if !ok {
+			return nil, fmt.Errorf("parse %s %s[%d] %q", MethodFeeHistory, field, i, s)
+		}
+		out[i] = v
+	}
+	return out, nil
+}
+
+// FeeQuote is what one provider answered to the three fee methods. A field
+// is only meaningful when its method has no entry in Errors.
+type FeeQuote struct {
+	GasPrice    *big.Int
+	PriorityFee *big.Int // eth_maxPriorityFeePerGas
+	History     *FeeHistory
+
+	// Errors maps method name → failure, for the methods that failed.
+	Errors map[string]error
+}
+
+// Err returns the failure for method, or nil if it succeeded.
+func (q *FeeQuote) Err(method string) error { return q.Errors[method] }
+
+// FeeQuote calls the three fee methods one after another — eth_feeHistory
+// over the blocks blocks ending at "latest", with tips at percentiles — and
+// collects whatever succeeds. As with NodeInfo, it returns an error only
+// when every method failed, and the latency is the total across all calls.
+func (c *Client) FeeQuote(ctx context.Context, blocks uint64, percentiles []float64) (*FeeQuote, time.Duration, error) {
+	q := &FeeQuote{Errors: make(map[string]error)}
+	var total time.Duration
+
+	for _, method := range FeeMethods {
+		var latency time.Duration
+		var err error
+		switch method {
+		case MethodGasPrice:
+			q.GasPrice, latency, err = c.GasPrice(ctx)
+		case MethodMaxPriorityFee:
+			q.PriorityFee, latency, err = c.MaxPriorityFeePerGas(ctx)
+		case MethodFeeHistory:
+			q.History, latency, err = c.FeeHistory(ctx, blocks, "latest", percentiles)
+		}
+		total += latency
+		if err != nil {
+			q.Errors[method] = err
+		}
+	}
+
+	if len(q.Errors) == len(FeeMethods) {
+		return nil, total, q.Errors[MethodGasPrice]
+	}
+	return q, total, nil
+}
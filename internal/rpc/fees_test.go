@@ -0,0 +1,97 @@
+package rpc
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+)
+
+const feeHistoryReply = `{
+	"oldestBlock": "0x64",
+	"baseFeePerGas": ["0x3b9aca00", "0x3b9aca01", "0x77359400", "0x77359401"],
+	"gasUsedRatio": [0.5, 0.25, 0.75],
+	"reward": [["0x1", "0x64"], ["0x2", "0xc8"], ["0x0", "0x0"]],
+	"baseFeePerBlobGas": ["0x1", "0x1", "0x2", "0x3"],
+	"blobGasUsedRatio": [0, 0.5, 1]
+}`
+
+func TestClient_FeeQuote(t *testing.T) {
+	srv := nodeServer(t, map[string]string{
+		MethodGasPrice:   `"0x77359400"`,
+		MethodFeeHistory: feeHistoryReply,
+		// eth_maxPriorityFeePerGas disabled, as on some older clients
+	})
+	defer srv.Close()
+
+	q, _, err := NewClient("t", srv.URL, 2*time.Second).FeeQuote(context.Background(), 3, []float64{10, 90})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q.GasPrice.Int64() != 2_000_000_000 {
+		t.Errorf("GasPrice = %v", q.GasPrice)
+	}
+	if q.PriorityFee != nil || q.Err(MethodMaxPriorityFee) == nil {
+		t.Errorf("disabled eth_maxPriorityFeePerGas: fee %v, err %v", q.PriorityFee, q.Err(MethodMaxPriorityFee))
+	}
+
+	h := q.History
+	if h.Blocks() != 3 || h.OldestBlock != 100 || h.NewestBlock() != 102 {
+		t.Fatalf("blocks %d, oldest %d, newest %d", h.Blocks(), h.OldestBlock, h.NewestBlock())
+	}
+	if h.NextBaseFee().Int64() != 2_000_000_001 || h.NextBlobBaseFee().Int64() != 3 {
+		t.Errorf("next base fee %v, next blob base fee %v", h.NextBaseFee(), h.NextBlobBaseFee())
+	}
+	if h.MedianReward(0).Int64() != 1 || h.MedianReward(1).Int64() != 100 {
+		t.Errorf("median rewards %v, %v", h.MedianReward(0), h.MedianReward(1))
+	}
+	if h.MedianReward(2) != nil {
+		t.Error("a percentile that was not asked has no median")
+	}
+	if r := h.MeanGasUsedRatio(); r != 0.5 {
+		t.Errorf("MeanGasUsedRatio = %v", r)
+	}
+}
+
+func TestClient_FeeQuote_allFail(t *testing.T) {
+	srv := nodeServer(t, nil)
+	defer srv.Close()
+	if _, _, err := NewClient("t", srv.URL, 2*time.Second).FeeQuote(context.Background(), 3, nil); err == nil {
+		t.Fatal("a provider answering no fee method should fail")
+	}
+}
+
+func TestParseFeeHistory_preCancunAndErrors(t *testing.T) {
+	h, err := parseFeeHistory([]byte(`{"oldestBlock":"0x1","baseFeePerGas":["0x1","0x2"],"gasUsedRatio":[0.5]}`), []float64{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h.NextBlobBaseFee() != nil || h.Rewards != nil || h.MedianReward(0) != nil {
+		t.Errorf("pre-Cancun history without rewards = %+v", h)
+	}
+
+	for _, bad := range []struct{ raw, want string }{
+		{`{"oldestBlock":"0x1","baseFeePerGas":["0x1"],"gasUsedRatio":[0.5]}`, "1 base fees for 1 blocks"},
+		{`{"oldestBlock":"0x1","baseFeePerGas":["0x1","0x2"],"gasUsedRatio":[0.5],"reward":[["0x1"]]}`, "1 rewards for 2 percentiles"},
+		{`{"oldestBlock":"0x1","baseFeePerGas":["0x1","zz"],"gasUsedRatio":[0.5]}`, `baseFeePerGas[1] "zz"`},
+		{`{"oldestBlock":"nope"}`, "oldestBlock"},
+	} {
+		if _, err := parseFeeHistory([]byte(bad.raw), []float64{10, 90}); err == nil || !strings.Contains(err.Error(), bad.want) {
+			t.Errorf("parseFeeHistory(%s) = %v, want %q", bad.raw, err, bad.want)
+		}
+	}
+}
+
+func TestMedianBig(t *testing.T) {
+	if MedianBig(nil) != nil {
+		t.Fatal("median of nothing should be nil")
+	}
+	values := []*big.Int{big.NewInt(9), big.NewInt(1), big.NewInt(5), big.NewInt(3)}
+	if m := MedianBig(values); m.Int64() != 3 {
+		t.Fatalf("median = %v, want the lower middle 3", m)
+	}
+	if values[0].Int64() != 9 {
+		t.Fatal("MedianBig reordered its input")
+	}
+}
@@ -241,6 +241,11 @@ func FormatNumber(n uint64) string {
 // The gwei.Float64() at the end converts the arbitrary-precision big.Float
 // to a standard float64 for formatting. This loses precision for very large
 // numbers, but base fees are small enough that float64 is adequate.
+//
+// Below 0.01 gwei two decimals would print a real fee as "0.00 gwei": blob
+// base fees sit at 1 wei for long stretches, and priority fee suggestions
+// on quiet chains are a few hundred thousand wei. Those are printed with as
+// many decimals as they need instead — "0.000000001 gwei", "0.0004 gwei".
 func FormatGwei(wei *big.Int) string {
 	// If wei is nil, this block has no base fee (pre-EIP-1559).
 	// Return an em-dash to indicate "not applicable."
@@ -251,5 +256,36 @@ func FormatGwei(wei *big.Int) string {
 	// Each new() allocates a fresh big.Float — we don't modify the input.
 	gwei := new(big.Float).Quo(new(big.Float).SetInt(wei), big.NewFloat(1e9))
 	f, _ := gwei.Float64()
+	if f > 0 && f < 0.01 {
+		return strconv.FormatFloat(f, 'f', -1, 64) + " gwei"
+	}
 	return fmt.Sprintf("%.2f gwei", f)
 }
+
+// FormatEther converts a wei amount to ether with up to six decimals, e.g.
+// "1.5 ETH" or "0.000042 ETH".
+//
+// Unlike FormatGwei this works in exact integer arithmetic: transfer values
+// routinely exceed float64's 53 bits of precision (1000 ETH is 10^21 wei),
+// and a value display that rounds "1.000000000000000001" to "1" is fine but
+// one that prints "0.99999999" is not. Non-zero amounts below the sixth
+// decimal render as "<0.000001 ETH" so dust never looks like zero.
+func FormatEther(wei *big.Int) string {
+	if wei == nil {
+		return "—"
+	}
+	if wei.Sign() == 0 {
+		return "0 ETH"
+	}
+
+	// Work in micro-ether (10^12 wei): whole = µETH / 10^6, frac = µETH % 10^6.
+	micro := new(big.Int).Quo(wei, big.NewInt(1e12))
+	if micro.Sign() == 0 {
+		return "<0.000001 ETH"
+	}
+	whole, frac := new(big.Int).QuoRem(micro, big.NewInt(1e6), new(big.Int))
+	if frac.Sign() == 0 {
+		return whole.String() + " ETH"
+	}
+	return fmt.Sprintf("%s.%s ETH", whole, strings.TrimRight(fmt.Sprintf("%06d", frac.Int64()), "0"))
+}
@@ -41,3 +41,43 @@ func TestFormatGwei_nil(t *testing.T) {
 		t.Fatalf("got %q", got)
 	}
 }
+
+func TestFormatGwei(t *testing.T) {
+	tests := []struct {
+		wei  string
+		want string
+	}{
+		{"0x0", "0.00 gwei"},
+		{"0x59682f000", "24.00 gwei"}, // 24 × 10^9
+		{"0x1dcd6500", "0.50 gwei"},
+		{"0x61a80", "0.0004 gwei"}, // 400,000 wei
+		{"0x1", "0.000000001 gwei"},
+	}
+	for _, tc := range tests {
+		if got := FormatGwei(ParseHexBigInt(tc.wei)); got != tc.want {
+			t.Errorf("FormatGwei(%s) = %q want %q", tc.wei, got, tc.want)
+		}
+	}
+}
+
+func TestFormatEther(t *testing.T) {
+	tests := []struct {
+		wei  string
+		want string
+	}{
+		{"0x0", "0 ETH"},
+		{"0xde0b6b3a7640000", "1 ETH"},       // 10^18
+		{"0x14d1120d7b160000", "1.5 ETH"},    // 1.5 × 10^18
+		{"0x2632e314a000", "0.000042 ETH"},   // 4.2 × 10^13
+		{"0x3635c9adc5dea00000", "1000 ETH"}, // 10^21, beyond float64 precision
+		{"0x1", "<0.000001 ETH"},
+	}
+	for _, tc := range tests {
+		if got := FormatEther(ParseHexBigInt(tc.wei)); got != tc.want {
+			t.Fatalf("FormatEther(%s) = %q want %q", tc.wei, got, tc.want)
+		}
+	}
+	if got := FormatEther(nil); got != "—" {
+		t.Fatalf("nil: got %q", got)
+	}
+}
@@ -0,0 +1,239 @@
+// =============================================================================
+// FILE: internal/rpc/header.go
+// ROLE: Header Verification — Recompute the Block Hash Instead of Trusting It
+// =============================================================================
+//
+// SYSTEM CONTEXT
+// ==============
+// `snapshot` compares the hashes providers return for the same block, and
+// `block` prints one. Both used to take the "hash" field on faith. But the
+// hash is not an opaque ID — it is DEFINED as
+//
+//   hash = keccak256(rlp([parentHash, sha3Uncles, miner, ..., nonce, ...]))
+//
+// so every header a provider sends can be checked against itself. A
+// mismatch means the provider (or a cache or proxy in front of it) served a
+// header whose fields do not belong to the hash it claims: a stale or
+// corrupted cache entry, fields mixed from two blocks during a reorg, or a
+// node that does not know a fork's new header field yet.
+//
+// HEADER LAYOUTS, FRONTIER THROUGH PRAGUE
+// =======================================
+// The header started with 15 fields and forks have only ever APPENDED:
+//
+//   Frontier … Berlin   parentHash sha3Uncles miner stateRoot
+//                       transactionsRoot receiptsRoot logsBloom difficulty
+//                       number gasLimit gasUsed timestamp extraData mixHash
+//                       nonce                                          (15)
+//   London … Paris      + baseFeePerGas                    EIP-1559    (16)
+//   Shanghai            + withdrawalsRoot                  EIP-4895    (17)
+//   Cancun              + blobGasUsed excessBlobGas
+//                         parentBeaconBlockRoot            EIP-4844/4788 (20)
+//   Prague              + requestsHash                     EIP-7685    (21)
+//
+// The Merge (Paris) added no field — it redefined mixHash as prevRandao and
+// pinned difficulty and nonce to zero. The layout is therefore read off
+// which optional fields the JSON carries; a later field without an earlier
+// one is not a layout any fork ever produced, and is reported as such.
+//
+// ENCODING
+// ========
+// Hashes, the address, the bloom and the nonce are fixed-size byte strings;
+// every other number is an RLP integer (minimal big-endian, 0 = empty). See
+// rlp.go for the rules and keccak.go for the hash.
+// =============================================================================
+
+package rpc
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// headerExtension is one field a fork appended to the header.
+type headerExtension struct {
+	fork  string
+	field string
+	value func(*Block) string
+}
+
+// headerExtensions lists the optional fields in header order. A layout is
+// valid only if the present ones form a prefix ending on a fork boundary.
+var headerExtensions = []headerExtension{
+	{"London", "baseFeePerGas", func(b *Block) string { return b.BaseFeePerGas }},
+	{"Shanghai", "withdrawalsRoot", func(b *Block) string { return b.WithdrawalsRoot }},
+	{"Cancun", "blobGasUsed", func(b *Block) string { return b.BlobGasUsed }},
+	{"Cancun", "excessBlobGas", func(b *Block) string { return b.ExcessBlobGas }},
+	{"Cancun", "parentBeaconBlockRoot", func(b *Block) string { return b.ParentBeaconBlockRoot }},
+	{"Prague", "requestsHash", func(b *Block) string { return b.RequestsHash }},
+}
+
+// HashCheck is the result of recomputing one block's hash from its header.
+type HashCheck struct {
+	Layout   string // Newest fork whose header layout the fields match, e.g. "Cancun"; "pre-London" for the original 15 fields
+	Claimed  string // Hash the provider returned
+	Computed string // keccak256(rlp(header)), lowercase 0x-hex; "" when Err is set
+	Err      error  // Why the header could not be encoded (missing or malformed field)
+}
+
+// OK reports whether the header hashed to exactly the claimed hash.
+func (h HashCheck) OK() bool {
+	return h.Err == nil && strings.EqualFold(h.Claimed, h.Computed)
+}
+
+// Problem describes a failed check in one line, or returns "" when OK.
+func (h HashCheck) Problem() string {
+	switch {
+	case h.Err != nil:
+		return "cannot verify header: " + h.Err.Error()
+	case !h.OK():
+		return fmt.Sprintf("header hashes to %s, not the returned %s", h.Computed, h.Claimed)
+	}
+	return ""
+}
+
+// VerifyBlockHash recomputes b's hash from its header fields and compares it
+// with the hash the provider returned.
+func VerifyBlockHash(b *Block) HashCheck {
+	check := HashCheck{Claimed: b.Hash}
+	if b.Hash == "" {
+		check.Err = fmt.Errorf("no hash returned (pending block?)")
+		return check
+	}
+	enc, layout, err := encodeHeader(b)
+	check.Layout = layout
+	if err != nil {
+		check.Err = err
+		return check
+	}
+	check.Computed = "0x" + hex.EncodeToString(Keccak256(enc))
+	return check
+}
+
+// encodeHeader returns the RLP encoding of b's header and the name of its
+// layout.
+func encodeHeader(b *Block) ([]byte, string, error) {
+	d := fieldDecoder{}
+	items := [][]byte{
+		d.fixed("parentHash", b.ParentHash, 32),
+		d.fixed("sha3Uncles", b.Sha3Uncles, 32),
+		d.fixed("miner", b.Miner, 20),
+		d.fixed("stateRoot", b.StateRoot, 32),
+		d.fixed("transactionsRoot", b.TransactionsRoot, 32),
+		d.fixed("receiptsRoot", b.ReceiptsRoot, 32),
+		d.fixed("logsBloom", b.LogsBloom, 256),
+		d.quantity("difficulty", b.Difficulty),
+		d.quantity("number", b.Number),
+		d.quantity("gasLimit", b.GasLimit),
+		d.quantity("gasUsed", b.GasUsed),
+		d.quantity("timestamp", b.Timestamp),
+		d.bytes("extraData", b.ExtraData),
+		d.fixed("mixHash", b.MixHash, 32),
+		d.fixed("nonce", b.Nonce, 8),
+	}
+
+	// Optional fields: take the present prefix, then make sure nothing
+	// follows a gap and the prefix ends where a fork's additions end.
+	layout := "pre-London"
+	n := 0
+	for n < len(headerExtensions) && headerExtensions[n].value(b) != "" {
+		n++
+	}
+	for _, ext := range headerExtensions[n:] {
+		if ext.value(b) != "" {
+			return nil, layout, fmt.Errorf("header has %s but no %s", ext.field, headerExtensions[n].field)
+		}
+	}
+	if n > 0 {
+		layout = headerExtensions[n-1].fork
+		if n < len(headerExtensions) && headerExtensions[n].fork == layout {
+			return nil, layout, fmt.Errorf("%s header is missing %s", layout, headerExtensions[n].field)
+		}
+	}
+	for _, ext := range headerExtensions[:n] {
+		v := ext.value(b)
+		switch ext.field {
+		case "withdrawalsRoot", "parentBeaconBlockRoot", "requestsHash":
+			items = append(items, d.fixed(ext.field, v, 32))
+		default:
+			items = append(items, d.quantity(ext.field, v))
+		}
+	}
+
+	if d.err != nil {
+		return nil, layout, d.err
+	}
+	return rlpList(items...), layout, nil
+}
+
+// fieldDecoder turns hex JSON fields into encoded RLP items, keeping the
+// first error so callers (encodeHeader, encodeTransaction, ...) can build an
+// item list in one expression and check for failure once.
+type fieldDecoder struct {
+	err error
+}
+
+// bytes decodes a variable-length byte string.
+func (d *fieldDecoder) bytes(field, s string) []byte {
+	raw, err := decodeHexField(field, s)
+	if err != nil {
+		d.fail(err)
+		return nil
+	}
+	return rlpBytes(raw)
+}
+
+// fixed decodes a byte string that must be exactly size bytes.
+func (d *fieldDecoder) fixed(field, s string, size int) []byte {
+	raw, err := decodeHexField(field, s)
+	if err == nil && len(raw) != size {
+		err = fmt.Errorf("%s is %d bytes, want %d", field, len(raw), size)
+	}
+	if err != nil {
+		d.fail(err)
+		return nil
+	}
+	return rlpBytes(raw)
+}
+
+// quantity decodes a hex number ("0x0", "0x1b4").
+func (d *fieldDecoder) quantity(field, s string) []byte {
+	v, ok := hexQuantity(s)
+	if !ok {
+		d.fail(fmt.Errorf("%s %q is not a hex quantity", field, s))
+		return nil
+	}
+	return rlpBig(v)
+}
+
+func (d *fieldDecoder) fail(err error) {
+	if d.err == nil {
+		d.err = err
+	}
+}
+
+// decodeHexField decodes a 0x-prefixed hex byte string. A missing field is
+// an error: every header field is part of the hash.
+func decodeHexField(field, s string) ([]byte, error) {
+	if s == "" {
+		return nil, fmt.Errorf("missing %s", field)
+	}
+	raw, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", field, err)
+	}
+	return raw, nil
+}
+
+// hexQuantity parses a non-negative hex number. Unlike ParseHexBigInt it
+// reports malformed input instead of returning zero.
+func hexQuantity(s string) (*big.Int, bool) {
+	digits := strings.TrimPrefix(s, "0x")
+	v, ok := new(big.Int).SetString(digits, 16)
+	if digits == "" || !ok || v.Sign() < 0 {
+		return nil, false
+	}
+	return v, true
+}
@@ -0,0 +1,149 @@
+package rpc
+
+import (
+	"strings"
+	"testing"
+)
+
+const (
+	zeroHash   = "0x0000000000000000000000000000000000000000000000000000000000000000"
+	emptyTrie  = "0x56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421"
+	emptyOmmer = "0x1dcc4de8dec75d7aab85b567b6ccd41ad312451b948a7413f0a142fd40d49347"
+)
+
+var zeroBloom = "0x" + strings.Repeat("00", 256)
+
+// mainnetGenesis and mainnetBlock1 are the first two Ethereum mainnet
+// headers, as eth_getBlockByNumber returns them.
+func mainnetGenesis() *Block {
+	return &Block{
+		Number: "0x0", Hash: "0xd4e56740f876aef8c010b86a40d5f56745a118d0906a34e69aec8c0db1cb8fa3",
+		ParentHash: zeroHash, Sha3Uncles: emptyOmmer, Miner: "0x0000000000000000000000000000000000000000",
+		StateRoot:        "0xd7f8974fb5ac78d9ac099b9ad5018bedc2ce0a72dad1827a1709da30580f0544",
+		TransactionsRoot: emptyTrie, ReceiptsRoot: emptyTrie, LogsBloom: zeroBloom,
+		Difficulty: "0x400000000", GasLimit: "0x1388", GasUsed: "0x0", Timestamp: "0x0",
+		ExtraData: "0x11bbe8db4e347b4e8c937c1c8370e4b5ed33adb3db69cbdb7a38e1e50b1b82fa",
+		MixHash:   zeroHash, Nonce: "0x0000000000000042",
+	}
+}
+
+func mainnetBlock1() *Block {
+	return &Block{
+		Number: "0x1", Hash: "0x88e96d4537bea4d9c05d12549907b32561d3bf31f45aae734cdc119f13406cb6",
+		ParentHash: "0xd4e56740f876aef8c010b86a40d5f56745a118d0906a34e69aec8c0db1cb8fa3",
+		Sha3Uncles: emptyOmmer, Miner: "0x05a56e2d52c817161883f50c441c3228cfe54d9f",
+		StateRoot:        "0xd67e4d450343046425ae4271474353857ab860dbc0a1dde64b41b5cd3a532bf3",
+		TransactionsRoot: emptyTrie, ReceiptsRoot: emptyTrie, LogsBloom: zeroBloom,
+		Difficulty: "0x3ff800000", GasLimit: "0x1388", GasUsed: "0x0", Timestamp: "0x55ba4224",
+		ExtraData: "0x476574682f76312e302e302f6c696e75782f676f312e342e32",
+		MixHash:   "0x969b900de27b6ac6a67742365dd65f55a0526c41fd18e1b16f1a1215c2e66f59",
+		Nonce:     "0x539bd4979fef1ec4",
+	}
+}
+
+func TestVerifyBlockHash_mainnet(t *testing.T) {
+	for _, b := range []*Block{mainnetGenesis(), mainnetBlock1()} {
+		c := VerifyBlockHash(b)
+		if !c.OK() || c.Layout != "pre-London" || c.Problem() != "" {
+			t.Fatalf("block %s: %+v", b.Number, c)
+		}
+	}
+}
+
+// rlpListLen counts the items of an encoded RLP list, so layout tests can
+// check how many fields a header encoded to.
+func rlpListLen(t *testing.T, enc []byte) int {
+	t.Helper()
+	itemLen := func(b []byte) (head, size int) {
+		switch p := b[0]; {
+		case p < 0x80:
+			return 0, 1
+		case p <= 0xb7:
+			return 1, int(p - 0x80)
+		case p < 0xc0:
+			n := int(p - 0xb7)
+			for _, x := range b[1 : 1+n] {
+				size = size<<8 | int(x)
+			}
+			return 1 + n, size
+		case p <= 0xf7:
+			return 1, int(p - 0xc0)
+		default:
+			n := int(p - 0xf7)
+			for _, x := range b[1 : 1+n] {
+				size = size<<8 | int(x)
+			}
+			return 1 + n, size
+		}
+	}
+	head, size := itemLen(enc)
+	if head+size != len(enc) {
+		t.Fatalf("list length %d+%d, encoding is %d bytes", head, size, len(enc))
+	}
+	count := 0
+	for rest := enc[head:]; len(rest) > 0; count++ {
+		h, s := itemLen(rest)
+		rest = rest[h+s:]
+	}
+	return count
+}
+
+func TestEncodeHeader_layouts(t *testing.T) {
+	b := mainnetBlock1()
+	steps := []struct {
+		set    func()
+		layout string
+		fields int
+	}{
+		{func() {}, "pre-London", 15},
+		{func() { b.BaseFeePerGas = "0x3b9aca00" }, "London", 16},
+		{func() { b.WithdrawalsRoot = emptyTrie }, "Shanghai", 17},
+		{func() { b.BlobGasUsed, b.ExcessBlobGas, b.ParentBeaconBlockRoot = "0x20000", "0x0", zeroHash }, "Cancun", 20},
+		{func() { b.RequestsHash = emptyOmmer }, "Prague", 21},
+	}
+	for _, s := range steps {
+		s.set()
+		enc, layout, err := encodeHeader(b)
+		if err != nil || layout != s.layout {
+			t.Fatalf("%s: layout %q, err %v", s.layout, layout, err)
+		}
+		if n := rlpListLen(t, enc); n != s.fields {
+			t.Fatalf("%s: %d fields, want %d", s.layout, n, s.fields)
+		}
+	}
+}
+
+func TestVerifyBlockHash_problems(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(b *Block)
+		want   string
+	}{
+		{"tampered field", func(b *Block) { b.Timestamp = "0x55ba4225" }, "header hashes to 0x"},
+		{"wrong hash", func(b *Block) { b.Hash = zeroHash }, "not the returned " + zeroHash},
+		{"missing field", func(b *Block) { b.MixHash = "" }, "missing mixHash"},
+		{"short hash field", func(b *Block) { b.StateRoot = "0x1234" }, "stateRoot is 2 bytes, want 32"},
+		{"bad quantity", func(b *Block) { b.GasUsed = "0xzz" }, "gasUsed"},
+		{"gap", func(b *Block) { b.WithdrawalsRoot = emptyTrie }, "withdrawalsRoot but no baseFeePerGas"},
+		{"partial fork", func(b *Block) {
+			b.BaseFeePerGas, b.WithdrawalsRoot, b.BlobGasUsed = "0x7", emptyTrie, "0x0"
+		}, "Cancun header is missing excessBlobGas"},
+		{"pending", func(b *Block) { b.Hash = "" }, "no hash returned"},
+	}
+	for _, tt := range tests {
+		b := mainnetBlock1()
+		tt.mutate(b)
+		c := VerifyBlockHash(b)
+		if c.OK() || !strings.Contains(c.Problem(), tt.want) {
+			t.Errorf("%s: problem %q, want %q", tt.name, c.Problem(), tt.want)
+		}
+	}
+
+	// Hashes compare case-insensitively: checksummed or upper-case hex from
+	// a provider is the same hash.
+	b := mainnetBlock1()
+	b.Hash = "0x" + strings.ToUpper(b.Hash[2:])
+	if c := VerifyBlockHash(b); !c.OK() {
+		t.Fatalf("upper-case hash: %s", c.Problem())
+	}
+}
@@ -0,0 +1,131 @@
+// =============================================================================
+// FILE: internal/rpc/ids.go
+// ROLE: Request Identity — Unique IDs and Response ID Validation
+// =============================================================================
+//
+// SYSTEM CONTEXT
+// ==============
+// JSON-RPC pairs every response with its request through the "id" field.
+// Over plain HTTP it is tempting to ignore it — one POST, one reply — but
+// between us and the node there are usually load balancers, caches and
+// connection-pooling proxies. A buggy one can hand us a response that was
+// meant for a DIFFERENT request (another method, another block, another
+// customer). Without ID checking that answer is silently accepted as ours.
+//
+// So every call gets a fresh ID, and every reply must echo it:
+//
+//   Client                                    Provider / proxy
+//   ──── {"id": 2718281829, "method": ...} ──▶
+//   ◀─── {"id": 2718281829, "result": ...} ── ✓ accepted
+//   ◀─── {"id": 1,          "result": ...} ── ✗ *IDMismatchError
+//
+// ID ALLOCATION
+// =============
+// Each Client and WSClient starts its counter at a random 31-bit base and increments it
+// atomically. The random base matters: if every client started at 1, two
+// monitors behind the same misbehaving proxy would issue identical IDs and a
+// crossed response would still "match".
+//
+// ACCEPTED ID FORMS
+// =================
+// The spec allows numbers and strings. We always SEND numbers, but accept
+// any echo that denotes the same value: 42, "42", or "0x2a". A null id is
+// only legitimate on an error response (the server could not read our id);
+// on a success response it is a mismatch.
+// =============================================================================
+
+package rpc
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// idSource hands out unique request IDs for one client.
+type idSource struct {
+	next atomic.Uint64
+}
+
+// newIDSource returns a source whose first ID is a random value in
+// [1, 2^31). Staying below 2^31 keeps IDs exact even for JavaScript-based
+// proxies that decode JSON numbers as float64.
+func newIDSource() *idSource {
+	var b [4]byte
+	_, _ = rand.Read(b[:]) // crypto/rand never fails on supported platforms
+	s := &idSource{}
+	s.next.Store(uint64(binary.BigEndian.Uint32(b[:])>>1) + 1)
+	return s
+}
+
+// take returns the next ID. Safe for concurrent use.
+func (s *idSource) take() uint64 {
+	return s.next.Add(1) - 1
+}
+
+// IDMismatchError means a response carried an ID other than the one we
+// sent — the reply belongs to some other request. It is a protocol error
+// (Category() == CategoryProtocol), but has its own type because it signals
+// a correctness problem in the provider's infrastructure rather than a
+// malformed reply.
+//
+// Sent is 0 for a WebSocket response that matched no pending call: IDs
+// start at 1, and there is no request to name.
+type IDMismatchError struct {
+	Sent uint64 // The ID in our request; 0 = none was waiting
+	Got  string // The raw ID the provider returned ("null" if absent)
+}
+
+func (e *IDMismatchError) Error() string {
+	if e.Sent == 0 {
+		return fmt.Sprintf("response id mismatch: got %s, no request pending", e.Got)
+	}
+	return fmt.Sprintf("response id mismatch: sent %d, got %s", e.Sent, e.Got)
+}
+func (e *IDMismatchError) Category() ErrorCategory { return CategoryProtocol }
+
+// parseID decodes a response "id" in any accepted form. ok is false for
+// null, missing, or non-numeric IDs.
+func parseID(raw json.RawMessage) (id uint64, ok bool) {
+	s := strings.TrimSpace(string(raw))
+	if s == "" || s == "null" {
+		return 0, false
+	}
+	if s[0] == '"' {
+		var str string
+		if json.Unmarshal(raw, &str) != nil {
+			return 0, false
+		}
+		s = str
+		if strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X") {
+			n, err := strconv.ParseUint(s[2:], 16, 64)
+			return n, err == nil
+		}
+	}
+	n, err := strconv.ParseUint(s, 10, 64)
+	return n, err == nil
+}
+
+// checkID validates that resp answers the request with ID sent.
+//
+// An error response with a null ID is accepted: per JSON-RPC 2.0 that is
+// how a server reports a request it could not parse far enough to read the
+// ID, and the RPCError itself is the more useful thing to return.
+func checkID(resp *Response, sent uint64) error {
+	got, ok := parseID(resp.ID)
+	if ok && got == sent {
+		return nil
+	}
+	if !ok && resp.Error != nil && isNullResult(resp.ID) {
+		return nil
+	}
+	raw := strings.TrimSpace(string(resp.ID))
+	if raw == "" {
+		raw = "null"
+	}
+	return &IDMismatchError{Sent: sent, Got: raw}
+}
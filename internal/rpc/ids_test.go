@@ -0,0 +1,87 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestIDSource_uniqueAndBounded(t *testing.T) {
+	s := newIDSource()
+	first := s.take()
+	if first == 0 || first >= 1<<31 {
+		t.Fatalf("first id %d out of range", first)
+	}
+	if next := s.take(); next != first+1 {
+		t.Fatalf("ids not sequential: %d then %d", first, next)
+	}
+}
+
+func TestParseID_forms(t *testing.T) {
+	cases := map[string]struct {
+		id uint64
+		ok bool
+	}{
+		`42`:     {42, true},
+		`"42"`:   {42, true},
+		`"0x2a"`: {42, true},
+		`null`:   {0, false},
+		``:       {0, false},
+		`"abc"`:  {0, false},
+		`-1`:     {0, false},
+	}
+	for raw, want := range cases {
+		id, ok := parseID(json.RawMessage(raw))
+		if id != want.id || ok != want.ok {
+			t.Errorf("parseID(%s) = %d,%v want %d,%v", raw, id, ok, want.id, want.ok)
+		}
+	}
+}
+
+func TestCheckID(t *testing.T) {
+	if err := checkID(&Response{ID: json.RawMessage(`"7"`)}, 7); err != nil {
+		t.Fatalf("string echo should match: %v", err)
+	}
+	if err := checkID(&Response{ID: json.RawMessage(`null`), Error: &RPCError{Code: -32700}}, 7); err != nil {
+		t.Fatalf("null id on an error response is allowed: %v", err)
+	}
+	var mm *IDMismatchError
+	if err := checkID(&Response{ID: json.RawMessage(`null`)}, 7); !errors.As(err, &mm) || mm.Got != "null" {
+		t.Fatalf("null id on a result should mismatch: %v", err)
+	}
+}
+
+func TestClient_Call_idMismatch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":"0x10"}`))
+	}))
+	defer srv.Close()
+
+	c := NewClient("t", srv.URL, time.Second)
+	c.ids.next.Store(100)
+	_, _, err := c.Call(context.Background(), "eth_blockNumber")
+	var mm *IDMismatchError
+	if !errors.As(err, &mm) || mm.Sent != 100 || mm.Got != "1" || Classify(err) != CategoryProtocol {
+		t.Fatalf("want id mismatch, got %v", err)
+	}
+}
+
+func TestClient_CallBatch_foreignIDIsMismatch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(echoIDs(r, `[{"jsonrpc":"2.0","id":1,"result":"0x1"},{"jsonrpc":"2.0","id":"0xdead","result":"0x2"}]`))
+	}))
+	defer srv.Close()
+
+	res, _, err := NewClient("t", srv.URL, time.Second).CallBatch(context.Background(), []BatchElem{{Method: "a"}, {Method: "b"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var mm *IDMismatchError
+	if res[0].Error != nil || !errors.As(res[1].Error, &mm) || mm.Got != `"0xdead"` {
+		t.Fatalf("results: %+v / %+v", res[0], res[1])
+	}
+}
@@ -0,0 +1,179 @@
+// =============================================================================
+// FILE: internal/rpc/ipc.go
+// ROLE: IPC Transport — JSON-RPC Over a Local Unix Socket
+// =============================================================================
+//
+// SYSTEM CONTEXT
+// ==============
+// geth, reth, nethermind and erigon can expose their JSON-RPC API on a Unix
+// domain socket (geth.ipc, reth.ipc). On the same machine that is the
+// lowest-latency path to the node there is: no TCP, no TLS, no HTTP
+// framing. Putting it next to remote providers in `test`, `snapshot` or
+// `monitor` gives the clean baseline every other row is compared against.
+//
+// A provider URL of the form
+//
+//   ipc:///var/lib/geth/geth.ipc
+//
+// makes NewClient speak to the socket instead of HTTP. Everything above
+// post() — Call, CallBatch, id matching, error types, latency, WarmUp — is
+// unchanged; only the exchange itself differs:
+//
+//   HTTP:  POST body ──▶ status line, headers ──▶ body
+//   IPC:   body + "\n" ──▶ one JSON value back on the same stream
+//
+// The node writes exactly one JSON value (object, or array for a batch) per
+// request, followed by a newline. A json.Decoder reads one value at a time,
+// so the newline is not required on the way back.
+//
+// CONNECTIONS AND POLICIES
+// ========================
+// Requests on one socket are serialized (write, then read the reply) so
+// replies can never be paired with the wrong request. Like HTTP keep-alive,
+// the connection stays open between calls — unless the transport policy is
+// cold, in which case every call dials the socket anew. http1/http2 only
+// mean "keep the connection"; there is no HTTP on a socket. Any I/O error
+// closes the connection and the next call redials.
+//
+// ERRORS AND TIMING
+// =================
+// Dial and I/O failures are *TransportError, with deadline failures
+// classified as timeouts, exactly as for HTTP. The client timeout and the
+// context deadline both bound the exchange. With WithTiming, Connect is the
+// socket dial, TTFB runs from request written to first reply byte, and
+// Proto is "ipc". There is no DNS or TLS phase.
+// =============================================================================
+
+package rpc
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ipcScheme is the URL prefix that selects the IPC transport.
+const ipcScheme = "ipc://"
+
+// IsIPC reports whether url names a Unix socket (ipc:///path).
+func IsIPC(url string) bool { return strings.HasPrefix(url, ipcScheme) }
+
+// ipcTransport is one client's connection to a node's IPC socket.
+type ipcTransport struct {
+	path      string        // Filesystem path of the socket
+	timeout   time.Duration // Per-exchange limit, like http.Client.Timeout
+	keepAlive bool          // false under the cold policy: dial for every call
+
+	mu   sync.Mutex // Serializes exchanges and guards conn
+	conn net.Conn
+	dec  *json.Decoder
+}
+
+func newIPCTransport(url string, timeout time.Duration, policy Policy) *ipcTransport {
+	return &ipcTransport{
+		path:      strings.TrimPrefix(url, ipcScheme),
+		timeout:   timeout,
+		keepAlive: policy != PolicyCold,
+	}
+}
+
+// roundTrip writes one request and reads one reply. tracer may be nil.
+func (t *ipcTransport) roundTrip(ctx context.Context, body []byte, tracer *phaseTracer) ([]byte, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	// The earlier of the client timeout and the context deadline bounds
+	// the whole exchange, dial included. Zero means no limit.
+	var deadline time.Time
+	if t.timeout > 0 {
+		deadline = time.Now().Add(t.timeout)
+	}
+	if d, ok := ctx.Deadline(); ok && (deadline.IsZero() || d.Before(deadline)) {
+		deadline = d
+	}
+
+	if t.conn == nil {
+		dialer := net.Dialer{Deadline: deadline}
+		start := time.Now()
+		conn, err := dialer.DialContext(ctx, "unix", t.path)
+		if err != nil {
+			return nil, &TransportError{Err: fmt.Errorf("dial ipc %s: %w", t.path, err)}
+		}
+		if tracer != nil {
+			tracer.mu.Lock()
+			tracer.connectStart, tracer.connectDone = start, time.Now()
+			tracer.mu.Unlock()
+		}
+		t.conn = conn
+		t.dec = json.NewDecoder(bufio.NewReader(conn))
+	} else if tracer != nil {
+		tracer.mu.Lock()
+		tracer.reused = true
+		tracer.mu.Unlock()
+	}
+	if tracer != nil {
+		tracer.gotProto("ipc")
+	}
+	if !t.keepAlive {
+		defer t.close()
+	}
+
+	// Cancelling ctx unblocks a pending read or write by moving the
+	// deadline into the past.
+	conn := t.conn
+	conn.SetDeadline(deadline)
+	stop := context.AfterFunc(ctx, func() { conn.SetDeadline(time.Unix(1, 0)) })
+	defer stop()
+
+	if _, err := conn.Write(append(body, '\n')); err != nil {
+		t.close()
+		return nil, &TransportError{Err: ipcErr(ctx, "write", err)}
+	}
+	if tracer != nil {
+		tracer.mu.Lock()
+		tracer.wroteRequest = time.Now()
+		tracer.mu.Unlock()
+	}
+
+	var raw json.RawMessage
+	if err := t.dec.Decode(&raw); err != nil {
+		t.close()
+		var syntax *json.SyntaxError
+		if errors.As(err, &syntax) {
+			return nil, &ProtocolError{Msg: "decode rpc response", Err: err}
+		}
+		return nil, &TransportError{Err: ipcErr(ctx, "read", err)}
+	}
+	if tracer != nil {
+		// The decoder hands over the value only once it is complete, so
+		// the first byte and the last arrive together as far as we can see.
+		tracer.mu.Lock()
+		tracer.firstByte = time.Now()
+		tracer.mu.Unlock()
+	}
+	return raw, nil
+}
+
+// ipcErr describes a failed read or write, preferring the context's reason
+// when the context is what ended the exchange. A passed deadline surfaces as
+// a net.Error with Timeout() true, which TransportError already classifies.
+func ipcErr(ctx context.Context, op string, err error) error {
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return fmt.Errorf("ipc %s: %w", op, ctxErr)
+	}
+	return fmt.Errorf("ipc %s: %w", op, err)
+}
+
+// close drops the connection; the next exchange redials. Callers hold mu.
+func (t *ipcTransport) close() {
+	if t.conn != nil {
+		t.conn.Close()
+		t.conn, t.dec = nil, nil
+	}
+}
@@ -0,0 +1,137 @@
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// ipcServer listens on a Unix socket and answers every JSON value it reads
+// with reply(requestBody) followed by a newline. It counts connections.
+func ipcServer(t *testing.T, reply func(body []byte) string) (url string, conns *int32) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "node.ipc")
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	conns = new(int32)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			atomic.AddInt32(conns, 1)
+			go func() {
+				defer conn.Close()
+				dec := json.NewDecoder(conn)
+				for {
+					var raw json.RawMessage
+					if dec.Decode(&raw) != nil {
+						return
+					}
+					conn.Write([]byte(reply(raw) + "\n"))
+				}
+			}()
+		}
+	}()
+	return "ipc://" + path, conns
+}
+
+// ipcEcho turns a canned reply into one carrying the ids actually sent.
+func ipcEcho(reply string) func([]byte) string {
+	return func(body []byte) string {
+		return string(echoIDs(httptest.NewRequest("POST", "/", bytes.NewReader(body)), reply))
+	}
+}
+
+func TestIPC_callsReuseOneConnection(t *testing.T) {
+	url, conns := ipcServer(t, ipcEcho(`{"jsonrpc":"2.0","id":1,"result":"0x2a"}`))
+	c := NewClient("geth-ipc", url, 2*time.Second)
+
+	var first, second Timing
+	h, _, err := c.BlockNumber(WithTiming(context.Background(), &first))
+	if err != nil || h != 42 {
+		t.Fatalf("height %d, err %v", h, err)
+	}
+	if _, _, err := c.BlockNumber(WithTiming(context.Background(), &second)); err != nil {
+		t.Fatal(err)
+	}
+	if first.Reused || first.Proto != "ipc" || !second.Reused {
+		t.Fatalf("first %+v, second %+v", first, second)
+	}
+	if n := atomic.LoadInt32(conns); n != 1 {
+		t.Fatalf("%d connections, want 1", n)
+	}
+}
+
+func TestIPC_batch(t *testing.T) {
+	url, _ := ipcServer(t, ipcEcho(`[{"jsonrpc":"2.0","id":2,"result":"0x2"},{"jsonrpc":"2.0","id":1,"result":"0x1"}]`))
+	c := NewClient("geth-ipc", url, 2*time.Second)
+
+	results, _, err := c.CallBatch(context.Background(), []BatchElem{{Method: "eth_blockNumber"}, {Method: "eth_chainId"}})
+	if err != nil || len(results) != 2 || results[0].Error != nil || results[1].Error != nil ||
+		string(results[0].Response.Result) != `"0x1"` || string(results[1].Response.Result) != `"0x2"` {
+		t.Fatalf("results %+v, err %v", results, err)
+	}
+}
+
+func TestIPC_coldPolicyDialsEveryCall(t *testing.T) {
+	url, conns := ipcServer(t, ipcEcho(`{"jsonrpc":"2.0","id":1,"result":"0x1"}`))
+	c := NewClient("geth-ipc", url, 2*time.Second, WithPolicy(PolicyCold))
+	for i := 0; i < 3; i++ {
+		if _, _, err := c.BlockNumber(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The server counts a connection when it accepts it; give the last one
+	// a moment in case Accept is still returning.
+	time.Sleep(20 * time.Millisecond)
+	if n := atomic.LoadInt32(conns); n != 3 {
+		t.Fatalf("%d connections, want 3", n)
+	}
+}
+
+func TestIPC_errorCategories(t *testing.T) {
+	missing := NewClient("x", "ipc://"+filepath.Join(t.TempDir(), "absent.ipc"), time.Second)
+	if _, _, err := missing.BlockNumber(context.Background()); Classify(err) != CategoryNetwork {
+		t.Errorf("missing socket: %v (%s)", err, Classify(err))
+	}
+
+	slowURL, _ := ipcServer(t, func(body []byte) string {
+		time.Sleep(300 * time.Millisecond)
+		return `{"jsonrpc":"2.0","id":1,"result":"0x1"}`
+	})
+	slow := NewClient("x", slowURL, 50*time.Millisecond)
+	if _, _, err := slow.BlockNumber(context.Background()); Classify(err) != CategoryTimeout {
+		t.Errorf("slow node: %v (%s)", err, Classify(err))
+	}
+
+	garbageURL, _ := ipcServer(t, func([]byte) string { return "}not json" })
+	garbage := NewClient("x", garbageURL, time.Second)
+	if _, _, err := garbage.BlockNumber(context.Background()); Classify(err) != CategoryProtocol {
+		t.Errorf("garbage reply: %v (%s)", err, Classify(err))
+	}
+}
+
+func TestIPC_rpcErrorKeepsConnection(t *testing.T) {
+	url, conns := ipcServer(t, ipcEcho(`{"jsonrpc":"2.0","id":1,"error":{"code":-32601,"message":"nope"}}`))
+	c := NewClient("x", url, time.Second)
+	for i := 0; i < 2; i++ {
+		if _, _, err := c.Call(context.Background(), "debug_nope"); Classify(err) != CategoryNotFound {
+			t.Fatalf("call %d: %v (%s)", i, err, Classify(err))
+		}
+	}
+	if n := atomic.LoadInt32(conns); n != 1 {
+		t.Fatalf("%d connections, want 1", n)
+	}
+}
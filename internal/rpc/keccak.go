@@ -0,0 +1,129 @@
+// =============================================================================
+// FILE: internal/rpc/keccak.go
+// ROLE: Keccak-256 — Ethereum's Hash Function
+// =============================================================================
+//
+// SYSTEM CONTEXT
+// ==============
+// Every identifier Ethereum derives from content — block hashes, transaction
+// hashes, trie nodes, event topics, addresses — is Keccak-256. To check what
+// a provider returns instead of trusting it (header.go), we need to compute
+// it ourselves.
+//
+// WHY NOT crypto/sha3?
+// ====================
+// Ethereum adopted Keccak before NIST finished standardizing it as SHA-3.
+// The final standard changed the padding byte (0x06 instead of Keccak's
+// 0x01), so SHA3-256 and Keccak-256 give DIFFERENT digests for the same
+// input. The standard library only ships the standardized variant, and this
+// package stays dependency-free (see client.go), so the permutation lives
+// here — about sixty lines.
+//
+// THE SPONGE
+// ==========
+// The state is 25 64-bit lanes (1600 bits). For a 256-bit digest, 136 bytes
+// of it (the "rate") are exposed to input; the rest is the hidden capacity:
+//
+//   input ─▶ pad to a multiple of 136 bytes (0x01 … 0x80)
+//         ─▶ for each 136-byte block: XOR into the state, run Keccak-f[1600]
+//         ─▶ digest = first 32 bytes of the state
+//
+// Known answers (keccak_test.go): keccak256("") =
+// c5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470.
+// =============================================================================
+
+package rpc
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+// keccakRate is the sponge rate in bytes for Keccak-256 (1600 - 2*256 bits).
+const keccakRate = 136
+
+// keccakRC are the round constants XORed into lane 0 by the iota step.
+var keccakRC = [24]uint64{
+	0x0000000000000001, 0x0000000000008082, 0x800000000000808A, 0x8000000080008000,
+	0x000000000000808B, 0x0000000080000001, 0x8000000080008081, 0x8000000000008009,
+	0x000000000000008A, 0x0000000000000088, 0x0000000080008009, 0x000000008000000A,
+	0x000000008000808B, 0x800000000000008B, 0x8000000000008089, 0x8000000000008003,
+	0x8000000000008002, 0x8000000000000080, 0x000000000000800A, 0x800000008000000A,
+	0x8000000080008081, 0x8000000000008080, 0x0000000080000001, 0x8000000080008008,
+}
+
+// keccakRotc and keccakPiln drive the combined rho (rotate) and pi (permute)
+// steps: lane keccakPiln[i] receives the previous lane rotated by
+// keccakRotc[i].
+var (
+	keccakRotc = [24]int{1, 3, 6, 10, 15, 21, 28, 36, 45, 55, 2, 14, 27, 41, 56, 8, 25, 43, 62, 18, 39, 61, 20, 44}
+	keccakPiln = [24]int{10, 7, 11, 17, 18, 3, 5, 16, 8, 21, 24, 4, 15, 23, 19, 13, 12, 2, 20, 14, 22, 9, 6, 1}
+)
+
+// keccakF1600 applies the 24-round Keccak-f[1600] permutation in place.
+func keccakF1600(a *[25]uint64) {
+	var bc [5]uint64
+	for round := 0; round < 24; round++ {
+		// Theta: mix each column's parity into its neighbours.
+		for i := 0; i < 5; i++ {
+			bc[i] = a[i] ^ a[i+5] ^ a[i+10] ^ a[i+15] ^ a[i+20]
+		}
+		for i := 0; i < 5; i++ {
+			t := bc[(i+4)%5] ^ bits.RotateLeft64(bc[(i+1)%5], 1)
+			for j := 0; j < 25; j += 5 {
+				a[j+i] ^= t
+			}
+		}
+
+		// Rho and pi: rotate every lane and move it to its new position.
+		t := a[1]
+		for i := 0; i < 24; i++ {
+			j := keccakPiln[i]
+			bc[0] = a[j]
+			a[j] = bits.RotateLeft64(t, keccakRotc[i])
+			t = bc[0]
+		}
+
+		// Chi: the only non-linear step, row by row.
+		for j := 0; j < 25; j += 5 {
+			for i := 0; i < 5; i++ {
+				bc[i] = a[j+i]
+			}
+			for i := 0; i < 5; i++ {
+				a[j+i] ^= ^bc[(i+1)%5] & bc[(i+2)%5]
+			}
+		}
+
+		// Iota: break the symmetry between rounds.
+		a[0] ^= keccakRC[round]
+	}
+}
+
+// Keccak256 returns the 32-byte Keccak-256 digest of the concatenated inputs.
+func Keccak256(data ...[]byte) []byte {
+	var msg []byte
+	for _, d := range data {
+		msg = append(msg, d...)
+	}
+
+	// Pad: 0x01 after the message, 0x80 in the last byte of the final block
+	// (the same byte when only one padding byte fits).
+	padded := make([]byte, (len(msg)/keccakRate+1)*keccakRate)
+	copy(padded, msg)
+	padded[len(msg)] ^= 0x01
+	padded[len(padded)-1] ^= 0x80
+
+	var state [25]uint64
+	for off := 0; off < len(padded); off += keccakRate {
+		for i := 0; i < keccakRate/8; i++ {
+			state[i] ^= binary.LittleEndian.Uint64(padded[off+8*i:])
+		}
+		keccakF1600(&state)
+	}
+
+	out := make([]byte, 32)
+	for i := 0; i < 4; i++ {
+		binary.LittleEndian.PutUint64(out[8*i:], state[i])
+	}
+	return out
+}
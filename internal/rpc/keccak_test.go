@@ -0,0 +1,80 @@
+package rpc
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestKeccak256(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"", "c5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470"},
+		{"abc", "4e03657aea45a94fc7d47ba826c8d667c0d1e6e33a64a036ec44f58fa12d6c45"},
+		{"Transfer(address,address,uint256)", "ddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"},
+	}
+	for _, tt := range tests {
+		if got := hex.EncodeToString(Keccak256([]byte(tt.in))); got != tt.want {
+			t.Errorf("keccak256(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+
+	// Inputs are concatenated, and lengths around the 136-byte rate (one
+	// padding byte, a full extra block) agree with hashing in one piece.
+	for _, n := range []int{135, 136, 137, 272} {
+		msg := []byte(strings.Repeat("a", n))
+		if hex.EncodeToString(Keccak256(msg[:7], msg[7:])) != hex.EncodeToString(Keccak256(msg)) {
+			t.Errorf("split input of %d bytes hashes differently", n)
+		}
+	}
+}
+
+func TestRLP(t *testing.T) {
+	tests := []struct {
+		name string
+		got  []byte
+		want string
+	}{
+		{"zero", rlpUint(0), "80"},
+		{"small int", rlpUint(15), "0f"},
+		{"1024", rlpUint(1024), "820400"},
+		{"dog", rlpBytes([]byte("dog")), "83646f67"},
+		{"empty list", rlpList(), "c0"},
+		{"cat dog", rlpList(rlpBytes([]byte("cat")), rlpBytes([]byte("dog"))), "c88363617483646f67"},
+		{"long string", rlpBytes([]byte(strings.Repeat("a", 56))), "b838" + strings.Repeat("61", 56)},
+	}
+	for _, tt := range tests {
+		if got := hex.EncodeToString(tt.got); got != tt.want {
+			t.Errorf("%s: %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRLPDecode(t *testing.T) {
+	long := bytes.Repeat([]byte{0xab}, 60)
+	enc := rlpList(rlpUint(0), rlpUint(1024), rlpBytes(long), rlpList(rlpBytes([]byte{0x7f})))
+	items, err := rlpItems(enc)
+	if err != nil || len(items) != 4 {
+		t.Fatalf("rlpItems = %d items, %v", len(items), err)
+	}
+	want := [][]byte{{}, {0x04, 0x00}, long}
+	for i, w := range want {
+		payload, isList, rest, err := rlpSplit(items[i])
+		if err != nil || isList || len(rest) != 0 || !bytes.Equal(payload, w) {
+			t.Fatalf("item %d = %x list=%v rest=%x err=%v", i, payload, isList, rest, err)
+		}
+	}
+	if _, isList, _, _ := rlpSplit(items[3]); !isList {
+		t.Fatal("nested list decoded as string")
+	}
+
+	if _, err := rlpItems(enc[:len(enc)-1]); err == nil {
+		t.Fatal("truncated list decoded")
+	}
+	if _, err := rlpItems(rlpBytes(long)); err == nil {
+		t.Fatal("string decoded as list")
+	}
+}
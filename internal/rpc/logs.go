@@ -0,0 +1,186 @@
+// =============================================================================
+// FILE: internal/rpc/logs.go
+// ROLE: Event Log Queries — eth_getLogs with Automatic Range Splitting
+// =============================================================================
+//
+// SYSTEM CONTEXT
+// ==============
+// eth_getLogs returns every log matching a filter over a block range. It is
+// the backbone of indexers and accounting pipelines, and it is also the call
+// providers are least consistent about:
+//
+//   - Most cap the RANGE ("eth_getLogs is limited to a 10,000 block range")
+//     or the RESULT SIZE ("query returned more than 10000 results") and
+//     answer wider queries with an error.
+//   - Some silently return a partial result instead — the dangerous case,
+//     which only cross-provider comparison (cmd/logs) can catch.
+//
+// This file handles the first case. GetLogsRange issues the query; when the
+// provider rejects it as too wide, the range is cut in half and each half is
+// queried separately, recursively, until every piece is accepted:
+//
+//   [19,000,000 ─────────────────────── 19,009,999]   ✗ range limit
+//   [19,000,000 ──── 19,004,999] [19,005,000 ──── 19,009,999]
+//          ✓ 812 logs                  ✗ too many results
+//                                [19,005,000 ─ 19,007,499] [19,007,500 ─ 19,009,999]
+//                                      ✓ 3,140 logs              ✓ 2,977 logs
+//
+// Pieces are queried in block order and concatenated, so the result is in
+// the same (blockNumber, logIndex) order a single query would have produced.
+// Any other error aborts the whole query: a log set with a hole in it is
+// worse than no answer.
+// =============================================================================
+
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// maxLogRequests bounds how many eth_getLogs calls one GetLogsRange may make
+// before giving up. Halving reaches single blocks after ~20 levels, so this
+// only trips on providers that reject everything as "too large".
+const maxLogRequests = 1024
+
+// LogFilter selects logs by contract address and topics.
+//
+// Topics is positional: Topics[0] constrains the first topic (usually the
+// event signature), Topics[1] the second, and so on. Each position lists
+// alternatives (OR); an empty position matches anything.
+//
+//	Topics: [][]string{{transferSig}, nil, {padded(myAddr)}}
+//	→ Transfer events (any sender) TO myAddr
+type LogFilter struct {
+	Addresses []string   // Contract addresses (OR); empty = any contract
+	Topics    [][]string // Positional topic alternatives; nil/empty position = wildcard
+}
+
+// params builds the eth_getLogs filter object for one block range.
+func (f LogFilter) params(from, to uint64) map[string]interface{} {
+	p := map[string]interface{}{
+		"fromBlock": fmt.Sprintf("0x%x", from),
+		"toBlock":   fmt.Sprintf("0x%x", to),
+	}
+	if len(f.Addresses) > 0 {
+		p["address"] = f.Addresses
+	}
+	if len(f.Topics) > 0 {
+		topics := make([]interface{}, len(f.Topics))
+		for i, alts := range f.Topics {
+			if len(alts) > 0 {
+				topics[i] = alts
+			} // else stays nil → JSON null → wildcard
+		}
+		p["topics"] = topics
+	}
+	return p
+}
+
+// LogRangeResult is the outcome of GetLogsRange.
+type LogRangeResult struct {
+	Logs     []Log
+	Requests int    // eth_getLogs calls made (1 if no splitting was needed)
+	Splits   int    // Times a range was rejected as too wide and halved
+	MaxSpan  uint64 // Widest range (in blocks) the provider accepted
+}
+
+// GetLogs calls eth_getLogs once for blocks [from, to].
+func (c *Client) GetLogs(ctx context.Context, f LogFilter, from, to uint64) ([]Log, time.Duration, error) {
+	resp, latency, err := c.Call(ctx, "eth_getLogs", f.params(from, to))
+	if err != nil {
+		return nil, latency, err
+	}
+	var logs []Log
+	if err := json.Unmarshal(resp.Result, &logs); err != nil {
+		return nil, latency, fmt.Errorf("unmarshal getLogs result: %w", err)
+	}
+	return logs, latency, nil
+}
+
+// GetLogsRange queries blocks [from, to], splitting the range whenever the
+// provider rejects it as too wide (see IsRangeLimitError). The returned
+// latency is the total wall time across all requests.
+func (c *Client) GetLogsRange(ctx context.Context, f LogFilter, from, to uint64) (*LogRangeResult, time.Duration, error) {
+	if to < from {
+		return nil, 0, fmt.Errorf("invalid block range %d-%d", from, to)
+	}
+	start := time.Now()
+	res := &LogRangeResult{}
+	err := c.getLogsSplit(ctx, f, from, to, res)
+	if err != nil {
+		return nil, time.Since(start), err
+	}
+	return res, time.Since(start), nil
+}
+
+// getLogsSplit is the recursive half of GetLogsRange.
+func (c *Client) getLogsSplit(ctx context.Context, f LogFilter, from, to uint64, res *LogRangeResult) error {
+	if res.Requests >= maxLogRequests {
+		return fmt.Errorf("eth_getLogs: gave up after %d requests (range still rejected at %d-%d)", res.Requests, from, to)
+	}
+	res.Requests++
+
+	logs, _, err := c.GetLogs(ctx, f, from, to)
+	if err == nil {
+		res.Logs = append(res.Logs, logs...)
+		if span := to - from + 1; span > res.MaxSpan {
+			res.MaxSpan = span
+		}
+		return nil
+	}
+	if !IsRangeLimitError(err) || from == to {
+		return fmt.Errorf("eth_getLogs %d-%d: %w", from, to, err)
+	}
+
+	res.Splits++
+	mid := from + (to-from)/2
+	if err := c.getLogsSplit(ctx, f, from, mid, res); err != nil {
+		return err
+	}
+	return c.getLogsSplit(ctx, f, mid+1, to, res)
+}
+
+// IsRangeLimitError reports whether err is a provider refusing an eth_getLogs
+// query because its block range or result set is too large — the errors
+// that splitting the range can fix.
+//
+// There is no standard code for this (Infura reuses -32005, which otherwise
+// means rate limiting), so the message decides. Some gateways refuse before
+// the node answers, with an HTTP status instead of a JSON-RPC error: 413
+// (the response would be too large) counts on its own, and any other
+// status counts when its body uses one of the same phrases. Known
+// phrasings include:
+//
+//	"query returned more than 10000 results"
+//	"eth_getLogs is limited to a 10,000 range"
+//	"Log response size exceeded. You can make eth_getLogs requests with up to a 2K block range"
+//	"exceed maximum block range: 5000"
+//	"block range is too wide" / "block range too large"
+//	"query exceeds max results"
+func IsRangeLimitError(err error) bool {
+	var rpcErr *RPCError
+	var httpErr *HTTPError
+	var msg string
+	switch {
+	case errors.As(err, &rpcErr):
+		msg = rpcErr.Message
+	case errors.As(err, &httpErr):
+		if httpErr.StatusCode == http.StatusRequestEntityTooLarge {
+			return true
+		}
+		msg = httpErr.Body
+	default:
+		return false
+	}
+	msg = strings.ToLower(msg)
+	return containsAny(msg,
+		"returned more than", "response size", "block range", "range limit",
+		"max range", "maximum range", "is limited to", "range too", "range is too",
+		"too many blocks", "too many logs", "too many results", "exceeds max", "query exceeds")
+}
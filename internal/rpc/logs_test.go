@@ -0,0 +1,137 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// logsServer serves one log per block and rejects ranges wider than maxSpan
+// with an Alchemy-style range-limit error, or, when status is set, with that
+// HTTP status and the same message as a plain-text gateway body.
+func logsServer(t *testing.T, maxSpan uint64, status int) (*httptest.Server, *[][2]uint64) {
+	t.Helper()
+	var ranges [][2]uint64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     uint64                   `json:"id"`
+			Params []map[string]interface{} `json:"params"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		from, _ := ParseHexUint64(req.Params[0]["fromBlock"].(string))
+		to, _ := ParseHexUint64(req.Params[0]["toBlock"].(string))
+		ranges = append(ranges, [2]uint64{from, to})
+
+		if to-from+1 > maxSpan && status != 0 {
+			http.Error(w, fmt.Sprintf("eth_getLogs is limited to a %d block range", maxSpan), status)
+			return
+		}
+		if to-from+1 > maxSpan {
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%d,"error":{"code":-32600,"message":"eth_getLogs is limited to a %d block range"}}`, req.ID, maxSpan)
+			return
+		}
+		var logs []string
+		for b := from; b <= to; b++ {
+			logs = append(logs, fmt.Sprintf(`{"blockNumber":"0x%x","blockHash":"0xb%d","transactionHash":"0xt%d","logIndex":"0x0"}`, b, b, b))
+		}
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%d,"result":[%s]}`, req.ID, strings.Join(logs, ","))
+	}))
+	return srv, &ranges
+}
+
+func TestClient_GetLogsRange_noSplit(t *testing.T) {
+	srv, ranges := logsServer(t, 100, 0)
+	defer srv.Close()
+
+	res, _, err := NewClient("t", srv.URL, 2*time.Second).GetLogsRange(context.Background(), LogFilter{}, 10, 19)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Logs) != 10 || res.Requests != 1 || res.Splits != 0 || res.MaxSpan != 10 || len(*ranges) != 1 {
+		t.Fatalf("got %+v, ranges %v", res, *ranges)
+	}
+}
+
+func TestClient_GetLogsRange_splits(t *testing.T) {
+	// A JSON-RPC error, then a gateway's HTTP 400 with the message as body.
+	for _, status := range []int{0, http.StatusBadRequest} {
+		srv, _ := logsServer(t, 3, status)
+		defer srv.Close()
+
+		res, _, err := NewClient("t", srv.URL, 2*time.Second).GetLogsRange(context.Background(), LogFilter{}, 100, 109)
+		if err != nil {
+			t.Fatalf("status %d: %v", status, err)
+		}
+		if len(res.Logs) != 10 || res.Splits == 0 || res.MaxSpan > 3 {
+			t.Fatalf("status %d: got %d logs, %+v", status, len(res.Logs), res)
+		}
+		// Pieces are concatenated in block order.
+		for i, l := range res.Logs {
+			if n, _ := ParseHexUint64(l.BlockNumber); n != uint64(100+i) {
+				t.Fatalf("status %d: log %d is from block %d", status, i, n)
+			}
+		}
+	}
+}
+
+func TestClient_GetLogsRange_otherErrorAborts(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(echoIDs(r, `{"jsonrpc":"2.0","id":1,"error":{"code":-32603,"message":"internal error"}}`))
+	}))
+	defer srv.Close()
+
+	_, _, err := NewClient("t", srv.URL, 2*time.Second).GetLogsRange(context.Background(), LogFilter{}, 1, 1000)
+	var rpcErr *RPCError
+	if !errors.As(err, &rpcErr) || rpcErr.Code != -32603 {
+		t.Fatalf("err = %v", err)
+	}
+}
+
+func TestLogFilter_params(t *testing.T) {
+	f := LogFilter{
+		Addresses: []string{"0xc0"},
+		Topics:    [][]string{{"0xsig"}, nil, {"0xa", "0xb"}},
+	}
+	raw, _ := json.Marshal(f.params(16, 31))
+	want := `{"address":["0xc0"],"fromBlock":"0x10","toBlock":"0x1f","topics":[["0xsig"],null,["0xa","0xb"]]}`
+	if string(raw) != want {
+		t.Fatalf("got  %s\nwant %s", raw, want)
+	}
+}
+
+func TestIsRangeLimitError(t *testing.T) {
+	limited := []string{
+		"query returned more than 10000 results",
+		"eth_getLogs is limited to a 10,000 range",
+		"Log response size exceeded. You can make eth_getLogs requests with up to a 2K block range",
+		"exceed maximum block range: 5000",
+		"block range is too wide",
+	}
+	for _, msg := range limited {
+		if !IsRangeLimitError(&RPCError{Code: -32005, Message: msg}) {
+			t.Fatalf("%q not recognized", msg)
+		}
+		if !IsRangeLimitError(&HTTPError{StatusCode: 400, Body: msg}) {
+			t.Fatalf("HTTP 400 %q not recognized", msg)
+		}
+	}
+	if !IsRangeLimitError(&HTTPError{StatusCode: 413}) {
+		t.Fatal("HTTP 413 not recognized")
+	}
+	for _, err := range []error{
+		&RPCError{Code: -32005, Message: "rate limit exceeded"},
+		&RPCError{Code: -32603, Message: "internal error"},
+		&HTTPError{StatusCode: 400, Body: "invalid json"},
+		&HTTPError{StatusCode: 502, Body: "<html>Bad Gateway</html>"},
+	} {
+		if IsRangeLimitError(err) {
+			t.Fatalf("%v misclassified as range limit", err)
+		}
+	}
+}
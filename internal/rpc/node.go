@@ -0,0 +1,255 @@
+// =============================================================================
+// FILE: internal/rpc/node.go
+// ROLE: Node Metadata — Who Is Actually Behind This Endpoint?
+// =============================================================================
+//
+// SYSTEM CONTEXT
+// ==============
+// config.Provider.Type is a label we type in by hand. This file asks the node
+// itself. Five cheap, parameterless methods describe what sits behind a URL:
+//
+//   web3_clientVersion  "Geth/v1.14.8-stable-a9523b64/linux-amd64/go1.22.6"
+//   eth_chainId         "0x1"      (EIP-155 chain ID, used in tx signatures)
+//   net_version         "1"        (devp2p network ID, a DECIMAL string)
+//   eth_syncing         false  |  {"currentBlock":"0x...","highestBlock":"0x..."}
+//   net_peerCount       "0x32"
+//
+// Hosted providers often disable the net_* namespace or answer it with a
+// canned value, so each method's failure is recorded separately instead of
+// failing the whole probe.
+//
+// CLIENT VERSION STRINGS
+// ======================
+// web3_clientVersion has no formal grammar, but every major execution client
+// uses the same slash-separated shape:
+//
+//   Geth/v1.14.8-stable-a9523b64/linux-amd64/go1.22.6
+//   Geth/my-node-name/v1.13.5-stable/linux-amd64/go1.21.4   ← --identity set
+//   Nethermind/v1.28.0+9c4816c2/linux-x64/dotnet8.0.8
+//   erigon/2.60.6/linux-amd64/go1.22.5
+//   reth/v1.0.5-603e39ab/x86_64-unknown-linux-gnu
+//   besu/v24.7.1/linux-x86_64/openjdk-java-21
+//    │       │            │                │
+//   name   version     platform         runtime
+//
+// ParseClientVersion takes the first segment as the implementation, the first
+// segment that looks like a version number as the version, and whatever
+// follows as platform and runtime.
+// =============================================================================
+
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Known execution client implementations, as returned by ClientVersion.Client.
+const (
+	ClientGeth       = "geth"
+	ClientNethermind = "nethermind"
+	ClientErigon     = "erigon"
+	ClientReth       = "reth"
+	ClientBesu       = "besu"
+	ClientUnknown    = "unknown"
+)
+
+// knownClients maps the lowercased first segment of web3_clientVersion to an
+// implementation name.
+var knownClients = map[string]string{
+	"geth":        ClientGeth,
+	"go-ethereum": ClientGeth,
+	"nethermind":  ClientNethermind,
+	"erigon":      ClientErigon,
+	"reth":        ClientReth,
+	"besu":        ClientBesu,
+}
+
+// ClientVersion is a parsed web3_clientVersion string.
+type ClientVersion struct {
+	Raw      string // Unmodified response
+	Client   string // ClientGeth, ..., or ClientUnknown
+	Name     string // First segment as reported ("Geth", "Nethermind", ...)
+	Version  string // Without the leading "v": "1.14.8-stable-a9523b64"
+	Platform string // "linux-amd64"; empty if not reported
+	Runtime  string // "go1.22.6", "dotnet8.0.8"; empty if not reported
+}
+
+// Known reports whether the implementation was recognized.
+func (v ClientVersion) Known() bool { return v.Client != ClientUnknown }
+
+// ParseClientVersion splits a web3_clientVersion string into its parts (see
+// CLIENT VERSION STRINGS). Unrecognized implementations keep their Name and
+// get Client = ClientUnknown; it never fails.
+func ParseClientVersion(raw string) ClientVersion {
+	v := ClientVersion{Raw: raw, Client: ClientUnknown}
+	parts := strings.Split(strings.TrimSpace(raw), "/")
+	if len(parts) == 0 || parts[0] == "" {
+		return v
+	}
+	v.Name = parts[0]
+	if c, ok := knownClients[strings.ToLower(v.Name)]; ok {
+		v.Client = c
+	}
+
+	// The version is the first segment after the name that starts with a
+	// digit (optionally after "v"). Anything before it is a node identity.
+	for i := 1; i < len(parts); i++ {
+		if !looksLikeVersion(parts[i]) {
+			continue
+		}
+		v.Version = strings.TrimPrefix(strings.TrimPrefix(parts[i], "v"), "V")
+		if i+1 < len(parts) {
+			v.Platform = parts[i+1]
+		}
+		if i+2 < len(parts) {
+			v.Runtime = strings.Join(parts[i+2:], "/")
+		}
+		break
+	}
+	return v
+}
+
+// looksLikeVersion reports whether s starts with a digit, optionally after a
+// "v" prefix: "v1.14.8", "2.60.6".
+func looksLikeVersion(s string) bool {
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "v"), "V")
+	return s != "" && s[0] >= '0' && s[0] <= '9'
+}
+
+// SyncStatus is the result of eth_syncing.
+type SyncStatus struct {
+	Syncing      bool
+	CurrentBlock uint64 // Zero when not syncing
+	HighestBlock uint64 // Zero when not syncing
+}
+
+// Remaining is how many blocks the node is behind the highest block it knows.
+func (s SyncStatus) Remaining() uint64 {
+	if !s.Syncing || s.HighestBlock < s.CurrentBlock {
+		return 0
+	}
+	return s.HighestBlock - s.CurrentBlock
+}
+
+// NodeInfo is everything the five metadata methods reported. A field is only
+// meaningful when its method has no entry in Errors.
+type NodeInfo struct {
+	Version   ClientVersion
+	ChainID   uint64
+	NetworkID string // net_version; decimal string per spec
+	Sync      SyncStatus
+	PeerCount uint64
+
+	// Errors maps method name → failure, for the methods that failed.
+	Errors map[string]error
+}
+
+// The probe's methods, in the order they are called.
+const (
+	MethodClientVersion = "web3_clientVersion"
+	MethodChainID       = "eth_chainId"
+	MethodNetVersion    = "net_version"
+	MethodSyncing       = "eth_syncing"
+	MethodPeerCount     = "net_peerCount"
+)
+
+// NodeInfoMethods lists the probe's methods in call order.
+var NodeInfoMethods = []string{MethodClientVersion, MethodChainID, MethodNetVersion, MethodSyncing, MethodPeerCount}
+
+// Err returns the failure for method, or nil if it succeeded.
+func (n *NodeInfo) Err(method string) error { return n.Errors[method] }
+
+// NodeInfo calls the five metadata methods one after another and collects
+// whatever succeeds. It returns an error only when every method failed — a
+// node that answers nothing is down, not merely locked down. The latency is
+// the total across all calls.
+func (c *Client) NodeInfo(ctx context.Context) (*NodeInfo, time.Duration, error) {
+	info := &NodeInfo{Errors: make(map[string]error)}
+	var total time.Duration
+
+	for _, method := range NodeInfoMethods {
+		resp, latency, err := c.Call(ctx, method)
+		total += latency
+		if err == nil {
+			err = info.decode(method, resp.Result)
+		}
+		if err != nil {
+			info.Errors[method] = err
+		}
+	}
+
+	if len(info.Errors) == len(NodeInfoMethods) {
+		return nil, total, info.Errors[MethodChainID]
+	}
+	return info, total, nil
+}
+
+// decode stores one method's result in the matching NodeInfo field.
+func (n *NodeInfo) decode(method string, raw json.RawMessage) error {
+	switch method {
+	case MethodClientVersion:
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return fmt.Errorf("unmarshal %s result: %w", method, err)
+		}
+		n.Version = ParseClientVersion(s)
+
+	case MethodChainID, MethodPeerCount:
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return fmt.Errorf("unmarshal %s result: %w", method, err)
+		}
+		v, err := ParseHexUint64(s)
+		if err != nil {
+			return fmt.Errorf("parse %s result %q: %w", method, s, err)
+		}
+		if method == MethodChainID {
+			n.ChainID = v
+		} else {
+			n.PeerCount = v
+		}
+
+	case MethodNetVersion:
+		if err := json.Unmarshal(raw, &n.NetworkID); err != nil {
+			return fmt.Errorf("unmarshal %s result: %w", method, err)
+		}
+
+	case MethodSyncing:
+		sync, err := parseSyncing(raw)
+		if err != nil {
+			return err
+		}
+		n.Sync = sync
+	}
+	return nil
+}
+
+// parseSyncing decodes eth_syncing, which returns either the literal false or
+// an object with hex progress fields.
+func parseSyncing(raw json.RawMessage) (SyncStatus, error) {
+	var done bool
+	if err := json.Unmarshal(raw, &done); err == nil {
+		if done {
+			// true without details is not in the spec; treat as syncing
+			// with unknown progress rather than guessing.
+			return SyncStatus{Syncing: true}, nil
+		}
+		return SyncStatus{}, nil
+	}
+
+	var progress struct {
+		CurrentBlock string `json:"currentBlock"`
+		HighestBlock string `json:"highestBlock"`
+	}
+	if err := json.Unmarshal(raw, &progress); err != nil {
+		return SyncStatus{}, fmt.Errorf("unmarshal eth_syncing result: %w", err)
+	}
+	s := SyncStatus{Syncing: true}
+	s.CurrentBlock, _ = ParseHexUint64(progress.CurrentBlock)
+	s.HighestBlock, _ = ParseHexUint64(progress.HighestBlock)
+	return s, nil
+}
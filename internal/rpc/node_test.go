@@ -0,0 +1,99 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestParseClientVersion(t *testing.T) {
+	tests := []struct {
+		raw                                 string
+		client, name, version, platform, rt string
+	}{
+		{"Geth/v1.14.8-stable-a9523b64/linux-amd64/go1.22.6", ClientGeth, "Geth", "1.14.8-stable-a9523b64", "linux-amd64", "go1.22.6"},
+		{"Geth/my-node/v1.13.5-stable/linux-amd64/go1.21.4", ClientGeth, "Geth", "1.13.5-stable", "linux-amd64", "go1.21.4"},
+		{"Nethermind/v1.28.0+9c4816c2/linux-x64/dotnet8.0.8", ClientNethermind, "Nethermind", "1.28.0+9c4816c2", "linux-x64", "dotnet8.0.8"},
+		{"erigon/2.60.6/linux-amd64/go1.22.5", ClientErigon, "erigon", "2.60.6", "linux-amd64", "go1.22.5"},
+		{"reth/v1.0.5-603e39ab/x86_64-unknown-linux-gnu", ClientReth, "reth", "1.0.5-603e39ab", "x86_64-unknown-linux-gnu", ""},
+		{"besu/v24.7.1/linux-x86_64/openjdk-java-21", ClientBesu, "besu", "24.7.1", "linux-x86_64", "openjdk-java-21"},
+		{"bor/v1.3.7/linux-amd64/go1.22.1", ClientUnknown, "bor", "1.3.7", "linux-amd64", "go1.22.1"},
+		{"", ClientUnknown, "", "", "", ""},
+	}
+	for _, tt := range tests {
+		v := ParseClientVersion(tt.raw)
+		if v.Client != tt.client || v.Name != tt.name || v.Version != tt.version || v.Platform != tt.platform || v.Runtime != tt.rt {
+			t.Errorf("ParseClientVersion(%q) = %+v", tt.raw, v)
+		}
+		if v.Raw != tt.raw {
+			t.Errorf("Raw = %q, want %q", v.Raw, tt.raw)
+		}
+	}
+}
+
+// nodeServer answers each metadata method from replies (method → raw result
+// JSON); methods not in replies get a -32601 error.
+func nodeServer(t *testing.T, replies map[string]string) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req Request
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		if result, ok := replies[req.Method]; ok {
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%d,"result":%s}`, req.ID, result)
+			return
+		}
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%d,"error":{"code":-32601,"message":"the method %s does not exist/is not available"}}`, req.ID, req.Method)
+	}))
+}
+
+func TestClient_NodeInfo(t *testing.T) {
+	srv := nodeServer(t, map[string]string{
+		MethodClientVersion: `"Geth/v1.14.8-stable/linux-amd64/go1.22.6"`,
+		MethodChainID:       `"0x1"`,
+		MethodNetVersion:    `"1"`,
+		MethodSyncing:       `{"startingBlock":"0x0","currentBlock":"0x64","highestBlock":"0xc8"}`,
+		// net_peerCount disabled, as on most hosted endpoints
+	})
+	defer srv.Close()
+
+	info, _, err := NewClient("t", srv.URL, 2*time.Second).NodeInfo(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Version.Client != ClientGeth || info.ChainID != 1 || info.NetworkID != "1" {
+		t.Fatalf("got %+v", info)
+	}
+	if !info.Sync.Syncing || info.Sync.Remaining() != 100 {
+		t.Fatalf("sync = %+v", info.Sync)
+	}
+	if info.Err(MethodPeerCount) == nil || len(info.Errors) != 1 {
+		t.Fatalf("errors = %v", info.Errors)
+	}
+}
+
+func TestClient_NodeInfo_synced(t *testing.T) {
+	srv := nodeServer(t, map[string]string{MethodSyncing: `false`, MethodPeerCount: `"0x32"`})
+	defer srv.Close()
+
+	info, _, err := NewClient("t", srv.URL, 2*time.Second).NodeInfo(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Sync.Syncing || info.PeerCount != 50 {
+		t.Fatalf("got %+v", info)
+	}
+}
+
+func TestClient_NodeInfo_allFailed(t *testing.T) {
+	srv := nodeServer(t, nil)
+	defer srv.Close()
+
+	info, _, err := NewClient("t", srv.URL, 2*time.Second).NodeInfo(context.Background())
+	if err == nil || info != nil {
+		t.Fatalf("info = %+v, err = %v", info, err)
+	}
+}
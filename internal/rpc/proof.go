@@ -0,0 +1,362 @@
+// =============================================================================
+// FILE: internal/rpc/proof.go
+// ROLE: State Proofs — eth_getProof, Verified Against the Block's stateRoot
+// =============================================================================
+//
+// SYSTEM CONTEXT
+// ==============
+// eth_getBalance and friends return a number and ask to be believed. The
+// header's stateRoot commits to every account on the chain, and
+// eth_getProof (EIP-1186) returns, next to the account's fields, the trie
+// nodes on the path from that root to the account — and from the account's
+// storageHash to each requested storage slot. Walking those nodes and
+// hashing each one proves the values are the chain's, given only a trusted
+// stateRoot. `account` gets the root from GetBlock (whose header
+// VerifyBlockHash checks) and verifies every provider's proof locally.
+//
+// THE TWO TRIES
+// =============
+//
+//   state trie     keccak256(address)         → rlp([nonce, balance,
+//   (stateRoot)                                     storageHash, codeHash])
+//
+//   storage trie   keccak256(32-byte slot)    → rlp(value)   (minimal bytes)
+//   (storageHash)
+//
+// Keys are hashed ("secure" tries), so a path is always 64 nibbles.
+//
+// WALKING A PROOF
+// ===============
+// The proof is the list of RLP-encoded nodes from the root down. Each node
+// must hash to the reference its parent holds (the first: to the root);
+// nodes under 32 bytes are embedded in their parent instead of listed. At
+// each node the key's remaining nibbles pick the way:
+//
+//   branch (17 items)    take child[next nibble]
+//   extension (2 items)  the path must continue with its shared nibbles
+//   leaf (2 items)       the rest of the path must equal its nibbles
+//
+// A proof can also prove ABSENCE: an empty branch slot, a leaf for a
+// different key, or an extension that diverges. An absent account must be
+// reported empty (nonce 0, balance 0, no code, empty storage), an absent
+// slot as 0.
+// =============================================================================
+
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// emptyCodeHash is keccak256 of empty code.
+const emptyCodeHash = "0xc5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470"
+
+// emptyTrieRoot is the root of a trie with nothing in it.
+const emptyTrieRoot = "0x56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421"
+
+// AccountProof is an eth_getProof result.
+type AccountProof struct {
+	Address      string         `json:"address"`
+	AccountProof []string       `json:"accountProof"` // State trie nodes, root first
+	Balance      string         `json:"balance"`
+	CodeHash     string         `json:"codeHash"`
+	Nonce        string         `json:"nonce"`
+	StorageHash  string         `json:"storageHash"`
+	StorageProof []StorageProof `json:"storageProof"`
+}
+
+// StorageProof is the proof of one storage slot, rooted at StorageHash.
+type StorageProof struct {
+	Key   string   `json:"key"`
+	Value string   `json:"value"`
+	Proof []string `json:"proof"` // Storage trie nodes, root first
+}
+
+// GetProof calls eth_getProof for address and storage slots at blockNum.
+func (c *Client) GetProof(ctx context.Context, address string, slots []string, blockNum string) (*AccountProof, time.Duration, error) {
+	if slots == nil {
+		slots = []string{} // The node wants an array, not null
+	}
+	resp, latency, err := c.Call(ctx, "eth_getProof", address, slots, blockNum)
+	if err != nil {
+		return nil, latency, err
+	}
+	if isNullResult(resp.Result) {
+		return nil, latency, &NotFoundError{Method: "eth_getProof", Arg: address}
+	}
+	var p AccountProof
+	if err := json.Unmarshal(resp.Result, &p); err != nil {
+		return nil, latency, fmt.Errorf("unmarshal proof result: %w", err)
+	}
+	return &p, latency, nil
+}
+
+// ProofCheck is the result of verifying an AccountProof against a state
+// root.
+type ProofCheck struct {
+	StateRoot string
+	Account   error   // nil when the account fields are proven
+	Slots     []error // Parallel to StorageProof; nil entries are proven
+}
+
+// OK reports whether the account and every slot were proven.
+func (c ProofCheck) OK() bool {
+	if c.Account != nil {
+		return false
+	}
+	for _, err := range c.Slots {
+		if err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// Problems lists one line per failed proof.
+func (c ProofCheck) Problems() []string {
+	var out []string
+	if c.Account != nil {
+		out = append(out, "account: "+c.Account.Error())
+	}
+	for i, err := range c.Slots {
+		if err != nil {
+			out = append(out, fmt.Sprintf("slot %d: %v", i, err))
+		}
+	}
+	return out
+}
+
+// VerifyProof checks p against stateRoot: the account fields against the
+// state trie, then each slot against the (now proven) storageHash. address
+// and slots are what was asked for, so a proof of a different account or
+// slot is not accepted.
+func VerifyProof(stateRoot, address string, slots []string, p *AccountProof) ProofCheck {
+	check := ProofCheck{StateRoot: stateRoot, Slots: make([]error, len(p.StorageProof))}
+	check.Account = verifyAccount(stateRoot, address, p)
+	if len(p.StorageProof) != len(slots) {
+		check.Account = errors.Join(check.Account,
+			fmt.Errorf("%d storage proofs for %d slots", len(p.StorageProof), len(slots)))
+	}
+	for i := range p.StorageProof {
+		var want string
+		if i < len(slots) {
+			want = slots[i]
+		}
+		check.Slots[i] = verifySlot(p.StorageHash, want, &p.StorageProof[i])
+	}
+	return check
+}
+
+// verifyAccount proves p's account fields against stateRoot.
+func verifyAccount(stateRoot, address string, p *AccountProof) error {
+	addr, err := decodeHexField("address", address)
+	if err != nil {
+		return err
+	}
+	if got, _ := decodeHexField("address", p.Address); !bytes.Equal(got, addr) {
+		return fmt.Errorf("proof is for %s, not %s", p.Address, address)
+	}
+	root, err := decodeHexField("stateRoot", stateRoot)
+	if err != nil {
+		return err
+	}
+
+	proven, err := walkProof(root, Keccak256(addr), p.AccountProof)
+	if err != nil {
+		return err
+	}
+	if proven == nil {
+		// Absent. Nodes report the code hash of a missing account as
+		// either keccak256("") or zero.
+		zero := "0x" + strings.Repeat("00", 32)
+		if !isZeroQuantity(p.Nonce) || !isZeroQuantity(p.Balance) ||
+			!strings.EqualFold(p.StorageHash, emptyTrieRoot) ||
+			!(strings.EqualFold(p.CodeHash, emptyCodeHash) || strings.EqualFold(p.CodeHash, zero)) {
+			return fmt.Errorf("state trie proves the account does not exist, but non-empty fields were returned")
+		}
+		return nil
+	}
+
+	d := fieldDecoder{}
+	claimed := rlpList(
+		d.quantity("nonce", p.Nonce),
+		d.quantity("balance", p.Balance),
+		d.fixed("storageHash", p.StorageHash, 32),
+		d.fixed("codeHash", p.CodeHash, 32),
+	)
+	if d.err != nil {
+		return d.err
+	}
+	if !bytes.Equal(proven, claimed) {
+		return fmt.Errorf("returned nonce, balance, storageHash or codeHash differ from the proven account")
+	}
+	return nil
+}
+
+// verifySlot proves one storage value against storageHash.
+func verifySlot(storageHash, wantKey string, sp *StorageProof) error {
+	key, err := slotKey(sp.Key)
+	if err != nil {
+		return err
+	}
+	if want, err := slotKey(wantKey); err != nil || !bytes.Equal(want, key) {
+		return fmt.Errorf("proof is for slot %s, not %s", sp.Key, wantKey)
+	}
+	root, err := decodeHexField("storageHash", storageHash)
+	if err != nil {
+		return err
+	}
+	value, ok := hexQuantity(sp.Value)
+	if !ok {
+		return fmt.Errorf("value %q is not a hex quantity", sp.Value)
+	}
+
+	proven, err := walkProof(root, Keccak256(key), sp.Proof)
+	if err != nil {
+		return err
+	}
+	var provenValue []byte // Absent slots are zero
+	if proven != nil {
+		payload, isList, rest, err := rlpSplit(proven)
+		if err != nil || isList || len(rest) > 0 {
+			return fmt.Errorf("proven slot value is not an RLP string")
+		}
+		provenValue = payload
+	}
+	if !bytes.Equal(provenValue, value.Bytes()) {
+		return fmt.Errorf("returned value %s, proven 0x%x", sp.Value, new(big.Int).SetBytes(provenValue))
+	}
+	return nil
+}
+
+// slotKey decodes a storage slot and left-pads it to 32 bytes. Nodes echo
+// the key as given, which may be a short quantity like "0x0".
+func slotKey(s string) ([]byte, error) {
+	digits := strings.TrimPrefix(s, "0x")
+	if len(digits)%2 == 1 {
+		digits = "0" + digits
+	}
+	raw, err := hex.DecodeString(digits)
+	if s == "" || err != nil || len(raw) > 32 {
+		return nil, fmt.Errorf("storage key %q is not a 32-byte hex value", s)
+	}
+	return append(make([]byte, 32-len(raw)), raw...), nil
+}
+
+// walkProof follows proof from root along key and returns the value stored
+// there, or nil if the proof shows there is none.
+func walkProof(root, key []byte, proof []string) ([]byte, error) {
+	if len(proof) == 0 && "0x"+hex.EncodeToString(root) == emptyTrieRoot {
+		return nil, nil
+	}
+	path := keyNibbles(key)
+	want := root // Hash the next listed node must have
+	var node []byte
+	next := 0
+	for {
+		if node == nil {
+			if next >= len(proof) {
+				return nil, fmt.Errorf("proof ends after %d nodes without reaching the key", next)
+			}
+			raw, err := decodeHexField("proof node", proof[next])
+			if err != nil {
+				return nil, err
+			}
+			if !bytes.Equal(Keccak256(raw), want) {
+				return nil, fmt.Errorf("proof node %d does not hash to its parent's reference", next)
+			}
+			node = raw
+			next++
+		}
+
+		items, err := rlpItems(node)
+		if err != nil {
+			return nil, fmt.Errorf("proof node %d: %w", next-1, err)
+		}
+		var ref []byte
+		switch len(items) {
+		case 17:
+			if len(path) == 0 {
+				return rlpStringValue(items[16])
+			}
+			ref, path = items[path[0]], path[1:]
+		case 2:
+			compact, err := rlpStringValue(items[0])
+			if err != nil {
+				return nil, err
+			}
+			nibbles, leaf := decodeHexPrefix(compact)
+			if leaf {
+				if !bytes.Equal(nibbles, path) {
+					return nil, nil // A different key's leaf: ours is absent
+				}
+				return rlpStringValue(items[1])
+			}
+			if !bytes.HasPrefix(path, nibbles) {
+				return nil, nil // The extension leads elsewhere
+			}
+			ref, path = items[1], path[len(nibbles):]
+		default:
+			return nil, fmt.Errorf("proof node %d has %d items", next-1, len(items))
+		}
+
+		// Follow the reference: empty (absent), embedded node, or hash.
+		payload, isList, _, err := rlpSplit(ref)
+		switch {
+		case err != nil:
+			return nil, err
+		case isList:
+			node = ref
+		case len(payload) == 0:
+			return nil, nil
+		case len(payload) == 32:
+			want, node = payload, nil
+		default:
+			return nil, fmt.Errorf("proof node %d has a %d-byte child reference", next-1, len(payload))
+		}
+	}
+}
+
+// rlpStringValue decodes an encoded RLP string item. An empty value means
+// absent and returns nil.
+func rlpStringValue(item []byte) ([]byte, error) {
+	payload, isList, _, err := rlpSplit(item)
+	if err != nil {
+		return nil, err
+	}
+	if isList {
+		return nil, errors.New("rlp: expected a string, got a list")
+	}
+	if len(payload) == 0 {
+		return nil, nil
+	}
+	return payload, nil
+}
+
+// decodeHexPrefix is the inverse of hexPrefix (trie.go).
+func decodeHexPrefix(compact []byte) (nibbles []byte, leaf bool) {
+	if len(compact) == 0 {
+		return nil, false
+	}
+	n := keyNibbles(compact)
+	flag := n[0]
+	leaf = flag >= 2
+	if flag%2 == 1 {
+		return n[1:], leaf // Odd: the first nibble shares the flag byte
+	}
+	return n[2:], leaf
+}
+
+// isZeroQuantity reports whether s is a hex zero ("0x0", "0x00").
+func isZeroQuantity(s string) bool {
+	v, ok := hexQuantity(s)
+	return ok && v.Sign() == 0
+}
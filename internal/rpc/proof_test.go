@@ -0,0 +1,186 @@
+package rpc
+
+import (
+	"encoding/hex"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// secureTrie builds a keccak-keyed trie (like the state and storage tries)
+// and returns its root and a proof function for any key.
+func secureTrie(kv map[string][]byte) (root string, prove func(key []byte) []string) {
+	var entries []trieEntry
+	for k, v := range kv {
+		entries = append(entries, trieEntry{path: keyNibbles(Keccak256([]byte(k))), value: v})
+	}
+	sort.Slice(entries, func(i, j int) bool { return string(entries[i].path) < string(entries[j].path) })
+	root = "0x" + hex.EncodeToString(trieRoot(entries))
+
+	prove = func(key []byte) []string {
+		var proof []string
+		collectProof(entries, 0, keyNibbles(Keccak256(key)), &proof)
+		return proof
+	}
+	return root, prove
+}
+
+// collectProof mirrors trieNode, appending every hashed node on the way to
+// path — the format eth_getProof returns.
+func collectProof(entries []trieEntry, depth int, path []byte, proof *[]string) {
+	enc := trieNode(entries, depth)
+	if depth == 0 || len(enc) >= 32 {
+		*proof = append(*proof, "0x"+hex.EncodeToString(enc))
+	}
+	if len(entries) <= 1 {
+		return
+	}
+	first, last := entries[0].path, entries[len(entries)-1].path
+	shared := 0
+	for depth+shared < len(first) && depth+shared < len(last) && first[depth+shared] == last[depth+shared] {
+		shared++
+	}
+	if shared > 0 {
+		if string(path[depth:depth+shared]) == string(first[depth:depth+shared]) {
+			collectProof(entries, depth+shared, path, proof)
+		}
+		return
+	}
+	var next []trieEntry
+	for _, e := range entries {
+		if e.path[depth] == path[depth] {
+			next = append(next, e)
+		}
+	}
+	if len(next) > 0 {
+		collectProof(next, depth+1, path, proof)
+	}
+}
+
+func addr(b byte) string { return "0x" + strings.Repeat(hex.EncodeToString([]byte{b}), 20) }
+
+func TestVerifyProof(t *testing.T) {
+	slot0 := make([]byte, 32)
+	slot1 := append(make([]byte, 31), 1)
+	storageRoot, proveSlot := secureTrie(map[string][]byte{
+		string(slot0): rlpBytes([]byte{0x2a}),       // 42
+		string(slot1): rlpBytes([]byte{0x01, 0x00}), // 256
+	})
+
+	account := func(nonce, balance uint64, storage string) []byte {
+		s, _ := hex.DecodeString(storage[2:])
+		c, _ := hex.DecodeString(emptyCodeHash[2:])
+		return rlpList(rlpUint(nonce), rlpUint(balance), rlpBytes(s), rlpBytes(c))
+	}
+	state := map[string][]byte{}
+	for i, a := range []string{addr(0x11), addr(0x22), addr(0x33)} {
+		raw, _ := hex.DecodeString(a[2:])
+		state[string(raw)] = account(uint64(i), 1000*uint64(i+1), emptyTrieRoot)
+	}
+	target, _ := hex.DecodeString(addr(0x44)[2:])
+	state[string(target)] = account(7, 5000, storageRoot)
+	stateRoot, proveAccount := secureTrie(state)
+
+	proof := func() *AccountProof {
+		return &AccountProof{
+			Address: addr(0x44), Nonce: "0x7", Balance: "0x1388",
+			StorageHash: storageRoot, CodeHash: emptyCodeHash,
+			AccountProof: proveAccount(target),
+			StorageProof: []StorageProof{
+				{Key: "0x0", Value: "0x2a", Proof: proveSlot(slot0)},
+				{Key: "0x1", Value: "0x100", Proof: proveSlot(slot1)},
+				{Key: "0x5", Value: "0x0", Proof: proveSlot(append(make([]byte, 31), 5))}, // Absent
+			},
+		}
+	}
+	slots := []string{"0x0", "0x01", "0x5"}
+
+	if c := VerifyProof(stateRoot, addr(0x44), slots, proof()); !c.OK() || len(c.Problems()) != 0 {
+		t.Fatalf("valid proof: %q", c.Problems())
+	}
+
+	p := proof()
+	p.Balance = "0x1389"
+	if c := VerifyProof(stateRoot, addr(0x44), slots, p); c.Account == nil || !strings.Contains(c.Account.Error(), "differ from the proven account") {
+		t.Fatalf("wrong balance: %q", c.Problems())
+	}
+
+	p = proof()
+	p.StorageProof[1].Value = "0x101"
+	p.StorageProof[2].Value = "0x1"
+	c := VerifyProof(stateRoot, addr(0x44), slots, p)
+	if c.Account != nil || c.Slots[0] != nil || c.Slots[1] == nil || c.Slots[2] == nil {
+		t.Fatalf("wrong slot values: %q", c.Problems())
+	}
+	if !strings.Contains(c.Slots[1].Error(), "returned value 0x101, proven 0x100") {
+		t.Fatalf("slot 1: %v", c.Slots[1])
+	}
+
+	// A node that does not hash to its reference breaks the chain.
+	p = proof()
+	last := p.AccountProof[len(p.AccountProof)-1]
+	flipped := "0"
+	if strings.HasSuffix(last, "0") {
+		flipped = "1"
+	}
+	p.AccountProof[len(p.AccountProof)-1] = last[:len(last)-1] + flipped
+	if c := VerifyProof(stateRoot, addr(0x44), slots, p); c.Account == nil || !strings.Contains(c.Account.Error(), "does not hash") {
+		t.Fatalf("tampered node: %q", c.Problems())
+	}
+
+	// The same proof checked against another block's root.
+	if c := VerifyProof(emptyOmmer, addr(0x44), slots, proof()); c.OK() {
+		t.Fatal("proof verified against the wrong state root")
+	}
+
+	// A proof for another account, or another slot, is not accepted.
+	if c := VerifyProof(stateRoot, addr(0x55), slots, proof()); c.Account == nil || !strings.Contains(c.Account.Error(), "proof is for") {
+		t.Fatalf("other account: %q", c.Problems())
+	}
+	if c := VerifyProof(stateRoot, addr(0x44), []string{"0x0", "0x2", "0x5"}, proof()); c.Slots[1] == nil {
+		t.Fatal("proof for another slot accepted")
+	}
+}
+
+func TestVerifyProof_absentAccount(t *testing.T) {
+	state := map[string][]byte{}
+	for _, a := range []string{addr(0x11), addr(0x22)} {
+		raw, _ := hex.DecodeString(a[2:])
+		state[string(raw)] = rlpList(rlpUint(1), rlpUint(1), rlpBytes(mustHex(emptyTrieRoot[2:])), rlpBytes(mustHex(emptyCodeHash[2:])))
+	}
+	stateRoot, prove := secureTrie(state)
+	missing, _ := hex.DecodeString(addr(0x99)[2:])
+	p := &AccountProof{
+		Address: addr(0x99), Nonce: "0x0", Balance: "0x0", StorageHash: emptyTrieRoot, CodeHash: zeroHash,
+		AccountProof: prove(missing),
+		StorageProof: []StorageProof{{Key: "0x0", Value: "0x0", Proof: []string{}}},
+	}
+	if c := VerifyProof(stateRoot, addr(0x99), []string{"0x0"}, p); !c.OK() {
+		t.Fatalf("absent account: %q", c.Problems())
+	}
+
+	p.Balance = "0x1"
+	if c := VerifyProof(stateRoot, addr(0x99), []string{"0x0"}, p); c.OK() || !strings.Contains(c.Account.Error(), "does not exist") {
+		t.Fatalf("absent account with balance: %q", c.Problems())
+	}
+}
+
+func TestSlotKeyAndHexPrefixDecoding(t *testing.T) {
+	k, err := slotKey("0x1")
+	if err != nil || len(k) != 32 || k[31] != 1 {
+		t.Fatalf("slotKey = %x, %v", k, err)
+	}
+	if _, err := slotKey("0x" + strings.Repeat("00", 33)); err == nil {
+		t.Fatal("33-byte slot accepted")
+	}
+
+	for _, tc := range []struct {
+		nibbles []byte
+		leaf    bool
+	}{{[]byte{1, 2, 3}, true}, {[]byte{1, 2}, false}, {nil, true}} {
+		got, leaf := decodeHexPrefix(hexPrefix(tc.nibbles, tc.leaf))
+		if string(got) != string(tc.nibbles) || leaf != tc.leaf {
+			t.Fatalf("round trip of %v/%v = %v/%v", tc.nibbles, tc.leaf, got, leaf)
+		}
+	}
+}
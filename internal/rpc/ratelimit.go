@@ -0,0 +1,150 @@
+// =============================================================================
+// FILE: internal/rpc/ratelimit.go
+// ROLE: Client-Side Rate Limiting — Stay Inside a Provider's Quota
+// =============================================================================
+//
+// SYSTEM CONTEXT
+// ==============
+// Free and metered tiers cap requests per second. `test` fires a sample
+// every 200ms per provider and `monitor` fans out freely; once the cap is
+// hit the provider answers 429, and those failures land in the reliability
+// numbers even though the node itself is fine. A per-provider limit keeps
+// the tool inside the quota, so a 429 means the quota changed rather than
+// that we flooded it.
+//
+// TOKEN BUCKET
+// ============
+// The bucket holds up to Burst tokens and refills at RPS tokens per second.
+// Each request takes one token (a batch takes one per element, which is how
+// providers count them):
+//
+//   tokens ─┬─ full (burst) ──▶ requests go straight through
+//           ├─ refilling    ──▶ the next request waits for its token
+//           └─ in debt      ──▶ a batch larger than the burst waits until
+//                               the debt is repaid at RPS
+//
+// Taking tokens is a reservation: the bucket may go negative, and the caller
+// sleeps until its share has been refilled. Requests are therefore served in
+// the order they arrived, and a burst of N concurrent callers is spread out
+// at exactly RPS instead of retrying in a loop.
+//
+// ONE BUCKET PER PROVIDER
+// =======================
+// A command can build several Clients for the same provider (chaincheck,
+// one per transport policy in `test --transport all`). They all receive the
+// SAME *RateLimiter through WithRateLimiter, so the quota holds for the
+// provider as a whole, not per Client.
+//
+// QUEUE TIME IS NOT LATENCY
+// =========================
+// Call and CallBatch wait on the limiter BEFORE their latency timer starts.
+// Time spent queued is reported separately in Timing.Queue, so a tight
+// limit never shows up as a slow provider.
+// =============================================================================
+
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// RateLimiter is a token bucket shared by every Client of one provider.
+// The zero value is not usable; create one with NewRateLimiter. A nil
+// *RateLimiter never waits.
+type RateLimiter struct {
+	rps   float64 // Refill rate, tokens per second
+	burst float64 // Bucket capacity
+
+	mu     sync.Mutex
+	tokens float64   // Tokens available now; negative = reserved ahead
+	last   time.Time // When tokens was last brought up to date
+}
+
+// NewRateLimiter returns a limiter allowing rps requests per second with
+// bursts of up to burst requests. burst below 1 is treated as 1.
+func NewRateLimiter(rps float64, burst int) *RateLimiter {
+	b := math.Max(float64(burst), 1)
+	return &RateLimiter{rps: rps, burst: b, tokens: b, last: time.Now()}
+}
+
+// String describes the limit, e.g. "5 rps, burst 10".
+func (l *RateLimiter) String() string {
+	return fmt.Sprintf("%g rps, burst %g", l.rps, l.burst)
+}
+
+// reserve takes n tokens at now and returns how long the caller must wait
+// before they are really available.
+func (l *RateLimiter) reserve(n int, now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if elapsed := now.Sub(l.last); elapsed > 0 {
+		l.tokens = math.Min(l.burst, l.tokens+elapsed.Seconds()*l.rps)
+		l.last = now
+	}
+	l.tokens -= float64(n)
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens / l.rps * float64(time.Second))
+}
+
+// cancel returns n reserved tokens to the bucket, for a caller that gave up
+// waiting.
+func (l *RateLimiter) cancel(n int) {
+	l.mu.Lock()
+	l.tokens = math.Min(l.burst, l.tokens+float64(n))
+	l.mu.Unlock()
+}
+
+// Wait blocks until n requests may be sent and returns the time spent
+// waiting. If ctx ends first, the reservation is released and ctx's error
+// is returned.
+func (l *RateLimiter) Wait(ctx context.Context, n int) (time.Duration, error) {
+	if l == nil {
+		return 0, nil
+	}
+	start := time.Now()
+	delay := l.reserve(n, start)
+	if delay == 0 {
+		return 0, nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return time.Since(start), nil
+	case <-ctx.Done():
+		l.cancel(n)
+		return time.Since(start), ctx.Err()
+	}
+}
+
+// WithRateLimiter makes the Client wait on l before every request. Pass the
+// same limiter to every Client of a provider so they share its quota.
+// WSClient ignores it: a subscription is one request, not a stream of them.
+func WithRateLimiter(l *RateLimiter) Option { return func(o *options) { o.limiter = l } }
+
+// throttle waits for n tokens before a request and returns the queue time.
+// A context that ends while queued surfaces as a wrapped context error, so
+// Classify reports a deadline as a timeout like any other.
+func (c *Client) throttle(ctx context.Context, n int) (time.Duration, error) {
+	queued, err := c.limiter.Wait(ctx, n)
+	if err != nil {
+		return queued, fmt.Errorf("queued %s behind rate limit (%s): %w", queued.Round(time.Millisecond), c.limiter, err)
+	}
+	return queued, nil
+}
+
+// recordQueue stores the queue time in the Timing attached to ctx, if any.
+// post() overwrites the whole Timing when it finishes, so this runs after it.
+func recordQueue(ctx context.Context, queued time.Duration) {
+	if t := timingFrom(ctx); t != nil {
+		t.Queue = queued
+	}
+}
@@ -0,0 +1,95 @@
+package rpc
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter_reserve(t *testing.T) {
+	l := NewRateLimiter(10, 2)
+	now := l.last
+	for i, want := range []time.Duration{0, 0, 100 * time.Millisecond, 200 * time.Millisecond} {
+		if got := l.reserve(1, now); got != want {
+			t.Fatalf("request %d: wait %v, want %v", i, got, want)
+		}
+	}
+	// Half a second later the 2-token debt is repaid and 3 tokens have
+	// accrued — but the bucket never holds more than the burst.
+	now = now.Add(500 * time.Millisecond)
+	if got := l.reserve(2, now); got != 0 {
+		t.Fatalf("after refill: wait %v", got)
+	}
+	if got := l.reserve(3, now); got != 300*time.Millisecond {
+		t.Fatalf("batch of 3 on an empty bucket: wait %v", got)
+	}
+	l.cancel(3)
+	if got := l.reserve(1, now); got != 100*time.Millisecond {
+		t.Fatalf("after cancel: wait %v", got)
+	}
+}
+
+func TestRateLimiter_nilNeverWaits(t *testing.T) {
+	var l *RateLimiter
+	if d, err := l.Wait(context.Background(), 100); d != 0 || err != nil {
+		t.Fatalf("nil limiter: %v, %v", d, err)
+	}
+}
+
+func TestClient_rateLimitQueueIsNotLatency(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(echoIDs(r, `{"jsonrpc":"2.0","id":1,"result":"0x10"}`))
+	}))
+	defer srv.Close()
+
+	// Two clients of one provider share the limiter: the second call has to
+	// wait for a token even though it uses a different Client.
+	limiter := NewRateLimiter(10, 1)
+	a := NewClient("t", srv.URL, 2*time.Second, WithRateLimiter(limiter))
+	b := NewClient("t", srv.URL, 2*time.Second, WithRateLimiter(limiter))
+
+	var first, second Timing
+	if _, _, err := a.BlockNumber(WithTiming(context.Background(), &first)); err != nil {
+		t.Fatal(err)
+	}
+	_, latency, err := b.BlockNumber(WithTiming(context.Background(), &second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Queue != 0 {
+		t.Errorf("first call queued %v", first.Queue)
+	}
+	if second.Queue < 80*time.Millisecond {
+		t.Errorf("second call queued %v, want ~100ms", second.Queue)
+	}
+	if latency >= second.Queue {
+		t.Errorf("latency %v includes the %v queue time", latency, second.Queue)
+	}
+}
+
+func TestClient_rateLimitBatchAndDeadline(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(echoIDs(r, `[{"jsonrpc":"2.0","id":1,"result":"0x1"},{"jsonrpc":"2.0","id":2,"result":"0x2"}]`))
+	}))
+	defer srv.Close()
+
+	c := NewClient("t", srv.URL, 2*time.Second, WithRateLimiter(NewRateLimiter(1, 2)))
+	if _, _, err := c.CallBatch(context.Background(), []BatchElem{{Method: "eth_blockNumber"}, {Method: "eth_chainId"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The batch took both tokens; the next token is a second away, past
+	// this call's deadline.
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	var tm Timing
+	_, _, err := c.BlockNumber(WithTiming(ctx, &tm))
+	if Classify(err) != CategoryTimeout {
+		t.Fatalf("queued past the deadline: %v (%s)", err, Classify(err))
+	}
+	if tm.Queue < 40*time.Millisecond || tm.Total != 0 {
+		t.Fatalf("timing %+v: want queue time and no exchange", tm)
+	}
+}
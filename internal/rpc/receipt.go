@@ -0,0 +1,399 @@
+// =============================================================================
+// FILE: internal/rpc/receipt.go
+// ROLE: Receipt Data Model — Execution Results, Logs, and Block Receipt Fetching
+// =============================================================================
+//
+// SYSTEM CONTEXT
+// ==============
+// A block says WHAT was submitted; receipts say WHAT HAPPENED. Every executed
+// transaction produces exactly one receipt with:
+//
+//   - status             1 = success, 0 = reverted (Byzantium and later)
+//   - gasUsed            gas actually consumed (the block only has the LIMIT)
+//   - effectiveGasPrice  price per gas actually charged
+//   - logs               events emitted by contracts (ERC-20 Transfer, ...)
+//   - contractAddress    set when the transaction deployed a contract
+//
+// Receipts are not part of the block body, so nodes serve them separately.
+// There are two ways to get them:
+//
+//   eth_getTransactionReceipt(hash)   one receipt per call — universal
+//   eth_getBlockReceipts(block)       every receipt in one call — newer, and
+//                                     not offered by every provider
+//
+// GetBlockReceipts tries the second and falls back to the first (one call per
+// transaction, a few at a time) when the provider says the method does not
+// exist. BlockReceipts.Method records which path was taken, since "this
+// provider has no eth_getBlockReceipts" is itself worth knowing.
+//
+// COMPLETENESS
+// ============
+// Providers have been known to return truncated receipt lists, receipts from
+// a different fork of the block, or receipts out of order. VerifyReceipts
+// checks a receipt list against its block:
+//
+//   block.transactions[i] ══ receipts[i].transactionHash   (same tx, same order)
+//   block.hash            ══ receipts[i].blockHash         (same block)
+//   cumulativeGasUsed[i] - cumulativeGasUsed[i-1] ══ gasUsed[i]
+//   cumulativeGasUsed[last] ══ block.gasUsed               (nothing missing)
+//
+// Those checks compare the list with the block's transaction hashes, not
+// with anything the header commits to. VerifyReceiptsRoot goes further: the
+// header's receiptsRoot is the trie root (trie.go) over every receipt's
+// consensus encoding,
+//
+//   legacy   rlp([statusOrRoot, cumulativeGasUsed, logsBloom, logs])
+//   typed    type || rlp([...same four...])
+//   log      [address, [topic, ...], data]
+//
+// where statusOrRoot is the status as an integer (1 → 0x01, 0 → empty)
+// since Byzantium and the 32-byte intermediate state root before it. gasUsed,
+// effectiveGasPrice and the hashes are NOT part of it — nodes derive them.
+// bloom.go checks the blooms the same way.
+// =============================================================================
+
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// =============================================================================
+// SECTION 1: Wire Types
+// =============================================================================
+
+// Log is one event emitted during a transaction. Topics[0] is usually the
+// event signature hash (e.g. keccak256("Transfer(address,address,uint256)")).
+type Log struct {
+	Address          string   `json:"address"`
+	Topics           []string `json:"topics"`
+	Data             string   `json:"data"`
+	BlockNumber      string   `json:"blockNumber"`
+	BlockHash        string   `json:"blockHash"`
+	TransactionHash  string   `json:"transactionHash"`
+	TransactionIndex string   `json:"transactionIndex"`
+	LogIndex         string   `json:"logIndex"`
+	Removed          bool     `json:"removed"` // True if dropped by a reorg (subscriptions only)
+}
+
+// Receipt holds one transaction receipt, exactly as the node returned it.
+//
+// Status is "0x1" or "0x0". Receipts from before Byzantium (block 4,370,000)
+// have no status and carry an intermediate state Root instead; for those,
+// neither Succeeded nor Failed is true.
+type Receipt struct {
+	TransactionHash   string `json:"transactionHash"`
+	TransactionIndex  string `json:"transactionIndex"`
+	BlockHash         string `json:"blockHash"`
+	BlockNumber       string `json:"blockNumber"`
+	From              string `json:"from"`
+	To                string `json:"to,omitempty"`              // Empty for contract creation
+	ContractAddress   string `json:"contractAddress,omitempty"` // Set only for contract creation
+	Type              string `json:"type,omitempty"`
+	Status            string `json:"status,omitempty"` // Absent before Byzantium
+	Root              string `json:"root,omitempty"`   // Present only before Byzantium
+	CumulativeGasUsed string `json:"cumulativeGasUsed"`
+	GasUsed           string `json:"gasUsed"`
+	EffectiveGasPrice string `json:"effectiveGasPrice,omitempty"`
+	BlobGasUsed       string `json:"blobGasUsed,omitempty"`  // EIP-4844 transactions only
+	BlobGasPrice      string `json:"blobGasPrice,omitempty"` // EIP-4844 transactions only
+	LogsBloom         string `json:"logsBloom"`
+	Logs              []Log  `json:"logs"`
+}
+
+// Succeeded reports whether the receipt has status 1.
+func (r *Receipt) Succeeded() bool { return r.Status == "0x1" }
+
+// Failed reports whether the receipt has status 0 (the transaction reverted
+// but was still included, and still paid for its gas).
+func (r *Receipt) Failed() bool { return r.Status == "0x0" }
+
+// ParsedReceipt holds the numeric receipt fields as native Go types.
+type ParsedReceipt struct {
+	TransactionHash   string
+	Index             uint64
+	CumulativeGasUsed uint64
+	GasUsed           uint64
+	EffectiveGasPrice *big.Int // nil if the node did not report it
+	BlobGasUsed       uint64
+	BlobGasPrice      *big.Int // nil for non-blob transactions
+	LogCount          int
+}
+
+// Parsed converts the hex fields into a ParsedReceipt. As with
+// Block.Parsed, malformed hex becomes zero rather than an error.
+func (r *Receipt) Parsed() ParsedReceipt {
+	index, _ := ParseHexUint64(r.TransactionIndex)
+	cumulative, _ := ParseHexUint64(r.CumulativeGasUsed)
+	gasUsed, _ := ParseHexUint64(r.GasUsed)
+	var blobGasUsed uint64
+	if r.BlobGasUsed != "" {
+		blobGasUsed, _ = ParseHexUint64(r.BlobGasUsed)
+	}
+	return ParsedReceipt{
+		TransactionHash:   r.TransactionHash,
+		Index:             index,
+		CumulativeGasUsed: cumulative,
+		GasUsed:           gasUsed,
+		EffectiveGasPrice: optionalBigInt(r.EffectiveGasPrice),
+		BlobGasUsed:       blobGasUsed,
+		BlobGasPrice:      optionalBigInt(r.BlobGasPrice),
+		LogCount:          len(r.Logs),
+	}
+}
+
+// Fee returns what the transaction paid in wei: gasUsed × effectiveGasPrice,
+// plus blobGasUsed × blobGasPrice for blob transactions. Returns nil if the
+// receipt has no effectiveGasPrice.
+func (r *Receipt) Fee() *big.Int {
+	p := r.Parsed()
+	if p.EffectiveGasPrice == nil {
+		return nil
+	}
+	fee := new(big.Int).Mul(new(big.Int).SetUint64(p.GasUsed), p.EffectiveGasPrice)
+	if p.BlobGasPrice != nil && p.BlobGasUsed > 0 {
+		fee.Add(fee, new(big.Int).Mul(new(big.Int).SetUint64(p.BlobGasUsed), p.BlobGasPrice))
+	}
+	return fee
+}
+
+// =============================================================================
+// SECTION 2: Client Methods
+// =============================================================================
+
+// receiptFallbackParallelism bounds the concurrent eth_getTransactionReceipt
+// calls when falling back. A block has hundreds of transactions; firing them
+// all at once is a good way to get rate-limited halfway through.
+const receiptFallbackParallelism = 8
+
+// BlockReceipts is the result of GetBlockReceipts.
+type BlockReceipts struct {
+	Receipts []Receipt // In transaction order
+
+	// Method is "eth_getBlockReceipts", or "eth_getTransactionReceipt" when
+	// the provider lacked the former and receipts were fetched one by one.
+	Method string
+
+	// FallbackErr is why eth_getBlockReceipts was abandoned; nil when it
+	// was used.
+	FallbackErr error
+}
+
+// GetTransactionReceipt calls eth_getTransactionReceipt. A null result
+// (unknown or still-pending transaction) returns *NotFoundError.
+func (c *Client) GetTransactionReceipt(ctx context.Context, txHash string) (*Receipt, time.Duration, error) {
+	resp, latency, err := c.Call(ctx, "eth_getTransactionReceipt", txHash)
+	if err != nil {
+		return nil, latency, err
+	}
+	if isNullResult(resp.Result) {
+		return nil, latency, &NotFoundError{Method: "eth_getTransactionReceipt", Arg: txHash}
+	}
+	var r Receipt
+	if err := json.Unmarshal(resp.Result, &r); err != nil {
+		return nil, latency, fmt.Errorf("unmarshal receipt result: %w", err)
+	}
+	return &r, latency, nil
+}
+
+// GetBlockReceipts returns every receipt of a block, identified by number,
+// tag, or hash. It uses eth_getBlockReceipts when the provider supports it
+// and otherwise falls back to one eth_getTransactionReceipt per transaction.
+//
+// The returned latency is the wall time of whichever path produced the
+// receipts; for the fallback that includes the block lookup it needs.
+func (c *Client) GetBlockReceipts(ctx context.Context, blockNum string) (*BlockReceipts, time.Duration, error) {
+	resp, latency, err := c.Call(ctx, "eth_getBlockReceipts", blockNum)
+	if err != nil {
+		if !methodUnsupported(err, "eth_getBlockReceipts") {
+			return nil, latency, err
+		}
+		start := time.Now()
+		receipts, fbErr := c.receiptsOneByOne(ctx, blockNum)
+		if fbErr != nil {
+			return nil, time.Since(start), fbErr
+		}
+		return &BlockReceipts{
+			Receipts:    receipts,
+			Method:      "eth_getTransactionReceipt",
+			FallbackErr: err,
+		}, time.Since(start), nil
+	}
+
+	if isNullResult(resp.Result) {
+		return nil, latency, &NotFoundError{Method: "eth_getBlockReceipts", Arg: blockNum}
+	}
+	var receipts []Receipt
+	if err := json.Unmarshal(resp.Result, &receipts); err != nil {
+		return nil, latency, fmt.Errorf("unmarshal block receipts result: %w", err)
+	}
+	return &BlockReceipts{Receipts: receipts, Method: "eth_getBlockReceipts"}, latency, nil
+}
+
+// methodUnsupported reports whether err is the provider saying it does not
+// offer method at all, as opposed to a failure worth surfacing:
+//
+//	-32601                                         → unsupported
+//	"method not found", "unsupported method", ...  → unsupported
+//	"does not exist" / "not available" / "not supported"
+//	  in a message that names method               → unsupported
+//	anything else                                  → surface the error
+//
+// RPCError.Category is deliberately not used: it files "header not found"
+// and "unknown block" under CategoryNotFound too, and a provider that lacks
+// the BLOCK must report that, not be sent down the per-transaction path.
+func methodUnsupported(err error, method string) bool {
+	var rpcErr *RPCError
+	if !errors.As(err, &rpcErr) {
+		return false
+	}
+	msg := strings.ToLower(rpcErr.Message)
+	if rpcErr.Code == -32601 || containsAny(msg, "method not found", "unsupported method", "method not supported") {
+		return true
+	}
+	return strings.Contains(msg, strings.ToLower(method)) &&
+		containsAny(msg, "does not exist", "not available", "not supported")
+}
+
+// receiptsOneByOne is the fallback path: look up the block's transaction
+// hashes, then fetch each receipt individually, receiptFallbackParallelism at
+// a time. The first failure cancels the rest — a partial receipt list would
+// only be mistaken for an incomplete provider response.
+func (c *Client) receiptsOneByOne(ctx context.Context, blockNum string) ([]Receipt, error) {
+	block, _, err := c.GetBlock(ctx, blockNum)
+	if err != nil {
+		return nil, fmt.Errorf("receipt fallback: %w", err)
+	}
+
+	receipts := make([]Receipt, len(block.Transactions))
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(receiptFallbackParallelism)
+	for i, hash := range block.Transactions {
+		i, hash := i, hash
+		g.Go(func() error {
+			r, _, err := c.GetTransactionReceipt(gctx, hash)
+			if err != nil {
+				return fmt.Errorf("receipt fallback: tx %s: %w", hash, err)
+			}
+			receipts[i] = *r // Each goroutine owns its slot; no mutex needed
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return receipts, nil
+}
+
+// =============================================================================
+// SECTION 3: Completeness Check
+// =============================================================================
+
+// VerifyReceipts checks receipts against the block they claim to belong to
+// (see COMPLETENESS in the header) and returns one human-readable problem
+// per violation. An empty result means the list is complete and consistent.
+func VerifyReceipts(block *Block, receipts []Receipt) []string {
+	var problems []string
+	if len(receipts) != len(block.Transactions) {
+		problems = append(problems, fmt.Sprintf("got %d receipts for %d transactions",
+			len(receipts), len(block.Transactions)))
+	}
+
+	var prevCumulative uint64
+	for i := range receipts {
+		r := &receipts[i]
+		p := r.Parsed()
+		if i < len(block.Transactions) && !strings.EqualFold(r.TransactionHash, block.Transactions[i]) {
+			problems = append(problems, fmt.Sprintf("receipt %d is for tx %s, block has %s",
+				i, r.TransactionHash, block.Transactions[i]))
+		}
+		if r.BlockHash != "" && block.Hash != "" && !strings.EqualFold(r.BlockHash, block.Hash) {
+			problems = append(problems, fmt.Sprintf("receipt %d is from block %s, not %s",
+				i, r.BlockHash, block.Hash))
+		}
+		if p.Index != uint64(i) {
+			problems = append(problems, fmt.Sprintf("receipt %d has transactionIndex %d", i, p.Index))
+		}
+		if p.CumulativeGasUsed < prevCumulative || p.CumulativeGasUsed-prevCumulative != p.GasUsed {
+			problems = append(problems, fmt.Sprintf("receipt %d: cumulativeGasUsed %d - previous %d != gasUsed %d",
+				i, p.CumulativeGasUsed, prevCumulative, p.GasUsed))
+		}
+		prevCumulative = p.CumulativeGasUsed
+	}
+
+	if len(receipts) > 0 && len(receipts) == len(block.Transactions) {
+		if gasUsed, _ := ParseHexUint64(block.GasUsed); gasUsed != prevCumulative {
+			problems = append(problems, fmt.Sprintf("receipts account for %d gas, block used %d",
+				prevCumulative, gasUsed))
+		}
+	}
+	return problems
+}
+
+// =============================================================================
+// SECTION 4: receiptsRoot
+// =============================================================================
+
+// VerifyReceiptsRoot rebuilds block's receiptsRoot from receipts (see the
+// file header). Unlike VerifyReceipts it proves the receipts are the ones
+// the header commits to, down to every log.
+func VerifyReceiptsRoot(block *Block, receipts []Receipt) RootCheck {
+	check := RootCheck{Field: "receiptsRoot", Claimed: block.ReceiptsRoot, Count: len(receipts)}
+	items := make([][]byte, len(receipts))
+	for i := range receipts {
+		enc, err := encodeReceipt(&receipts[i])
+		if err != nil {
+			check.Err = fmt.Errorf("receipt %d: %w", i, err)
+			return check
+		}
+		items[i] = enc
+	}
+	check.Computed = OrderedTrieRoot(items)
+	return check
+}
+
+// encodeReceipt returns r's consensus encoding.
+func encodeReceipt(r *Receipt) ([]byte, error) {
+	d := fieldDecoder{}
+	typ := parseTxType(r.Type)
+	if typ > TxSetCode {
+		return nil, fmt.Errorf("unsupported receipt type %s", typ)
+	}
+
+	var outcome []byte
+	if r.Status != "" {
+		outcome = d.quantity("status", r.Status)
+	} else {
+		outcome = d.fixed("root", r.Root, 32) // Pre-Byzantium
+	}
+	logs := make([][]byte, len(r.Logs))
+	for i, l := range r.Logs {
+		topics := make([][]byte, len(l.Topics))
+		for j, t := range l.Topics {
+			topics[j] = d.fixed("log topic", t, 32)
+		}
+		logs[i] = rlpList(d.fixed("log address", l.Address, 20), rlpList(topics...), d.bytes("log data", l.Data))
+	}
+	payload := rlpList(
+		outcome,
+		d.quantity("cumulativeGasUsed", r.CumulativeGasUsed),
+		d.fixed("logsBloom", r.LogsBloom, bloomBytes),
+		rlpList(logs...),
+	)
+
+	if d.err != nil {
+		return nil, d.err
+	}
+	if typ == TxLegacy {
+		return payload, nil
+	}
+	return append([]byte{byte(typ)}, payload...), nil
+}
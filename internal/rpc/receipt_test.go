@@ -0,0 +1,257 @@
+package rpc
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func receiptJSON(i int, hash string, cumulative, gasUsed uint64, status string) string {
+	return fmt.Sprintf(`{"transactionHash":%q,"transactionIndex":"0x%x","blockHash":"0xblock",
+		"cumulativeGasUsed":"0x%x","gasUsed":"0x%x","effectiveGasPrice":"0x3b9aca00","status":%q,
+		"logs":[{"address":"0xc0","topics":["0xt"],"data":"0x","logIndex":"0x%x"}]}`,
+		hash, i, cumulative, gasUsed, status, i)
+}
+
+// receiptServer answers eth_getBlockByNumber with a two-transaction block
+// and eth_getTransactionReceipt per hash. eth_getBlockReceipts returns
+// blockReceiptsReply verbatim (with the id filled in).
+func receiptServer(t *testing.T, blockReceiptsReply string, perTxCalls *atomic.Int32) *httptest.Server {
+	t.Helper()
+	receipts := map[string]string{
+		"0xaa": receiptJSON(0, "0xaa", 21000, 21000, "0x1"),
+		"0xbb": receiptJSON(1, "0xbb", 71000, 50000, "0x0"),
+	}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req Request
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		var reply string
+		switch req.Method {
+		case "eth_getBlockReceipts":
+			reply = blockReceiptsReply
+		case "eth_getBlockByNumber":
+			reply = `{"jsonrpc":"2.0","id":1,"result":{"number":"0x1","hash":"0xblock","gasUsed":"0x11558","transactions":["0xaa","0xbb"]}}`
+		case "eth_getTransactionReceipt":
+			perTxCalls.Add(1)
+			reply = `{"jsonrpc":"2.0","id":1,"result":` + receipts[req.Params[0].(string)] + `}`
+		}
+		_, _ = w.Write([]byte(placeholderID.ReplaceAllString(reply, fmt.Sprintf(`"id":%d`, req.ID))))
+	}))
+}
+
+func TestClient_GetBlockReceipts_native(t *testing.T) {
+	var perTx atomic.Int32
+	reply := `{"jsonrpc":"2.0","id":1,"result":[` + receiptJSON(0, "0xaa", 21000, 21000, "0x1") + `]}`
+	srv := receiptServer(t, reply, &perTx)
+	defer srv.Close()
+
+	br, _, err := NewClient("t", srv.URL, 2*time.Second).GetBlockReceipts(context.Background(), "0x1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if br.Method != "eth_getBlockReceipts" || br.FallbackErr != nil || len(br.Receipts) != 1 || perTx.Load() != 0 {
+		t.Fatalf("got %+v (per-tx calls %d)", br, perTx.Load())
+	}
+	if got := br.Receipts[0].Logs; len(got) != 1 || got[0].Address != "0xc0" {
+		t.Fatalf("logs: %+v", got)
+	}
+}
+
+func TestClient_GetBlockReceipts_fallback(t *testing.T) {
+	var perTx atomic.Int32
+	reply := `{"jsonrpc":"2.0","id":1,"error":{"code":-32601,"message":"the method eth_getBlockReceipts does not exist/is not available"}}`
+	srv := receiptServer(t, reply, &perTx)
+	defer srv.Close()
+
+	br, _, err := NewClient("t", srv.URL, 2*time.Second).GetBlockReceipts(context.Background(), "0x1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if br.Method != "eth_getTransactionReceipt" || br.FallbackErr == nil || perTx.Load() != 2 {
+		t.Fatalf("got %+v (per-tx calls %d)", br, perTx.Load())
+	}
+	// Receipts come back in block order even though they were fetched concurrently.
+	if br.Receipts[0].TransactionHash != "0xaa" || br.Receipts[1].TransactionHash != "0xbb" {
+		t.Fatalf("order: %s, %s", br.Receipts[0].TransactionHash, br.Receipts[1].TransactionHash)
+	}
+	if !br.Receipts[0].Succeeded() || !br.Receipts[1].Failed() {
+		t.Fatalf("status: %+v", br.Receipts)
+	}
+}
+
+func TestClient_GetBlockReceipts_otherErrorNoFallback(t *testing.T) {
+	var perTx atomic.Int32
+	reply := `{"jsonrpc":"2.0","id":1,"error":{"code":-32005,"message":"rate limit exceeded"}}`
+	srv := receiptServer(t, reply, &perTx)
+	defer srv.Close()
+
+	_, _, err := NewClient("t", srv.URL, 2*time.Second).GetBlockReceipts(context.Background(), "0x1")
+	var rpcErr *RPCError
+	if !errors.As(err, &rpcErr) || rpcErr.Code != -32005 || perTx.Load() != 0 {
+		t.Fatalf("err=%v per-tx calls=%d", err, perTx.Load())
+	}
+}
+
+func TestClient_GetBlockReceipts_headerNotFoundNoFallback(t *testing.T) {
+	var perTx atomic.Int32
+	reply := `{"jsonrpc":"2.0","id":1,"error":{"code":-32000,"message":"header not found"}}`
+	srv := receiptServer(t, reply, &perTx)
+	defer srv.Close()
+
+	br, _, err := NewClient("t", srv.URL, 2*time.Second).GetBlockReceipts(context.Background(), "0x1")
+	var rpcErr *RPCError
+	if !errors.As(err, &rpcErr) || rpcErr.Message != "header not found" || br != nil || perTx.Load() != 0 {
+		t.Fatalf("br=%+v err=%v per-tx calls=%d", br, err, perTx.Load())
+	}
+}
+
+func TestMethodUnsupported(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{&RPCError{Code: -32601, Message: "Method not found"}, true},
+		{&RPCError{Code: -32000, Message: "the method eth_getBlockReceipts does not exist/is not available"}, true},
+		{&RPCError{Code: -32000, Message: "Unsupported method: eth_getBlockReceipts"}, true},
+		{&RPCError{Code: -32000, Message: "header not found"}, false},
+		{&RPCError{Code: -32000, Message: "block 0x1 does not exist"}, false},
+		{&RPCError{Code: -32602, Message: "unknown block"}, false},
+		{&HTTPError{StatusCode: 404}, false},
+	}
+	for _, tc := range tests {
+		if got := methodUnsupported(tc.err, "eth_getBlockReceipts"); got != tc.want {
+			t.Errorf("methodUnsupported(%v) = %v", tc.err, got)
+		}
+	}
+}
+
+func TestClient_GetTransactionReceipt_null(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(echoIDs(r, `{"jsonrpc":"2.0","id":1,"result":null}`))
+	}))
+	defer srv.Close()
+
+	_, _, err := NewClient("t", srv.URL, 2*time.Second).GetTransactionReceipt(context.Background(), "0xdead")
+	if Classify(err) != CategoryNotFound {
+		t.Fatalf("err=%v", err)
+	}
+}
+
+func TestReceipt_Fee(t *testing.T) {
+	r := Receipt{GasUsed: "0x5208", EffectiveGasPrice: "0x3b9aca00"} // 21000 × 1 gwei
+	if got := r.Fee().String(); got != "21000000000000" {
+		t.Fatalf("fee = %s", got)
+	}
+	r.BlobGasUsed, r.BlobGasPrice = "0x20000", "0x1" // + 131072 × 1 wei
+	if got := r.Fee().String(); got != "21000000131072" {
+		t.Fatalf("blob fee = %s", got)
+	}
+	if (&Receipt{GasUsed: "0x5208"}).Fee() != nil {
+		t.Fatal("fee without effectiveGasPrice should be nil")
+	}
+}
+
+func TestVerifyReceipts(t *testing.T) {
+	block := &Block{Hash: "0xblock", GasUsed: "0x11558", Transactions: []string{"0xaa", "0xbb"}}
+	decode := func(parts ...string) []Receipt {
+		var rs []Receipt
+		raw := "["
+		for i, p := range parts {
+			if i > 0 {
+				raw += ","
+			}
+			raw += p
+		}
+		if err := json.Unmarshal([]byte(raw+"]"), &rs); err != nil {
+			t.Fatal(err)
+		}
+		return rs
+	}
+
+	good := decode(receiptJSON(0, "0xaa", 21000, 21000, "0x1"), receiptJSON(1, "0xbb", 71000, 50000, "0x1"))
+	if p := VerifyReceipts(block, good); len(p) != 0 {
+		t.Fatalf("unexpected problems: %v", p)
+	}
+
+	truncated := good[:1]
+	if p := VerifyReceipts(block, truncated); len(p) != 1 {
+		t.Fatalf("truncated: %v", p)
+	}
+
+	swapped := decode(receiptJSON(0, "0xbb", 21000, 21000, "0x1"), receiptJSON(1, "0xaa", 71000, 50000, "0x1"))
+	if p := VerifyReceipts(block, swapped); len(p) != 2 {
+		t.Fatalf("swapped: %v", p)
+	}
+
+	badGas := decode(receiptJSON(0, "0xaa", 21000, 21000, "0x1"), receiptJSON(1, "0xbb", 70000, 50000, "0x1"))
+	if p := VerifyReceipts(block, badGas); len(p) != 2 { // cumulative step + block total
+		t.Fatalf("bad gas: %v", p)
+	}
+}
+
+func TestEncodeReceipt(t *testing.T) {
+	// status 1, cumulativeGasUsed 21000, empty bloom, no logs.
+	r := Receipt{Status: "0x1", CumulativeGasUsed: "0x5208", LogsBloom: zeroBloom, Logs: []Log{}}
+	want := "f90108" + "01" + "825208" + "b90100" + strings.Repeat("00", 256) + "c0"
+	enc, err := encodeReceipt(&r)
+	if err != nil || hex.EncodeToString(enc) != want {
+		t.Fatalf("legacy receipt = %x, %v", enc, err)
+	}
+
+	r.Type = "0x2"
+	if enc, _ := encodeReceipt(&r); hex.EncodeToString(enc) != "02"+want {
+		t.Fatalf("typed receipt = %x", enc)
+	}
+
+	// A failed receipt encodes status as the empty string; pre-Byzantium
+	// receipts carry a state root in its place.
+	r.Type, r.Status = "", "0x0"
+	if enc, _ := encodeReceipt(&r); hex.EncodeToString(enc[3:4]) != "80" {
+		t.Fatalf("failed receipt = %x", enc)
+	}
+	r.Status, r.Root = "", zeroHash
+	if enc, err := encodeReceipt(&r); err != nil || rlpListLen(t, enc) != 4 || enc[3] != 0xa0 {
+		t.Fatalf("pre-Byzantium receipt = %x, %v", enc, err)
+	}
+
+	r.Root, r.Type = "", "0x7e"
+	if _, err := encodeReceipt(&r); err == nil || !strings.Contains(err.Error(), "unsupported receipt type") {
+		t.Fatalf("deposit receipt: %v", err)
+	}
+}
+
+func TestVerifyReceiptsRoot(t *testing.T) {
+	receipts := []Receipt{
+		{Status: "0x1", CumulativeGasUsed: "0x5208", LogsBloom: zeroBloom},
+		{Type: "0x2", Status: "0x1", CumulativeGasUsed: "0xa410", LogsBloom: zeroBloom, Logs: []Log{transferLog()}},
+	}
+	items := make([][]byte, len(receipts))
+	for i := range receipts {
+		items[i], _ = encodeReceipt(&receipts[i])
+	}
+	block := &Block{ReceiptsRoot: OrderedTrieRoot(items)}
+	if c := VerifyReceiptsRoot(block, receipts); !c.OK() || c.Count != 2 {
+		t.Fatalf("matching receipts: %+v", c)
+	}
+
+	// One log altered, or a receipt dropped, changes the root.
+	receipts[1].Logs[0].Data = "0x02"
+	if c := VerifyReceiptsRoot(block, receipts); c.OK() || !strings.HasPrefix(c.Problem(), "receiptsRoot: 2 items hash to") {
+		t.Fatalf("altered log: %q", c.Problem())
+	}
+	if c := VerifyReceiptsRoot(block, receipts[:1]); c.OK() {
+		t.Fatal("truncated list verified")
+	}
+
+	if c := VerifyReceiptsRoot(mainnetBlock1(), nil); !c.OK() {
+		t.Fatalf("empty block: %+v", c)
+	}
+}
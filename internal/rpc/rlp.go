@@ -0,0 +1,163 @@
+// =============================================================================
+// FILE: internal/rpc/rlp.go
+// ROLE: RLP Encoding — Ethereum's Canonical Serialization
+// =============================================================================
+//
+// SYSTEM CONTEXT
+// ==============
+// A block hash is keccak256 of the RLP-encoded header (header.go). JSON-RPC
+// hands us the header as a JSON object of hex strings; to recompute the hash
+// we rebuild the exact bytes the consensus layer hashed, and that encoding
+// is RLP (Recursive Length Prefix).
+//
+// THE RULES
+// =========
+// RLP knows two things: byte strings and lists of items. A prefix byte says
+// which one follows and how long it is:
+//
+//   single byte < 0x80          the byte itself
+//   string, 0-55 bytes          0x80+len, bytes
+//   string, longer              0xb7+len(len), len (big-endian), bytes
+//   list, payload 0-55 bytes    0xc0+len, payload
+//   list, longer                0xf7+len(len), len (big-endian), payload
+//
+// Integers are big-endian byte strings with NO leading zeros, so 0 encodes
+// as the empty string (0x80), 1 as 0x01, 1024 as 0x82 0x04 0x00. Hashes,
+// addresses and the bloom are fixed-size byte strings and keep their zeros.
+//
+// The encoding helpers return already-encoded items, and rlpList wraps
+// encoded items into a list. Decoding is only needed to walk Merkle proofs
+// (proof.go): rlpSplit reads one item, rlpItems the items of a list.
+// =============================================================================
+
+package rpc
+
+import (
+	"encoding/binary"
+	"errors"
+	"math/big"
+)
+
+// rlpBytes encodes b as an RLP string.
+func rlpBytes(b []byte) []byte {
+	if len(b) == 1 && b[0] < 0x80 {
+		return []byte{b[0]}
+	}
+	return append(rlpHeader(0x80, len(b)), b...)
+}
+
+// rlpUint encodes v as an RLP integer (minimal big-endian bytes).
+func rlpUint(v uint64) []byte {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], v)
+	i := 0
+	for i < 8 && buf[i] == 0 {
+		i++
+	}
+	return rlpBytes(buf[i:])
+}
+
+// rlpBig encodes a non-negative v as an RLP integer. nil encodes as 0.
+func rlpBig(v *big.Int) []byte {
+	if v == nil {
+		return rlpBytes(nil)
+	}
+	return rlpBytes(v.Bytes()) // Bytes() is already minimal big-endian
+}
+
+// rlpList wraps already-encoded items into an RLP list.
+func rlpList(items ...[]byte) []byte {
+	size := 0
+	for _, it := range items {
+		size += len(it)
+	}
+	out := rlpHeader(0xc0, size)
+	for _, it := range items {
+		out = append(out, it...)
+	}
+	return out
+}
+
+// rlpHeader returns the prefix for a string (base 0x80) or list (base 0xc0)
+// whose payload is size bytes long.
+func rlpHeader(base byte, size int) []byte {
+	if size <= 55 {
+		return []byte{base + byte(size)}
+	}
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], uint64(size))
+	i := 0
+	for buf[i] == 0 {
+		i++
+	}
+	return append([]byte{base + 55 + byte(8-i)}, buf[i:]...)
+}
+
+// errRLPShort is returned when an item claims more bytes than remain.
+var errRLPShort = errors.New("rlp: item runs past end of input")
+
+// rlpSplit reads the item at the front of b and returns its payload, whether
+// it is a list, and the bytes after it.
+func rlpSplit(b []byte) (payload []byte, isList bool, rest []byte, err error) {
+	if len(b) == 0 {
+		return nil, false, nil, errRLPShort
+	}
+	p := b[0]
+	var offset, size int
+	switch {
+	case p < 0x80:
+		return b[:1], false, b[1:], nil
+	case p <= 0xb7:
+		offset, size = 1, int(p-0x80)
+	case p < 0xc0:
+		offset, size, err = rlpLongSize(b, int(p-0xb7))
+	case p <= 0xf7:
+		offset, size, isList = 1, int(p-0xc0), true
+	default:
+		isList = true
+		offset, size, err = rlpLongSize(b, int(p-0xf7))
+	}
+	if err != nil {
+		return nil, false, nil, err
+	}
+	if offset+size > len(b) {
+		return nil, false, nil, errRLPShort
+	}
+	return b[offset : offset+size], isList, b[offset+size:], nil
+}
+
+// rlpLongSize reads the n-byte big-endian length after a long-form prefix.
+func rlpLongSize(b []byte, n int) (offset, size int, err error) {
+	if n > 8 || 1+n > len(b) {
+		return 0, 0, errRLPShort
+	}
+	var v uint64
+	for _, c := range b[1 : 1+n] {
+		v = v<<8 | uint64(c)
+	}
+	if v > uint64(len(b)) {
+		return 0, 0, errRLPShort
+	}
+	return 1 + n, int(v), nil
+}
+
+// rlpItems splits the encoded list b into its encoded items.
+func rlpItems(b []byte) ([][]byte, error) {
+	payload, isList, rest, err := rlpSplit(b)
+	if err != nil {
+		return nil, err
+	}
+	if !isList || len(rest) > 0 {
+		return nil, errors.New("rlp: not a single list")
+	}
+	var items [][]byte
+	for len(payload) > 0 {
+		_, _, next, err := rlpSplit(payload)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, payload[:len(payload)-len(next)])
+		payload = next
+	}
+	return items, nil
+}
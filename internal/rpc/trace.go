@@ -0,0 +1,174 @@
+// =============================================================================
+// FILE: internal/rpc/trace.go
+// ROLE: Latency Attribution — Per-Phase Timing via net/http/httptrace
+// =============================================================================
+//
+// SYSTEM CONTEXT
+// ==============
+// Client.Call returns ONE duration: everything from "start sending" to
+// "response decoded". That number answers "how slow was it?" but not "WHY
+// was it slow?". A 400ms P99 could be a slow DNS resolver, a fresh TCP+TLS
+// handshake on a cold connection, or the provider's node taking 380ms to
+// answer. Those have completely different fixes.
+//
+// This file splits one HTTP exchange into phases using the standard
+// library's httptrace hooks, which net/http calls at each milestone:
+//
+//   start ─┬─ DNS ─┬─ Connect ─┬─ TLS ─┬─ (write) ─┬─ TTFB ─┬─ Body ─┬─ end
+//          │       │  (TCP)    │       │ request   │ server │ read   │
+//          │       │           │       │           │ + RTT  │ rest   │
+//          DNSStart DNSDone    TLSStart TLSDone  WroteRequest  FirstByte
+//
+// On a REUSED keep-alive connection the first three phases never happen —
+// net/http takes an idle connection from its pool — so DNS/Connect/TLS are
+// zero and Reused is true. That is exactly the difference between a "cold"
+// poll (monitor's default policy: no keep-alive) and a "warm" one (test
+// reuses one pooled connection for all samples) — see transport.go.
+//
+// HOW CALLERS OPT IN
+// ==================
+// Timing is attached to the CONTEXT rather than returned from Call, so every
+// existing caller (BlockNumber, GetBlock, CallBatch...) keeps its signature
+// and only callers that want the breakdown pay for it:
+//
+//   var t rpc.Timing
+//   height, latency, err := client.BlockNumber(rpc.WithTiming(ctx, &t))
+//   // t.DNS, t.Connect, t.TLS, t.TTFB, t.Body are now filled in
+//
+// This mirrors how httptrace itself works (httptrace.WithClientTrace also
+// rides on the context).
+// =============================================================================
+
+package rpc
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// Timing is the phase breakdown of one HTTP exchange.
+//
+// Phases that did not happen (for example DNS on a reused connection, or TLS
+// for a plain http:// endpoint) are zero. The phases do not necessarily add
+// up to Total: writing the request body and waiting for a pooled connection
+// fall between them.
+type Timing struct {
+	DNS     time.Duration // Name resolution (DNSStart → DNSDone)
+	Connect time.Duration // TCP connect (ConnectStart → ConnectDone)
+	TLS     time.Duration // TLS handshake (TLSHandshakeStart → TLSHandshakeDone)
+	TTFB    time.Duration // Request written → first response byte (server time + one RTT)
+	Body    time.Duration // First response byte → body fully read
+	Total   time.Duration // Whole HTTP exchange, excluding JSON decoding
+	Reused  bool          // True if the connection came from the keep-alive pool
+	Proto   string        // Protocol of the response, e.g. "HTTP/1.1" or "HTTP/2.0"
+	Queue   time.Duration // Waiting for the provider's rate limiter, before the exchange and outside Total
+}
+
+// timingKey is the context key for an attached *Timing. An unexported
+// struct type guarantees no other package can collide with it.
+type timingKey struct{}
+
+// WithTiming returns a context that makes Client record the phase breakdown
+// of the next HTTP exchange into t. The same *Timing is overwritten by each
+// exchange made with the returned context, so use one per call.
+func WithTiming(ctx context.Context, t *Timing) context.Context {
+	return context.WithValue(ctx, timingKey{}, t)
+}
+
+// timingFrom returns the *Timing attached by WithTiming, or nil.
+func timingFrom(ctx context.Context) *Timing {
+	t, _ := ctx.Value(timingKey{}).(*Timing)
+	return t
+}
+
+// phaseTracer collects raw timestamps from httptrace hooks.
+//
+// The hooks may fire from different goroutines (net/http dials in the
+// background, and may race several connection attempts), so every access
+// goes through the mutex.
+type phaseTracer struct {
+	mu sync.Mutex
+
+	start             time.Time
+	dnsStart, dnsDone time.Time
+	connectStart      time.Time
+	connectDone       time.Time
+	tlsStart, tlsDone time.Time
+	wroteRequest      time.Time
+	firstByte         time.Time
+	reused            bool
+	proto             string
+}
+
+// newPhaseTracer starts the clock and returns a context carrying the hooks.
+func newPhaseTracer(ctx context.Context) (*phaseTracer, context.Context) {
+	p := &phaseTracer{start: time.Now()}
+	stamp := func(dst *time.Time) {
+		p.mu.Lock()
+		if dst.IsZero() {
+			*dst = time.Now()
+		}
+		p.mu.Unlock()
+	}
+
+	trace := &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) { stamp(&p.dnsStart) },
+		DNSDone:  func(httptrace.DNSDoneInfo) { stamp(&p.dnsDone) },
+		// Only the first attempt's start and the first SUCCESSFUL connect
+		// count — with several resolved addresses net/http may try more
+		// than one, and the winner is what the request actually waited on.
+		ConnectStart: func(_, _ string) { stamp(&p.connectStart) },
+		ConnectDone: func(_, _ string, err error) {
+			if err == nil {
+				stamp(&p.connectDone)
+			}
+		},
+		TLSHandshakeStart: func() { stamp(&p.tlsStart) },
+		TLSHandshakeDone:  func(tls.ConnectionState, error) { stamp(&p.tlsDone) },
+		GotConn: func(info httptrace.GotConnInfo) {
+			p.mu.Lock()
+			p.reused = info.Reused
+			p.mu.Unlock()
+		},
+		WroteRequest:         func(httptrace.WroteRequestInfo) { stamp(&p.wroteRequest) },
+		GotFirstResponseByte: func() { stamp(&p.firstByte) },
+	}
+	return p, httptrace.WithClientTrace(ctx, trace)
+}
+
+// gotProto records the protocol the response arrived on. httptrace has no
+// hook for it, so post() calls this once the response headers are in.
+func (p *phaseTracer) gotProto(proto string) {
+	p.mu.Lock()
+	p.proto = proto
+	p.mu.Unlock()
+}
+
+// finish converts the collected timestamps into a Timing. end is the moment
+// the response body was fully read (or the exchange failed).
+func (p *phaseTracer) finish(end time.Time) Timing {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return Timing{
+		DNS:     span(p.dnsStart, p.dnsDone),
+		Connect: span(p.connectStart, p.connectDone),
+		TLS:     span(p.tlsStart, p.tlsDone),
+		TTFB:    span(p.wroteRequest, p.firstByte),
+		Body:    span(p.firstByte, end),
+		Total:   end.Sub(p.start),
+		Reused:  p.reused,
+		Proto:   p.proto,
+	}
+}
+
+// span returns b - a, or 0 if either milestone was never reached.
+func span(a, b time.Time) time.Duration {
+	if a.IsZero() || b.IsZero() || b.Before(a) {
+		return 0
+	}
+	return b.Sub(a)
+}
@@ -0,0 +1,62 @@
+package rpc
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestWithTiming_coldThenReused(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(20 * time.Millisecond)
+		w.Write(echoIDs(r, `{"jsonrpc":"2.0","id":1,"result":"0x10"}`))
+	}))
+	defer srv.Close()
+
+	c := NewClient("t", srv.URL, 2*time.Second)
+
+	var cold Timing
+	if _, _, err := c.BlockNumber(WithTiming(context.Background(), &cold)); err != nil {
+		t.Fatal(err)
+	}
+	if cold.Reused || cold.Connect <= 0 {
+		t.Fatalf("cold call should dial: %+v", cold)
+	}
+	if cold.TTFB < 20*time.Millisecond || cold.Total < cold.TTFB {
+		t.Fatalf("TTFB should include server time: %+v", cold)
+	}
+
+	var warm Timing
+	if _, _, err := c.BlockNumber(WithTiming(context.Background(), &warm)); err != nil {
+		t.Fatal(err)
+	}
+	if !warm.Reused || warm.Connect != 0 || warm.DNS != 0 || warm.TLS != 0 {
+		t.Fatalf("warm call should reuse the connection: %+v", warm)
+	}
+}
+
+func TestWithTiming_tlsHandshake(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(echoIDs(r, `{"jsonrpc":"2.0","id":1,"result":"0x10"}`))
+	}))
+	defer srv.Close()
+
+	c := NewClient("t", srv.URL, 2*time.Second)
+	c.httpClient = srv.Client()
+
+	var tm Timing
+	if _, _, err := c.BlockNumber(WithTiming(context.Background(), &tm)); err != nil {
+		t.Fatal(err)
+	}
+	if tm.TLS <= 0 {
+		t.Fatalf("expected a TLS phase: %+v", tm)
+	}
+}
+
+func TestWithTiming_notAttached(t *testing.T) {
+	if timingFrom(context.Background()) != nil {
+		t.Fatal("expected no timing on a bare context")
+	}
+}
@@ -0,0 +1,240 @@
+// =============================================================================
+// FILE: internal/rpc/transaction.go
+// ROLE: Transaction Data Model — Hydrated Transaction Objects
+// =============================================================================
+//
+// SYSTEM CONTEXT
+// ==============
+// eth_getBlockByNumber takes a second parameter, "hydrated". With false (what
+// GetBlock sends) the block's "transactions" array holds 32-byte hashes. With
+// true (GetFullBlock) it holds one full object per transaction:
+//
+//   false: "transactions": ["0x5c50…", "0x9a1f…"]
+//   true:  "transactions": [{"hash": "0x5c50…", "from": "0x…", "to": …}, …]
+//
+// This file defines the Go shape of those objects. Like Block, Transaction
+// keeps every field as the hex string the node sent; Parsed() converts the
+// numeric ones to native types.
+//
+// TRANSACTION TYPES (EIP-2718 ENVELOPES)
+// ======================================
+// Every hard fork that changed how fees or authorization work introduced a new
+// "typed transaction". They all share the basic fields (nonce, gas, to, value,
+// input, signature) and add their own:
+//
+//   ┌──────┬─────────────┬──────────┬──────────────────────────────────────┐
+//   │ Type │ Name        │ Fork     │ Adds                                 │
+//   ├──────┼─────────────┼──────────┼──────────────────────────────────────┤
+//   │ 0x0  │ legacy      │ Frontier │ gasPrice (EIP-155 folds the chain id │
+//   │      │             │          │ into v)                              │
+//   │ 0x1  │ access list │ Berlin   │ chainId, accessList (EIP-2930)       │
+//   │ 0x2  │ dynamic fee │ London   │ maxFeePerGas, maxPriorityFeePerGas   │
+//   │      │             │          │ instead of gasPrice (EIP-1559)       │
+//   │ 0x3  │ blob        │ Cancun   │ maxFeePerBlobGas, blobVersionedHashes│
+//   │      │             │          │ (EIP-4844)                           │
+//   │ 0x4  │ set code    │ Prague   │ authorizationList (EIP-7702)         │
+//   └──────┴─────────────┴──────────┴──────────────────────────────────────┘
+//
+// Fields a type does not use are simply absent from the JSON and stay empty
+// here. Very old nodes omit "type" on legacy transactions altogether, which
+// TxType() treats as 0x0.
+//
+// WHAT DID THE SENDER ACTUALLY PAY PER GAS?
+// =========================================
+// For legacy and access-list transactions, gasPrice. For dynamic-fee types it
+// depends on the block's base fee:
+//
+//   effective price = min(maxFeePerGas, baseFeePerGas + maxPriorityFeePerGas)
+//
+// Most clients also fill "gasPrice" with this value for mined transactions,
+// but not all do, so EffectiveGasPrice computes it from the block instead of
+// trusting the field. The total fee additionally needs gasUsed, which only the
+// receipt carries — a block alone gives the price, not the bill.
+// =============================================================================
+
+package rpc
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// TxType is the EIP-2718 transaction type byte.
+type TxType uint8
+
+const (
+	TxLegacy     TxType = 0x0 // Pre-Berlin transactions
+	TxAccessList TxType = 0x1 // EIP-2930
+	TxDynamicFee TxType = 0x2 // EIP-1559
+	TxBlob       TxType = 0x3 // EIP-4844
+	TxSetCode    TxType = 0x4 // EIP-7702
+)
+
+// String returns a short name for display ("legacy", "eip1559", ...).
+// Types this tool does not know yet render as their hex byte.
+func (t TxType) String() string {
+	switch t {
+	case TxLegacy:
+		return "legacy"
+	case TxAccessList:
+		return "eip2930"
+	case TxDynamicFee:
+		return "eip1559"
+	case TxBlob:
+		return "eip4844"
+	case TxSetCode:
+		return "eip7702"
+	default:
+		return fmt.Sprintf("0x%x", uint8(t))
+	}
+}
+
+// AccessTuple is one entry of an EIP-2930 access list: an address and the
+// storage slots the transaction declares it will touch.
+type AccessTuple struct {
+	Address     string   `json:"address"`
+	StorageKeys []string `json:"storageKeys"`
+}
+
+// Authorization is one entry of an EIP-7702 authorization list: a signed
+// statement by an account that its code should delegate to Address.
+type Authorization struct {
+	ChainID string `json:"chainId"`
+	Address string `json:"address"`
+	Nonce   string `json:"nonce"`
+	YParity string `json:"yParity"`
+	R       string `json:"r"`
+	S       string `json:"s"`
+}
+
+// Transaction holds one hydrated transaction object, exactly as the node
+// returned it. Numeric fields are hex strings; fields the transaction's type
+// does not use are empty.
+//
+// To is empty for contract creations (the node sends "to": null).
+type Transaction struct {
+	Hash             string `json:"hash"`
+	Type             string `json:"type,omitempty"` // Hex type byte; absent on very old nodes
+	BlockHash        string `json:"blockHash,omitempty"`
+	BlockNumber      string `json:"blockNumber,omitempty"`
+	TransactionIndex string `json:"transactionIndex,omitempty"`
+	From             string `json:"from"`
+	To               string `json:"to,omitempty"`
+	Nonce            string `json:"nonce"`
+	Gas              string `json:"gas"` // Gas LIMIT, not gas used
+	Value            string `json:"value"`
+	Input            string `json:"input"`
+	ChainID          string `json:"chainId,omitempty"`
+
+	// Fee fields. Legacy/2930 use GasPrice; 1559 and later use the Max* pair.
+	GasPrice             string `json:"gasPrice,omitempty"`
+	MaxFeePerGas         string `json:"maxFeePerGas,omitempty"`
+	MaxPriorityFeePerGas string `json:"maxPriorityFeePerGas,omitempty"`
+
+	// Type-specific payloads.
+	AccessList          []AccessTuple   `json:"accessList,omitempty"`          // 0x1 and later
+	MaxFeePerBlobGas    string          `json:"maxFeePerBlobGas,omitempty"`    // 0x3
+	BlobVersionedHashes []string        `json:"blobVersionedHashes,omitempty"` // 0x3
+	AuthorizationList   []Authorization `json:"authorizationList,omitempty"`   // 0x4
+
+	// Signature. Legacy transactions use V; typed ones use yParity (most
+	// nodes send both, with v == yParity).
+	V       string `json:"v,omitempty"`
+	R       string `json:"r,omitempty"`
+	S       string `json:"s,omitempty"`
+	YParity string `json:"yParity,omitempty"`
+}
+
+// TxType returns the transaction's EIP-2718 type. A missing or unparseable
+// "type" field means legacy.
+func (tx *Transaction) TxType() TxType {
+	return parseTxType(tx.Type)
+}
+
+// parseTxType parses a hex "type" field as sent on transactions and
+// receipts; anything missing or malformed is legacy.
+func parseTxType(s string) TxType {
+	if s == "" {
+		return TxLegacy
+	}
+	n, err := ParseHexUint64(s)
+	if err != nil || n > 0xff {
+		return TxLegacy
+	}
+	return TxType(n)
+}
+
+// ParsedTransaction holds a transaction's fields as native Go types.
+//
+// Fee fields are nil when the transaction's type does not carry them, the
+// same convention ParsedBlock uses for BaseFeePerGas.
+type ParsedTransaction struct {
+	Hash                 string
+	Type                 TxType
+	Index                uint64
+	From                 string
+	To                   string // "" for contract creation
+	Nonce                uint64
+	Gas                  uint64   // Gas limit
+	Value                *big.Int // Wei transferred (never nil)
+	GasPrice             *big.Int
+	MaxFeePerGas         *big.Int
+	MaxPriorityFeePerGas *big.Int
+	MaxFeePerBlobGas     *big.Int
+	BlobCount            int // len(BlobVersionedHashes)
+	AuthCount            int // len(AuthorizationList)
+}
+
+// Parsed converts the hex fields into a ParsedTransaction. As with
+// Block.Parsed, malformed hex becomes zero rather than an error.
+func (tx *Transaction) Parsed() ParsedTransaction {
+	nonce, _ := ParseHexUint64(tx.Nonce)
+	gas, _ := ParseHexUint64(tx.Gas)
+	index, _ := ParseHexUint64(tx.TransactionIndex)
+
+	value := optionalBigInt(tx.Value)
+	if value == nil {
+		value = new(big.Int)
+	}
+
+	return ParsedTransaction{
+		Hash:                 tx.Hash,
+		Type:                 tx.TxType(),
+		Index:                index,
+		From:                 tx.From,
+		To:                   tx.To,
+		Nonce:                nonce,
+		Gas:                  gas,
+		Value:                value,
+		GasPrice:             optionalBigInt(tx.GasPrice),
+		MaxFeePerGas:         optionalBigInt(tx.MaxFeePerGas),
+		MaxPriorityFeePerGas: optionalBigInt(tx.MaxPriorityFeePerGas),
+		MaxFeePerBlobGas:     optionalBigInt(tx.MaxFeePerBlobGas),
+		BlobCount:            len(tx.BlobVersionedHashes),
+		AuthCount:            len(tx.AuthorizationList),
+	}
+}
+
+// EffectiveGasPrice returns the price per gas the sender paid in a block with
+// the given base fee (see the header comment). baseFee may be nil for
+// pre-London blocks. Returns nil if the transaction carries no usable fee
+// fields.
+func (tx *Transaction) EffectiveGasPrice(baseFee *big.Int) *big.Int {
+	p := tx.Parsed()
+	if p.MaxFeePerGas == nil || p.MaxPriorityFeePerGas == nil || baseFee == nil {
+		return p.GasPrice
+	}
+	price := new(big.Int).Add(baseFee, p.MaxPriorityFeePerGas)
+	if price.Cmp(p.MaxFeePerGas) > 0 {
+		price.Set(p.MaxFeePerGas)
+	}
+	return price
+}
+
+// optionalBigInt parses a hex quantity, returning nil for an absent field.
+func optionalBigInt(hex string) *big.Int {
+	if hex == "" {
+		return nil
+	}
+	return ParseHexBigInt(hex)
+}
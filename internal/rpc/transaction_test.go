@@ -0,0 +1,162 @@
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// hydratedBlock has one transaction of every supported type, trimmed to the
+// fields each type actually carries.
+const hydratedBlock = `{
+	"number": "0x10", "hash": "0xblock", "baseFeePerGas": "0x64",
+	"transactions": [
+		{"hash": "0xa0", "transactionIndex": "0x0", "from": "0xf0", "to": "0xt0",
+		 "nonce": "0x1", "gas": "0x5208", "value": "0xde0b6b3a7640000", "input": "0x",
+		 "gasPrice": "0xc8", "v": "0x25", "r": "0x1", "s": "0x2"},
+		{"hash": "0xa1", "type": "0x1", "transactionIndex": "0x1", "from": "0xf1", "to": "0xt1",
+		 "nonce": "0x2", "gas": "0x7530", "value": "0x0", "input": "0x", "chainId": "0x1",
+		 "gasPrice": "0x96",
+		 "accessList": [{"address": "0xaa", "storageKeys": ["0x01", "0x02"]}]},
+		{"hash": "0xa2", "type": "0x2", "transactionIndex": "0x2", "from": "0xf2", "to": null,
+		 "nonce": "0x3", "gas": "0x100000", "value": "0x0", "input": "0x6080",
+		 "maxFeePerGas": "0x12c", "maxPriorityFeePerGas": "0xa", "accessList": []},
+		{"hash": "0xa3", "type": "0x3", "transactionIndex": "0x3", "from": "0xf3", "to": "0xt3",
+		 "nonce": "0x4", "gas": "0x5208", "value": "0x0", "input": "0x",
+		 "maxFeePerGas": "0x6e", "maxPriorityFeePerGas": "0x32",
+		 "maxFeePerBlobGas": "0x3e8", "blobVersionedHashes": ["0x01aa", "0x01bb"]},
+		{"hash": "0xa4", "type": "0x4", "transactionIndex": "0x4", "from": "0xf4", "to": "0xf4",
+		 "nonce": "0x5", "gas": "0x186a0", "value": "0x0", "input": "0x",
+		 "maxFeePerGas": "0x12c", "maxPriorityFeePerGas": "0x1",
+		 "authorizationList": [{"chainId": "0x1", "address": "0xde", "nonce": "0x0",
+		                        "yParity": "0x1", "r": "0x3", "s": "0x4"}]}
+	]
+}`
+
+func TestBlock_UnmarshalHydrated(t *testing.T) {
+	var b Block
+	if err := json.Unmarshal([]byte(hydratedBlock), &b); err != nil {
+		t.Fatal(err)
+	}
+	if len(b.FullTransactions) != 5 {
+		t.Fatalf("full txs = %d", len(b.FullTransactions))
+	}
+	if strings.Join(b.Transactions, ",") != "0xa0,0xa1,0xa2,0xa3,0xa4" {
+		t.Fatalf("hashes = %v", b.Transactions)
+	}
+	if b.Parsed().TxCount != 5 || b.Number != "0x10" {
+		t.Fatalf("block fields lost: %+v", b)
+	}
+
+	wantTypes := []TxType{TxLegacy, TxAccessList, TxDynamicFee, TxBlob, TxSetCode}
+	for i, tx := range b.FullTransactions {
+		if got := tx.TxType(); got != wantTypes[i] {
+			t.Fatalf("tx %d type = %v want %v", i, got, wantTypes[i])
+		}
+	}
+
+	legacy := b.FullTransactions[0].Parsed()
+	if legacy.Value.String() != "1000000000000000000" || legacy.Gas != 21000 || legacy.GasPrice.Int64() != 200 {
+		t.Fatalf("legacy: %+v", legacy)
+	}
+	if legacy.MaxFeePerGas != nil {
+		t.Fatal("legacy tx should have no maxFeePerGas")
+	}
+	if al := b.FullTransactions[1].AccessList; len(al) != 1 || len(al[0].StorageKeys) != 2 {
+		t.Fatalf("access list: %+v", al)
+	}
+	if create := b.FullTransactions[2]; create.To != "" {
+		t.Fatalf("contract creation To = %q", create.To)
+	}
+	blob := b.FullTransactions[3].Parsed()
+	if blob.BlobCount != 2 || blob.MaxFeePerBlobGas.Int64() != 1000 {
+		t.Fatalf("blob: %+v", blob)
+	}
+	setCode := b.FullTransactions[4]
+	if len(setCode.AuthorizationList) != 1 || setCode.AuthorizationList[0].Address != "0xde" {
+		t.Fatalf("authorizations: %+v", setCode.AuthorizationList)
+	}
+}
+
+func TestBlock_UnmarshalHashesOnly(t *testing.T) {
+	var b Block
+	if err := json.Unmarshal([]byte(`{"number":"0x1","transactions":["0xa","0xb"]}`), &b); err != nil {
+		t.Fatal(err)
+	}
+	if len(b.Transactions) != 2 || b.FullTransactions != nil {
+		t.Fatalf("got %+v", b)
+	}
+
+	var empty Block
+	if err := json.Unmarshal([]byte(`{"number":"0x1","transactions":[]}`), &empty); err != nil {
+		t.Fatal(err)
+	}
+	if empty.Transactions == nil || len(empty.Transactions) != 0 {
+		t.Fatalf("empty block transactions = %#v", empty.Transactions)
+	}
+}
+
+func TestTransaction_EffectiveGasPrice(t *testing.T) {
+	var b Block
+	if err := json.Unmarshal([]byte(hydratedBlock), &b); err != nil {
+		t.Fatal(err)
+	}
+	baseFee := b.Parsed().BaseFeePerGas // 100 wei
+
+	tests := []struct {
+		i    int
+		want int64
+	}{
+		{0, 200}, // legacy: gasPrice
+		{1, 150}, // access list: gasPrice
+		{2, 110}, // 1559: base 100 + tip 10, under the 300 cap
+		{3, 110}, // 4844: base 100 + tip 50 = 150, capped at maxFee 110
+		{4, 101}, // 7702: base 100 + tip 1
+	}
+	for _, tc := range tests {
+		got := b.FullTransactions[tc.i].EffectiveGasPrice(baseFee)
+		if got == nil || got.Int64() != tc.want {
+			t.Fatalf("tx %d effective price = %v want %d", tc.i, got, tc.want)
+		}
+	}
+
+	// Without a base fee the dynamic-fee formula can't be applied; fall back
+	// to whatever gasPrice the node reported (none here).
+	if got := b.FullTransactions[2].EffectiveGasPrice(nil); got != nil {
+		t.Fatalf("no base fee: got %v", got)
+	}
+}
+
+func TestTxType_String(t *testing.T) {
+	if TxDynamicFee.String() != "eip1559" || TxType(0x7e).String() != "0x7e" {
+		t.Fatalf("got %q %q", TxDynamicFee, TxType(0x7e))
+	}
+}
+
+func TestClient_GetFullBlock(t *testing.T) {
+	var hydrated bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var req Request
+		_ = json.Unmarshal(body, &req)
+		hydrated = len(req.Params) == 2 && req.Params[1] == true
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		_, _ = w.Write(echoIDs(r, `{"jsonrpc":"2.0","id":1,"result":`+hydratedBlock+`}`))
+	}))
+	defer srv.Close()
+
+	c := NewClient("t", srv.URL, 2*time.Second)
+	b, _, err := c.GetFullBlock(context.Background(), "latest")
+	if err != nil || !hydrated {
+		t.Fatalf("err=%v hydrated=%v", err, hydrated)
+	}
+	if len(b.FullTransactions) != 5 {
+		t.Fatalf("full txs = %d", len(b.FullTransactions))
+	}
+}
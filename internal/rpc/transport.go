@@ -0,0 +1,157 @@
+// =============================================================================
+// FILE: internal/rpc/transport.go
+// ROLE: Transport Policies — How Connections Are Made and Reused
+// =============================================================================
+//
+// SYSTEM CONTEXT
+// ==============
+// Every latency this tool reports depends on what the connection was doing
+// before the request went out. A request on a pooled keep-alive connection
+// costs one round trip plus server time; a request on a fresh connection
+// also pays DNS, TCP connect and (for https) the TLS handshake — often more
+// than the RPC itself. Which of the two a production client sees depends on
+// its HTTP settings, so the tool lets you choose explicitly:
+//
+//   Policy  Connections                     Warm-up   Protocol
+//   ──────  ──────────────────────────────  ────────  ──────────────────────
+//   cold    new connection for EVERY call   none      negotiated (h1 or h2)
+//   warm    keep-alive pool, own per client one call  negotiated (h1 or h2)
+//   http1   keep-alive pool, own per client one call  HTTP/1.1 only
+//   http2   keep-alive pool, own per client one call  HTTP/2 only (https://)
+//
+// "warm-keepalive" is accepted as another name for warm.
+//
+// The warm-up is one discarded eth_blockNumber that block, test and snapshot
+// send before measuring (see Client.WarmUp); it opens the pooled connection
+// so the measured calls reuse it. A cold client skips it — there is nothing
+// to prime when every call dials anew.
+//
+// Each policy client gets its OWN http.Transport, cloned from
+// http.DefaultTransport. Sharing the default transport (what a client with
+// no policy does) would let one client's pooled connections serve another's
+// "cold" requests and blur the comparison.
+//
+// Forcing a protocol fails loudly instead of falling back: an http2 client
+// against a server that only speaks HTTP/1.1 gets a transport error, which is
+// itself the answer to "does this endpoint support HTTP/2?".
+//
+// That answer only means something where HTTP/2 can be negotiated, and
+// clients and servers negotiate it during the TLS handshake (ALPN). A
+// cleartext http:// endpoint — a local node, mockfleet — or an ipc://
+// socket can never get there, so http2 does not apply to them (Unsupported)
+// and is skipped rather than counted as a failing provider.
+// =============================================================================
+
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Policy selects how a Client makes and reuses connections. The zero value
+// keeps the historical behavior: the shared http.DefaultTransport, with
+// warm-up.
+type Policy string
+
+const (
+	PolicyCold  Policy = "cold"  // No keep-alive: every call opens a new connection
+	PolicyWarm  Policy = "warm"  // Keep-alive pool plus a warm-up call
+	PolicyHTTP1 Policy = "http1" // Like warm, but HTTP/1.1 only
+	PolicyHTTP2 Policy = "http2" // Like warm, but HTTP/2 only
+)
+
+// Policies lists every policy in display order.
+var Policies = []Policy{PolicyCold, PolicyWarm, PolicyHTTP1, PolicyHTTP2}
+
+// ParsePolicy accepts a policy name (case-insensitive) as written in YAML or
+// on the command line.
+func ParsePolicy(s string) (Policy, error) {
+	name := strings.ToLower(strings.TrimSpace(s))
+	if name == "warm-keepalive" {
+		return PolicyWarm, nil
+	}
+	for _, p := range Policies {
+		if string(p) == name {
+			return p, nil
+		}
+	}
+	return "", fmt.Errorf("unknown transport policy %q (want cold, warm, http1 or http2)", s)
+}
+
+// ParsePolicies parses a comma-separated list such as "cold,warm,http2".
+// "all" expands to every policy. Duplicates are dropped.
+func ParsePolicies(s string) ([]Policy, error) {
+	if strings.TrimSpace(strings.ToLower(s)) == "all" {
+		return append([]Policy(nil), Policies...), nil
+	}
+	var out []Policy
+	seen := make(map[Policy]bool)
+	for _, part := range strings.Split(s, ",") {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+		p, err := ParsePolicy(part)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[p] {
+			seen[p] = true
+			out = append(out, p)
+		}
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("no transport policy in %q", s)
+	}
+	return out, nil
+}
+
+// WarmsUp reports whether callers should prime the connection before
+// measuring. Only cold clients skip it.
+func (p Policy) WarmsUp() bool { return p != PolicyCold }
+
+// transport builds the http.RoundTripper for p, or returns nil (use
+// http.DefaultTransport) for the zero policy.
+func (p Policy) transport() http.RoundTripper {
+	if p == "" {
+		return nil
+	}
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	switch p {
+	case PolicyCold:
+		t.DisableKeepAlives = true
+	case PolicyHTTP1:
+		t.Protocols = new(http.Protocols)
+		t.Protocols.SetHTTP1(true)
+	case PolicyHTTP2:
+		t.Protocols = new(http.Protocols)
+		t.Protocols.SetHTTP2(true)
+	}
+	return t
+}
+
+// Unsupported returns why p cannot be used against url, or "" when it can.
+// http2 needs TLS to negotiate the protocol, so it needs an https:// URL.
+func (p Policy) Unsupported(url string) string {
+	if p == PolicyHTTP2 && !strings.HasPrefix(strings.ToLower(url), "https://") {
+		return "needs https"
+	}
+	return ""
+}
+
+// WithPolicy gives the client its own transport configured for p.
+func WithPolicy(p Policy) Option { return func(o *options) { o.policy = p } }
+
+// Policy returns the client's transport policy ("" when none was set).
+func (c *Client) Policy() Policy { return c.policy }
+
+// WarmUp sends one discarded eth_blockNumber so the connection is open
+// before measured calls, unless the policy is cold. Errors are ignored: a
+// provider that is down will fail the measured call too, and report it there.
+func (c *Client) WarmUp(ctx context.Context) {
+	if c.policy.WarmsUp() {
+		c.BlockNumber(ctx)
+	}
+}
@@ -0,0 +1,129 @@
+package rpc
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestParsePolicies(t *testing.T) {
+	got, err := ParsePolicies(" Cold, warm-keepalive ,http2,warm")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 3 || got[0] != PolicyCold || got[1] != PolicyWarm || got[2] != PolicyHTTP2 {
+		t.Fatalf("got %v", got)
+	}
+	if all, _ := ParsePolicies("all"); len(all) != len(Policies) {
+		t.Fatalf("all = %v", all)
+	}
+	for _, bad := range []string{"", ",", "quic"} {
+		if _, err := ParsePolicies(bad); err == nil {
+			t.Errorf("ParsePolicies(%q) should fail", bad)
+		}
+	}
+}
+
+// blockServer answers eth_blockNumber and counts new connections.
+func blockServer(t *testing.T, conns *int32) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(echoIDs(r, `{"jsonrpc":"2.0","id":1,"result":"0x10"}`))
+	}))
+	srv.Config.ConnState = func(_ net.Conn, s http.ConnState) {
+		if s == http.StateNew {
+			atomic.AddInt32(conns, 1)
+		}
+	}
+	return srv
+}
+
+func TestPolicy_coldDialsEveryCall(t *testing.T) {
+	var conns int32
+	srv := blockServer(t, &conns)
+	srv.Start()
+	defer srv.Close()
+
+	cold := NewClient("t", srv.URL, 2*time.Second, WithPolicy(PolicyCold))
+	cold.WarmUp(context.Background()) // no-op for cold
+	for i := 0; i < 3; i++ {
+		var tm Timing
+		if _, _, err := cold.BlockNumber(WithTiming(context.Background(), &tm)); err != nil {
+			t.Fatal(err)
+		}
+		if tm.Reused {
+			t.Fatalf("call %d reused a connection", i)
+		}
+	}
+	if n := atomic.LoadInt32(&conns); n != 3 {
+		t.Fatalf("cold client opened %d connections, want 3", n)
+	}
+}
+
+func TestPolicy_warmReusesAfterWarmUp(t *testing.T) {
+	var conns int32
+	srv := blockServer(t, &conns)
+	srv.Start()
+	defer srv.Close()
+
+	warm := NewClient("t", srv.URL, 2*time.Second, WithPolicy(PolicyWarm))
+	warm.WarmUp(context.Background())
+	var tm Timing
+	if _, _, err := warm.BlockNumber(WithTiming(context.Background(), &tm)); err != nil {
+		t.Fatal(err)
+	}
+	if !tm.Reused || tm.Proto != "HTTP/1.1" {
+		t.Fatalf("measured call should reuse the warm-up connection: %+v", tm)
+	}
+	if n := atomic.LoadInt32(&conns); n != 1 {
+		t.Fatalf("warm client opened %d connections, want 1", n)
+	}
+}
+
+func TestPolicy_forcedProtocols(t *testing.T) {
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(echoIDs(r, `{"jsonrpc":"2.0","id":1,"result":"0x10"}`))
+	}))
+	srv.EnableHTTP2 = true
+	srv.StartTLS()
+	defer srv.Close()
+
+	for _, tt := range []struct {
+		policy Policy
+		proto  string
+	}{{PolicyHTTP1, "HTTP/1.1"}, {PolicyHTTP2, "HTTP/2.0"}} {
+		c := NewClient("t", srv.URL, 2*time.Second, WithPolicy(tt.policy))
+		// Trust the test server's certificate on the policy's own transport.
+		c.httpClient.Transport.(*http.Transport).TLSClientConfig = srv.Client().Transport.(*http.Transport).TLSClientConfig
+		var tm Timing
+		if _, _, err := c.BlockNumber(WithTiming(context.Background(), &tm)); err != nil {
+			t.Fatalf("%s: %v", tt.policy, err)
+		}
+		if tm.Proto != tt.proto {
+			t.Errorf("%s: proto = %q, want %q", tt.policy, tm.Proto, tt.proto)
+		}
+	}
+}
+
+func TestPolicy_Unsupported(t *testing.T) {
+	for _, tt := range []struct {
+		policy Policy
+		url    string
+		want   string
+	}{
+		{PolicyHTTP2, "https://eth.example", ""},
+		{PolicyHTTP2, "HTTPS://eth.example", ""},
+		{PolicyHTTP2, "http://127.0.0.1:8545", "needs https"},
+		{PolicyHTTP2, "ipc:///tmp/geth.ipc", "needs https"},
+		{PolicyHTTP1, "http://127.0.0.1:8545", ""},
+		{PolicyCold, "ipc:///tmp/geth.ipc", ""},
+	} {
+		if got := tt.policy.Unsupported(tt.url); got != tt.want {
+			t.Errorf("%s on %s: %q, want %q", tt.policy, tt.url, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+// =============================================================================
+// FILE: internal/rpc/trie.go
+// ROLE: Ordered Merkle Patricia Trie — Roots Over Lists (transactions, ...)
+// =============================================================================
+//
+// SYSTEM CONTEXT
+// ==============
+// A block header does not list its transactions; it commits to them with a
+// single 32-byte root. The transactions (and, since Shanghai, withdrawals;
+// and receipts) are stored in a Merkle Patricia trie keyed by their index,
+// and the header carries the trie's root hash:
+//
+//   transactionsRoot = root of { rlp(0) → tx0, rlp(1) → tx1, ... }
+//
+// Rebuilding that root from the list a provider returned proves the list is
+// exactly the one the header committed to — nothing dropped, added,
+// reordered or altered. Only building is needed (not lookups or updates), so
+// this is the "ordered trie" / DeriveSha construction, done recursively over
+// the sorted keys.
+//
+// NODES
+// =====
+// Keys are walked as nibbles (half-bytes). Three node kinds, each an RLP
+// list:
+//
+//   leaf       [hexPrefix(rest of key, leaf), value]
+//   extension  [hexPrefix(shared nibbles), child]       all keys share a run
+//   branch     [child0 … child15, value]                keys diverge here
+//
+// A child is referenced by keccak256 of its encoding — unless the encoding
+// is shorter than 32 bytes, in which case it is embedded as-is. The root is
+// always hashed. The empty trie's root is keccak256(rlp("")) =
+// 0x56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421.
+//
+// HEX-PREFIX
+// ==========
+// A nibble path is packed back into bytes with a flag nibble in front:
+// 0/1 = extension with even/odd length, 2/3 = leaf with even/odd length. An
+// odd path puts its first nibble next to the flag; an even one pads a zero.
+// =============================================================================
+
+package rpc
+
+import (
+	"encoding/hex"
+	"sort"
+)
+
+// trieEntry is one key/value pair, with the key expanded to nibbles.
+type trieEntry struct {
+	path  []byte // One nibble (0-15) per element
+	value []byte
+}
+
+// OrderedTrieRoot returns, as 0x-hex, the root of the trie that maps
+// rlp(i) to items[i] — how transactionsRoot, withdrawalsRoot and
+// receiptsRoot commit to their lists.
+func OrderedTrieRoot(items [][]byte) string {
+	entries := make([]trieEntry, len(items))
+	for i, item := range items {
+		entries[i] = trieEntry{path: keyNibbles(rlpUint(uint64(i))), value: item}
+	}
+	return "0x" + hex.EncodeToString(trieRoot(entries))
+}
+
+// trieRoot returns the 32-byte root hash of a trie holding entries.
+func trieRoot(entries []trieEntry) []byte {
+	sort.Slice(entries, func(i, j int) bool { return string(entries[i].path) < string(entries[j].path) })
+	return Keccak256(trieNode(entries, 0))
+}
+
+// trieNode encodes the node holding entries, whose paths all share their
+// first depth nibbles. entries are sorted by path.
+func trieNode(entries []trieEntry, depth int) []byte {
+	switch len(entries) {
+	case 0:
+		return rlpBytes(nil)
+	case 1:
+		return rlpList(rlpBytes(hexPrefix(entries[0].path[depth:], true)), rlpBytes(entries[0].value))
+	}
+
+	// Extension: every remaining path shares at least one more nibble.
+	// Sorted order means the first and last paths bound the shared run.
+	first, last := entries[0].path, entries[len(entries)-1].path
+	shared := 0
+	for depth+shared < len(first) && depth+shared < len(last) && first[depth+shared] == last[depth+shared] {
+		shared++
+	}
+	if shared > 0 {
+		child := trieNode(entries, depth+shared)
+		return rlpList(rlpBytes(hexPrefix(first[depth:depth+shared], false)), trieRef(child))
+	}
+
+	// Branch: group by the nibble at depth. A path that ends exactly here
+	// (sorted first) is the branch's own value.
+	items := make([][]byte, 17)
+	items[16] = rlpBytes(nil)
+	if len(first) == depth {
+		items[16] = rlpBytes(entries[0].value)
+		entries = entries[1:]
+	}
+	for nibble := byte(0); nibble < 16; nibble++ {
+		start := 0
+		for start < len(entries) && entries[start].path[depth] < nibble {
+			start++
+		}
+		end := start
+		for end < len(entries) && entries[end].path[depth] == nibble {
+			end++
+		}
+		if start == end {
+			items[nibble] = rlpBytes(nil)
+		} else {
+			items[nibble] = trieRef(trieNode(entries[start:end], depth+1))
+		}
+		entries = entries[end:]
+	}
+	return rlpList(items...)
+}
+
+// trieRef is how a parent refers to an encoded child node: embedded when
+// shorter than 32 bytes, otherwise by hash.
+func trieRef(enc []byte) []byte {
+	if len(enc) < 32 {
+		return enc
+	}
+	return rlpBytes(Keccak256(enc))
+}
+
+// keyNibbles splits each byte of key into its high and low nibble.
+func keyNibbles(key []byte) []byte {
+	out := make([]byte, 0, 2*len(key))
+	for _, b := range key {
+		out = append(out, b>>4, b&0x0f)
+	}
+	return out
+}
+
+// hexPrefix packs a nibble path into bytes with the leaf/odd flag nibble.
+func hexPrefix(nibbles []byte, leaf bool) []byte {
+	flag := byte(0)
+	if leaf {
+		flag = 2
+	}
+	if len(nibbles)%2 == 1 {
+		flag++
+		nibbles = append([]byte{0}, nibbles...) // The flag shares the first byte with nibble 0
+	} else {
+		nibbles = append([]byte{0, 0}, nibbles...)
+	}
+	out := make([]byte, len(nibbles)/2)
+	for i := range out {
+		out[i] = nibbles[2*i]<<4 | nibbles[2*i+1]
+	}
+	out[0] |= flag << 4
+	return out
+}
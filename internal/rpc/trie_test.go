@@ -0,0 +1,73 @@
+package rpc
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func rawTrieRoot(pairs ...string) string {
+	var entries []trieEntry
+	for i := 0; i < len(pairs); i += 2 {
+		entries = append(entries, trieEntry{path: keyNibbles([]byte(pairs[i])), value: []byte(pairs[i+1])})
+	}
+	return "0x" + hex.EncodeToString(trieRoot(entries))
+}
+
+// Vectors from the Ethereum consensus tests (TrieTests/trietest.json).
+func TestTrieRoot_knownVectors(t *testing.T) {
+	tests := []struct {
+		name  string
+		pairs []string
+		want  string
+	}{
+		{"empty", nil, emptyTrie},
+		{"dogs", []string{"doe", "reindeer", "dog", "puppy", "dogglesworth", "cat"},
+			"0x8aad789dff2f538bca5d8ea56e8abe10f4c7ba3a5dea95fea4cd6e7c3a1168d3"},
+		{"puppy", []string{"do", "verb", "horse", "stallion", "doge", "coin", "dog", "puppy"},
+			"0x5991bb8c6514148a29db676a14ac506cd2cd5775ace63c30a4fe457715e9ac84"},
+	}
+	for _, tt := range tests {
+		if got := rawTrieRoot(tt.pairs...); got != tt.want {
+			t.Errorf("%s: root %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestOrderedTrieRoot(t *testing.T) {
+	if got := OrderedTrieRoot(nil); got != emptyTrie {
+		t.Fatalf("empty list: %s", got)
+	}
+	// Indexes 0, 1..127 and 128+ encode to keys of different shapes (0x80,
+	// one byte, 0x81xx); the root must not depend on insertion order.
+	items := make([][]byte, 300)
+	for i := range items {
+		items[i] = []byte{byte(i), byte(i >> 8), 0xaa}
+	}
+	root := OrderedTrieRoot(items)
+	swapped := append([][]byte(nil), items...)
+	swapped[5], swapped[200] = swapped[200], swapped[5]
+	if OrderedTrieRoot(swapped) == root || OrderedTrieRoot(items[:299]) == root {
+		t.Fatal("reordering or truncating the list must change the root")
+	}
+	if OrderedTrieRoot(items) != root {
+		t.Fatal("root is not deterministic")
+	}
+}
+
+func TestHexPrefix(t *testing.T) {
+	tests := []struct {
+		nibbles []byte
+		leaf    bool
+		want    string
+	}{
+		{[]byte{1, 2, 3, 4, 5}, false, "112345"},
+		{[]byte{0, 1, 2, 3, 4, 5}, false, "00012345"},
+		{[]byte{0, 15, 1, 12, 11, 8}, true, "200f1cb8"},
+		{[]byte{15, 1, 12, 11, 8}, true, "3f1cb8"},
+	}
+	for _, tt := range tests {
+		if got := hex.EncodeToString(hexPrefix(tt.nibbles, tt.leaf)); got != tt.want {
+			t.Errorf("hexPrefix(%v, %v) = %s, want %s", tt.nibbles, tt.leaf, got, tt.want)
+		}
+	}
+}
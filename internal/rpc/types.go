@@ -55,7 +55,9 @@
 package rpc
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
 	"math/big"
 )
 
@@ -97,14 +99,15 @@ import (
 // a number, a bool, or any other value. This flexibility is necessary because
 // the JSON-RPC spec does not constrain parameter types.
 //
-// The ID field is used to match requests with responses in asynchronous
-// protocols. Since we use synchronous HTTP (one request, one response per
-// connection), we hardcode this to 1 everywhere.
+// The ID field is used to match requests with responses. Even over
+// synchronous HTTP it matters: every call gets a unique ID and the reply
+// must echo it, which catches proxies that hand back someone else's
+// response (see ids.go).
 type Request struct {
 	JSONRPC string        `json:"jsonrpc"` // Always "2.0" — protocol version
 	Method  string        `json:"method"`  // RPC method name, e.g., "eth_blockNumber"
 	Params  []interface{} `json:"params"`  // Method arguments — varies per method
-	ID      int           `json:"id"`      // Request identifier (always 1 in this codebase)
+	ID      uint64        `json:"id"`      // Unique per call (see ids.go)
 }
 
 // Response represents a JSON-RPC 2.0 response from an Ethereum node.
@@ -166,7 +169,7 @@ type Request struct {
 // key is missing, leave the pointer as nil.
 type Response struct {
 	JSONRPC string          `json:"jsonrpc"`         // Always "2.0"
-	ID      int             `json:"id"`              // Matches the request ID
+	ID      json.RawMessage `json:"id"`              // Echo of the request ID — number or string form (see ids.go)
 	Result  json.RawMessage `json:"result"`          // Raw JSON — parsed later by caller
 	Error   *RPCError       `json:"error,omitempty"` // nil when no error; pointer to RPCError on failure
 }
@@ -183,10 +186,16 @@ type Response struct {
 //
 // Ethereum nodes may also return custom error codes (e.g., -32000 for
 // execution reverted). The Message field contains a human-readable
-// description of what went wrong.
+// description of what went wrong; Data is optional extra detail (revert
+// data, the provider's rate-limit window, ...) kept raw because its shape
+// differs between clients.
+//
+// *RPCError implements the error interface (see errors.go), so Call returns
+// it directly and callers can recover the code with errors.As.
 type RPCError struct {
-	Code    int    `json:"code"`    // Numeric error code (negative = standard, positive = custom)
-	Message string `json:"message"` // Human-readable error description
+	Code    int             `json:"code"`           // Numeric error code (negative = standard, positive = custom)
+	Message string          `json:"message"`        // Human-readable error description
+	Data    json.RawMessage `json:"data,omitempty"` // Optional, client-specific detail
 }
 
 // =============================================================================
@@ -196,7 +205,9 @@ type RPCError struct {
 // When we call eth_getBlockByNumber, the Ethereum node returns a JSON object
 // with dozens of fields. The Block struct captures the fields we care about
 // for monitoring: block identity (number, hash, parent), timing (timestamp),
-// gas economics (gasUsed, gasLimit, baseFeePerGas), and transaction count.
+// gas economics (gasUsed, gasLimit, baseFeePerGas), and transaction count —
+// plus the rest of the header, so the block hash can be recomputed locally
+// instead of trusted (header.go).
 //
 // CRITICAL: Every numeric field arrives as a hex string.
 //
@@ -227,12 +238,17 @@ type RPCError struct {
 // All numeric fields are strings because they arrive as hex from the wire.
 // To get usable numeric values, call the Parsed() method (see below).
 //
-// The Transactions field is a slice of transaction hash strings. We pass
-// `false` as the second parameter to eth_getBlockByNumber, which tells the
-// node to return only transaction hashes (not full transaction objects).
+// The Transactions field is a slice of transaction hash strings. GetBlock
+// passes `false` as the second parameter to eth_getBlockByNumber, which tells
+// the node to return only transaction hashes (not full transaction objects).
 // This saves bandwidth and parsing time — for monitoring, we only need the
 // count, not the details.
 //
+// GetFullBlock passes `true` instead, and the node returns full objects. The
+// custom UnmarshalJSON below accepts either form: FullTransactions receives
+// the decoded objects, and Transactions is still filled with their hashes, so
+// code that only counts or lists hashes works the same on both.
+//
 // The `omitempty` tag on BaseFeePerGas handles pre-EIP-1559 blocks (before
 // the London hard fork in August 2021), which do not have a base fee field.
 // For those blocks, the JSON key is simply absent, and this field remains
@@ -245,7 +261,79 @@ type Block struct {
 	GasUsed       string   `json:"gasUsed"`                 // Gas consumed by all txns, as hex
 	GasLimit      string   `json:"gasLimit"`                // Maximum gas allowed in this block, as hex
 	BaseFeePerGas string   `json:"baseFeePerGas,omitempty"` // EIP-1559 base fee in wei, as hex (absent pre-London)
-	Transactions  []string `json:"transactions"`            // Transaction hashes (not full tx objects)
+	Transactions  []string `json:"transactions"`            // Transaction hashes (always filled)
+
+	// The rest of the header. Together with the fields above these are
+	// everything the block hash commits to (see header.go); the later ones
+	// only exist from the fork noted and are empty before it.
+	Sha3Uncles            string `json:"sha3Uncles"`                      // Hash of the ommers list
+	Miner                 string `json:"miner"`                           // Fee recipient (coinbase)
+	StateRoot             string `json:"stateRoot"`                       // World state trie root after this block
+	TransactionsRoot      string `json:"transactionsRoot"`                // Transaction trie root
+	ReceiptsRoot          string `json:"receiptsRoot"`                    // Receipt trie root
+	LogsBloom             string `json:"logsBloom"`                       // 256-byte bloom filter over all logs
+	Difficulty            string `json:"difficulty"`                      // Proof-of-work difficulty; 0 after the Merge
+	ExtraData             string `json:"extraData"`                       // Up to 32 bytes chosen by the producer
+	MixHash               string `json:"mixHash"`                         // PoW mix hash; prevRandao after the Merge
+	Nonce                 string `json:"nonce"`                           // 8-byte PoW nonce; zero after the Merge
+	WithdrawalsRoot       string `json:"withdrawalsRoot,omitempty"`       // Shanghai: withdrawal trie root
+	BlobGasUsed           string `json:"blobGasUsed,omitempty"`           // Cancun: blob gas consumed (EIP-4844)
+	ExcessBlobGas         string `json:"excessBlobGas,omitempty"`         // Cancun: running blob gas excess (EIP-4844)
+	ParentBeaconBlockRoot string `json:"parentBeaconBlockRoot,omitempty"` // Cancun: beacon block root (EIP-4788)
+	RequestsHash          string `json:"requestsHash,omitempty"`          // Prague: execution-layer requests commitment (EIP-7685)
+
+	// Withdrawals are part of the body from Shanghai on; withdrawalsRoot
+	// commits to them (see body.go).
+	Withdrawals []Withdrawal `json:"withdrawals,omitempty"`
+
+	// FullTransactions holds the decoded objects when the block was fetched
+	// hydrated (GetFullBlock); nil for a hashes-only fetch.
+	FullTransactions []Transaction `json:"-"`
+}
+
+// UnmarshalJSON decodes a block whose "transactions" array holds either hash
+// strings (hydrated=false) or transaction objects (hydrated=true).
+//
+// The blockFields alias has the same fields as Block but none of its
+// methods, so decoding into it does not recurse back into this function.
+// Its Transactions field is shadowed by a json.RawMessage, which lets us
+// look at the array before deciding how to decode it.
+func (b *Block) UnmarshalJSON(data []byte) error {
+	type blockFields Block
+	var raw struct {
+		blockFields
+		Transactions json.RawMessage `json:"transactions"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*b = Block(raw.blockFields)
+	b.Transactions = nil
+
+	// Peek at the first element: a string means hashes, anything else is
+	// an object. An empty or missing array is valid in both forms.
+	var elems []json.RawMessage
+	if len(raw.Transactions) > 0 && !isNullResult(raw.Transactions) {
+		if err := json.Unmarshal(raw.Transactions, &elems); err != nil {
+			return fmt.Errorf("block transactions: %w", err)
+		}
+	}
+	if len(elems) == 0 {
+		b.Transactions = []string{}
+		return nil
+	}
+	if first := bytes.TrimSpace(elems[0]); len(first) > 0 && first[0] == '"' {
+		return json.Unmarshal(raw.Transactions, &b.Transactions)
+	}
+
+	if err := json.Unmarshal(raw.Transactions, &b.FullTransactions); err != nil {
+		return fmt.Errorf("block transaction objects: %w", err)
+	}
+	b.Transactions = make([]string, len(b.FullTransactions))
+	for i := range b.FullTransactions {
+		b.Transactions[i] = b.FullTransactions[i].Hash
+	}
+	return nil
 }
 
 // =============================================================================
@@ -308,6 +396,14 @@ type ParsedBlock struct {
 	GasLimit      uint64   // Maximum gas allowed in this block
 	BaseFeePerGas *big.Int // EIP-1559 base fee in wei; nil for pre-London blocks
 	TxCount       int      // Number of transactions (derived from len(Transactions))
+
+	// Fields later forks added; zero values on blocks from before them.
+	WithdrawalsRoot       string   // Shanghai: withdrawal trie root
+	Withdrawals           int      // Shanghai: withdrawals in the body (len(Withdrawals))
+	WithdrawalsGwei       uint64   // Shanghai: their amounts summed, in gwei
+	BlobGas               *BlobGas // Cancun: blob gas and blob base fee (blob.go); nil before
+	ParentBeaconBlockRoot string   // Cancun: beacon block root (EIP-4788)
+	RequestsHash          string   // Prague: execution-layer requests commitment (EIP-7685)
 }
 
 // =============================================================================
@@ -385,8 +481,13 @@ type ParsedBlock struct {
 //   - Number, Timestamp, GasUsed, GasLimit → uint64 via ParseHexUint64
 //   - BaseFeePerGas → *big.Int via ParseHexBigInt (only if present)
 //   - TxCount is derived from the length of the Transactions slice
+//   - Withdrawals and WithdrawalsGwei are counted and summed from the body
+//   - BlobGas is read from blobGasUsed and excessBlobGas, with the blob
+//     base fee computed for the fork the header layout implies (blob.go);
+//     ParsedOn uses the chain's fork timetable instead
 //
-// Hash and ParentHash are already strings and pass through unchanged.
+// Hash, ParentHash and the post-Shanghai roots are already strings and pass
+// through unchanged.
 func (b *Block) Parsed() ParsedBlock {
 	// Parse each hex field into its native type.
 	// The _ discards the error — see the ERROR HANDLING STRATEGY comment above.
@@ -411,6 +512,14 @@ func (b *Block) Parsed() ParsedBlock {
 		baseFee = ParseHexBigInt(b.BaseFeePerGas)
 	}
 
+	// Withdrawal amounts are gwei; a block holds at most 16, each well
+	// under 2^64 gwei, so the sum fits a uint64.
+	var withdrawn uint64
+	for _, wd := range b.Withdrawals {
+		amount, _ := ParseHexUint64(wd.Amount)
+		withdrawn += amount
+	}
+
 	// Return a ParsedBlock VALUE (not a pointer).
 	// This struct is small enough (~80 bytes) that returning by value is
 	// efficient. The caller gets their own copy on the stack, and the
@@ -434,5 +543,22 @@ func (b *Block) Parsed() ParsedBlock {
 		GasLimit:      gasLimit,
 		BaseFeePerGas: baseFee,
 		TxCount:       len(b.Transactions),
+
+		WithdrawalsRoot:       b.WithdrawalsRoot,
+		Withdrawals:           len(b.Withdrawals),
+		WithdrawalsGwei:       withdrawn,
+		BlobGas:               b.blobGas(0),
+		ParentBeaconBlockRoot: b.ParentBeaconBlockRoot,
+		RequestsHash:          b.RequestsHash,
 	}
 }
+
+// ParsedOn is Parsed for a block of a known chain: the blob base fee is
+// computed under the blob schedule chainID ran at the block's timestamp.
+// Osaka and the BPO forks keep Prague's header layout, so without the chain
+// ID their fee would be priced with Prague's update fraction.
+func (b *Block) ParsedOn(chainID uint64) ParsedBlock {
+	p := b.Parsed()
+	p.BlobGas = b.blobGas(chainID)
+	return p
+}
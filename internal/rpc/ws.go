@@ -0,0 +1,890 @@
+// =============================================================================
+// FILE: internal/rpc/ws.go
+// ROLE: Network Layer — WebSocket JSON-RPC Client with eth_subscribe Support
+// =============================================================================
+//
+// SYSTEM CONTEXT
+// ==============
+// client.go speaks JSON-RPC over HTTP POST: one request, one response, and
+// the server can never talk first. That forces `monitor` to POLL
+// eth_blockNumber on a ticker, so a new head is only noticed at the next
+// tick. Over a WebSocket, the node PUSHES every new head the moment it
+// imports it:
+//
+//   HTTP polling (client.go):            WebSocket push (THIS FILE):
+//
+//   t=0s   ──▶ eth_blockNumber           ──▶ eth_subscribe("newHeads")
+//          ◀── 0x100                     ◀── "0xsubid"
+//   t=30s  ──▶ eth_blockNumber                 ...
+//          ◀── 0x102  (two heads late)   ◀── eth_subscription {number: 0x101}
+//                                        ◀── eth_subscription {number: 0x102}
+//                                              ↑ arrives when the node has it
+//
+// Comparing the moment each provider PUSHES the same head is the most direct
+// measure of how quickly a provider propagates new blocks.
+//
+// WHY NOT A WEBSOCKET LIBRARY?
+// ============================
+// The project is standard-library only (see client.go, DESIGN DECISIONS #4).
+// The subset of RFC 6455 a JSON-RPC client needs is small: an HTTP Upgrade
+// handshake, then length-prefixed frames. Everything lives in this file:
+//
+//   SECTION 1: Frame encoding/decoding (shared with the test stand-in server)
+//   SECTION 2: Opening handshake (HTTP/1.1 Upgrade → 101 Switching Protocols)
+//   SECTION 3: WSClient — request/response multiplexing over one socket
+//   SECTION 4: Subscriptions — newHeads stream, unsubscribe
+//   SECTION 5: Keepalive (ping/pong) and automatic reconnection
+//
+// CS CONCEPTS: MULTIPLEXING OVER ONE CONNECTION
+// ==============================================
+// Over HTTP each request has its own exchange. Over a WebSocket, requests,
+// responses and server-initiated notifications all share one stream. A
+// single reader goroutine (readLoop) demultiplexes them:
+//
+//                       ┌──────────────────────┐
+//   Call(id=7) ───────▶ │                      │ ──▶ pending[7] ──▶ Call returns
+//   Call(id=8) ───────▶ │   one TCP/TLS conn   │ ──▶ pending[8] ──▶ Call returns
+//                       │                      │ ──▶ subs["0xab"] ──▶ Heads() chan
+//                       └──────────────────────┘
+//
+// Responses are routed by "id"; notifications by params.subscription.
+// IDs come from the same random-base idSource as Client (ids.go), so two
+// monitors behind one misbehaving proxy never share an ID. A response whose
+// id matches no pending call is not dropped silently: it is counted as an
+// *IDMismatchError (IDMismatches), because a stream that answers requests
+// we never sent is the WebSocket form of a crossed HTTP response.
+// =============================================================================
+
+package rpc
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// =============================================================================
+// SECTION 1: Frame Encoding and Decoding (RFC 6455 §5)
+// =============================================================================
+//
+// Every WebSocket message travels as one or more FRAMES:
+//
+//   byte 0:  FIN(1 bit) RSV(3 bits) OPCODE(4 bits)
+//   byte 1:  MASK(1 bit) PAYLOAD LEN(7 bits)
+//            126 → next 2 bytes hold the length
+//            127 → next 8 bytes hold the length
+//   [4-byte masking key, if MASK is set]
+//   [payload]
+//
+// Clients MUST mask frames they send; servers MUST NOT. The mask is a
+// 4-byte key XORed over the payload — it exists to stop cache-poisoning
+// attacks on intermediaries, not for secrecy.
+// =============================================================================
+
+// WebSocket opcodes used by this client.
+const (
+	wsOpContinuation = 0x0
+	wsOpText         = 0x1
+	wsOpBinary       = 0x2
+	wsOpClose        = 0x8
+	wsOpPing         = 0x9
+	wsOpPong         = 0xA
+)
+
+// wsMaxMessage bounds the size of a single reassembled message. A full block
+// notification is a few KB; 16 MB leaves room for large call results while
+// protecting against a broken server streaming an endless frame.
+const wsMaxMessage = 16 << 20
+
+// wsGUID is the fixed key suffix from RFC 6455 §1.3 used to compute
+// Sec-WebSocket-Accept.
+const wsGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// wsFrame is one decoded frame.
+type wsFrame struct {
+	fin     bool
+	opcode  byte
+	payload []byte
+}
+
+// writeWSFrame encodes and writes one frame. mask must be true for frames
+// sent by a client and false for frames sent by a server.
+func writeWSFrame(w io.Writer, opcode byte, payload []byte, mask bool) error {
+	header := make([]byte, 0, 14)
+	header = append(header, 0x80|opcode) // FIN + opcode; we never fragment
+
+	maskBit := byte(0)
+	if mask {
+		maskBit = 0x80
+	}
+	switch n := len(payload); {
+	case n < 126:
+		header = append(header, maskBit|byte(n))
+	case n <= 0xFFFF:
+		header = append(header, maskBit|126)
+		header = binary.BigEndian.AppendUint16(header, uint16(n))
+	default:
+		header = append(header, maskBit|127)
+		header = binary.BigEndian.AppendUint64(header, uint64(n))
+	}
+
+	if mask {
+		var key [4]byte
+		if _, err := rand.Read(key[:]); err != nil {
+			return fmt.Errorf("websocket mask key: %w", err)
+		}
+		header = append(header, key[:]...)
+		masked := make([]byte, len(payload))
+		for i := range payload {
+			masked[i] = payload[i] ^ key[i%4]
+		}
+		payload = masked
+	}
+
+	if _, err := w.Write(append(header, payload...)); err != nil {
+		return err
+	}
+	return nil
+}
+
+// readWSFrame reads and decodes one frame, unmasking the payload if needed.
+func readWSFrame(r io.Reader) (wsFrame, error) {
+	var hdr [2]byte
+	if _, err := io.ReadFull(r, hdr[:]); err != nil {
+		return wsFrame{}, err
+	}
+	f := wsFrame{fin: hdr[0]&0x80 != 0, opcode: hdr[0] & 0x0F}
+	masked := hdr[1]&0x80 != 0
+
+	length := uint64(hdr[1] & 0x7F)
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(r, ext[:]); err != nil {
+			return wsFrame{}, err
+		}
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(r, ext[:]); err != nil {
+			return wsFrame{}, err
+		}
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+	if length > wsMaxMessage {
+		return wsFrame{}, fmt.Errorf("websocket frame too large: %d bytes", length)
+	}
+
+	var key [4]byte
+	if masked {
+		if _, err := io.ReadFull(r, key[:]); err != nil {
+			return wsFrame{}, err
+		}
+	}
+	f.payload = make([]byte, length)
+	if _, err := io.ReadFull(r, f.payload); err != nil {
+		return wsFrame{}, err
+	}
+	if masked {
+		for i := range f.payload {
+			f.payload[i] ^= key[i%4]
+		}
+	}
+	return f, nil
+}
+
+// wsAcceptKey computes the Sec-WebSocket-Accept value for a client key.
+func wsAcceptKey(key string) string {
+	sum := sha1.Sum([]byte(key + wsGUID))
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// =============================================================================
+// SECTION 2: Opening Handshake (RFC 6455 §4)
+// =============================================================================
+//
+// A WebSocket starts life as an ordinary HTTP/1.1 GET with special headers.
+// If the server agrees, it answers "101 Switching Protocols" and from then on
+// the same TCP connection carries frames instead of HTTP:
+//
+//   Client                                   Server
+//   GET /ws HTTP/1.1
+//   Upgrade: websocket
+//   Connection: Upgrade
+//   Sec-WebSocket-Key: <random base64>  ──▶
+//                                       ◀──  HTTP/1.1 101 Switching Protocols
+//                                            Sec-WebSocket-Accept: <derived>
+//   ═══════════════ frames from here on ═══════════════
+// =============================================================================
+
+// wsConn is an established WebSocket connection.
+//
+// br wraps the raw connection for reading because http.ReadResponse may
+// have buffered the first frames together with the 101 response.
+type wsConn struct {
+	conn net.Conn
+	br   *bufio.Reader
+}
+
+// dialWebSocket opens a TCP (ws://) or TLS (wss://) connection and performs
+// the opening handshake.
+func dialWebSocket(ctx context.Context, rawURL string, auth *Auth) (*wsConn, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("parse websocket url: %w", err)
+	}
+
+	host := u.Host
+	secure := false
+	switch u.Scheme {
+	case "ws":
+		if u.Port() == "" {
+			host = net.JoinHostPort(u.Hostname(), "80")
+		}
+	case "wss":
+		secure = true
+		if u.Port() == "" {
+			host = net.JoinHostPort(u.Hostname(), "443")
+		}
+	default:
+		return nil, fmt.Errorf("unsupported websocket scheme %q", u.Scheme)
+	}
+
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", host)
+	if err != nil {
+		return nil, &TransportError{Err: err}
+	}
+	if secure {
+		tlsConn := tls.Client(conn, &tls.Config{ServerName: u.Hostname()})
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			conn.Close()
+			return nil, &TransportError{Err: err}
+		}
+		conn = tlsConn
+	}
+
+	// Bound the handshake by the context deadline (if any); cleared below.
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	var keyBytes [16]byte
+	if _, err := rand.Read(keyBytes[:]); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("websocket key: %w", err)
+	}
+	key := base64.StdEncoding.EncodeToString(keyBytes[:])
+
+	req := &http.Request{
+		Method:     "GET",
+		URL:        u,
+		Host:       u.Host,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header: http.Header{
+			"Upgrade":               {"websocket"},
+			"Connection":            {"Upgrade"},
+			"Sec-WebSocket-Key":     {key},
+			"Sec-WebSocket-Version": {"13"},
+		},
+	}
+	auth.apply(req.Header, time.Now())
+	if err := req.Write(conn); err != nil {
+		conn.Close()
+		return nil, &TransportError{Err: fmt.Errorf("websocket handshake: %w", err)}
+	}
+
+	br := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(br, req)
+	if err != nil {
+		conn.Close()
+		return nil, &TransportError{Err: fmt.Errorf("websocket handshake: %w", err)}
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		// Providers reject bad keys (401/403) and over-quota clients (429)
+		// here, before any JSON-RPC traffic — same categories as HTTP.
+		conn.Close()
+		return nil, fmt.Errorf("websocket handshake: %w", &HTTPError{
+			StatusCode: resp.StatusCode,
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+		})
+	}
+	if resp.Header.Get("Sec-WebSocket-Accept") != wsAcceptKey(key) {
+		conn.Close()
+		return nil, &ProtocolError{Msg: "websocket handshake: bad Sec-WebSocket-Accept"}
+	}
+
+	conn.SetDeadline(time.Time{})
+	return &wsConn{conn: conn, br: br}, nil
+}
+
+// =============================================================================
+// SECTION 3: WSClient — JSON-RPC over a WebSocket
+// =============================================================================
+
+// Head is one block header pushed by an eth_subscribe("newHeads")
+// subscription, together with the local time it arrived.
+//
+// Received is the measurement: comparing Received for the same Number
+// across providers shows which provider announced the block first.
+type Head struct {
+	Number     uint64    // Block height
+	Hash       string    // Block hash
+	ParentHash string    // Parent block hash
+	Timestamp  uint64    // Block timestamp (Unix seconds)
+	Received   time.Time // Local arrival time of the notification
+}
+
+// WSClient is a WebSocket JSON-RPC client for a single provider.
+//
+// Unlike Client (one HTTP exchange per call), a WSClient holds ONE long-lived
+// connection. A background reader goroutine routes responses to waiting
+// callers and notifications to subscriptions. If the connection drops, the
+// client reconnects with backoff and re-issues every active subscription, so
+// a Subscription's Heads channel survives reconnects.
+type WSClient struct {
+	name    string
+	url     string
+	timeout time.Duration // Per-call and dial timeout
+	auth    *Auth         // Applied to the upgrade request; nil = none
+
+	// PingInterval controls keepalive pings. A pong must arrive within
+	// `timeout`, otherwise the connection is considered dead and replaced.
+	// Zero disables pings. Set before Connect.
+	PingInterval time.Duration
+
+	writeMu sync.Mutex // Serializes frame writes (one writer at a time)
+
+	mu         sync.Mutex
+	conn       *wsConn
+	gen        int // Incremented on every (re)connect; stale goroutines exit
+	ids        *idSource
+	pending    map[uint64]*wsPending
+	subs       map[string]*Subscription // Keyed by the SERVER's subscription ID
+	active     []*Subscription          // All subscriptions to restore on reconnect
+	lastPong   time.Time
+	closed     bool
+	reconnects int
+	mismatches int              // Responses whose id matched no pending call
+	mismatch   *IDMismatchError // The most recent of them
+}
+
+// NewWSClient creates a WebSocket client. No connection is made until Connect.
+func NewWSClient(name, url string, timeout time.Duration, opts ...Option) *WSClient {
+	o := applyOptions(opts)
+	return &WSClient{
+		name:         name,
+		url:          url,
+		timeout:      timeout,
+		auth:         o.auth,
+		PingInterval: 15 * time.Second,
+		ids:          newIDSource(),
+		pending:      make(map[uint64]*wsPending),
+		subs:         make(map[string]*Subscription),
+	}
+}
+
+// Name returns the human-readable provider name for this client.
+func (c *WSClient) Name() string { return c.name }
+
+// Reconnects returns how many times the connection has been re-established
+// after a drop. A steadily rising count is itself a reliability signal.
+func (c *WSClient) Reconnects() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.reconnects
+}
+
+// IDMismatches returns how many responses carried an id that matched no
+// pending call, and the most recent one (nil when there were none). A late
+// answer to a call that already timed out counts too: either way the
+// provider sent a response nobody was waiting for.
+func (c *WSClient) IDMismatches() (int, *IDMismatchError) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.mismatches, c.mismatch
+}
+
+// Connect dials the provider and starts the reader and keepalive goroutines.
+func (c *WSClient) Connect(ctx context.Context) error {
+	dctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	conn, err := dialWebSocket(dctx, c.url, c.auth)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		conn.conn.Close()
+		return errors.New("websocket client closed")
+	}
+	c.conn = conn
+	c.gen++
+	gen := c.gen
+	c.lastPong = time.Now()
+	c.mu.Unlock()
+
+	go c.readLoop(conn, gen)
+	if c.PingInterval > 0 {
+		go c.pingLoop(conn, gen)
+	}
+	return nil
+}
+
+// Close shuts the connection down permanently and closes every
+// subscription's Heads channel. Close is safe to call more than once.
+func (c *WSClient) Close() error {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return nil
+	}
+	c.closed = true
+	conn := c.conn
+	c.conn = nil
+	active := c.active
+	c.active = nil
+	c.subs = make(map[string]*Subscription)
+	c.mu.Unlock()
+
+	for _, s := range active {
+		s.close()
+	}
+	if conn == nil {
+		return nil
+	}
+	c.writeMu.Lock()
+	writeWSFrame(conn.conn, wsOpClose, nil, true)
+	c.writeMu.Unlock()
+	return conn.conn.Close()
+}
+
+// wsPending is a call waiting for its response. sub is set only for
+// eth_subscribe calls: the reader registers the subscription the moment the
+// response arrives, BEFORE reading the next frame, so a notification that
+// immediately follows the subscribe response can't be dropped as unknown.
+type wsPending struct {
+	ch  chan *Response
+	sub *Subscription
+}
+
+// Call sends a JSON-RPC request over the WebSocket and waits for the
+// matching response. Latency is measured from write to response arrival.
+func (c *WSClient) Call(ctx context.Context, method string, params ...interface{}) (*Response, time.Duration, error) {
+	return c.call(ctx, nil, method, params...)
+}
+
+// call is Call with an optional subscription to register on success.
+func (c *WSClient) call(ctx context.Context, sub *Subscription, method string, params ...interface{}) (*Response, time.Duration, error) {
+	if params == nil {
+		params = []interface{}{}
+	}
+
+	c.mu.Lock()
+	conn := c.conn
+	if conn == nil {
+		c.mu.Unlock()
+		return nil, 0, &TransportError{Err: errors.New("websocket not connected")}
+	}
+	id := c.ids.take()
+	ch := make(chan *Response, 1)
+	c.pending[id] = &wsPending{ch: ch, sub: sub}
+	c.mu.Unlock()
+
+	defer func() {
+		c.mu.Lock()
+		delete(c.pending, id)
+		c.mu.Unlock()
+	}()
+
+	body, err := json.Marshal(Request{JSONRPC: "2.0", Method: method, Params: params, ID: id})
+	if err != nil {
+		return nil, 0, fmt.Errorf("marshal rpc request: %w", err)
+	}
+
+	start := time.Now()
+	if err := c.write(conn, wsOpText, body); err != nil {
+		return nil, 0, err
+	}
+
+	timer := time.NewTimer(c.timeout)
+	defer timer.Stop()
+	select {
+	case resp, ok := <-ch:
+		if !ok || resp == nil {
+			return nil, 0, &TransportError{Err: errors.New("websocket connection lost")}
+		}
+		if resp.Error != nil {
+			return nil, 0, resp.Error
+		}
+		return resp, time.Since(start), nil
+	case <-timer.C:
+		return nil, 0, &TransportError{Err: fmt.Errorf("websocket call %s: %w after %s", method, context.DeadlineExceeded, c.timeout)}
+	case <-ctx.Done():
+		return nil, 0, ctx.Err()
+	}
+}
+
+// write sends one masked client frame, serialized against other writers.
+func (c *WSClient) write(conn *wsConn, opcode byte, payload []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	conn.conn.SetWriteDeadline(time.Now().Add(c.timeout))
+	return writeWSFrame(conn.conn, opcode, payload, true)
+}
+
+// wsMessage is the union of everything a server can send: a response
+// (ID set) or a subscription notification (Method == "eth_subscription").
+type wsMessage struct {
+	ID     json.RawMessage `json:"id"`
+	Method string          `json:"method"`
+	Result json.RawMessage `json:"result"`
+	Error  *RPCError       `json:"error"`
+	Params struct {
+		Subscription string          `json:"subscription"`
+		Result       json.RawMessage `json:"result"`
+	} `json:"params"`
+}
+
+// readLoop is the single reader for one connection generation. It
+// reassembles fragmented messages, answers pings, and routes each message.
+// When the connection fails it hands off to reconnect.
+func (c *WSClient) readLoop(conn *wsConn, gen int) {
+	var msg []byte
+	for {
+		f, err := readWSFrame(conn.br)
+		if err != nil {
+			c.connectionLost(conn, gen)
+			return
+		}
+		switch f.opcode {
+		case wsOpPing:
+			c.write(conn, wsOpPong, f.payload)
+			continue
+		case wsOpPong:
+			c.mu.Lock()
+			c.lastPong = time.Now()
+			c.mu.Unlock()
+			continue
+		case wsOpClose:
+			c.connectionLost(conn, gen)
+			return
+		case wsOpText, wsOpBinary:
+			msg = append(msg[:0], f.payload...)
+		case wsOpContinuation:
+			msg = append(msg, f.payload...)
+		}
+		if len(msg) > wsMaxMessage {
+			c.connectionLost(conn, gen)
+			return
+		}
+		if !f.fin {
+			continue
+		}
+		c.dispatch(msg, time.Now())
+	}
+}
+
+// dispatch routes one complete message to a pending call or a subscription.
+func (c *WSClient) dispatch(raw []byte, received time.Time) {
+	var m wsMessage
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return // Not JSON-RPC; nothing sensible to route it to.
+	}
+
+	if m.Method == "eth_subscription" {
+		c.mu.Lock()
+		sub := c.subs[m.Params.Subscription]
+		c.mu.Unlock()
+		if sub != nil {
+			sub.deliver(m.Params.Result, received)
+		}
+		return
+	}
+
+	id, ok := parseID(m.ID)
+	c.mu.Lock()
+	p := c.pending[id]
+	if !ok || p == nil {
+		raw := strings.TrimSpace(string(m.ID))
+		if raw == "" {
+			raw = "null"
+		}
+		c.mismatches++
+		c.mismatch = &IDMismatchError{Got: raw}
+		c.mu.Unlock()
+		return
+	}
+	if p.sub != nil && m.Error == nil {
+		var subID string
+		if json.Unmarshal(m.Result, &subID) == nil && subID != "" {
+			p.sub.mu.Lock()
+			p.sub.id = subID
+			p.sub.mu.Unlock()
+			c.subs[subID] = p.sub
+		}
+	}
+	c.mu.Unlock()
+	// Non-blocking: a duplicate response for the same ID must not stall
+	// the reader (the buffer of 1 already holds the first answer).
+	select {
+	case p.ch <- &Response{JSONRPC: "2.0", ID: m.ID, Result: m.Result, Error: m.Error}:
+	default:
+	}
+}
+
+// =============================================================================
+// SECTION 4: Subscriptions
+// =============================================================================
+
+// Subscription is a live eth_subscribe("newHeads") stream.
+//
+// The Heads channel is buffered; if the consumer falls behind, the OLDEST
+// unread head is dropped so the newest information always gets through —
+// for latency monitoring a stale head is worth less than a fresh one.
+type Subscription struct {
+	client *WSClient
+	heads  chan Head
+
+	mu     sync.Mutex
+	id     string // Current server-side subscription ID (changes on reconnect)
+	closed bool
+}
+
+// Heads returns the channel of pushed headers. It is closed when the
+// subscription is unsubscribed or the client is closed.
+func (s *Subscription) Heads() <-chan Head { return s.heads }
+
+// ID returns the server-assigned subscription ID currently in use.
+func (s *Subscription) ID() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.id
+}
+
+// SubscribeNewHeads issues eth_subscribe("newHeads") and returns the stream.
+func (c *WSClient) SubscribeNewHeads(ctx context.Context) (*Subscription, error) {
+	s := &Subscription{client: c, heads: make(chan Head, 64)}
+	if err := c.subscribe(ctx, s); err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	c.active = append(c.active, s)
+	c.mu.Unlock()
+	return s, nil
+}
+
+// subscribe sends eth_subscribe for s. The reader registers the returned
+// server ID (see wsPending); here we only validate the result.
+func (c *WSClient) subscribe(ctx context.Context, s *Subscription) error {
+	resp, _, err := c.call(ctx, s, "eth_subscribe", "newHeads")
+	if err != nil {
+		return fmt.Errorf("eth_subscribe: %w", err)
+	}
+	var id string
+	if err := json.Unmarshal(resp.Result, &id); err != nil || id == "" {
+		return fmt.Errorf("eth_subscribe: unexpected result %s", string(resp.Result))
+	}
+	return nil
+}
+
+// Unsubscribe cancels the subscription on the server and closes Heads.
+func (s *Subscription) Unsubscribe(ctx context.Context) error {
+	c := s.client
+	id := s.ID()
+
+	c.mu.Lock()
+	delete(c.subs, id)
+	for i, a := range c.active {
+		if a == s {
+			c.active = append(c.active[:i], c.active[i+1:]...)
+			break
+		}
+	}
+	c.mu.Unlock()
+	s.close()
+
+	resp, _, err := c.Call(ctx, "eth_unsubscribe", id)
+	if err != nil {
+		return fmt.Errorf("eth_unsubscribe: %w", err)
+	}
+	var ok bool
+	if err := json.Unmarshal(resp.Result, &ok); err != nil || !ok {
+		return fmt.Errorf("eth_unsubscribe: server returned %s", string(resp.Result))
+	}
+	return nil
+}
+
+// deliver decodes one newHeads notification and pushes it to the channel.
+func (s *Subscription) deliver(raw json.RawMessage, received time.Time) {
+	var hdr struct {
+		Number     string `json:"number"`
+		Hash       string `json:"hash"`
+		ParentHash string `json:"parentHash"`
+		Timestamp  string `json:"timestamp"`
+	}
+	if err := json.Unmarshal(raw, &hdr); err != nil {
+		return
+	}
+	num, err := ParseHexUint64(hdr.Number)
+	if err != nil {
+		return
+	}
+	ts, _ := ParseHexUint64(hdr.Timestamp)
+	h := Head{Number: num, Hash: hdr.Hash, ParentHash: hdr.ParentHash, Timestamp: ts, Received: received}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	select {
+	case s.heads <- h:
+	default:
+		// Buffer full: drop the oldest head, then enqueue the new one.
+		select {
+		case <-s.heads:
+		default:
+		}
+		s.heads <- h
+	}
+}
+
+// close marks the subscription finished and closes its channel once.
+func (s *Subscription) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.closed {
+		s.closed = true
+		close(s.heads)
+	}
+}
+
+// =============================================================================
+// SECTION 5: Keepalive and Reconnection
+// =============================================================================
+//
+// Two things can silently kill a long-lived socket: the server (or a load
+// balancer in front of it) drops idle connections, or the network path dies
+// without a FIN ever arriving. Pings handle both — a missing pong tells us
+// the connection is dead even when the OS doesn't know yet.
+//
+// On any failure the current generation is torn down and reconnect() dials
+// again with exponential backoff (1s, 2s, 4s ... capped at 30s), then
+// re-subscribes. Generations keep goroutines from the old connection from
+// touching the new one:
+//
+//   gen 1: readLoop ─╳ (EOF) ──▶ connectionLost(gen 1) ──▶ reconnect()
+//   gen 2: readLoop, pingLoop started; subscriptions re-issued
+//   gen 1's pingLoop notices gen != 1 and exits
+// =============================================================================
+
+// pingLoop sends a ping every PingInterval and declares the connection dead
+// if the previous ping went unanswered for longer than the call timeout.
+func (c *WSClient) pingLoop(conn *wsConn, gen int) {
+	ticker := time.NewTicker(c.PingInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		c.mu.Lock()
+		current := c.gen == gen && !c.closed
+		sincePong := time.Since(c.lastPong)
+		c.mu.Unlock()
+		if !current {
+			return
+		}
+		if sincePong > c.PingInterval+c.timeout {
+			conn.conn.Close() // readLoop sees the error and triggers reconnect
+			return
+		}
+		if err := c.write(conn, wsOpPing, []byte("eth-rpc-monitor")); err != nil {
+			conn.conn.Close()
+			return
+		}
+	}
+}
+
+// connectionLost fails every pending call for this generation and starts a
+// reconnect unless the client was closed on purpose.
+func (c *WSClient) connectionLost(conn *wsConn, gen int) {
+	conn.conn.Close()
+
+	c.mu.Lock()
+	if c.gen != gen {
+		c.mu.Unlock()
+		return
+	}
+	c.conn = nil
+	for id, p := range c.pending {
+		close(p.ch)
+		delete(c.pending, id)
+	}
+	c.subs = make(map[string]*Subscription)
+	closed := c.closed
+	c.mu.Unlock()
+
+	if !closed {
+		go c.reconnect()
+	}
+}
+
+// reconnect re-dials with exponential backoff and restores subscriptions.
+func (c *WSClient) reconnect() {
+	backoff := time.Second
+	for {
+		c.mu.Lock()
+		closed := c.closed
+		c.mu.Unlock()
+		if closed {
+			return
+		}
+
+		if err := c.Connect(context.Background()); err == nil {
+			c.mu.Lock()
+			c.reconnects++
+			active := append([]*Subscription(nil), c.active...)
+			c.mu.Unlock()
+
+			for _, s := range active {
+				ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+				err := c.subscribe(ctx, s)
+				cancel()
+				if err != nil {
+					// The new connection is unusable for subscriptions; drop
+					// it and let the read loop start the next attempt.
+					c.mu.Lock()
+					conn := c.conn
+					c.mu.Unlock()
+					if conn != nil {
+						conn.conn.Close()
+					}
+					return
+				}
+			}
+			return
+		}
+
+		time.Sleep(backoff)
+		if backoff < 30*time.Second {
+			backoff *= 2
+		}
+	}
+}
@@ -0,0 +1,307 @@
+package rpc
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// wsStandIn is a minimal WebSocket JSON-RPC server for tests. It answers
+// eth_subscribe / eth_unsubscribe / eth_blockNumber, echoes pings, and lets
+// the test push heads or drop the connection.
+type wsStandIn struct {
+	srv *httptest.Server
+
+	mu       sync.Mutex
+	conns    []net.Conn
+	subCount int
+	pings    int
+	unsubs   []string
+	ids      []int
+	subReady chan string
+}
+
+func newWSStandIn(t *testing.T) *wsStandIn {
+	s := &wsStandIn{subReady: make(chan string, 8)}
+	s.srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+			http.Error(w, "not a websocket", http.StatusBadRequest)
+			return
+		}
+		conn, brw, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			t.Errorf("hijack: %v", err)
+			return
+		}
+		fmt.Fprintf(brw, "HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\nSec-WebSocket-Accept: %s\r\n\r\n",
+			wsAcceptKey(r.Header.Get("Sec-WebSocket-Key")))
+		brw.Flush()
+
+		s.mu.Lock()
+		s.conns = append(s.conns, conn)
+		s.mu.Unlock()
+		s.serve(conn, brw.Reader)
+	}))
+	t.Cleanup(s.srv.Close)
+	return s
+}
+
+func (s *wsStandIn) url() string { return "ws" + strings.TrimPrefix(s.srv.URL, "http") }
+
+func (s *wsStandIn) serve(conn net.Conn, br *bufio.Reader) {
+	defer conn.Close()
+	for {
+		f, err := readWSFrame(br)
+		if err != nil {
+			return
+		}
+		switch f.opcode {
+		case wsOpPing:
+			s.mu.Lock()
+			s.pings++
+			s.mu.Unlock()
+			writeWSFrame(conn, wsOpPong, f.payload, false)
+			continue
+		case wsOpClose:
+			return
+		}
+
+		var req struct {
+			ID     int               `json:"id"`
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+		}
+		if err := json.Unmarshal(f.payload, &req); err != nil {
+			return
+		}
+		s.mu.Lock()
+		s.ids = append(s.ids, req.ID)
+		s.mu.Unlock()
+		var result string
+		switch req.Method {
+		case "eth_subscribe":
+			s.mu.Lock()
+			s.subCount++
+			id := fmt.Sprintf("0xsub%d", s.subCount)
+			s.mu.Unlock()
+			result = `"` + id + `"`
+		case "eth_unsubscribe":
+			var id string
+			json.Unmarshal(req.Params[0], &id)
+			s.mu.Lock()
+			s.unsubs = append(s.unsubs, id)
+			s.mu.Unlock()
+			result = "true"
+		default:
+			result = `"0x10"`
+		}
+		writeWSFrame(conn, wsOpText, []byte(fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"result":%s}`, req.ID, result)), false)
+		if req.Method == "eth_subscribe" {
+			s.subReady <- strings.Trim(result, `"`)
+		}
+	}
+}
+
+// push sends a newHeads notification for block n on the most recent connection.
+func (s *wsStandIn) push(subID string, n uint64) {
+	s.mu.Lock()
+	conn := s.conns[len(s.conns)-1]
+	s.mu.Unlock()
+	msg := fmt.Sprintf(`{"jsonrpc":"2.0","method":"eth_subscription","params":{"subscription":%q,"result":{"number":"0x%x","hash":"0xh%d","parentHash":"0xp","timestamp":"0x1"}}}`, subID, n, n)
+	writeWSFrame(conn, wsOpText, []byte(msg), false)
+}
+
+// send writes a raw text message on the most recent connection.
+func (s *wsStandIn) send(msg string) {
+	s.mu.Lock()
+	conn := s.conns[len(s.conns)-1]
+	s.mu.Unlock()
+	writeWSFrame(conn, wsOpText, []byte(msg), false)
+}
+
+// drop abruptly closes the most recent connection.
+func (s *wsStandIn) drop() {
+	s.mu.Lock()
+	conn := s.conns[len(s.conns)-1]
+	s.mu.Unlock()
+	conn.Close()
+}
+
+func waitHead(t *testing.T, sub *Subscription) Head {
+	t.Helper()
+	select {
+	case h, ok := <-sub.Heads():
+		if !ok {
+			t.Fatal("heads channel closed")
+		}
+		return h
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out waiting for head")
+	}
+	return Head{}
+}
+
+func waitSub(t *testing.T, s *wsStandIn) string {
+	t.Helper()
+	select {
+	case id := <-s.subReady:
+		return id
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for subscription")
+	}
+	return ""
+}
+
+func TestWSFrame_roundTrip(t *testing.T) {
+	for _, n := range []int{0, 5, 125, 126, 70000} {
+		var buf strings.Builder
+		payload := []byte(strings.Repeat("x", n))
+		if err := writeWSFrame(&buf, wsOpText, payload, true); err != nil {
+			t.Fatal(err)
+		}
+		f, err := readWSFrame(strings.NewReader(buf.String()))
+		if err != nil || !f.fin || f.opcode != wsOpText || string(f.payload) != string(payload) {
+			t.Fatalf("n=%d: frame %+v err=%v", n, f.opcode, err)
+		}
+	}
+}
+
+func TestWSAcceptKey_rfcExample(t *testing.T) {
+	// RFC 6455 §1.3 worked example.
+	if got := wsAcceptKey("dGhlIHNhbXBsZSBub25jZQ=="); got != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Fatalf("got %q", got)
+	}
+}
+
+func TestWSClient_subscribeCallUnsubscribe(t *testing.T) {
+	s := newWSStandIn(t)
+	c := NewWSClient("t", s.url(), 2*time.Second)
+	c.PingInterval = 0
+	ctx := context.Background()
+	if err := c.Connect(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	resp, _, err := c.Call(ctx, "eth_blockNumber")
+	if err != nil || string(resp.Result) != `"0x10"` {
+		t.Fatalf("call: %v %v", resp, err)
+	}
+
+	sub, err := c.SubscribeNewHeads(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := waitSub(t, s)
+	s.push(id, 100)
+	if h := waitHead(t, sub); h.Number != 100 || h.Hash != "0xh100" || h.Received.IsZero() {
+		t.Fatalf("head: %+v", h)
+	}
+
+	if err := sub.Unsubscribe(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := <-sub.Heads(); ok {
+		t.Fatal("expected closed channel after unsubscribe")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.unsubs) != 1 || s.unsubs[0] != id {
+		t.Fatalf("unsubs: %v", s.unsubs)
+	}
+}
+
+func TestWSClient_strayResponseID(t *testing.T) {
+	s := newWSStandIn(t)
+	c := NewWSClient("t", s.url(), 2*time.Second)
+	c.PingInterval = 0
+	ctx := context.Background()
+	if err := c.Connect(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	if _, _, err := c.Call(ctx, "eth_blockNumber"); err != nil {
+		t.Fatal(err)
+	}
+	s.send(`{"jsonrpc":"2.0","id":1,"result":"0x1"}`)
+	// The stray message is read before this call's response.
+	if _, _, err := c.Call(ctx, "eth_blockNumber"); err != nil {
+		t.Fatal(err)
+	}
+
+	n, last := c.IDMismatches()
+	if n != 1 || last == nil || last.Got != "1" || last.Sent != 0 {
+		t.Fatalf("mismatches = %d, %v", n, last)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.ids) != 2 || s.ids[0] == 1 || s.ids[1] != s.ids[0]+1 {
+		t.Fatalf("request ids %v: want a random base, then consecutive", s.ids)
+	}
+}
+
+func TestWSClient_reconnectResubscribes(t *testing.T) {
+	s := newWSStandIn(t)
+	c := NewWSClient("t", s.url(), 2*time.Second)
+	c.PingInterval = 0
+	ctx := context.Background()
+	if err := c.Connect(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	sub, err := c.SubscribeNewHeads(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := waitSub(t, s)
+	s.push(first, 1)
+	waitHead(t, sub)
+
+	s.drop()
+	second := waitSub(t, s)
+	if second == first {
+		t.Fatalf("expected a fresh subscription id, got %s twice", first)
+	}
+	s.push(second, 2)
+	if h := waitHead(t, sub); h.Number != 2 {
+		t.Fatalf("head after reconnect: %+v", h)
+	}
+	if c.Reconnects() != 1 || sub.ID() != second {
+		t.Fatalf("reconnects=%d id=%s", c.Reconnects(), sub.ID())
+	}
+}
+
+func TestWSClient_pingPong(t *testing.T) {
+	s := newWSStandIn(t)
+	c := NewWSClient("t", s.url(), time.Second)
+	c.PingInterval = 20 * time.Millisecond
+	if err := c.Connect(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		s.mu.Lock()
+		pings := s.pings
+		s.mu.Unlock()
+		if pings >= 2 {
+			if c.Reconnects() != 0 {
+				t.Fatalf("answered pings should not trigger reconnects")
+			}
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("server never saw pings")
+}
@@ -0,0 +1,310 @@
+// =============================================================================
+// FILE: internal/rpctest/chain.go
+// ROLE: Simulated Chain — Blocks Real Enough to Verify, Cheap Enough to Mine
+// =============================================================================
+//
+// SYSTEM CONTEXT
+// ==============
+// Package rpctest is an in-process JSON-RPC provider for tests and offline
+// demos. A Chain is the ground truth; any number of Providers (provider.go)
+// serve views of it, each with its own latency, errors, throttling, lag or
+// fork. A test points rpc.Client at a Provider the same way the commands
+// point it at Alchemy or a local geth.
+//
+// The blocks are empty (no transactions, withdrawals or logs) but otherwise
+// complete post-Cancun headers: every root is the root of an empty list,
+// gasUsed is 0, the base fee wanders by a seeded step (nextBaseFee), and
+// the hash is keccak256(rlp(header)) computed with rpc's own encoder. So
+// rpc.VerifyBlockHash, `block --verify` and `snapshot` accept them — and
+// catch a Provider serving a fork, because a forked block hashes
+// differently all the way down.
+//
+//   genesis ── 1 ── 2 ── ... ── head-1 ── head          Chain (canonical)
+//                                   └──── head'         Provider with ForkDepth 1
+//
+// TIME
+// ====
+// NewChain back-dates genesis so the head is "now", BlockTime apart. Mine
+// appends one block BlockTime after its parent; Run calls Mine every
+// BlockTime of wall time, so timestamps track the clock while it runs.
+// Reorg replaces the newest blocks with siblings — same numbers, new hashes
+// — which is what a provider's clients see when the chain reorganizes.
+// =============================================================================
+
+package rpctest
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dando385/eth-rpc-monitor/internal/rpc"
+)
+
+// Depths behind the head at which Providers answer the "safe" and
+// "finalized" tags: roughly one and two epochs, as on mainnet.
+const (
+	safeDepth      = 32
+	finalizedDepth = 64
+)
+
+// genesisBaseFee is the base fee of block 0: 1 gwei.
+const genesisBaseFee = 1_000_000_000
+
+var (
+	emptyRoot   = rpc.OrderedTrieRoot(nil)                               // Root of an empty transaction/receipt/withdrawal list
+	emptyUncles = "0x" + hex.EncodeToString(rpc.Keccak256([]byte{0xc0})) // keccak256(rlp([]))
+	emptyBloom  = "0x" + strings.Repeat("00", 256)
+)
+
+// ChainOptions configures NewChain. Zero fields take the defaults noted.
+type ChainOptions struct {
+	ChainID    uint64        // eth_chainId; default 1
+	Length     uint64        // Head block number at start; default 256
+	BlockTime  time.Duration // Timestamp spacing, and Run's pace; default 12s
+	GasLimit   uint64        // Per block; default 30,000,000
+	ReorgEvery int           // Run: reorganize after every N mined blocks (0 = never)
+	ReorgDepth int           // Blocks each of Run's reorgs replaces; default 1
+}
+
+// Chain is a simulated chain. It is safe for concurrent use; the blocks it
+// returns are shared and must not be modified.
+type Chain struct {
+	opts ChainOptions
+
+	mu     sync.RWMutex
+	blocks []*rpc.Block // Canonical chain, indexed by number
+	byHash map[string]*rpc.Block
+	mined  int // Blocks mined since the last reorg (Run's trigger)
+	reorgs int
+}
+
+// NewChain builds a chain of opts.Length+1 blocks ending at the current time.
+func NewChain(opts ChainOptions) *Chain {
+	if opts.ChainID == 0 {
+		opts.ChainID = 1
+	}
+	if opts.Length == 0 {
+		opts.Length = 256
+	}
+	if opts.BlockTime <= 0 {
+		opts.BlockTime = 12 * time.Second
+	}
+	if opts.GasLimit == 0 {
+		opts.GasLimit = 30_000_000
+	}
+	if opts.ReorgDepth <= 0 {
+		opts.ReorgDepth = 1
+	}
+	c := &Chain{opts: opts, byHash: map[string]*rpc.Block{}}
+	start := uint64(time.Now().Add(-time.Duration(opts.Length) * opts.BlockTime).Unix())
+	c.append(makeBlock(nil, start, opts.GasLimit, ""))
+	for i := uint64(0); i < opts.Length; i++ {
+		c.append(c.child(c.blocks[len(c.blocks)-1], ""))
+	}
+	return c
+}
+
+// ID returns the chain ID.
+func (c *Chain) ID() uint64 { return c.opts.ChainID }
+
+// Head returns the number of the newest block.
+func (c *Chain) Head() uint64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return uint64(len(c.blocks) - 1)
+}
+
+// Block returns canonical block n, or nil beyond the head.
+func (c *Chain) Block(n uint64) *rpc.Block {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if n >= uint64(len(c.blocks)) {
+		return nil
+	}
+	return c.blocks[n]
+}
+
+// BlockByHash returns the canonical block with hash h, or nil. Blocks
+// replaced by a reorg are no longer found.
+func (c *Chain) BlockByHash(h string) *rpc.Block {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.byHash[strings.ToLower(h)]
+}
+
+// Reorgs returns how many reorgs the chain has gone through.
+func (c *Chain) Reorgs() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.reorgs
+}
+
+// Mine appends one block and returns it.
+func (c *Chain) Mine() *rpc.Block {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	b := c.child(c.blocks[len(c.blocks)-1], "")
+	c.append(b)
+	c.mined++
+	return b
+}
+
+// Reorg replaces the newest depth blocks (at most all but genesis) with
+// siblings: same numbers and timestamps, different hashes.
+func (c *Chain) Reorg(depth int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if depth <= 0 {
+		return
+	}
+	if depth > len(c.blocks)-1 {
+		depth = len(c.blocks) - 1
+	}
+	c.reorgs++
+	c.mined = 0
+	first := len(c.blocks) - depth
+	for _, b := range c.blocks[first:] {
+		delete(c.byHash, b.Hash)
+	}
+	c.blocks = c.blocks[:first]
+	for i := 0; i < depth; i++ {
+		c.append(c.child(c.blocks[len(c.blocks)-1], fmt.Sprintf("reorg-%d", c.reorgs)))
+	}
+}
+
+// Run mines a block every BlockTime, reorganizing as ChainOptions say,
+// until ctx is done.
+func (c *Chain) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.opts.BlockTime)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.Mine()
+			c.mu.RLock()
+			due := c.opts.ReorgEvery > 0 && c.mined >= c.opts.ReorgEvery
+			c.mu.RUnlock()
+			if due {
+				c.Reorg(c.opts.ReorgDepth)
+			}
+		}
+	}
+}
+
+// fork returns a private sibling chain for the newest depth blocks up to
+// head: blocks head-depth+1 … head, derived from the canonical block before
+// them with salt mixed in. Every call with the same arguments returns
+// blocks with the same hashes.
+func (c *Chain) fork(head uint64, depth int, salt string) []*rpc.Block {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if head >= uint64(len(c.blocks)) || depth <= 0 {
+		return nil
+	}
+	if uint64(depth) > head {
+		depth = int(head)
+	}
+	out := make([]*rpc.Block, depth)
+	parent := c.blocks[head-uint64(depth)]
+	for i := range out {
+		out[i] = c.child(parent, salt)
+		parent = out[i]
+	}
+	return out
+}
+
+// append adds b to the canonical chain. Callers hold c.mu (or own c).
+func (c *Chain) append(b *rpc.Block) {
+	c.blocks = append(c.blocks, b)
+	c.byHash[b.Hash] = b
+}
+
+// child derives the block after parent; salt tells siblings apart.
+func (c *Chain) child(parent *rpc.Block, salt string) *rpc.Block {
+	ts, _ := rpc.ParseHexUint64(parent.Timestamp)
+	step := uint64(c.opts.BlockTime / time.Second)
+	if step == 0 {
+		step = 1
+	}
+	return makeBlock(parent, ts+step, c.opts.GasLimit, salt)
+}
+
+// makeBlock builds and hashes the block after parent (genesis when parent
+// is nil).
+func makeBlock(parent *rpc.Block, timestamp, gasLimit uint64, salt string) *rpc.Block {
+	number, parentHash, baseFee := uint64(0), "0x"+strings.Repeat("00", 32), uint64(genesisBaseFee)
+	if parent != nil {
+		number, _ = rpc.ParseHexUint64(parent.Number)
+		number++
+		parentHash = parent.Hash
+		baseFee = nextBaseFee(parent)
+	}
+	seed := fmt.Sprintf("%d/%s/%s", number, parentHash, salt)
+
+	b := &rpc.Block{
+		Number:                fmt.Sprintf("0x%x", number),
+		ParentHash:            parentHash,
+		Timestamp:             fmt.Sprintf("0x%x", timestamp),
+		GasUsed:               "0x0", // No transactions
+		GasLimit:              fmt.Sprintf("0x%x", gasLimit),
+		BaseFeePerGas:         fmt.Sprintf("0x%x", baseFee),
+		Transactions:          []string{},
+		Sha3Uncles:            emptyUncles,
+		Miner:                 word("miner/" + salt)[:42],
+		StateRoot:             word("state/" + seed),
+		TransactionsRoot:      emptyRoot,
+		ReceiptsRoot:          emptyRoot,
+		LogsBloom:             emptyBloom,
+		Difficulty:            "0x0",
+		ExtraData:             "0x" + hex.EncodeToString([]byte(truncateSalt("rpctest "+salt))),
+		MixHash:               word("randao/" + seed),
+		Nonce:                 "0x0000000000000000",
+		WithdrawalsRoot:       emptyRoot,
+		BlobGasUsed:           "0x0",
+		ExcessBlobGas:         "0x0",
+		ParentBeaconBlockRoot: word("beacon/" + seed),
+	}
+	b.Hash = "0x" // VerifyBlockHash needs a claimed hash to compute one
+	b.Hash = rpc.VerifyBlockHash(b).Computed
+	return b
+}
+
+// nextBaseFee returns the base fee of the block after parent. The blocks
+// are empty, so EIP-1559 would only ever lower it; instead it steps toward
+// a target seeded by the block number, 0.5–1.5× the genesis fee, by at most
+// the 1/8 EIP-1559 allows per block. The fee history then moves like a real
+// chain's, and a fork at the same height has the same fee.
+func nextBaseFee(parent *rpc.Block) uint64 {
+	fee, _ := rpc.ParseHexUint64(parent.BaseFeePerGas)
+	number, _ := rpc.ParseHexUint64(parent.Number)
+	r := binary.BigEndian.Uint64(rpc.Keccak256([]byte(fmt.Sprintf("basefee/%d", number+1)))[:8])
+	target := genesisBaseFee/2 + r%genesisBaseFee
+	step := fee / 8
+	switch {
+	case target > fee:
+		return fee + min(target-fee, step)
+	case target < fee:
+		return fee - min(fee-target, step)
+	}
+	return fee
+}
+
+// word is a deterministic 32-byte value derived from s, as 0x-hex.
+func word(s string) string {
+	return "0x" + hex.EncodeToString(rpc.Keccak256([]byte(s)))
+}
+
+// truncateSalt keeps extraData within the 32 bytes consensus allows.
+func truncateSalt(s string) string {
+	if len(s) > 32 {
+		return s[:32]
+	}
+	return s
+}
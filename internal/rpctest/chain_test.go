@@ -0,0 +1,78 @@
+package rpctest
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/dando385/eth-rpc-monitor/internal/rpc"
+)
+
+func TestNewChain_blocksVerify(t *testing.T) {
+	c := NewChain(ChainOptions{Length: 20})
+	if c.Head() != 20 || c.ID() != 1 {
+		t.Fatalf("head %d, id %d", c.Head(), c.ID())
+	}
+	for n := uint64(0); n <= c.Head(); n++ {
+		b := c.Block(n)
+		if check := rpc.VerifyBlockHash(b); !check.OK() || check.Layout != "Cancun" {
+			t.Fatalf("block %d: %s (layout %s)", n, check.Problem(), check.Layout)
+		}
+		if check := rpc.VerifyTransactionsRoot(b); !check.OK() {
+			t.Fatalf("block %d: %s", n, check.Problem())
+		}
+		if n > 0 && b.ParentHash != c.Block(n-1).Hash {
+			t.Fatalf("block %d does not extend %d", n, n-1)
+		}
+		if c.BlockByHash(b.Hash) != b {
+			t.Fatalf("block %d not found by hash", n)
+		}
+	}
+	if c.Block(21) != nil {
+		t.Fatal("block above the head")
+	}
+
+	// Empty blocks use no gas; the base fee still moves, within the 1/8
+	// per block EIP-1559 allows.
+	moved := false
+	for n := uint64(1); n <= 20; n++ {
+		p := c.Block(n - 1).Parsed()
+		b := c.Block(n).Parsed()
+		if b.GasUsed != 0 {
+			t.Fatalf("block %d uses %d gas with no transactions", n, b.GasUsed)
+		}
+		delta := new(big.Int).Sub(b.BaseFeePerGas, p.BaseFeePerGas)
+		if limit := new(big.Int).Quo(p.BaseFeePerGas, big.NewInt(8)); delta.CmpAbs(limit) > 0 {
+			t.Fatalf("block %d: base fee %s after %s", n, b.BaseFeePerGas, p.BaseFeePerGas)
+		}
+		moved = moved || delta.Sign() != 0
+	}
+	if !moved {
+		t.Fatal("base fee never moved")
+	}
+}
+
+func TestChain_mineAndReorg(t *testing.T) {
+	c := NewChain(ChainOptions{Length: 10})
+	mined := c.Mine()
+	if c.Head() != 11 || mined.ParentHash != c.Block(10).Hash {
+		t.Fatalf("head %d", c.Head())
+	}
+
+	old9, old10, old11 := c.Block(9), c.Block(10), c.Block(11)
+	c.Reorg(2)
+	if c.Head() != 11 || c.Reorgs() != 1 {
+		t.Fatalf("head %d, reorgs %d", c.Head(), c.Reorgs())
+	}
+	if c.Block(9) != old9 || c.Block(10).Hash == old10.Hash || c.Block(11).Hash == old11.Hash {
+		t.Fatal("reorg replaced the wrong blocks")
+	}
+	if c.Block(10).Timestamp != old10.Timestamp || c.Block(10).ParentHash != old9.Hash {
+		t.Fatal("sibling should share number, timestamp and parent")
+	}
+	if c.BlockByHash(old11.Hash) != nil {
+		t.Fatal("replaced block still found by hash")
+	}
+	if !rpc.VerifyBlockHash(c.Block(11)).OK() {
+		t.Fatal("reorged block does not verify")
+	}
+}
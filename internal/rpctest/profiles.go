@@ -0,0 +1,60 @@
+// =============================================================================
+// FILE: internal/rpctest/profiles.go
+// ROLE: Named Behaviors — A Ready-Made Fleet of Imperfect Providers
+// =============================================================================
+//
+// SYSTEM CONTEXT
+// ==============
+// cmd/mockfleet builds its providers from these names, and tests can use
+// them when the exact numbers do not matter. Each profile exaggerates one
+// failure that real endpoints show, so every command has something to find:
+//
+//   healthy     fast and correct
+//   slow        high median, heavy tail                   → test, monitor
+//   flaky       10% of calls fail with -32603             → test
+//   throttled   429 for 3 requests after every 8           → test, rate_limit
+//   stale       3 blocks behind                           → snapshot, monitor
+//   forked      newest 2 blocks from a private fork        → snapshot, block --verify
+//   pruned      128 blocks of state, no receipts or logs  → archive, capabilities
+// =============================================================================
+
+package rpctest
+
+import (
+	"sort"
+	"time"
+)
+
+var profiles = map[string]Behavior{
+	"healthy": {Latency: Latency{Base: 20 * time.Millisecond, Jitter: 15 * time.Millisecond, TailRate: 0.01, Tail: 150 * time.Millisecond}},
+	"slow":    {Latency: Latency{Base: 180 * time.Millisecond, Jitter: 120 * time.Millisecond, TailRate: 0.05, Tail: 800 * time.Millisecond}},
+	"flaky":   {Latency: Latency{Base: 40 * time.Millisecond, Jitter: 30 * time.Millisecond}, ErrorRate: 0.1},
+	"throttled": {
+		Latency:  Latency{Base: 30 * time.Millisecond, Jitter: 20 * time.Millisecond},
+		Throttle: Throttle{Every: 8, Burst: 3},
+	},
+	"stale":  {Latency: Latency{Base: 35 * time.Millisecond, Jitter: 20 * time.Millisecond}, Lag: 3},
+	"forked": {Latency: Latency{Base: 30 * time.Millisecond, Jitter: 20 * time.Millisecond}, ForkDepth: 2},
+	"pruned": {
+		Latency:     Latency{Base: 25 * time.Millisecond, Jitter: 15 * time.Millisecond},
+		StateWindow: 128,
+		Disabled:    []string{"eth_getBlockReceipts", "eth_getLogs"},
+	},
+}
+
+// Profile returns the named Behavior. The returned value is a copy; its
+// Disabled slice is shared and must not be modified.
+func Profile(name string) (Behavior, bool) {
+	b, ok := profiles[name]
+	return b, ok
+}
+
+// ProfileNames returns every profile name, sorted.
+func ProfileNames() []string {
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
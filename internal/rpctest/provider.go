@@ -0,0 +1,457 @@
+// =============================================================================
+// FILE: internal/rpctest/provider.go
+// ROLE: Simulated Provider — One Endpoint's View of the Chain, Flaws Included
+// =============================================================================
+//
+// SYSTEM CONTEXT
+// ==============
+// A Provider is an http.Handler that speaks JSON-RPC over a Chain. Hand it
+// to httptest.NewServer in a test, or to http.Serve in cmd/mockfleet, and
+// point rpc.Client at the URL. Its Behavior decides how far it departs from
+// a perfect node:
+//
+//   Latency / MethodLatency   how long each call takes (base + jitter, with
+//                             an occasional tail spike)
+//   ErrorRate                 fraction of calls answered -32603
+//   Throttle                  HTTP 429 for Burst requests after every Every
+//   Lag                       head served is Lag blocks behind the chain's
+//   ForkDepth                 newest N blocks come from a private fork
+//   StateWindow               state older than N blocks is "missing trie node"
+//   Disabled                  methods answered -32601
+//
+// Randomness comes from a generator seeded per Provider (Behavior.Seed, or
+// the name), so a test that sets a 10% error rate fails the same calls on
+// every run.
+//
+// METHODS
+// =======
+//   eth_blockNumber  eth_chainId  net_version  web3_clientVersion
+//   eth_syncing  net_peerCount  eth_getBlockByNumber  eth_getBlockByHash
+//   eth_getBlockReceipts  eth_getLogs  eth_gasPrice
+//   eth_maxPriorityFeePerGas  eth_feeHistory  eth_getBalance
+//   eth_getStorageAt
+//
+// Block arguments accept numbers and the latest, pending, safe, finalized
+// and earliest tags, resolved against the Provider's own (lagging or
+// forked) head. Batches are answered element by element in one response;
+// their latency is the slowest element's. Anything else is -32601.
+// =============================================================================
+
+package rpctest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"math/rand"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dando385/eth-rpc-monitor/internal/rpc"
+)
+
+// Latency is how long one call takes: Base plus a uniform share of Jitter,
+// plus Tail on a TailRate fraction of calls.
+type Latency struct {
+	Base     time.Duration
+	Jitter   time.Duration
+	TailRate float64 // 0..1
+	Tail     time.Duration
+}
+
+// Throttle answers HTTP 429 in bursts: after every Every requests, the next
+// Burst are rejected. Zero Every disables it.
+type Throttle struct {
+	Every int
+	Burst int
+}
+
+// Behavior is how a Provider departs from a perfect, instant node.
+type Behavior struct {
+	Latency       Latency            // Every method without its own entry
+	MethodLatency map[string]Latency // Per method, overriding Latency
+	ErrorRate     float64            // Fraction of calls answered -32603 (0..1)
+	Throttle      Throttle
+	Lag           uint64   // Serve the chain's head minus Lag as latest
+	ForkDepth     int      // Newest ForkDepth blocks are on a private fork
+	StateWindow   uint64   // Blocks of state kept behind the head (0 = archive)
+	Disabled      []string // Methods answered -32601
+	ClientVersion string   // web3_clientVersion; default "rpctest/v1.0.0/<name>"
+	Seed          int64    // Random seed; 0 = derived from the name
+}
+
+// Provider serves one simulated endpoint. Create it with NewProvider.
+type Provider struct {
+	name  string
+	chain *Chain
+	b     Behavior
+
+	mu       sync.Mutex
+	rng      *rand.Rand
+	requests int            // HTTP requests seen (Throttle's counter)
+	calls    map[string]int // Calls answered per method, errors included
+}
+
+// NewProvider returns a Provider named name serving chain with behavior b.
+func NewProvider(name string, chain *Chain, b Behavior) *Provider {
+	if b.ClientVersion == "" {
+		b.ClientVersion = "rpctest/v1.0.0/" + name
+	}
+	seed := b.Seed
+	if seed == 0 {
+		h := fnv.New64a()
+		h.Write([]byte(name))
+		seed = int64(h.Sum64())
+	}
+	return &Provider{name: name, chain: chain, b: b, rng: rand.New(rand.NewSource(seed)), calls: map[string]int{}}
+}
+
+// Name returns the provider's name.
+func (p *Provider) Name() string { return p.name }
+
+// Calls returns how many calls of method the provider has answered. Calls
+// rejected by Throttle are not counted.
+func (p *Provider) Calls(method string) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.calls[method]
+}
+
+// =============================================================================
+// SECTION 1: HTTP Handling
+// =============================================================================
+
+// request is a JSON-RPC request as received. The ID is kept raw so it is
+// echoed exactly, whether the client sent a number or a string.
+type request struct {
+	ID     json.RawMessage `json:"id"`
+	Method string          `json:"method"`
+	Params []interface{}   `json:"params"`
+}
+
+// response is one JSON-RPC response.
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *rpc.RPCError   `json:"error,omitempty"`
+}
+
+// ServeHTTP answers a single request or a batch.
+func (p *Provider) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	batch := bytes.HasPrefix(bytes.TrimSpace(body), []byte("["))
+	var reqs []request
+	if batch {
+		err = json.Unmarshal(body, &reqs)
+	} else {
+		reqs = make([]request, 1)
+		err = json.Unmarshal(body, &reqs[0])
+	}
+	if err != nil {
+		http.Error(w, "parse error: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Draw everything random under the lock, then wait and answer outside it.
+	p.mu.Lock()
+	p.requests++
+	t := p.b.Throttle
+	throttled := t.Every > 0 && (p.requests-1)%(t.Every+t.Burst) >= t.Every
+	var wait time.Duration
+	failed := make([]bool, len(reqs))
+	if !throttled {
+		for i, req := range reqs {
+			p.calls[req.Method]++
+			if d := p.sample(req.Method); d > wait {
+				wait = d
+			}
+			failed[i] = p.rng.Float64() < p.b.ErrorRate
+		}
+	}
+	p.mu.Unlock()
+
+	if throttled {
+		w.Header().Set("Retry-After", "1")
+		http.Error(w, "Too Many Requests", http.StatusTooManyRequests)
+		return
+	}
+	select {
+	case <-time.After(wait):
+	case <-r.Context().Done():
+		return
+	}
+
+	resps := make([]response, len(reqs))
+	for i, req := range reqs {
+		resps[i] = response{JSONRPC: "2.0", ID: req.ID}
+		if failed[i] {
+			resps[i].Error = &rpc.RPCError{Code: -32603, Message: "internal error"}
+			continue
+		}
+		result, rpcErr := p.dispatch(req)
+		if rpcErr != nil {
+			resps[i].Error = rpcErr
+			continue
+		}
+		resps[i].Result, _ = json.Marshal(result) // nil marshals to null
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if batch {
+		json.NewEncoder(w).Encode(resps)
+		return
+	}
+	json.NewEncoder(w).Encode(resps[0])
+}
+
+// sample draws one call's latency. Callers hold p.mu.
+func (p *Provider) sample(method string) time.Duration {
+	l, ok := p.b.MethodLatency[method]
+	if !ok {
+		l = p.b.Latency
+	}
+	d := l.Base
+	if l.Jitter > 0 {
+		d += time.Duration(p.rng.Int63n(int64(l.Jitter)))
+	}
+	if l.TailRate > 0 && p.rng.Float64() < l.TailRate {
+		d += l.Tail
+	}
+	return d
+}
+
+// =============================================================================
+// SECTION 2: Methods
+// =============================================================================
+
+// errMethodNotFound is geth's answer for a method it does not serve.
+func errMethodNotFound(method string) *rpc.RPCError {
+	return &rpc.RPCError{Code: -32601, Message: fmt.Sprintf("the method %s does not exist/is not available", method)}
+}
+
+// dispatch answers one call. A nil result with a nil error is JSON null.
+func (p *Provider) dispatch(req request) (interface{}, *rpc.RPCError) {
+	for _, m := range p.b.Disabled {
+		if m == req.Method {
+			return nil, errMethodNotFound(req.Method)
+		}
+	}
+	head := p.head()
+	switch req.Method {
+	case "eth_blockNumber":
+		return fmt.Sprintf("0x%x", head), nil
+	case "eth_chainId":
+		return fmt.Sprintf("0x%x", p.chain.ID()), nil
+	case "net_version":
+		return fmt.Sprint(p.chain.ID()), nil
+	case "web3_clientVersion":
+		return p.b.ClientVersion, nil
+	case "eth_syncing":
+		return false, nil
+	case "net_peerCount":
+		return "0x19", nil
+	case "eth_gasPrice":
+		fee, _ := rpc.ParseHexUint64(p.block(head).BaseFeePerGas)
+		return fmt.Sprintf("0x%x", fee+1_000_000_000), nil
+	case "eth_maxPriorityFeePerGas":
+		return "0x3b9aca00", nil
+	case "eth_feeHistory":
+		return p.feeHistory(req, head)
+
+	case "eth_getBlockByNumber", "eth_getBlockReceipts", "eth_getBalance", "eth_getStorageAt":
+		n, err := p.blockParam(req, head)
+		if err != nil {
+			return nil, err
+		}
+		b := p.block(n)
+		if b == nil {
+			if req.Method == "eth_getBalance" || req.Method == "eth_getStorageAt" {
+				return nil, &rpc.RPCError{Code: -32000, Message: "header not found"}
+			}
+			return nil, nil
+		}
+		switch req.Method {
+		case "eth_getBlockByNumber":
+			return b, nil
+		case "eth_getBlockReceipts":
+			return []interface{}{}, nil
+		}
+		if p.b.StateWindow > 0 && n+p.b.StateWindow < head {
+			return nil, &rpc.RPCError{Code: -32000, Message: "missing trie node " + b.StateRoot[2:18] + " (path )"}
+		}
+		if req.Method == "eth_getBalance" {
+			return "0x0", nil
+		}
+		return "0x" + strings.Repeat("00", 32), nil
+
+	case "eth_getBlockByHash":
+		h, _ := param(req, 0).(string)
+		return p.blockByHash(h, head), nil
+
+	case "eth_getLogs":
+		filter, _ := param(req, 0).(map[string]interface{})
+		for _, key := range []string{"fromBlock", "toBlock"} {
+			if s, ok := filter[key].(string); ok {
+				if _, err := p.resolve(s, head); err != nil {
+					return nil, err
+				}
+			}
+		}
+		return []interface{}{}, nil
+	}
+	return nil, errMethodNotFound(req.Method)
+}
+
+// maxFeeHistory is the most blocks eth_feeHistory returns, as in geth.
+const maxFeeHistory = 1024
+
+// feeHistory answers eth_feeHistory from this provider's view of the chain.
+// The blocks are empty, so every reward is zero and no blob gas is used.
+func (p *Provider) feeHistory(req request, head uint64) (interface{}, *rpc.RPCError) {
+	var count uint64
+	switch c := param(req, 0).(type) {
+	case string:
+		count, _ = rpc.ParseHexUint64(c)
+	case float64:
+		count = uint64(c)
+	}
+	newestArg, _ := param(req, 1).(string)
+	newest, err := p.resolve(newestArg, head)
+	if err != nil {
+		return nil, err
+	}
+	if count == 0 || p.block(newest) == nil {
+		return nil, &rpc.RPCError{Code: -32602, Message: "invalid argument: block count or newest block"}
+	}
+	if count > maxFeeHistory {
+		count = maxFeeHistory
+	}
+	if count > newest+1 {
+		count = newest + 1
+	}
+	percentiles, _ := param(req, 2).([]interface{})
+
+	oldest := newest + 1 - count
+	h := map[string]interface{}{"oldestBlock": fmt.Sprintf("0x%x", oldest)}
+	var baseFees, blobFees, rewards []interface{}
+	var ratios, blobRatios []float64
+	for n := oldest; n <= newest; n++ {
+		b := p.block(n)
+		used, _ := rpc.ParseHexUint64(b.GasUsed)
+		limit, _ := rpc.ParseHexUint64(b.GasLimit)
+		baseFees = append(baseFees, b.BaseFeePerGas)
+		blobFees = append(blobFees, fmt.Sprintf("0x%x", b.Parsed().BlobGas.BaseFee))
+		ratios = append(ratios, float64(used)/float64(limit))
+		blobRatios = append(blobRatios, 0)
+		if len(percentiles) > 0 {
+			row := make([]string, len(percentiles))
+			for i := range row {
+				row[i] = "0x0"
+			}
+			rewards = append(rewards, row)
+		}
+	}
+	baseFees = append(baseFees, fmt.Sprintf("0x%x", nextBaseFee(p.block(newest))))
+	blobFees = append(blobFees, blobFees[len(blobFees)-1]) // No blobs, so the excess stays put
+	h["baseFeePerGas"], h["gasUsedRatio"] = baseFees, ratios
+	h["baseFeePerBlobGas"], h["blobGasUsedRatio"] = blobFees, blobRatios
+	if rewards != nil {
+		h["reward"] = rewards
+	}
+	return h, nil
+}
+
+// head is the block this provider reports as latest.
+func (p *Provider) head() uint64 {
+	h := p.chain.Head()
+	if p.b.Lag >= h {
+		return 0
+	}
+	return h - p.b.Lag
+}
+
+// block returns block n as this provider sees it: from its fork when n is
+// within ForkDepth of its head, canonical otherwise; nil above its head.
+func (p *Provider) block(n uint64) *rpc.Block {
+	head := p.head()
+	if n > head {
+		return nil
+	}
+	if p.b.ForkDepth > 0 && n+uint64(p.b.ForkDepth) > head {
+		fork := p.chain.fork(head, p.b.ForkDepth, p.name)
+		if i := len(fork) - int(head-n) - 1; i >= 0 {
+			return fork[i]
+		}
+	}
+	return p.chain.Block(n)
+}
+
+// blockByHash finds a block this provider would serve by number.
+func (p *Provider) blockByHash(h string, head uint64) *rpc.Block {
+	if b := p.chain.BlockByHash(h); b != nil {
+		if n, _ := rpc.ParseHexUint64(b.Number); p.block(n) == b {
+			return b
+		}
+	}
+	for _, b := range p.chain.fork(head, p.b.ForkDepth, p.name) {
+		if strings.EqualFold(b.Hash, h) {
+			return b
+		}
+	}
+	return nil
+}
+
+// blockParam resolves the block argument of req: the first parameter for
+// eth_getBlockByNumber and eth_getBlockReceipts, the last for state calls.
+func (p *Provider) blockParam(req request, head uint64) (uint64, *rpc.RPCError) {
+	i := 0
+	if req.Method == "eth_getBalance" || req.Method == "eth_getStorageAt" {
+		i = len(req.Params) - 1
+	}
+	s, ok := param(req, i).(string)
+	if !ok {
+		return 0, &rpc.RPCError{Code: -32602, Message: "invalid argument: missing block"}
+	}
+	return p.resolve(s, head)
+}
+
+// resolve turns a tag or hex number into a block number.
+func (p *Provider) resolve(s string, head uint64) (uint64, *rpc.RPCError) {
+	below := func(depth uint64) uint64 {
+		if depth > head {
+			return 0
+		}
+		return head - depth
+	}
+	switch s {
+	case "latest", "pending":
+		return head, nil
+	case "safe":
+		return below(safeDepth), nil
+	case "finalized":
+		return below(finalizedDepth), nil
+	case "earliest":
+		return 0, nil
+	}
+	n, err := rpc.ParseHexUint64(s)
+	if err != nil {
+		return 0, &rpc.RPCError{Code: -32602, Message: fmt.Sprintf("invalid argument: block %q", s)}
+	}
+	return n, nil
+}
+
+// param returns req's i-th parameter, or nil.
+func param(req request, i int) interface{} {
+	if i < 0 || i >= len(req.Params) {
+		return nil
+	}
+	return req.Params[i]
+}
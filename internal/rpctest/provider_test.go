@@ -0,0 +1,161 @@
+package rpctest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dando385/eth-rpc-monitor/internal/rpc"
+)
+
+// serve starts p and returns a client for it.
+func serve(t *testing.T, p *Provider) *rpc.Client {
+	t.Helper()
+	srv := httptest.NewServer(p)
+	t.Cleanup(srv.Close)
+	return rpc.NewClient(p.Name(), srv.URL, 2*time.Second)
+}
+
+func TestProvider_standardMethods(t *testing.T) {
+	chain := NewChain(ChainOptions{ChainID: 11155111, Length: 100})
+	c := serve(t, NewProvider("node", chain, Behavior{Disabled: []string{"eth_getBlockReceipts"}}))
+	ctx := context.Background()
+
+	head, _, err := c.BlockNumber(ctx)
+	if err != nil || head != 100 {
+		t.Fatalf("head = %d, %v", head, err)
+	}
+	b, _, err := c.GetBlock(ctx, "latest")
+	if err != nil || b.Hash != chain.Block(100).Hash || !rpc.VerifyBlockHash(b).OK() {
+		t.Fatalf("latest = %+v, %v", b, err)
+	}
+	if n, _, err := c.BlockNumberByTag(ctx, "finalized"); err != nil || n != 100-finalizedDepth {
+		t.Fatalf("finalized = %d, %v", n, err)
+	}
+	if err := c.VerifyChain(ctx, 11155111, chain.Block(0).Hash); err != nil {
+		t.Fatal(err)
+	}
+	info, _, _ := c.NodeInfo(ctx)
+	if len(info.Errors) != 0 || info.Version.Name != "rpctest" || info.NetworkID != "11155111" {
+		t.Fatalf("nodeinfo = %+v", info)
+	}
+	if _, _, err := c.GetBlock(ctx, "0x65"); !errors.As(err, new(*rpc.NotFoundError)) {
+		t.Fatalf("block above head: %v", err)
+	}
+
+	var rpcErr *rpc.RPCError
+	if _, _, err := c.Call(ctx, "eth_getBlockReceipts", "latest"); !errors.As(err, &rpcErr) || rpcErr.Code != -32601 {
+		t.Fatalf("disabled method: %v", err)
+	}
+	if _, _, err := c.Call(ctx, "debug_traceBlockByNumber", "latest"); !errors.As(err, &rpcErr) || rpcErr.Code != -32601 {
+		t.Fatalf("unknown method: %v", err)
+	}
+
+	results, _, err := c.CallBatch(ctx, []rpc.BatchElem{{Method: "eth_blockNumber"}, {Method: "eth_chainId"}, {Method: "txpool_status"}})
+	if err != nil || results[0].Error != nil || results[1].Error != nil || results[2].Error == nil {
+		t.Fatalf("batch = %+v, %v", results, err)
+	}
+}
+
+func TestProvider_lagAndFork(t *testing.T) {
+	chain := NewChain(ChainOptions{Length: 100})
+	ctx := context.Background()
+
+	stale := serve(t, NewProvider("stale", chain, Behavior{Lag: 3}))
+	if head, _, _ := stale.BlockNumber(ctx); head != 97 {
+		t.Fatalf("stale head = %d", head)
+	}
+	if _, _, err := stale.GetBlock(ctx, "0x62"); err == nil {
+		t.Fatal("stale provider served a block above its head")
+	}
+
+	forked := serve(t, NewProvider("forked", chain, Behavior{ForkDepth: 2}))
+	for n, same := range map[string]bool{"0x62": true, "0x63": false, "0x64": false} {
+		b, _, err := forked.GetBlock(ctx, n)
+		if err != nil || !rpc.VerifyBlockHash(b).OK() {
+			t.Fatalf("%s: %v", n, err)
+		}
+		num, _ := rpc.ParseHexUint64(n)
+		if (b.Hash == chain.Block(num).Hash) != same {
+			t.Fatalf("%s: hash %s, canonical %s", n, b.Hash, chain.Block(num).Hash)
+		}
+	}
+	tip, _, _ := forked.GetBlock(ctx, "latest")
+	byHash, _, err := forked.Call(ctx, "eth_getBlockByHash", tip.Hash, false)
+	if err != nil || !strings.Contains(string(byHash.Result), tip.Hash) {
+		t.Fatalf("fork block by hash: %v", err)
+	}
+}
+
+func TestProvider_failures(t *testing.T) {
+	chain := NewChain(ChainOptions{Length: 10})
+	ctx := context.Background()
+
+	throttled := serve(t, NewProvider("throttled", chain, Behavior{Throttle: Throttle{Every: 2, Burst: 1}}))
+	var got []string
+	for i := 0; i < 6; i++ {
+		_, _, err := throttled.BlockNumber(ctx)
+		got = append(got, string(rpc.Classify(err)))
+	}
+	if strings.Join(got, ",") != ",,rate-limited,,,rate-limited" {
+		t.Fatalf("throttle pattern = %v", got)
+	}
+
+	flaky := serve(t, NewProvider("flaky", chain, Behavior{ErrorRate: 1}))
+	var rpcErr *rpc.RPCError
+	if _, _, err := flaky.BlockNumber(ctx); !errors.As(err, &rpcErr) || rpcErr.Code != -32603 {
+		t.Fatalf("flaky: %v", err)
+	}
+
+	slow := serve(t, NewProvider("slow", chain, Behavior{
+		Latency:       Latency{Base: 5 * time.Millisecond},
+		MethodLatency: map[string]Latency{"eth_getBlockByNumber": {Base: 60 * time.Millisecond}},
+	}))
+	if _, latency, _ := slow.GetBlock(ctx, "latest"); latency < 60*time.Millisecond {
+		t.Fatalf("getBlock latency %v", latency)
+	}
+	if _, latency, _ := slow.BlockNumber(ctx); latency >= 60*time.Millisecond {
+		t.Fatalf("blockNumber latency %v", latency)
+	}
+}
+
+func TestProvider_stateWindow(t *testing.T) {
+	chain := NewChain(ChainOptions{Length: 1000})
+	c := serve(t, NewProvider("full", chain, Behavior{StateWindow: 128}))
+	p, err := c.ProbeArchive(context.Background(), 1000, "0x"+strings.Repeat("00", 20))
+	if err != nil || p.Archive || p.Depth() != 128 || p.Pattern != "missing trie node" {
+		t.Fatalf("probe = %+v, %v", p, err)
+	}
+}
+
+func TestProvider_feeHistory(t *testing.T) {
+	chain := NewChain(ChainOptions{Length: 100})
+	c := serve(t, NewProvider("node", chain, Behavior{}))
+	q, _, err := c.FeeQuote(context.Background(), 20, []float64{25, 75})
+	if err != nil || len(q.Errors) != 0 {
+		t.Fatalf("quote = %+v, %v", q, err)
+	}
+	h := q.History
+	if h.Blocks() != 20 || h.NewestBlock() != 100 || h.MedianReward(1).Sign() != 0 || h.NextBlobBaseFee().Int64() != 1 {
+		t.Fatalf("history = %+v", h)
+	}
+	if got, want := h.BaseFees[19].String(), rpc.ParseHexBigInt(chain.Block(100).BaseFeePerGas).String(); got != want {
+		t.Fatalf("base fee of block 100 = %s, want %s", got, want)
+	}
+	if h.NextBaseFee().Uint64() != nextBaseFee(chain.Block(100)) {
+		t.Fatalf("next base fee = %v", h.NextBaseFee())
+	}
+}
+
+func TestProvider_badRequest(t *testing.T) {
+	srv := httptest.NewServer(NewProvider("p", NewChain(ChainOptions{Length: 1}), Behavior{}))
+	defer srv.Close()
+	resp, err := http.Post(srv.URL, "application/json", strings.NewReader("{"))
+	if err != nil || resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("status = %v, %v", resp, err)
+	}
+}